@@ -13,18 +13,36 @@ Refactored: 7th Feb 2025
 ****************************************************************************
 */
 import (
-	"fmt"
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/adaptor/auditsign"
 	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"github.com/sugaml/lms-api/internal/adaptor/eventbus"
 	"github.com/sugaml/lms-api/internal/adaptor/http"
+	"github.com/sugaml/lms-api/internal/adaptor/mailer"
+	"github.com/sugaml/lms-api/internal/adaptor/metrics"
+	"github.com/sugaml/lms-api/internal/adaptor/retention"
+	"github.com/sugaml/lms-api/internal/adaptor/sms"
+	"github.com/sugaml/lms-api/internal/adaptor/statscache"
 	"github.com/sugaml/lms-api/internal/adaptor/storage/postgres"
 	"github.com/sugaml/lms-api/internal/adaptor/storage/postgres/repository"
 	"github.com/sugaml/lms-api/internal/adaptor/storage/uploader"
+	"github.com/sugaml/lms-api/internal/adaptor/telemetry"
+	"github.com/sugaml/lms-api/internal/adaptor/webhook"
 	"github.com/sugaml/lms-api/internal/core/auth"
 	"github.com/sugaml/lms-api/internal/core/service"
 )
 
+// tracingFlushTimeout bounds sending the spans left in the batch once everything else stopped
+const tracingFlushTimeout = 5 * time.Second
+
 // @title						LMS API
 // @version						1.0
 // @description					This is a simple RESTful Service API for LMS Management
@@ -40,6 +58,12 @@ func main() {
 	}
 	logrus.Infof("Starting the application %s in %s mode", config.APP_NAME, config.APP_ENV)
 
+	// Init tracing ahead of the database so that its queries are traced
+	shutdownTracing, err := telemetry.New(context.Background(), config)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error initializing tracing")
+	}
+
 	// Init database
 	db, err := postgres.NewDB(config)
 	if err != nil {
@@ -51,9 +75,78 @@ func main() {
 	if err != nil {
 		logrus.WithError(err).Fatal("Error initializing token maker")
 	}
-	svc := service.NewService(repo, tokenMaker)
-	uploader, err := uploader.GetUploader()
-	handler := http.NewHandler(svc, config, tokenMaker, uploader)
+	smtpMailer, err := mailer.NewSMTPMailer(config)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error initializing mailer")
+	}
+	shutdownTimeout, err := time.ParseDuration(config.SHUTDOWN_TIMEOUT)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error parsing SHUTDOWN_TIMEOUT")
+	}
+
+	// Background jobs and the notification listener run until the server is shut down
+	jobs, stopJobs := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	run := func(job func(context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			job(jobs)
+		}()
+	}
+
+	notificationHub := postgres.NewNotificationHub(config.DB_SOURCE)
+	run(notificationHub.Listen)
+	smsSender, err := sms.NewSender(config)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error initializing sms sender")
+	}
+	smsPolicy, err := sms.NewPolicy(config)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error loading sms policy")
+	}
+	auditSigner, err := auditsign.NewSigner(config)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error initializing audit signer")
+	}
+	auditCheckpointInterval, err := time.ParseDuration(config.AUDIT_CHECKPOINT_INTERVAL)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error parsing AUDIT_CHECKPOINT_INTERVAL")
+	}
+	retentionPolicy, err := retention.NewPolicy(config)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error loading retention policy")
+	}
+	archiveInterval, err := time.ParseDuration(config.ARCHIVE_INTERVAL)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error parsing ARCHIVE_INTERVAL")
+	}
+	statsCache, err := statscache.New(config)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error initializing statistics cache")
+	}
+	fileUploader, err := uploader.GetUploader()
+	if err != nil {
+		logrus.WithError(err).Warn("File storage is not configured")
+		fileUploader = nil
+	}
+	m := metrics.New()
+	sqlDB, err := db.DB()
+	if err != nil {
+		logrus.WithError(err).Fatal("Error getting the database pool")
+	}
+	m.RegisterDB(sqlDB)
+	svc := service.NewService(repo, tokenMaker, smtpMailer, smsSender, smsPolicy, webhook.NewHTTPPoster(), notificationHub, eventbus.NewBus(), auditSigner, uploader.NewArchiveStore(fileUploader), retentionPolicy, statsCache, m)
+	m.RegisterLoans(svc.GetLoanGauges)
+	run(svc.RunNotifier)
+	run(svc.RunEmailDelivery)
+	run(svc.RunSMSDelivery)
+	run(svc.RunAnnouncementPublisher)
+	run(svc.RunWebhookDelivery)
+	run(func(ctx context.Context) { svc.RunAuditCheckpoint(ctx, auditCheckpointInterval) })
+	run(func(ctx context.Context) { svc.RunArchival(ctx, archiveInterval) })
+	run(svc.RunStatsWarmer)
+	handler := http.NewHandler(svc, config, tokenMaker, fileUploader, m)
 
 	// Init router
 	router, err := http.NewRouter(
@@ -64,9 +157,63 @@ func main() {
 		logrus.WithError(err).Fatal("Error initializing router")
 	}
 
-	listenAddr := fmt.Sprintf(":%s", config.APP_PORT)
-	logrus.Infof("Starting the HTTP server in port %s", listenAddr)
-	if err := router.Serve(listenAddr); err != nil {
-		logrus.WithError(err).Fatal("Error starting the HTTP server")
+	server, err := http.NewServer(config, router)
+	if err != nil {
+		logrus.WithError(err).Fatal("Error initializing the HTTP server")
+	}
+
+	// Serve until SIGINT or SIGTERM, a second signal kills the process right away
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	failed := false
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			logrus.WithError(err).Error("Error starting the HTTP server")
+			failed = true
+		}
+	case <-signals.Done():
+		logrus.Info("Shutting down the application")
+	}
+	stopSignals()
+
+	// Drain the requests in flight, then stop the jobs, all within SHUTDOWN_TIMEOUT
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logrus.WithError(err).Warn("Requests still in flight were cut off")
+	}
+	stopJobs()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		logrus.Warn("Background jobs did not stop in time")
+	}
+
+	if closer, ok := statsCache.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logrus.WithError(err).Warn("Error closing the statistics cache")
+		}
+	}
+	if err := sqlDB.Close(); err != nil {
+		logrus.WithError(err).Warn("Error closing the database pool")
+	}
+	// spans of the shutdown itself are flushed too, with time of their own
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logrus.WithError(err).Warn("Error flushing traces")
+	}
+	if failed {
+		os.Exit(1)
 	}
+	logrus.Info("Application stopped")
 }
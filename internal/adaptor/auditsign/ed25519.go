@@ -0,0 +1,48 @@
+package auditsign
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
+)
+
+// Ed25519Signer signs audit checkpoints with an ed25519 key
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner loads the base64 ed25519 seed in AUDIT_SIGNING_KEY. A checkpoint signed with a key
+// made up at startup would prove nothing, so without one there is no signer and checkpoints are
+// disabled. Production refuses to start without it.
+func NewSigner(config config.Config) (port.AuditSigner, error) {
+	if config.AUDIT_SIGNING_KEY == "" {
+		if config.APP_ENV == "production" {
+			return nil, errors.New("AUDIT_SIGNING_KEY is required in production")
+		}
+		logrus.Warn("AUDIT_SIGNING_KEY is not set, audit checkpoints are disabled")
+		return nil, nil
+	}
+	seed, err := base64.StdEncoding.DecodeString(config.AUDIT_SIGNING_KEY)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("AUDIT_SIGNING_KEY must be a base64 %d byte ed25519 seed", ed25519.SeedSize)
+	}
+	return &Ed25519Signer{key: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+func (s *Ed25519Signer) Algorithm() string {
+	return domain.AuditSignatureEd25519
+}
+
+func (s *Ed25519Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+func (s *Ed25519Signer) Sign(payload []byte) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload)), nil
+}
@@ -5,6 +5,21 @@ type Config struct {
 	APP_ENV   string `json:"APP_ENV" default:"development"`
 	APP_DEBUG string `json:"APP_DEBUG" default:"true"`
 	APP_PORT  string `json:"APP_PORT" default:"8080"`
+	// json logs are one object per line for log collectors, text is easier to read in a terminal
+	LOG_FORMAT string `json:"LOG_FORMAT" default:"json"` // json or text
+
+	// the server drops clients that are slower than these to send a request or read the answer,
+	// 0 is no limit. Notification streams and exports are not cut short by the write timeout.
+	HTTP_READ_HEADER_TIMEOUT string `json:"HTTP_READ_HEADER_TIMEOUT" default:"10s"`
+	HTTP_READ_TIMEOUT        string `json:"HTTP_READ_TIMEOUT" default:"30s"`
+	HTTP_WRITE_TIMEOUT       string `json:"HTTP_WRITE_TIMEOUT" default:"60s"`
+	HTTP_IDLE_TIMEOUT        string `json:"HTTP_IDLE_TIMEOUT" default:"120s"`
+	HTTP_MAX_HEADER_BYTES    string `json:"HTTP_MAX_HEADER_BYTES" default:"1048576"`
+	// on SIGTERM the requests in flight and the background jobs get this long to finish
+	SHUTDOWN_TIMEOUT string `json:"SHUTDOWN_TIMEOUT" default:"30s"`
+	// HTTPS is served instead of HTTP when both are set
+	TLS_CERT_FILE string `json:"TLS_CERT_FILE" default:""`
+	TLS_KEY_FILE  string `json:"TLS_KEY_FILE" default:""`
 
 	JWT_SECRET string `json:"JWT_SECRET" default:"postgres"`
 
@@ -18,6 +33,56 @@ type Config struct {
 
 	REDIS_ADDR     string `json:"REDIS_ADDR" default:"localhost:6379"`
 	REDIS_PASSWORD string `json:"REDIS_PASSWORD" default:""`
+	// dashboard statistics are cached in Redis, or in memory without it, for at most this long
+	// and dropped sooner when books, borrows, fines or users change
+	STATS_CACHE_TTL string `json:"STATS_CACHE_TTL" default:"10m"`
+
+	// spans are printed to stdout, sent over OTLP/HTTP to OTEL_EXPORTER_OTLP_ENDPOINT or, with
+	// none, not exported at all. The ratio is the share of new traces that is sampled.
+	TRACING_EXPORTER            string `json:"TRACING_EXPORTER" default:"none"` // none, stdout or otlp
+	TRACING_SAMPLE_RATIO        string `json:"TRACING_SAMPLE_RATIO" default:"1"`
+	OTEL_EXPORTER_OTLP_ENDPOINT string `json:"OTEL_EXPORTER_OTLP_ENDPOINT" default:"http://localhost:4318"`
 
 	NOTIFICATION_GRPC_URL string `json:"NOTIFICATION_GRPC_URL" default:"localhost:50050"`
+
+	// defaults point at a local SMTP stand-in such as Mailpit or MailHog
+	SMTP_HOST     string `json:"SMTP_HOST" default:"localhost"`
+	SMTP_PORT     string `json:"SMTP_PORT" default:"1025"`
+	SMTP_USERNAME string `json:"SMTP_USERNAME" default:""`
+	SMTP_PASSWORD string `json:"SMTP_PASSWORD" default:""`
+	SMTP_FROM     string `json:"SMTP_FROM" default:"LMS <no-reply@lms.local>"`
+	SMTP_TLS      string `json:"SMTP_TLS" default:"none"` // none, starttls or tls
+
+	// log writes messages to SMS_LOG_PATH (or the application log) instead of sending them
+	SMS_DRIVER           string `json:"SMS_DRIVER" default:"log"` // log or http
+	SMS_LOG_PATH         string `json:"SMS_LOG_PATH" default:""`
+	SMS_GATEWAY_URL      string `json:"SMS_GATEWAY_URL" default:""`
+	SMS_GATEWAY_TOKEN    string `json:"SMS_GATEWAY_TOKEN" default:""`
+	SMS_SENDER_ID        string `json:"SMS_SENDER_ID" default:"LMS"`
+	SMS_TEMPLATES        string `json:"SMS_TEMPLATES" default:"due_reminder,hold_ready"`
+	SMS_DAILY_LIMIT      string `json:"SMS_DAILY_LIMIT" default:"3"`
+	SMS_QUIET_START      string `json:"SMS_QUIET_START" default:"21:00"`
+	SMS_QUIET_END        string `json:"SMS_QUIET_END" default:"07:00"`
+	SMS_TIMEZONE         string `json:"SMS_TIMEZONE" default:"Asia/Kathmandu"` // of the quiet hours
+	SMS_COST_PER_SEGMENT string `json:"SMS_COST_PER_SEGMENT" default:"150"`    // in paisa
+
+	// base64 ed25519 seed the audit checkpoints are signed with, `openssl rand -base64 32`.
+	// Checkpoints are disabled without it, production does not start without it
+	AUDIT_SIGNING_KEY         string `json:"AUDIT_SIGNING_KEY" default:""`
+	AUDIT_CHECKPOINT_INTERVAL string `json:"AUDIT_CHECKPOINT_INTERVAL" default:"24h"`
+
+	// retention in days before rows are archived to files, 0 keeps them forever. The action and
+	// module lists take "name=days" pairs, an action's days win over its module's
+	AUDIT_RETENTION_DAYS             string `json:"AUDIT_RETENTION_DAYS" default:"0"`
+	AUDIT_RETENTION_ACTIONS          string `json:"AUDIT_RETENTION_ACTIONS" default:"login=90"`
+	AUDIT_RETENTION_MODULES          string `json:"AUDIT_RETENTION_MODULES" default:"book_copy=365,notification=180"`
+	NOTIFICATION_RETENTION_DAYS      string `json:"NOTIFICATION_RETENTION_DAYS" default:"365"`
+	NOTIFICATION_READ_RETENTION_DAYS string `json:"NOTIFICATION_READ_RETENTION_DAYS" default:"30"` // purged, not archived
+	ARCHIVE_RESTORE_DAYS             string `json:"ARCHIVE_RESTORE_DAYS" default:"30"`
+	ARCHIVE_INTERVAL                 string `json:"ARCHIVE_INTERVAL" default:"24h"`
+
+	// institution name printed at the top of exported reports. PDF exports use Helvetica, which
+	// has no Devanagari, unless EXPORT_PDF_FONT points at a TTF font such as Noto Sans Devanagari
+	INSTITUTION_NAME string `json:"INSTITUTION_NAME" default:""`
+	EXPORT_PDF_FONT  string `json:"EXPORT_PDF_FONT" default:""`
 }
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
@@ -36,6 +37,9 @@ func LoadConfig() (config Config, err error) {
 		logLevel = logrus.DebugLevel
 	}
 	logrus.SetLevel(logLevel)
+	if config.LOG_FORMAT == "json" {
+		logrus.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+	}
 	logrus.Info("Successfully loaded configurations.")
 	return
 }
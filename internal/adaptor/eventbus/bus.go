@@ -0,0 +1,43 @@
+package eventbus
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// Bus is an in-process event bus, each event is handed to the subscribers on its own goroutine so
+// publishers never wait and one failing subscriber does not stop the others
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []func(event *domain.DomainEvent)
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(handler func(event *domain.DomainEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *Bus) Publish(event *domain.DomainEvent) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+	for _, handler := range handlers {
+		go deliver(handler, event)
+	}
+}
+
+func deliver(handler func(event *domain.DomainEvent), event *domain.DomainEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("event handler panicked on %s %s: %v", event.Type, event.ID, r)
+		}
+	}()
+	handler(event)
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// csvWriter streams rows through a buffered csv.Writer, the report header comes first as one
+// cell rows followed by a blank row
+type csvWriter struct {
+	buf         *bufio.Writer
+	w           *csv.Writer
+	institution string
+}
+
+func newCSVWriter(out io.Writer, institution string) *csvWriter {
+	// csv.NewWriter keeps buf as it is, so the byte order mark and the rows share one buffer
+	buf := bufio.NewWriter(out)
+	return &csvWriter{buf: buf, w: csv.NewWriter(buf), institution: institution}
+}
+
+func (c *csvWriter) WriteHeader(meta *domain.ExportMeta, columns []string) error {
+	// the byte order mark makes spreadsheet programs read the file as UTF-8
+	if _, err := c.buf.WriteString("\ufeff"); err != nil {
+		return err
+	}
+	for _, line := range headerLines(c.institution, meta) {
+		if err := c.w.Write([]string{line}); err != nil {
+			return err
+		}
+	}
+	if err := c.w.Write(nil); err != nil {
+		return err
+	}
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatValue(value)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
+)
+
+// Exporter makes the report file writers, every file carries the institution name
+type Exporter struct {
+	institution string
+	pdfFont     string
+}
+
+func New(config config.Config) *Exporter {
+	return &Exporter{
+		institution: config.INSTITUTION_NAME,
+		pdfFont:     config.EXPORT_PDF_FONT,
+	}
+}
+
+// NewWriter writes the format to w, csv goes out row by row while xlsx and pdf are written on Close
+func (e *Exporter) NewWriter(format string, w io.Writer) (port.ExportWriter, error) {
+	switch format {
+	case domain.ExportCSV:
+		return newCSVWriter(w, e.institution), nil
+	case domain.ExportXLSX:
+		return newXLSXWriter(w, e.institution)
+	case domain.ExportPDF:
+		return newPDFWriter(w, e.institution, e.pdfFont)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+func ContentType(format string) string {
+	switch format {
+	case domain.ExportCSV:
+		return "text/csv; charset=utf-8"
+	case domain.ExportXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case domain.ExportPDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// headerLines is the report header shared by every format: institution, title, subject, period
+// and when it was generated
+func headerLines(institution string, meta *domain.ExportMeta) []string {
+	var lines []string
+	if institution != "" {
+		lines = append(lines, institution)
+	}
+	lines = append(lines, domain.ExportLabel(meta.Lang, meta.Title))
+	if meta.Subject != "" {
+		lines = append(lines, meta.Subject)
+	}
+	if meta.From != "" || meta.To != "" {
+		lines = append(lines, fmt.Sprintf("%s: %s – %s", domain.ExportLabel(meta.Lang, "period"), meta.From, meta.To))
+	}
+	return append(lines, fmt.Sprintf("%s: %s", domain.ExportLabel(meta.Lang, "generated"), meta.GeneratedAt.Format("2006-01-02 15:04")))
+}
+
+// formatValue renders a cell as text, times without a clock part are written as dates
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return formatTime(v)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return formatTime(*v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if h, m, s := t.Clock(); h == 0 && m == 0 && s == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -0,0 +1,119 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const (
+	// pdfMaxRows caps a PDF, fpdf builds the whole document in memory and a table longer than
+	// this is better read as a spreadsheet
+	pdfMaxRows = 5000
+	pdfFont    = "body"
+	pdfMargin  = 10.0
+	pdfLine    = 6.0
+)
+
+// pdfWriter lays the rows out as a table on landscape A4 pages, repeating the column headers on
+// every page. Without a TTF font the core Helvetica font is used, which only covers Latin text.
+type pdfWriter struct {
+	out         io.Writer
+	pdf         *fpdf.Fpdf
+	tr          func(string) string
+	family      string
+	institution string
+	meta        *domain.ExportMeta
+	columns     []string
+	width       float64
+	rows        int
+}
+
+func newPDFWriter(out io.Writer, institution, font string) (*pdfWriter, error) {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	p := &pdfWriter{out: out, pdf: pdf, institution: institution, family: "Helvetica"}
+	if font != "" {
+		pdf.AddUTF8Font(pdfFont, "", font)
+		p.family, p.tr = pdfFont, func(s string) string { return s }
+	} else {
+		p.tr = pdf.UnicodeTranslatorFromDescriptor("")
+	}
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *pdfWriter) WriteHeader(meta *domain.ExportMeta, columns []string) error {
+	p.meta, p.columns = meta, columns
+	pageWidth, _ := p.pdf.GetPageSize()
+	p.width = (pageWidth - 2*pdfMargin) / float64(max(len(columns), 1))
+
+	p.pdf.AddPage()
+	for i, line := range headerLines(p.institution, meta) {
+		size := 10.0
+		if i == 0 {
+			size = 14
+		}
+		p.pdf.SetFont(p.family, "", size)
+		p.pdf.CellFormat(0, pdfLine+1, p.tr(line), "", 1, "L", false, 0, "")
+	}
+	p.pdf.Ln(pdfLine / 2)
+	p.tableHeader()
+	return p.pdf.Error()
+}
+
+func (p *pdfWriter) tableHeader() {
+	p.pdf.SetFont(p.family, "", 9)
+	p.pdf.SetFillColor(230, 230, 230)
+	for _, column := range p.columns {
+		p.pdf.CellFormat(p.width, pdfLine, p.fit(column), "1", 0, "L", true, 0, "")
+	}
+	p.pdf.Ln(-1)
+	p.pdf.SetFont(p.family, "", 8)
+}
+
+func (p *pdfWriter) WriteRow(values []any) error {
+	p.rows++
+	if p.rows > pdfMaxRows {
+		return nil
+	}
+	if _, pageHeight := p.pdf.GetPageSize(); p.pdf.GetY()+pdfLine > pageHeight-pdfMargin {
+		p.pdf.AddPage()
+		p.tableHeader()
+	}
+	for _, value := range values {
+		p.pdf.CellFormat(p.width, pdfLine, p.fit(formatValue(value)), "1", 0, "L", false, 0, "")
+	}
+	p.pdf.Ln(-1)
+	return p.pdf.Error()
+}
+
+// fit cuts the text short so that it stays inside its cell
+func (p *pdfWriter) fit(text string) string {
+	text = p.tr(text)
+	if p.pdf.GetStringWidth(text) <= p.width-2 {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && p.pdf.GetStringWidth(string(runes)+"...") > p.width-2 {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+func (p *pdfWriter) Close() error {
+	if p.rows > pdfMaxRows {
+		if _, pageHeight := p.pdf.GetPageSize(); p.pdf.GetY()+2*pdfLine > pageHeight-pdfMargin {
+			p.pdf.AddPage()
+		}
+		p.pdf.Ln(pdfLine / 2)
+		note := fmt.Sprintf(domain.ExportLabel(p.meta.Lang, "truncated"), p.rows-pdfMaxRows)
+		p.pdf.CellFormat(0, pdfLine, p.tr(note), "", 1, "L", false, 0, "")
+	}
+	return p.pdf.Output(p.out)
+}
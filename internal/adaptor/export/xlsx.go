@@ -0,0 +1,89 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+const xlsxSheet = "Sheet1"
+
+// xlsxWriter goes through the excelize stream writer, which spills rows to a temporary file
+// instead of keeping them in memory, the workbook is written out on Close
+type xlsxWriter struct {
+	out         io.Writer
+	file        *excelize.File
+	sw          *excelize.StreamWriter
+	bold        int
+	row         int
+	institution string
+}
+
+func newXLSXWriter(out io.Writer, institution string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	sw, err := file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	bold, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: out, file: file, sw: sw, bold: bold, institution: institution}, nil
+}
+
+func (x *xlsxWriter) WriteHeader(meta *domain.ExportMeta, columns []string) error {
+	if err := x.sw.SetColWidth(1, max(len(columns), 1), 20); err != nil {
+		return err
+	}
+	for i, line := range headerLines(x.institution, meta) {
+		style := 0
+		if i == 0 {
+			style = x.bold
+		}
+		if err := x.setRow([]any{excelize.Cell{StyleID: style, Value: line}}); err != nil {
+			return err
+		}
+	}
+	x.row++
+	cells := make([]any, len(columns))
+	for i, column := range columns {
+		cells[i] = excelize.Cell{StyleID: x.bold, Value: column}
+	}
+	return x.setRow(cells)
+}
+
+func (x *xlsxWriter) WriteRow(values []any) error {
+	cells := make([]any, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case time.Time, *time.Time:
+			cells[i] = formatValue(v)
+		default:
+			cells[i] = v
+		}
+	}
+	return x.setRow(cells)
+}
+
+func (x *xlsxWriter) setRow(cells []any) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.sw.SetRow(cell, cells)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
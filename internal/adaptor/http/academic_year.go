@@ -0,0 +1,128 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AddAcademicYear		godoc
+// @Summary			Add a new AcademicYear
+// @Description		Add a new AcademicYear
+// @Tags			AcademicYear
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			AcademicYearRequest			body		domain.AcademicYearRequest		true		"Add AcademicYear Request"
+// @Success			200						{object}	domain.AcademicYearResponse				"AcademicYear created"
+// @Router			/academic-years 				[post]
+func (h *Handler) CreateAcademicYear(ctx *gin.Context) {
+	var req *domain.AcademicYearRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateAcademicYear(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListAcademicYear 		godoc
+// @Summary 		List AcademicYear
+// @Description 	List AcademicYear
+// @Tags 			AcademicYear
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 						query 		string 		false 	"query"
+// @Success 		200 		{array} 		domain.AcademicYearResponse
+// @Router 			/academic-years	 	[get]
+func (h *Handler) ListAcademicYear(ctx *gin.Context) {
+	var req domain.ListAcademicYearRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListAcademicYear(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetAcademicYear 		godoc
+// @Summary 		Get AcademicYear
+// @Description 	Get AcademicYear from Id
+// @Tags 			AcademicYear
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "AcademicYear id"
+// @Success 		200 {object} domain.AcademicYearResponse
+// @Router 			/academic-years/{id} [get]
+func (h *Handler) GetAcademicYear(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetAcademicYear(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateAcademicYear			godoc
+// @Summary 			Update AcademicYear
+// @Description 		Update AcademicYear from Id
+// @Tags 				AcademicYear
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 							true 	"AcademicYear id"
+// @Param 				UpdateAcademicYearRequest	 		body 		domain.UpdateAcademicYearRequest 		true 	"Update AcademicYear Request"
+// @Success 			200 						{object} 	domain.AcademicYearResponse
+// @Router 				/academic-years/{id} 				[put]
+func (h *Handler) UpdateAcademicYear(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateAcademicYearRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateAcademicYear(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteAcademicYear 		godoc
+// @Summary 			Delete AcademicYear
+// @Description 		Delete AcademicYear from Id, refused while routines are scheduled in it
+// @Tags 				AcademicYear
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"AcademicYear id"
+// @Success 			200 					{object} 	domain.AcademicYearResponse
+// @Router 				/academic-years/{id} 			[delete]
+func (h *Handler) DeleteAcademicYear(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required academic year id"))
+		return
+	}
+	result, err := h.svc.DeleteAcademicYear(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,289 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	storage "github.com/sugaml/lms-api/internal/adaptor/storage/uploader"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateAnnouncement	godoc
+// @Summary			Add a new Announcement
+// @Description		Post an announcement to everybody, a role, a program, a batch or chosen users. It is published at publish_at, straight away when empty.
+// @Tags			Announcements
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			AnnouncementRequest			body		domain.AnnouncementRequest		true		"Add Announcement Request"
+// @Success			200					{object}			domain.AnnouncementResponse					"Announcement created"
+// @Router			/announcements				[post]
+func (h *Handler) CreateAnnouncement(ctx *gin.Context) {
+	var req *domain.AnnouncementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateAnnouncement(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListAnnouncement godoc
+// @Summary 		List Announcement
+// @Description 	List every announcement, pinned first, for those who manage them
+// @Tags 			Announcements
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 								query 		string 		false 	"query"
+// @Param 			audience 							query 		string 		false 	"all | role | program | batch | users"
+// @Param 			status 								query 		string 		false 	"scheduled | published | expired"
+// @Param 			is_pinned 							query 		bool 		false 	"is_pinned"
+// @Success 		200 				{array} 		domain.AnnouncementResponse
+// @Router 			/announcements	 	[get]
+func (h *Handler) ListAnnouncement(ctx *gin.Context) {
+	var req domain.ListAnnouncementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.SortDirection == "" {
+		req.SortDirection = "desc"
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListAnnouncement(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// ListAnnouncementFeed godoc
+// @Summary 		List my Announcements
+// @Description 	List the published announcements addressed to the caller, pinned first
+// @Tags 			Announcements
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 								query 		string 		false 	"query"
+// @Param 			is_pinned 							query 		bool 		false 	"is_pinned"
+// @Success 		200 				{array} 		domain.AnnouncementResponse
+// @Router 			/announcements/feed	 	[get]
+func (h *Handler) ListAnnouncementFeed(ctx *gin.Context) {
+	var req domain.ListAnnouncementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.SortDirection == "" {
+		req.SortDirection = "desc"
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListAnnouncementFeed(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetAnnouncement 	godoc
+// @Summary 		Get Announcement
+// @Description 	Get Announcement from Id, those who manage announcements also get the receipt counts
+// @Tags 			Announcements
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Announcement id"
+// @Success 		200 {object} domain.AnnouncementResponse
+// @Router 			/announcements/{id} [get]
+func (h *Handler) GetAnnouncement(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetAnnouncement(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateAnnouncement	godoc
+// @Summary 			Update Announcement
+// @Description 		Update Announcement from Id, publish_at can only change while it is scheduled
+// @Tags 				Announcements
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 								true 	"Announcement id"
+// @Param 				UpdateAnnouncementRequest	 body 		domain.UpdateAnnouncementRequest 	true 	"Update Announcement request"
+// @Success 			200 						{object} 	domain.AnnouncementResponse
+// @Router 				/announcements/{id} 		[put]
+func (h *Handler) UpdateAnnouncement(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateAnnouncementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	data, err := h.svc.UpdateAnnouncement(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, data)
+}
+
+// DeleteAnnouncement 	godoc
+// @Summary 			Delete Announcement
+// @Description 		Delete Announcement from Id along with the notifications it was published as
+// @Tags 				Announcements
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Announcement id"
+// @Success 			200 					{object} 	domain.AnnouncementResponse
+// @Router 				/announcements/{id} 	[delete]
+func (h *Handler) DeleteAnnouncement(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required announcement id"))
+		return
+	}
+	result, err := h.svc.DeleteAnnouncement(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ReadAnnouncement 	godoc
+// @Summary 			Mark Announcement read
+// @Description 		Record the caller's read receipt, its notification is marked read too
+// @Tags 				Announcements
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 		true 	"Announcement id"
+// @Success 			200 						{object} 	domain.AnnouncementResponse
+// @Router 				/announcements/{id}/read 	[post]
+func (h *Handler) ReadAnnouncement(ctx *gin.Context) {
+	result, err := h.svc.ReadAnnouncement(ctx, ctx.Param("id"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListAnnouncementReceipt 	godoc
+// @Summary 				List Announcement receipts
+// @Description 			List who an announcement reached and when they read it
+// @Tags 					Announcements
+// @Produce  				json
+// @Security 				ApiKeyAuth
+// @Param 					id 								path 		string 		true 	"Announcement id"
+// @Param 					query 							query 		string 		false 	"Recipient name"
+// @Param 					is_read 						query 		bool 		false 	"is_read"
+// @Success 				200 							{array} 	domain.AnnouncementReceiptResponse
+// @Router 					/announcements/{id}/receipts 	[get]
+func (h *Handler) ListAnnouncementReceipt(ctx *gin.Context) {
+	var req domain.ListAnnouncementReceiptRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListAnnouncementReceipt(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// AddAnnouncementAttachment 	godoc
+// @Summary 					Attach a file to an Announcement
+// @Description 				Upload a file through the configured file storage and attach it
+// @Tags 						Announcements
+// @Accept  					multipart/form-data
+// @Produce  					json
+// @Security 					ApiKeyAuth
+// @Param 						id 									path 		string 		true 	"Announcement id"
+// @Param 						file 								formData 	file 		true 	"Attachment"
+// @Success 					200 								{object} 	domain.AnnouncementResponse
+// @Router 						/announcements/{id}/attachments 	[post]
+func (h *Handler) AddAnnouncementAttachment(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if h.uploader == nil {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("file storage is not configured"))
+		return
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("file required"))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("file open failed"))
+		return
+	}
+	defer file.Close()
+
+	// the uploader builds the path from the name, keep only its base
+	fileHeader.Filename = filepath.Base(fileHeader.Filename)
+	url, err := h.uploader.UploadFile(&storage.FileDetails{
+		FileType:   storage.FileTypeAnnouncement,
+		EntityID:   id,
+		File:       file,
+		FileHeader: fileHeader,
+		Metadata: storage.FileMetadata{
+			FileName:    fileHeader.Filename,
+			Size:        fileHeader.Size,
+			ContentType: fileHeader.Header.Get("Content-Type"),
+			UploadedAt:  time.Now(),
+		},
+	})
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.AddAnnouncementAttachment(ctx, id, &domain.AnnouncementAttachmentRequest{
+		FileName:    fileHeader.Filename,
+		URL:         url,
+		ContentType: fileHeader.Header.Get("Content-Type"),
+		Size:        fileHeader.Size,
+	})
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteAnnouncementAttachment 	godoc
+// @Summary 						Remove an Announcement attachment
+// @Description 					Remove an attachment from an announcement
+// @Tags 							Announcements
+// @Produce  						json
+// @Security 						ApiKeyAuth
+// @Param 							id 													path 		string 		true 	"Announcement id"
+// @Param 							attachmentId 										path 		string 		true 	"Attachment id"
+// @Success 						200 												{object} 	domain.AnnouncementResponse
+// @Router 							/announcements/{id}/attachments/{attachmentId} 	[delete]
+func (h *Handler) DeleteAnnouncementAttachment(ctx *gin.Context) {
+	result, err := h.svc.DeleteAnnouncementAttachment(ctx, ctx.Param("id"), ctx.Param("attachmentId"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// ListArchive 		godoc
+// @Summary 		List Archives
+// @Description 	List the files the retention job archived audit logs and notifications to
+// @Tags 			Archive
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			kind 				query 		string 		false 	"audit_log | notification"
+// @Success 		200 {array} domain.ArchiveResponse
+// @Router 			/archives [get]
+func (h *Handler) ListArchive(ctx *gin.Context) {
+	var req domain.ListArchiveRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.SortDirection == "" {
+		req.SortDirection = "desc"
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListArchive(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetArchive 		godoc
+// @Summary 		Get Archive
+// @Description 	Get Archive from Id
+// @Tags 			Archive
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Archive id"
+// @Success 		200 {object} domain.ArchiveResponse
+// @Router 			/archives/{id} [get]
+func (h *Handler) GetArchive(ctx *gin.Context) {
+	result, err := h.svc.GetArchive(ctx, ctx.Param("id"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// RestoreArchive 	godoc
+// @Summary 		Restore an Archive
+// @Description 	Put the entries of an audit log archive back for an investigation, they show up in the audit log, history and verification again and are kept for ARCHIVE_RESTORE_DAYS
+// @Tags 			Archive
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id 							path 		string 		true 	"Archive id"
+// @Success 		200 						{object} 	domain.RestoreArchiveResponse
+// @Router 			/archives/{id}/restore 		[post]
+func (h *Handler) RestoreArchive(ctx *gin.Context) {
+	result, err := h.svc.RestoreArchive(ctx, ctx.Param("id"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -25,7 +26,7 @@ func (h *Handler) CreateAuditLog(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	result, err := h.svc.CreateAuditLog(req)
+	result, err := h.svc.CreateAuditLog(ctx, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -41,6 +42,11 @@ func (h *Handler) CreateAuditLog(ctx *gin.Context) {
 // @Produce  		json
 // @Security 		ApiKeyAuth
 // @Security 		UserAuth
+// @Param 			user_id 			query 		string 		false 	"Actor user id"
+// @Param 			action 				query 		string 		false 	"action"
+// @Param 			entity_type 		query 		string 		false 	"entity_type"
+// @Param 			entity_id 			query 		string 		false 	"entity_id"
+// @Param 			request_id 			query 		string 		false 	"request_id"
 // @Success 		200 {array} domain.AuditLogResponse
 // @Router 			/auditlog [get]
 func (h *Handler) ListAuditLog(ctx *gin.Context) {
@@ -50,7 +56,7 @@ func (h *Handler) ListAuditLog(ctx *gin.Context) {
 		return
 	}
 	req.Prepare()
-	result, count, err := h.svc.ListAuditLog(&req)
+	result, count, err := h.svc.ListAuditLog(ctx, &req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -71,7 +77,7 @@ func (h *Handler) ListAuditLog(ctx *gin.Context) {
 // @Router 			/auditlog/{id} [get]
 func (h *Handler) GetAuditLog(ctx *gin.Context) {
 	id := ctx.Param("id")
-	result, err := h.svc.GetAuditLog(id)
+	result, err := h.svc.GetAuditLog(ctx, id)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -79,54 +85,97 @@ func (h *Handler) GetAuditLog(ctx *gin.Context) {
 	SuccessResponse(ctx, result)
 }
 
-// UpdateAuditLog		godoc
-// @Summary 			Update AuditLog
-// @Description 		Update AuditLog from Id
-// @Tags 				AuditLog
-// @Accept  			json
-// @Produce  			json
-// @Security 			ApiKeyAuth
-// @Security 			UserAuth
-// @Param 				id 								path 			string 								true 	"AuditLog id"
-// @Param 				AuditLogUpdateRequest	 	body 			domain.AuditLogUpdateRequest 	true 	"Update AuditLog Response request"
-// @Success 			200 							{object} 		domain.AuditLogResponse
-// @Router 				/auditlog/{id} 				[put]
-func (h *Handler) UpdateAuditLog(ctx *gin.Context) {
-	id := ctx.Param("id")
-	var req *domain.AuditLogUpdateRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+// VerifyAuditLog 	godoc
+// @Summary 		Verify AuditLog chain
+// @Description 	Recompute the hash chain and report missing or edited entries and checkpoints that no longer match, the whole chain when no range is given
+// @Tags 			AuditLog
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Security 		UserAuth
+// @Param 			from_sequence 		query 		int 		false 	"First sequence"
+// @Param 			to_sequence 		query 		int 		false 	"Last sequence"
+// @Success 		200 {object} domain.VerifyAuditLogResponse
+// @Router 			/auditlog/verify [get]
+func (h *Handler) VerifyAuditLog(ctx *gin.Context) {
+	var req domain.VerifyAuditLogRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	data, err := h.svc.UpdateAuditLog(id, req)
+	result, err := h.svc.VerifyAuditLog(ctx, &req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	SuccessResponse(ctx, data)
+	SuccessResponse(ctx, result)
 }
 
-// DeleteAuditLog 		godoc
-// @Summary 			Delete AuditLog
-// @Description 		Delete AuditLog from Id
+// CreateAuditCheckpoint 	godoc
+// @Summary 				Sign an AuditLog checkpoint
+// @Description 			Verify the chain since the last checkpoint and sign its current head
+// @Tags 					AuditLog
+// @Produce  				json
+// @Security 				ApiKeyAuth
+// @Security 				UserAuth
+// @Success 				200 {object} domain.AuditCheckpointResponse
+// @Router 					/auditlog/checkpoints [post]
+func (h *Handler) CreateAuditCheckpoint(ctx *gin.Context) {
+	result, err := h.svc.CreateAuditCheckpoint(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListAuditCheckpoint 	godoc
+// @Summary 			List AuditLog checkpoints
+// @Description 		List the signed checkpoints of the audit chain
 // @Tags 				AuditLog
-// @Accept  			json
 // @Produce  			json
 // @Security 			ApiKeyAuth
 // @Security 			UserAuth
-// @Param 				id 						path 		string 						true 	"AuditLog id"
-// @Success 			200 					{object} 	domain.AuditLogResponse
-// @Router 				/auditlog/{id} 	[delete]
-func (ch *Handler) DeleteAuditLog(ctx *gin.Context) {
+// @Success 			200 {array} domain.AuditCheckpointResponse
+// @Router 				/auditlog/checkpoints [get]
+func (h *Handler) ListAuditCheckpoint(ctx *gin.Context) {
+	var req domain.ListAuditCheckpointRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.SortDirection == "" {
+		req.SortDirection = "desc"
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListAuditCheckpoint(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// ExportAuditCheckpoint 	godoc
+// @Summary 				Export an AuditLog checkpoint
+// @Description 			Download a checkpoint with the payload it signed, it can be checked with its public key away from the database
+// @Tags 					AuditLog
+// @Produce  				json
+// @Security 				ApiKeyAuth
+// @Security 				UserAuth
+// @Param 					id 									path 		string 		true 	"Checkpoint id"
+// @Success 				200 								{object} 	domain.AuditCheckpointExport
+// @Router 					/auditlog/checkpoints/{id}/export 	[get]
+func (h *Handler) ExportAuditCheckpoint(ctx *gin.Context) {
 	id := ctx.Param("id")
 	if id == "" {
-		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required audit log id"))
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required checkpoint id"))
 		return
 	}
-	err := ch.svc.DeleteAuditLog(id)
+	result, err := h.svc.ExportAuditCheckpoint(ctx, id)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	SuccessResponse(ctx, nil)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=audit-checkpoint-%d.json", result.Checkpoint.Sequence))
+	ctx.JSON(http.StatusOK, result)
 }
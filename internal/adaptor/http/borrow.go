@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
 )
 
 // AddBorrow		godoc
@@ -44,9 +45,14 @@ func (h *Handler) CreateBorrow(ctx *gin.Context) {
 // @Description 	List Borrow
 // @Tags 			Borrow
 // @Accept  		json
-// @Produce  		json
+// @Produce  		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
 // @Security 		ApiKeyAuth
 // @Param 			query 						query 		string 		false 	"query"
+// @Param 			start_date 					query 		string 		false 	"Start of the export period (YYYY-MM-DD), a month ago by default"
+// @Param 			end_date 					query 		string 		false 	"End of the export period (YYYY-MM-DD), today by default"
+// @Param 			format 						query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 			columns 					query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 			lang 						query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
 // @Success 		200 		{array} 		domain.BorrowedBookResponse
 // @Router 			/borrows	 	[get]
 func (h *Handler) ListBorrow(ctx *gin.Context) {
@@ -62,6 +68,17 @@ func (h *Handler) ListBorrow(ctx *gin.Context) {
 		return
 	}
 	req.Prepare()
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "borrows", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportBorrow(ctx, &req, exp, w)
+		})
+		return
+	}
 	result, count, err := h.svc.ListBorrow(ctx, &req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
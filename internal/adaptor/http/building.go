@@ -0,0 +1,155 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AddBuilding		godoc
+// @Summary			Add a new Building
+// @Description		Add a new Building
+// @Tags			Building
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			BuildingRequest			body		domain.BuildingRequest		true		"Add Building Request"
+// @Success			200						{object}	domain.BuildingResponse				"Building created"
+// @Router			/buildings 				[post]
+func (h *Handler) CreateBuilding(ctx *gin.Context) {
+	var req *domain.BuildingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateBuilding(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListBuilding 	godoc
+// @Summary 		List Building
+// @Description 	List Building
+// @Tags 			Building
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 						query 		string 		false 	"query"
+// @Param 			campus_id 					query 		string 		false 	"Campus id"
+// @Success 		200 		{array} 		domain.BuildingResponse
+// @Router 			/buildings	 	[get]
+func (h *Handler) ListBuilding(ctx *gin.Context) {
+	var req domain.ListBuildingRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListBuilding(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetBuilding 		godoc
+// @Summary 		Get Building
+// @Description 	Get Building from Id
+// @Tags 			Building
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Building id"
+// @Success 		200 {object} domain.BuildingResponse
+// @Router 			/buildings/{id} [get]
+func (h *Handler) GetBuilding(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetBuilding(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateBuilding		godoc
+// @Summary 			Update Building
+// @Description 		Update Building from Id
+// @Tags 				Building
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 							true 	"Building id"
+// @Param 				UpdateBuildingRequest	 	body 		domain.UpdateBuildingRequest 	true 	"Update Building Request"
+// @Success 			200 						{object} 	domain.BuildingResponse
+// @Router 				/buildings/{id} 			[put]
+func (h *Handler) UpdateBuilding(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateBuildingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateBuilding(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteBuilding 		godoc
+// @Summary 			Delete Building
+// @Description 		Delete Building from Id, refused while floors still belong to it
+// @Tags 				Building
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Building id"
+// @Success 			200 					{object} 	domain.BuildingResponse
+// @Router 				/buildings/{id} 		[delete]
+func (h *Handler) DeleteBuilding(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required building id"))
+		return
+	}
+	result, err := h.svc.DeleteBuilding(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListBuildingFloor 	godoc
+// @Summary 			List Building Floors
+// @Description 		List Floors of a Building
+// @Tags 				Building
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Building id"
+// @Success 			200 		{array} 		domain.FloorResponse
+// @Router 				/buildings/{id}/floors 	[get]
+func (h *Handler) ListBuildingFloor(ctx *gin.Context) {
+	var req domain.ListFloorRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	req.BuildingID = ctx.Param("id")
+	result, count, err := h.svc.ListFloor(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
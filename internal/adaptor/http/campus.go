@@ -0,0 +1,155 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AddCampus		godoc
+// @Summary			Add a new Campus
+// @Description		Add a new Campus
+// @Tags			Campus
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			CampusRequest			body		domain.CampusRequest		true		"Add Campus Request"
+// @Success			200						{object}	domain.CampusResponse				"Campus created"
+// @Router			/campuses 				[post]
+func (h *Handler) CreateCampus(ctx *gin.Context) {
+	var req *domain.CampusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateCampus(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListCampus 		godoc
+// @Summary 		List Campus
+// @Description 	List Campus
+// @Tags 			Campus
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 						query 		string 		false 	"query"
+// @Success 		200 		{array} 		domain.CampusResponse
+// @Router 			/campuses	 	[get]
+func (h *Handler) ListCampus(ctx *gin.Context) {
+	var req domain.ListCampusRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListCampus(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetCampus 		godoc
+// @Summary 		Get Campus
+// @Description 	Get Campus from Id
+// @Tags 			Campus
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Campus id"
+// @Success 		200 {object} domain.CampusResponse
+// @Router 			/campuses/{id} [get]
+func (h *Handler) GetCampus(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetCampus(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateCampus			godoc
+// @Summary 			Update Campus
+// @Description 		Update Campus from Id
+// @Tags 				Campus
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 							true 	"Campus id"
+// @Param 				UpdateCampusRequest	 		body 		domain.UpdateCampusRequest 		true 	"Update Campus Request"
+// @Success 			200 						{object} 	domain.CampusResponse
+// @Router 				/campuses/{id} 				[put]
+func (h *Handler) UpdateCampus(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateCampusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateCampus(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteCampus 		godoc
+// @Summary 			Delete Campus
+// @Description 		Delete Campus from Id, refused while buildings still belong to it
+// @Tags 				Campus
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Campus id"
+// @Success 			200 					{object} 	domain.CampusResponse
+// @Router 				/campuses/{id} 			[delete]
+func (h *Handler) DeleteCampus(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required campus id"))
+		return
+	}
+	result, err := h.svc.DeleteCampus(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListCampusBuilding 	godoc
+// @Summary 			List Campus Buildings
+// @Description 		List Buildings of a Campus
+// @Tags 				Campus
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Campus id"
+// @Param 				query 					query 		string 		false 	"query"
+// @Success 			200 		{array} 		domain.BuildingResponse
+// @Router 				/campuses/{id}/buildings 	[get]
+func (h *Handler) ListCampusBuilding(ctx *gin.Context) {
+	var req domain.ListBuildingRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	req.CampusID = ctx.Param("id")
+	result, count, err := h.svc.ListBuilding(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
@@ -55,7 +55,7 @@ func (ch *Handler) ListCategory(ctx *gin.Context) {
 	req.Prepare()
 	result, count, err := ch.svc.List(ctx, &req)
 	if err != nil {
-		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
@@ -75,7 +75,7 @@ func (ch *Handler) GetCategory(ctx *gin.Context) {
 	id := ctx.Param("id")
 	category, err := ch.svc.Get(ctx, id)
 	if err != nil {
-		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 	SuccessResponse(ctx, category)
@@ -101,12 +101,12 @@ func (ch *Handler) UpdateCategory(ctx *gin.Context) {
 	}
 	_, err := ch.svc.Get(ctx, id)
 	if err != nil {
-		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 	category, err := ch.svc.Update(ctx, id, req)
 	if err != nil {
-		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 	SuccessResponse(ctx, category)
@@ -130,12 +130,12 @@ func (ch *Handler) DeleteCategory(ctx *gin.Context) {
 	}
 	category, err := ch.svc.Get(ctx, id)
 	if err != nil {
-		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 	err = ch.svc.Delete(ctx, id)
 	if err != nil {
-		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 	SuccessResponse(ctx, category)
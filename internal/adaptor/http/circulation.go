@@ -0,0 +1,269 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
+)
+
+// GetTopBooks	godoc
+// @Summary 			Most Borrowed Titles
+// @Description 		Titles ranked by the loans of their copies issued in the period
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				start_date 		query 		string 		false 	"Start date (YYYY-MM-DD), a month ago by default"
+// @Param 				end_date 		query 		string 		false 	"End date (YYYY-MM-DD), today by default"
+// @Param 				limit 			query 		int 		false 	"Rows to return, default 10, at most 100"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {array} domain.TopBook
+// @Router 				/reports/top-books	[get]
+func (h *Handler) GetTopBooks(ctx *gin.Context) {
+	var req domain.CirculationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Prepare(); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "top-books", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportTopBooks(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetTopBooks(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// GetTopCategories	godoc
+// @Summary 			Most Borrowed Categories
+// @Description 		Categories ranked by the loans of their titles issued in the period
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				start_date 		query 		string 		false 	"Start date (YYYY-MM-DD), a month ago by default"
+// @Param 				end_date 		query 		string 		false 	"End date (YYYY-MM-DD), today by default"
+// @Param 				limit 			query 		int 		false 	"Rows to return, default 10, at most 100"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {array} domain.TopCategory
+// @Router 				/reports/top-categories	[get]
+func (h *Handler) GetTopCategories(ctx *gin.Context) {
+	var req domain.CirculationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Prepare(); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "top-categories", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportTopCategories(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetTopCategories(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListInactiveStock	godoc
+// @Summary 			Inactive Stock
+// @Description 		Active titles not borrowed in the last months, weeding candidates, never borrowed titles first
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				months 			query 		int 		false 	"Months without a loan, default 12"
+// @Param 				category_id 	query 		string 		false 	"Category ID"
+// @Param 				page 			query 		int 		false 	"Page"
+// @Param 				size 			query 		int 		false 	"Size"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {array} domain.InactiveBook
+// @Router 				/reports/inactive-stock	[get]
+func (h *Handler) ListInactiveStock(ctx *gin.Context) {
+	var req domain.InactiveStockRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "inactive-stock", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportInactiveStock(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, count, err := h.svc.ListInactiveStock(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetLoanDuration	godoc
+// @Summary 			Loan Duration
+// @Description 		Average, median and longest days the loans issued in the period were kept, with late returns
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				start_date 		query 		string 		false 	"Start date (YYYY-MM-DD), a month ago by default"
+// @Param 				end_date 		query 		string 		false 	"End date (YYYY-MM-DD), today by default"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {object} domain.LoanDuration
+// @Router 				/reports/loan-duration	[get]
+func (h *Handler) GetLoanDuration(ctx *gin.Context) {
+	var req domain.CirculationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Prepare(); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "loan-duration", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportLoanDuration(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetLoanDuration(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// GetBorrowingRates	godoc
+// @Summary 			Borrowing Rates
+// @Description 		Students, borrowers and loans per program and per batch in the period
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				start_date 		query 		string 		false 	"Start date (YYYY-MM-DD), a month ago by default"
+// @Param 				end_date 		query 		string 		false 	"End date (YYYY-MM-DD), today by default"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {object} domain.BorrowingRates
+// @Router 				/reports/borrowing-rates	[get]
+func (h *Handler) GetBorrowingRates(ctx *gin.Context) {
+	var req domain.CirculationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Prepare(); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "borrowing-rates", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportBorrowingRates(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetBorrowingRates(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// GetTopReaders	godoc
+// @Summary 			Top Readers
+// @Description 		Patrons ranked by the loans issued to them in the period
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				start_date 		query 		string 		false 	"Start date (YYYY-MM-DD), a month ago by default"
+// @Param 				end_date 		query 		string 		false 	"End date (YYYY-MM-DD), today by default"
+// @Param 				limit 			query 		int 		false 	"Rows to return, default 10, at most 100"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {array} domain.TopReader
+// @Router 				/reports/top-readers	[get]
+func (h *Handler) GetTopReaders(ctx *gin.Context) {
+	var req domain.CirculationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Prepare(); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "top-readers", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportTopReaders(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetTopReaders(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// ListEmailDelivery 	godoc
+// @Summary 			List EmailDelivery
+// @Description 		List queued, sent, failed and bounced emails
+// @Tags 				EmailDelivery
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				user_id 					query 		string 		false 	"User id"
+// @Param 				template 					query 		string 		false 	"due_reminder | overdue | hold_ready | fine_issued | account_created"
+// @Param 				status 						query 		string 		false 	"queued | sent | failed | bounced"
+// @Success 			200 		{array} 		domain.EmailDeliveryResponse
+// @Router 				/email-deliveries	 	[get]
+func (h *Handler) ListEmailDelivery(ctx *gin.Context) {
+	var req domain.ListEmailDeliveryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListEmailDelivery(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetEmailDelivery 	godoc
+// @Summary 			Get EmailDelivery
+// @Description 		Get EmailDelivery from Id
+// @Tags 				EmailDelivery
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id path string true "EmailDelivery id"
+// @Success 			200 {object} domain.EmailDeliveryResponse
+// @Router 				/email-deliveries/{id} [get]
+func (h *Handler) GetEmailDelivery(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetEmailDelivery(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// RetryEmailDelivery 	godoc
+// @Summary 			Retry EmailDelivery
+// @Description 		Queue a failed or bounced email again
+// @Tags 				EmailDelivery
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 								path 		string 		true 	"EmailDelivery id"
+// @Success 			200 							{object} 	domain.EmailDeliveryResponse
+// @Router 				/email-deliveries/{id}/retry 	[post]
+func (h *Handler) RetryEmailDelivery(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.RetryEmailDelivery(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/adaptor/export"
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
+)
+
+// exportRequest reads ?format=, ?columns= and ?lang=, the language falls back to Accept-Language
+func exportRequest(ctx *gin.Context) (*domain.ExportRequest, error) {
+	var req domain.ExportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+	req.Lang = domain.ExportLang(req.Lang, ctx.GetHeader("Accept-Language"))
+	return &req, nil
+}
+
+// export sends the file that write produces as an attachment named after the report
+func (h *Handler) export(ctx *gin.Context, name string, req *domain.ExportRequest, write func(w port.ExportWriter) error) {
+	out := &attachmentWriter{
+		ctx:         ctx,
+		filename:    fmt.Sprintf("%s-%s.%s", name, time.Now().Format("2006-01-02"), req.Format),
+		contentType: export.ContentType(req.Format),
+	}
+	w, err := h.exporter.NewWriter(req.Format, out)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := write(w); err != nil {
+		if out.started {
+			// the status line is gone, all that is left is to cut the download short
+			logrus.WithError(err).Errorf("%s export failed part way", name)
+			ctx.Abort()
+			return
+		}
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+	}
+}
+
+// attachmentWriter sets the download headers with the first write, so that an export failing
+// before it wrote anything can still be answered with a JSON error
+type attachmentWriter struct {
+	ctx         *gin.Context
+	filename    string
+	contentType string
+	started     bool
+}
+
+func (a *attachmentWriter) Write(p []byte) (int, error) {
+	if !a.started {
+		a.started = true
+		clearWriteDeadline(a.ctx)
+		a.ctx.Header("Content-Disposition", "attachment; filename="+a.filename)
+		a.ctx.Header("Content-Type", a.contentType)
+		a.ctx.Status(http.StatusOK)
+	}
+	return a.ctx.Writer.Write(p)
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AddFaculty		godoc
+// @Summary			Add a new Faculty
+// @Description		Add a new Faculty
+// @Tags			Faculty
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			FacultyRequest			body		domain.FacultyRequest		true		"Add Faculty Request"
+// @Success			200						{object}	domain.FacultyResponse				"Faculty created"
+// @Router			/faculties 				[post]
+func (h *Handler) CreateFaculty(ctx *gin.Context) {
+	var req *domain.FacultyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateFaculty(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListFaculty 	godoc
+// @Summary 		List Faculty
+// @Description 	List Faculty
+// @Tags 			Faculty
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 						query 		string 		false 	"query"
+// @Success 		200 		{array} 		domain.FacultyResponse
+// @Router 			/faculties	 	[get]
+func (h *Handler) ListFaculty(ctx *gin.Context) {
+	var req domain.ListFacultyRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListFaculty(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetFaculty 		godoc
+// @Summary 		Get Faculty
+// @Description 	Get Faculty from Id
+// @Tags 			Faculty
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Faculty id"
+// @Success 		200 {object} domain.FacultyResponse
+// @Router 			/faculties/{id} [get]
+func (h *Handler) GetFaculty(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetFaculty(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateFaculty		godoc
+// @Summary 			Update Faculty
+// @Description 		Update Faculty from Id
+// @Tags 				Faculty
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 							true 	"Faculty id"
+// @Param 				UpdateFacultyRequest	 	body 		domain.UpdateFacultyRequest 	true 	"Update Faculty Request"
+// @Success 			200 						{object} 	domain.FacultyResponse
+// @Router 				/faculties/{id} 			[put]
+func (h *Handler) UpdateFaculty(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateFacultyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateFaculty(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteFaculty 		godoc
+// @Summary 			Delete Faculty
+// @Description 		Delete Faculty from Id, refused while programs still belong to it
+// @Tags 				Faculty
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Faculty id"
+// @Success 			200 					{object} 	domain.FacultyResponse
+// @Router 				/faculties/{id} 		[delete]
+func (h *Handler) DeleteFaculty(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required faculty id"))
+		return
+	}
+	result, err := h.svc.DeleteFaculty(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListFacultyProgram 	godoc
+// @Summary 			List Faculty Programs
+// @Description 		List Programs of a Faculty
+// @Tags 				Faculty
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Faculty id"
+// @Success 			200 		{array} 		domain.ProgramResponse
+// @Router 				/faculties/{id}/programs 	[get]
+func (h *Handler) ListFacultyProgram(ctx *gin.Context) {
+	var req domain.ListProgramRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	req.FacultyID = ctx.Param("id")
+	result, count, err := h.svc.LisProgram(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
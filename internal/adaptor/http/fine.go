@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
 )
 
 // AddFine			godoc
@@ -24,7 +25,7 @@ func (h *Handler) CreateFine(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	result, err := h.svc.CreateFine(req)
+	result, err := h.svc.CreateFine(ctx, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -37,9 +38,14 @@ func (h *Handler) CreateFine(ctx *gin.Context) {
 // @Description 	List Fine
 // @Tags 			Fine
 // @Accept  		json
-// @Produce  		json
+// @Produce  		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
 // @Security 		ApiKeyAuth
 // @Param 			query 						query 		string 		false 	"query"
+// @Param 			start_date 					query 		string 		false 	"Start of the export period (YYYY-MM-DD), a month ago by default"
+// @Param 			end_date 					query 		string 		false 	"End of the export period (YYYY-MM-DD), today by default"
+// @Param 			format 						query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 			columns 					query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 			lang 						query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
 // @Success 		200 		{array} 		domain.FineResponse
 // @Router 			/fines	 	[get]
 func (h *Handler) ListFine(ctx *gin.Context) {
@@ -49,7 +55,18 @@ func (h *Handler) ListFine(ctx *gin.Context) {
 		return
 	}
 	req.Prepare()
-	result, count, err := h.svc.ListFine(&req)
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "fines", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportFine(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, count, err := h.svc.ListFine(ctx, &req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -69,7 +86,7 @@ func (h *Handler) ListFine(ctx *gin.Context) {
 // @Router 			/fines/{id} [get]
 func (h *Handler) GetFine(ctx *gin.Context) {
 	id := ctx.Param("id")
-	result, err := h.svc.GetFine(id)
+	result, err := h.svc.GetFine(ctx, id)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -95,7 +112,7 @@ func (h *Handler) UpdateFine(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	data, err := h.svc.UpdateFine(id, req)
+	data, err := h.svc.UpdateFine(ctx, id, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -120,7 +137,50 @@ func (ch *Handler) DeleteFine(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required Fine id"))
 		return
 	}
-	result, err := ch.svc.DeleteFine(id)
+	result, err := ch.svc.DeleteFine(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// PayFine 				godoc
+// @Summary 			Pay Fine
+// @Description 		Settle a pending fine in full, the signed in staff member is recorded as the collector and a receipt number is issued
+// @Tags 				Fine
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Fine id"
+// @Success 			200 					{object} 	domain.FineResponse
+// @Router 				/fines/{id}/pay 		[post]
+func (h *Handler) PayFine(ctx *gin.Context) {
+	result, err := h.svc.PayFine(ctx, ctx.Param("id"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// WaiveFine 			godoc
+// @Summary 			Waive Fine
+// @Description 		Write a pending fine off with a reason
+// @Tags 				Fine
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Fine id"
+// @Param 				WaiveFineRequest 		body 		domain.WaiveFineRequest 	true 	"Waiver reason"
+// @Success 			200 					{object} 	domain.FineResponse
+// @Router 				/fines/{id}/waive 		[post]
+func (h *Handler) WaiveFine(ctx *gin.Context) {
+	var req domain.WaiveFineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.WaiveFine(ctx, ctx.Param("id"), &req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
+)
+
+// GetFineCollection	godoc
+// @Summary 			Fine Collection
+// @Description 		Fines issued, collected and waived in the period by day, month or collector, amounts in paisa
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				start_date 		query 		string 		false 	"Start date (YYYY-MM-DD), a month ago by default"
+// @Param 				end_date 		query 		string 		false 	"End date (YYYY-MM-DD), today by default"
+// @Param 				group_by 		query 		string 		false 	"day (default), month or collector"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {object} domain.FineCollectionReport
+// @Router 				/reports/fine-collection	[get]
+func (h *Handler) GetFineCollection(ctx *gin.Context) {
+	var req domain.FineCollectionRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Prepare(); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "fine-collection", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportFineCollection(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetFineCollection(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// GetFineAging			godoc
+// @Summary 			Outstanding Fines by Age
+// @Description 		Pending fines in 0-30, 31-60 and 60+ days since they were issued, amounts in paisa
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {object} domain.FineAging
+// @Router 				/reports/fine-aging	[get]
+func (h *Handler) GetFineAging(ctx *gin.Context) {
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "fine-aging", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportFineAging(ctx, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetFineAging(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListStudentFineAging	godoc
+// @Summary 			Outstanding Fines by Student
+// @Description 		Students who owe fines with their balance split by age, the largest balances first, amounts in paisa
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				query 			query 		string 		false 	"Name or username"
+// @Param 				page 			query 		int 		false 	"Page"
+// @Param 				size 			query 		int 		false 	"Size"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {array} domain.StudentFineAging
+// @Router 				/reports/fine-aging/students	[get]
+func (h *Handler) ListStudentFineAging(ctx *gin.Context) {
+	var req domain.ListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "student-fine-aging", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportStudentFineAging(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, count, err := h.svc.ListStudentFineAging(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetFineStatement		godoc
+// @Summary 			Fine Statement
+// @Description 		A student's fines, payments with receipt numbers and waivers in the period with the running balance, amounts in paisa
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				user_id 		query 		string 		true 	"Student id"
+// @Param 				start_date 		query 		string 		false 	"Start date (YYYY-MM-DD), a month ago by default"
+// @Param 				end_date 		query 		string 		false 	"End date (YYYY-MM-DD), today by default"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {object} domain.FineStatement
+// @Router 				/reports/fine-statement	[get]
+func (h *Handler) GetFineStatement(ctx *gin.Context) {
+	var req domain.FineStatementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Prepare(); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "fine-statement", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportFineStatement(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetFineStatement(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
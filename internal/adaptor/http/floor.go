@@ -0,0 +1,156 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AddFloor			godoc
+// @Summary			Add a new Floor
+// @Description		Add a new Floor
+// @Tags			Floor
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			FloorRequest			body		domain.FloorRequest		true		"Add Floor Request"
+// @Success			200						{object}	domain.FloorResponse			"Floor created"
+// @Router			/floors 				[post]
+func (h *Handler) CreateFloor(ctx *gin.Context) {
+	var req *domain.FloorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateFloor(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListFloor 		godoc
+// @Summary 		List Floor
+// @Description 	List Floor
+// @Tags 			Floor
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			building_id 				query 		string 		false 	"Building id"
+// @Success 		200 		{array} 		domain.FloorResponse
+// @Router 			/floors	 	[get]
+func (h *Handler) ListFloor(ctx *gin.Context) {
+	var req domain.ListFloorRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListFloor(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetFloor 		godoc
+// @Summary 		Get Floor
+// @Description 	Get Floor from Id
+// @Tags 			Floor
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Floor id"
+// @Success 		200 {object} domain.FloorResponse
+// @Router 			/floors/{id} [get]
+func (h *Handler) GetFloor(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetFloor(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateFloor			godoc
+// @Summary 			Update Floor
+// @Description 		Update Floor from Id
+// @Tags 				Floor
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 						true 	"Floor id"
+// @Param 				UpdateFloorRequest	 		body 		domain.UpdateFloorRequest 	true 	"Update Floor Request"
+// @Success 			200 						{object} 	domain.FloorResponse
+// @Router 				/floors/{id} 				[put]
+func (h *Handler) UpdateFloor(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateFloorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateFloor(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteFloor 			godoc
+// @Summary 			Delete Floor
+// @Description 		Delete Floor from Id, refused while rooms still belong to it
+// @Tags 				Floor
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Floor id"
+// @Success 			200 					{object} 	domain.FloorResponse
+// @Router 				/floors/{id} 			[delete]
+func (h *Handler) DeleteFloor(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required floor id"))
+		return
+	}
+	result, err := h.svc.DeleteFloor(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListFloorRoom 		godoc
+// @Summary 			List Floor Rooms
+// @Description 		List Rooms of a Floor
+// @Tags 				Floor
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Floor id"
+// @Param 				room_type 				query 		string 		false 	"CLASSROOM, LAB, SEMINAR, AUDITORIUM"
+// @Param 				min_capacity 			query 		int 		false 	"Minimum capacity"
+// @Success 			200 		{array} 		domain.RoomResponse
+// @Router 				/floors/{id}/rooms 		[get]
+func (h *Handler) ListFloorRoom(ctx *gin.Context) {
+	var req domain.ListRoomRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	req.FloorID = ctx.Param("id")
+	result, count, err := h.svc.ListRoom(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// readinessTimeout bounds each dependency check of the readiness probe
+const readinessTimeout = 2 * time.Second
+
+// Healthz			godoc
+// @Summary			Liveness probe
+// @Description		Answers as long as the process can serve requests, dependencies are not checked
+// @Tags			Health
+// @Produce			json
+// @Success			200					{object}	responseData
+// @Router			/healthz 			[get]
+func (h *Handler) Healthz(ctx *gin.Context) {
+	SuccessResponse(ctx, "ok")
+}
+
+// Readyz			godoc
+// @Summary			Readiness probe
+// @Description		Checks that the database and the file storage can be reached, storage that is not configured is reported but does not fail the probe
+// @Tags			Health
+// @Produce			json
+// @Success			200					{object}	responseData
+// @Failure			503					{object}	responseData
+// @Router			/readyz 			[get]
+func (h *Handler) Readyz(ctx *gin.Context) {
+	checks := map[string]string{}
+	ready := true
+	check := func(name string, ping func(context.Context) error) {
+		c, cancel := context.WithTimeout(ctx.Request.Context(), readinessTimeout)
+		defer cancel()
+		if err := ping(c); err != nil {
+			// the probe is public, the error names hosts and drivers so it is only logged
+			logrus.WithField("check", name).WithError(err).Warn("readiness check failed")
+			checks[name] = "unavailable"
+			ready = false
+			return
+		}
+		checks[name] = "ok"
+	}
+	check("database", h.svc.Ping)
+	if h.uploader != nil {
+		check("storage", h.uploader.Ping)
+	} else {
+		checks["storage"] = "not configured"
+	}
+	if !ready {
+		ctx.JSON(http.StatusServiceUnavailable, responseData{
+			Error:   http.StatusServiceUnavailable,
+			Code:    "not_ready",
+			Message: "not ready",
+			Data:    checks,
+		})
+		return
+	}
+	SuccessResponse(ctx, checks)
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// ListBookHistory 	godoc
+// @Summary 		Book history
+// @Description 	Every change made to the book in order with who made it and the fields that changed, newest first with sort_direction=desc
+// @Tags 			History
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id 						path 		string 		true 	"Book id"
+// @Param 			action 					query 		string 		false 	"action"
+// @Param 			sort_direction 			query 		string 		false 	"asc | desc"
+// @Success 		200 					{array} 	domain.HistoryEntry
+// @Router 			/books/{id}/history 	[get]
+func (h *Handler) ListBookHistory(ctx *gin.Context) {
+	h.listEntityHistory(ctx, domain.HistoryBook)
+}
+
+// ListBookCopyHistory 	godoc
+// @Summary 		Book copy history
+// @Description 	Every change made to the book copy in order with who made it and the fields that changed, newest first with sort_direction=desc
+// @Tags 			History
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id 						path 		string 		true 	"Book copy id"
+// @Param 			action 					query 		string 		false 	"action"
+// @Param 			sort_direction 			query 		string 		false 	"asc | desc"
+// @Success 		200 					{array} 	domain.HistoryEntry
+// @Router 			/book-copies/{id}/history 	[get]
+func (h *Handler) ListBookCopyHistory(ctx *gin.Context) {
+	h.listEntityHistory(ctx, domain.HistoryBookCopy)
+}
+
+// ListBorrowHistory 	godoc
+// @Summary 		Borrow history
+// @Description 	Every change made to the borrow in order with who made it and the fields that changed, newest first with sort_direction=desc
+// @Tags 			History
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id 						path 		string 		true 	"Borrow id"
+// @Param 			action 					query 		string 		false 	"action"
+// @Param 			sort_direction 			query 		string 		false 	"asc | desc"
+// @Success 		200 					{array} 	domain.HistoryEntry
+// @Router 			/borrows/{id}/history 	[get]
+func (h *Handler) ListBorrowHistory(ctx *gin.Context) {
+	h.listEntityHistory(ctx, domain.HistoryBorrow)
+}
+
+// ListUserHistory 	godoc
+// @Summary 		User history
+// @Description 	Every change made to the user in order with who made it and the fields that changed, newest first with sort_direction=desc
+// @Tags 			History
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id 						path 		string 		true 	"User id"
+// @Param 			action 					query 		string 		false 	"action"
+// @Param 			sort_direction 			query 		string 		false 	"asc | desc"
+// @Success 		200 					{array} 	domain.HistoryEntry
+// @Router 			/users/{id}/history 	[get]
+func (h *Handler) ListUserHistory(ctx *gin.Context) {
+	h.listEntityHistory(ctx, domain.HistoryUser)
+}
+
+// ListFineHistory 	godoc
+// @Summary 		Fine history
+// @Description 	Every change made to the fine in order with who made it and the fields that changed, newest first with sort_direction=desc
+// @Tags 			History
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id 						path 		string 		true 	"Fine id"
+// @Param 			action 					query 		string 		false 	"action"
+// @Param 			sort_direction 			query 		string 		false 	"asc | desc"
+// @Success 		200 					{array} 	domain.HistoryEntry
+// @Router 			/fines/{id}/history 	[get]
+func (h *Handler) ListFineHistory(ctx *gin.Context) {
+	h.listEntityHistory(ctx, domain.HistoryFine)
+}
+
+func (h *Handler) listEntityHistory(ctx *gin.Context, entityType string) {
+	var req domain.ListHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, count, err := h.svc.ListEntityHistory(ctx, entityType, ctx.Param("id"), &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// ListPatronHistory 	godoc
+// @Summary 			Patron timeline
+// @Description 		Loan requests, loans, returns, fines and payments of one user in order, deleted loans and fines included. Patrons can read their own.
+// @Tags 				History
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 		true 	"User id"
+// @Param 				action 						query 		string 		false 	"action"
+// @Param 				sort_direction 				query 		string 		false 	"asc | desc"
+// @Success 			200 						{array} 	domain.HistoryEntry
+// @Router 				/users/{id}/timeline 		[get]
+func (h *Handler) ListPatronHistory(ctx *gin.Context) {
+	var req domain.ListHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, count, err := h.svc.ListPatronHistory(ctx, ctx.Param("id"), &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
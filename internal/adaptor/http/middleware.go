@@ -1,13 +1,21 @@
 package http
 
 import (
-	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/sugaml/lms-api/internal/core/auth"
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -15,36 +23,36 @@ const (
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authorization_payload"
 	authorizationUserrIDKey = "authorization_user_id"
-)
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
-}
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+	clientIPKey     = "client_ip"
+	userAgentKey    = "user_agent"
+	errorCodeKey    = "error_code"
+	maxRequestIDLen = 128
+)
 
 func authMiddleware(tokenMaker auth.Maker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
-			err := errors.New("authorization is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortUnauthorized(ctx, "missing_authorization", "authorization is not provided")
 			return
 		}
 		fields := strings.Fields(authorizationHeader)
 		if len(fields) < 2 {
-			err := errors.New("invalid authorization header format")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortUnauthorized(ctx, "invalid_authorization", "invalid authorization header format")
 			return
 		}
 		authorizationType := strings.ToLower(fields[0])
 		if authorizationType != authorizationTypeBearer {
-			err := fmt.Errorf("unsupported authorization type %s ", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortUnauthorized(ctx, "invalid_authorization", fmt.Sprintf("unsupported authorization type %s", authorizationType))
 			return
 		}
 		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortUnauthorized(ctx, "invalid_token", err.Error())
 			return
 		}
 		ctx.Set(authorizationHeaderKey, authorizationHeader)
@@ -54,11 +62,129 @@ func authMiddleware(tokenMaker auth.Maker) gin.HandlerFunc {
 	}
 }
 
+func abortUnauthorized(ctx *gin.Context, code, message string) {
+	ErrorResponse(ctx, http.StatusUnauthorized, domain.NewUnauthorizedError(code, message))
+	ctx.Abort()
+}
+
+// queryTokenMiddleware lets clients that cannot set headers, like the browser's EventSource,
+// pass the bearer token as the access_token query parameter
+func queryTokenMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := ctx.Query("access_token")
+		if ctx.GetHeader(authorizationHeaderKey) == "" && token != "" {
+			ctx.Request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+token)
+		}
+		ctx.Next()
+	}
+}
+
+// calendarAuthMiddleware lets calendar apps, which subscribe to a url and cannot set headers,
+// read the timetable feeds with the caller's calendar token in the token query parameter. A
+// session token is only taken from the Authorization header.
+func calendarAuthMiddleware(tokenMaker auth.Maker, svc port.Service) gin.HandlerFunc {
+	headerAuth := authMiddleware(tokenMaker)
+	return func(ctx *gin.Context) {
+		token := ctx.Query("token")
+		if token == "" {
+			headerAuth(ctx)
+			return
+		}
+		userID, err := svc.VerifyCalendarToken(ctx, token)
+		if err != nil {
+			ErrorResponse(ctx, http.StatusUnauthorized, err)
+			ctx.Abort()
+			return
+		}
+		ctx.Set(authorizationUserrIDKey, userID)
+		ctx.Next()
+	}
+}
+
+// requestContextMiddleware keeps the request id, client ip and user agent on the context for the
+// audit log, the caller's X-Request-ID is reused so a request can be traced across services
+func requestContextMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLen {
+			requestID = uuid.NewString()
+		}
+		ctx.Set(requestIDKey, requestID)
+		trace.SpanFromContext(ctx.Request.Context()).SetAttributes(attribute.String(requestIDKey, requestID))
+		ctx.Set(clientIPKey, ctx.ClientIP())
+		ctx.Set(userAgentKey, ctx.Request.UserAgent())
+		ctx.Header(requestIDHeader, requestID)
+		ctx.Next()
+	}
+}
+
+// untracedPaths are polled by the probes and Prometheus, their spans would drown out the requests
+// worth looking at
+var untracedPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+func tracedRequest(r *http.Request) bool {
+	return !untracedPaths[r.URL.Path]
+}
+
+// accessLogMiddleware logs every request once it is answered, with the request id and the caller
+// so that a complaint can be matched to the log and the trace
+func accessLogMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		status := ctx.Writer.Status()
+		fields := logrus.Fields{
+			requestIDKey: ctx.GetString(requestIDKey),
+			"method":     ctx.Request.Method,
+			"path":       ctx.Request.URL.Path,
+			"route":      ctx.FullPath(),
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      ctx.Writer.Size(),
+			clientIPKey:  ctx.ClientIP(),
+			userAgentKey: ctx.Request.UserAgent(),
+		}
+		if userID := ctx.GetString(authorizationUserrIDKey); userID != "" {
+			fields["user_id"] = userID
+		}
+		if code := ctx.GetString(errorCodeKey); code != "" {
+			fields[errorCodeKey] = code
+		}
+		if span := trace.SpanContextFromContext(ctx.Request.Context()); span.HasTraceID() {
+			fields["trace_id"] = span.TraceID().String()
+		}
+		entry := logrus.WithFields(fields)
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("request")
+		case status >= http.StatusBadRequest:
+			entry.Warn("request")
+		default:
+			entry.Info("request")
+		}
+	}
+}
+
+// recoveryMiddleware answers a panic like any internal error instead of with an empty 500
+func recoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, recovered any) {
+		logrus.WithField(requestIDKey, ctx.GetString(requestIDKey)).
+			Errorf("panic: %v\n%s", recovered, debug.Stack())
+		ErrorResponse(ctx, http.StatusInternalServerError, fmt.Errorf("panic: %v", recovered))
+		ctx.Abort()
+	})
+}
+
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, x-agent-code")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, x-agent-code")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
 		c.Header("Access-Control-Allow-Methods", "POST, HEAD, PATCH, OPTIONS, GET, PUT, DELETE")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -1,13 +1,20 @@
 package http
 
 import (
+	"context"
 	"errors"
+	"io"
 	"net/http"
+	"time"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
+// streamKeepalive keeps idle streams from being closed by proxies
+const streamKeepalive = 25 * time.Second
+
 // AddNotification	godoc
 // @Summary			Add a new Notifications
 // @Description		Add a new Notifications
@@ -24,7 +31,7 @@ func (h *Handler) CreateNotification(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	result, err := h.svc.CreateNotification(req)
+	result, err := h.svc.CreateNotification(ctx, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -40,6 +47,9 @@ func (h *Handler) CreateNotification(ctx *gin.Context) {
 // @Produce  		json
 // @Security 		ApiKeyAuth
 // @Param 			query 								query 		string 		false 	"query"
+// @Param 			type 								query 		string 		false 	"type"
+// @Param 			module 								query 		string 		false 	"module"
+// @Param 			is_read 							query 		bool 		false 	"is_read"
 // @Success 		200 				{array} 		domain.NotificationResponse
 // @Router 			/notifications	 	[get]
 func (h *Handler) ListNotification(ctx *gin.Context) {
@@ -48,13 +58,16 @@ func (h *Handler) ListNotification(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
+	if req.SortDirection == "" {
+		req.SortDirection = "desc"
+	}
 	req.Prepare()
-	result, count, err := h.svc.ListNotification(&req)
+	result, count, unread, err := h.svc.ListNotification(ctx, &req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size), WithUnread(unread))
 }
 
 // GetNotification 	godoc
@@ -69,7 +82,7 @@ func (h *Handler) ListNotification(ctx *gin.Context) {
 // @Router 			/notifications/{id} [get]
 func (h *Handler) GetNotification(ctx *gin.Context) {
 	id := ctx.Param("id")
-	result, err := h.svc.GetNotification(id)
+	result, err := h.svc.GetNotification(ctx, id)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -87,7 +100,7 @@ func (h *Handler) GetNotification(ctx *gin.Context) {
 // @Param 				id 							path 		string 								true 	"Notifications id"
 // @Param 				NotificationsUpdateRequest	 body 		domain.UpdateNotificationRequest 	true 	"Update Notifications Response request"
 // @Success 			200 						{object} 	domain.NotificationResponse
-// @Router 				/Notificationss/{id} 		[put]
+// @Router 				/notifications/{id} 		[put]
 func (h *Handler) UpdateNotification(ctx *gin.Context) {
 	id := ctx.Param("id")
 	var req *domain.UpdateNotificationRequest
@@ -95,7 +108,7 @@ func (h *Handler) UpdateNotification(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	data, err := h.svc.UpdateNotification(id, req)
+	data, err := h.svc.UpdateNotification(ctx, id, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -103,19 +116,69 @@ func (h *Handler) UpdateNotification(ctx *gin.Context) {
 	SuccessResponse(ctx, data)
 }
 
-// UpdateNotification	godoc
-// @Summary 			Update Notification
-// @Description 		Update Notification from Id
+// CountUnreadNotification 	godoc
+// @Summary 				Count my unread notifications
+// @Description 			Count the caller's unread notifications
+// @Tags 					Notifications
+// @Produce  				json
+// @Security 				ApiKeyAuth
+// @Success 				200 							{object} 	domain.UnreadNotificationCount
+// @Router 					/notifications/unread-count 	[get]
+func (h *Handler) CountUnreadNotification(ctx *gin.Context) {
+	data, err := h.svc.CountUnreadNotification(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, data)
+}
+
+// ReadNotification 	godoc
+// @Summary 			Mark notification read
+// @Description 		Mark one of the caller's notifications as read by them
 // @Tags 				Notifications
-// @Accept  			json
 // @Produce  			json
 // @Security 			ApiKeyAuth
-// @Param 				id 							path 		string 								true 	"Notifications id"
-// @Param 				NotificationsUpdateRequest	 body 		domain.UpdateNotificationRequest 	true 	"Update Notifications Response request"
-// @Success 			200 						{object} 	domain.NotificationResponse
-// @Router 				/Notificationss/read-all 		[put]
+// @Param 				id 							path 		string 		true 	"Notifications id"
+// @Success 			200 						{object} 	domain.ReadNotificationResponse
+// @Router 				/notifications/{id}/read 	[post]
+func (h *Handler) ReadNotification(ctx *gin.Context) {
+	data, err := h.svc.ReadNotification(ctx, ctx.Param("id"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, data)
+}
+
+// UnreadNotification 	godoc
+// @Summary 			Mark notification unread
+// @Description 		Mark one of the caller's notifications as not read by them
+// @Tags 				Notifications
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 		true 	"Notifications id"
+// @Success 			200 						{object} 	domain.ReadNotificationResponse
+// @Router 				/notifications/{id}/read 	[delete]
+func (h *Handler) UnreadNotification(ctx *gin.Context) {
+	data, err := h.svc.UnreadNotification(ctx, ctx.Param("id"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, data)
+}
+
+// ReadAllNotification 	godoc
+// @Summary 			Mark all notifications read
+// @Description 		Mark every notification addressed to the caller as read by them, other users are not affected
+// @Tags 				Notifications
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Success 			200 						{object} 	domain.ReadNotificationResponse
+// @Router 				/notifications/read-all 	[post]
 func (h *Handler) ReadAllNotification(ctx *gin.Context) {
-	data, err := h.svc.ReadAllNotification()
+	data, err := h.svc.ReadAllNotification(ctx)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -140,10 +203,60 @@ func (ch *Handler) DeleteNotification(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required notification id"))
 		return
 	}
-	result, err := ch.svc.DeleteNotification(id)
+	result, err := ch.svc.DeleteNotification(ctx, id)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
 	SuccessResponse(ctx, result)
 }
+
+// StreamNotification 	godoc
+// @Summary 			Stream my notifications
+// @Description 		Server-Sent Events of the caller's new notifications ("notification" events with an id) and unread count ("unread" events). Reconnect with the Last-Event-ID header, or last_event_id query, to receive what was missed. Browsers' EventSource cannot set headers so the token may be passed as access_token.
+// @Tags 				Notifications
+// @Produce  			text/event-stream
+// @Security 			ApiKeyAuth
+// @Param 				access_token 			query 		string 		false 	"Bearer token when the Authorization header cannot be set"
+// @Param 				last_event_id 			query 		string 		false 	"Resume after this event id"
+// @Success 			200 					{object} 	domain.NotificationResponse
+// @Router 				/notifications/stream 	[get]
+func (h *Handler) StreamNotification(ctx *gin.Context) {
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+	// gin reuses ctx once the handler returns, the stream goroutine gets the request context instead
+	streamCtx := context.WithValue(ctx.Request.Context(), authorizationUserrIDKey, ctx.GetString(authorizationUserrIDKey))
+	events, err := h.svc.StreamNotification(streamCtx, lastEventID)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no") // nginx ingress must not buffer the stream
+	clearWriteDeadline(ctx)
+
+	closing := serverClosing(ctx.Request.Context())
+
+	keepalive := time.NewTicker(streamKeepalive)
+	defer keepalive.Stop()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.Render(-1, sse.Event{Id: event.ID, Event: event.Event, Data: event.Data})
+			return true
+		case <-keepalive.C:
+			_, err := io.WriteString(w, ": keepalive\n\n")
+			return err == nil
+		case <-closing:
+			// the client reconnects to another instance with Last-Event-ID
+			return false
+		}
+	})
+}
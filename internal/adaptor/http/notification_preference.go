@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// ListNotificationPreference 	godoc
+// @Summary 					List my notification preferences
+// @Description 				Every notification event on every channel (in_app, email, sms, webhook) for the caller, with the role default where nothing has been saved
+// @Tags 						NotificationPreference
+// @Accept  					json
+// @Produce  					json
+// @Security 					ApiKeyAuth
+// @Success 					200 		{array} 		domain.NotificationPreferenceResponse
+// @Router 						/notification-preferences	 	[get]
+func (h *Handler) ListNotificationPreference(ctx *gin.Context) {
+	result, err := h.svc.ListNotificationPreference(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateNotificationPreference 	godoc
+// @Summary 						Update my notification preferences
+// @Description 					Turn events on or off per channel for the caller, the webhook channel needs a target url
+// @Tags 							NotificationPreference
+// @Accept  						json
+// @Produce  						json
+// @Security 						ApiKeyAuth
+// @Param 							UpdateNotificationPreferenceRequest 	body 		domain.UpdateNotificationPreferenceRequest 	true 	"Preferences"
+// @Success 						200 									{array} 	domain.NotificationPreferenceResponse
+// @Router 							/notification-preferences 				[put]
+func (h *Handler) UpdateNotificationPreference(ctx *gin.Context) {
+	var req domain.UpdateNotificationPreferenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateNotificationPreference(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -44,6 +44,7 @@ func (ch *Handler) CreateProgram(ctx *gin.Context) {
 // @Param search query string false "Search"
 // @Param sort-column query string false "Sort-Column"
 // @Param sort-Direction query string false "Sort-Direction"
+// @Param faculty_id query string false "Faculty id"
 // @Success 200 {array} domain.ProgramResponse
 // @Router /programs [get]
 func (ch *Handler) ListProgram(ctx *gin.Context) {
@@ -140,3 +141,59 @@ func (ch *Handler) DeleteProgram(ctx *gin.Context) {
 	}
 	SuccessResponse(ctx, Program)
 }
+
+// ListProgramSemester 	godoc
+// @Summary 			List Program Semesters
+// @Description 		List Semesters of a Program in order
+// @Tags 				Program
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Program id"
+// @Success 			200 		{array} 		domain.SemesterResponse
+// @Router 				/programs/{id}/semesters 	[get]
+func (ch *Handler) ListProgramSemester(ctx *gin.Context) {
+	var req domain.ListSemesterRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.SortColumn == "" {
+		req.SortColumn = "semester_no"
+	}
+	req.Prepare()
+	req.ProgramID = ctx.Param("id")
+	result, count, err := ch.svc.ListSemester(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// ListProgramSubject 	godoc
+// @Summary 			List Program Subjects
+// @Description 		List Subjects of a Program
+// @Tags 				Program
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Program id"
+// @Param 				semester_id 			query 		string 		false 	"Semester id"
+// @Success 			200 		{array} 		domain.SubjectResponse
+// @Router 				/programs/{id}/subjects 	[get]
+func (ch *Handler) ListProgramSubject(ctx *gin.Context) {
+	var req domain.ListSubjectRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	req.ProgramID = ctx.Param("id")
+	result, count, err := ch.svc.ListSubject(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
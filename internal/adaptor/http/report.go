@@ -5,36 +5,54 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
 )
 
 // ListLibraryDashboardStats	godoc
 // @Summary 		List LibraryDashboard
-// @Description 	List LibraryDashboard
+// @Description 	List LibraryDashboard, served from the statistics cache, meta freshness tells how old it is
 // @Tags 			Report
 // @Accept  		json
-// @Produce  		json
+// @Produce  		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
 // @Security 		ApiKeyAuth
+// @Param 			format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 			columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 			lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
 // @Success 		200 {object} domain.LibraryDashboardStats
 // @Router 			/reports/dashboard-stats	[get]
 func (h *Handler) GetLibraryDashboardStats(ctx *gin.Context) {
-	result, err := h.svc.GetLibraryDashboardStats()
+	exp, err := exportRequest(ctx)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	SuccessResponse(ctx, result)
+	if exp.IsExport() {
+		h.export(ctx, "dashboard-stats", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportLibraryDashboardStats(ctx, exp, w)
+		})
+		return
+	}
+	result, freshness, err := h.svc.GetLibraryDashboardStats(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithFreshness(freshness))
 }
 
 // GetMonthlyChartData	godoc
 // @Summary 		Chart Data
-// @Description 	Get chart data for various time ranges (daily, weekly, monthly, quarterly, yearly)
+// @Description 	Get chart data for various time ranges (daily, weekly, monthly, quarterly, yearly), served from the statistics cache, meta freshness tells how old it is
 // @Tags 			Report
 // @Accept  		json
-// @Produce  		json
+// @Produce  		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
 // @Security 		ApiKeyAuth
 // @Param 			range 			query 		string 		false 	"Time range: daily, weekly, monthly, quarterly, yearly"
 // @Param 			start_date 		query 		string 		false 	"Start date (YYYY-MM-DD)"
 // @Param 			end_date 		query 		string 		false 	"End date (YYYY-MM-DD)"
+// @Param 			format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 			columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 			lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
 // @Success 		200 {array} 			domain.ChartData
 // @Router 			/reports/chart-stats	[get]
 func (h *Handler) GetMonthlyChartData(ctx *gin.Context) {
@@ -47,24 +65,49 @@ func (h *Handler) GetMonthlyChartData(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	result, err := h.svc.GetDailyChartData(&req)
+	exp, err := exportRequest(ctx)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	SuccessResponse(ctx, result)
+	if exp.IsExport() {
+		h.export(ctx, "chart-stats", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportChartData(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, freshness, err := h.svc.GetDailyChartData(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithFreshness(freshness))
 }
 
 // ListBorrowStats	godoc
 // @Summary 		List Borrow
 // @Tags 			Report
 // @Accept  		json
-// @Produce  		json
+// @Produce  		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
 // @Security 		ApiKeyAuth
+// @Param 			format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 			columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 			lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
 // @Success 		200 {object} domain.BorrowedBookStats
 // @Router 			/reports/borrowedbookstats	[get]
 func (h *Handler) GetBorrowedBookStats(ctx *gin.Context) {
-	result, err := h.svc.GetBorrowedBookStats()
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "borrowedbookstats", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportBorrowedBookStats(ctx, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetBorrowedBookStats(ctx)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -74,14 +117,29 @@ func (h *Handler) GetBorrowedBookStats(ctx *gin.Context) {
 
 // ListBookProgramstats	godoc
 // @Summary 			List Borrow
+// @Description 		Inventory counts, served from the statistics cache, meta freshness tells how old they are
 // @Tags 				Report
 // @Accept  			json
-// @Produce  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
 // @Security 			ApiKeyAuth
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
 // @Success 			200 {array} domain.BookProgramstats
 // @Router 				/reports/program-stats	[get]
 func (h *Handler) GetBookProgramstats(ctx *gin.Context) {
-	result, err := h.svc.GetBookProgramstats()
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "program-stats", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportBookProgramstats(ctx, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetBookProgramstats(ctx)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -93,12 +151,103 @@ func (h *Handler) GetBookProgramstats(ctx *gin.Context) {
 // @Summary 			List Borrow
 // @Tags 				Report
 // @Accept  			json
-// @Produce  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
 // @Security 			ApiKeyAuth
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
 // @Success 			200 {array} domain.BookProgramstats
 // @Router 				/reports/inventory-stats	[get]
 func (h *Handler) GetInventorystats(ctx *gin.Context) {
-	result, err := h.svc.GetInventorystats()
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "inventory-stats", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportInventorystats(ctx, exp, w)
+		})
+		return
+	}
+	result, freshness, err := h.svc.GetInventorystats(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithFreshness(freshness))
+}
+
+// GetTeacherWorkload	godoc
+// @Summary 			Teacher Workload
+// @Description 		Weekly classes, contact hours and credit hours per teacher from class routines
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				academic_year 	query 		string 		false 	"Academic year"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {array} domain.TeacherWorkload
+// @Router 				/reports/teacher-workload	[get]
+func (h *Handler) GetTeacherWorkload(ctx *gin.Context) {
+	var req domain.AcademicReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "teacher-workload", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportTeacherWorkload(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetTeacherWorkload(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// GetRoomUtilization	godoc
+// @Summary 			Room Utilization
+// @Description 		Occupied vs available weekly slots per room and building, plus under-used labs
+// @Tags 				Report
+// @Accept  			json
+// @Produce  			json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security 			ApiKeyAuth
+// @Param 				academic_year 	query 		string 		false 	"Academic year"
+// @Param 				lab_threshold 	query 		number 		false 	"Utilization percent below which a lab is under-used, default 30"
+// @Param 				format 			query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 				columns 		query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 				lang 			query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
+// @Success 			200 {object} domain.RoomUtilizationReport
+// @Router 				/reports/room-utilization	[get]
+func (h *Handler) GetRoomUtilization(ctx *gin.Context) {
+	var req domain.AcademicReportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "room-utilization", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportRoomUtilization(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, err := h.svc.GetRoomUtilization(ctx, &req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -2,22 +2,29 @@ package http
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
-// response represents a response body format
+// response represents a response body format, failed requests carry a machine-readable code and
+// the request id to quote when reporting the problem
 type responseData struct {
-	Error   int    `json:"error" example:"0"`
-	Message string `json:"message" example:"Message"`
-	Data    any    `json:"data,omitempty"`
+	Error     int    `json:"error" example:"0"`
+	Code      string `json:"code,omitempty" example:"book_not_found"`
+	Message   string `json:"message" example:"Message"`
+	RequestID string `json:"request_id,omitempty"`
+	Data      any    `json:"data,omitempty"`
 }
 
 type metaData struct {
-	Count int64 `json:"count,omitempty"`
-	Page  int   `json:"page,omitempty"`
-	Size  int   `json:"size,omitempty"`
+	Count     int64             `json:"count,omitempty"`
+	Page      int               `json:"page,omitempty"`
+	Size      int               `json:"size,omitempty"`
+	Unread    *int64            `json:"unread,omitempty"`
+	Freshness *domain.Freshness `json:"freshness,omitempty"`
 }
 
 type response struct {
@@ -31,6 +38,9 @@ type metaOptions struct {
 	Count   int64  `json:"count"`
 	Page    int    `json:"page"`
 	Size    int    `json:"size"`
+	Unread  *int64 `json:"unread"`
+	// Freshness is how old cached statistics are
+	Freshness *domain.Freshness `json:"freshness"`
 }
 
 type SuccessOption func(req *metaOptions)
@@ -43,6 +53,20 @@ func WithPagination(count int64, page, size int) SuccessOption {
 	}
 }
 
+// WithUnread adds how many notifications the caller has not read yet
+func WithUnread(unread int64) SuccessOption {
+	return func(req *metaOptions) {
+		req.Unread = &unread
+	}
+}
+
+// WithFreshness adds when cached statistics were computed
+func WithFreshness(freshness *domain.Freshness) SuccessOption {
+	return func(req *metaOptions) {
+		req.Freshness = freshness
+	}
+}
+
 func WithMessage(message string) SuccessOption {
 	return func(req *metaOptions) {
 		req.Message = message
@@ -66,17 +90,56 @@ func SuccessResponse(ctx *gin.Context, data any, opts ...SuccessOption) {
 			Data:    data,
 		},
 		metaData: metaData{
-			Count: res.Count,
-			Page:  res.Page,
-			Size:  res.Size,
+			Count:     res.Count,
+			Page:      res.Page,
+			Size:      res.Size,
+			Unread:    res.Unread,
+			Freshness: res.Freshness,
 		},
 	})
 }
 
+// errorStatus is the status code of each kind of domain error
+var errorStatus = map[domain.ErrorKind]int{
+	domain.ErrorValidation:      http.StatusBadRequest,
+	domain.ErrorUnauthorized:    http.StatusUnauthorized,
+	domain.ErrorForbidden:       http.StatusForbidden,
+	domain.ErrorNotFound:        http.StatusNotFound,
+	domain.ErrorConflict:        http.StatusConflict,
+	domain.ErrorPolicyViolation: http.StatusUnprocessableEntity,
+	domain.ErrorInternal:        http.StatusInternalServerError,
+}
+
+// ErrorResponse answers a domain error with the status of its kind and its code. Any other error
+// keeps the status the handler gave, a server error among them is treated as internal. Internal
+// errors are logged and the client only learns that the request failed.
 func ErrorResponse(ctx *gin.Context, code int, err error) {
-	logrus.Errorf("Error response logged : %v", err)
+	apiErr, ok := domain.AsError(err)
+	switch {
+	case ok:
+		code = errorStatus[apiErr.Kind]
+	case code >= http.StatusInternalServerError:
+		apiErr, _ = domain.AsError(domain.NewInternalError(err))
+	default:
+		apiErr = &domain.Error{Code: defaultErrorCode(code), Message: err.Error()}
+	}
+	if code >= http.StatusInternalServerError {
+		logrus.WithFields(logrus.Fields{
+			requestIDKey: ctx.GetString(requestIDKey),
+			"route":      ctx.FullPath(),
+		}).WithError(err).Error("request failed")
+	}
+	ctx.Set(errorCodeKey, apiErr.Code)
 	ctx.JSON(code, responseData{
-		Error:   code,
-		Message: err.Error(),
+		Error:     code,
+		Code:      apiErr.Code,
+		Message:   apiErr.Message,
+		RequestID: ctx.GetString(requestIDKey),
 	})
 }
+
+// defaultErrorCode is the code of an error that is not a domain error, the status text in snake
+// case such as bad_request
+func defaultErrorCode(code int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(code)), " ", "_")
+}
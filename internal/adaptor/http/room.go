@@ -0,0 +1,135 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AddRoom			godoc
+// @Summary			Add a new Room
+// @Description		Add a new Room
+// @Tags			Room
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			RoomRequest				body		domain.RoomRequest		true		"Add Room Request"
+// @Success			200						{object}	domain.RoomResponse				"Room created"
+// @Router			/rooms 					[post]
+func (h *Handler) CreateRoom(ctx *gin.Context) {
+	var req *domain.RoomRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateRoom(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListRoom 		godoc
+// @Summary 		List Room
+// @Description 	List Room filtered by type, capacity and equipment
+// @Tags 			Room
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 						query 		string 		false 	"query"
+// @Param 			floor_id 					query 		string 		false 	"Floor id"
+// @Param 			building_id 				query 		string 		false 	"Building id"
+// @Param 			room_type 					query 		string 		false 	"CLASSROOM, LAB, SEMINAR, AUDITORIUM"
+// @Param 			min_capacity 				query 		int 		false 	"Minimum capacity"
+// @Param 			max_capacity 				query 		int 		false 	"Maximum capacity"
+// @Param 			has_projector 				query 		bool 		false 	"Has projector"
+// @Param 			has_ac 						query 		bool 		false 	"Has AC"
+// @Success 		200 		{array} 		domain.RoomResponse
+// @Router 			/rooms	 	[get]
+func (h *Handler) ListRoom(ctx *gin.Context) {
+	var req domain.ListRoomRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListRoom(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetRoom 			godoc
+// @Summary 		Get Room
+// @Description 	Get Room from Id
+// @Tags 			Room
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Room id"
+// @Success 		200 {object} domain.RoomResponse
+// @Router 			/rooms/{id} [get]
+func (h *Handler) GetRoom(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetRoom(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateRoom			godoc
+// @Summary 			Update Room
+// @Description 		Update Room from Id
+// @Tags 				Room
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 						true 	"Room id"
+// @Param 				UpdateRoomRequest	 		body 		domain.UpdateRoomRequest 	true 	"Update Room Request"
+// @Success 			200 						{object} 	domain.RoomResponse
+// @Router 				/rooms/{id} 				[put]
+func (h *Handler) UpdateRoom(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateRoomRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateRoom(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteRoom 			godoc
+// @Summary 			Delete Room
+// @Description 		Delete Room from Id, refused while class routines still use it
+// @Tags 				Room
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Room id"
+// @Success 			200 					{object} 	domain.RoomResponse
+// @Router 				/rooms/{id} 			[delete]
+func (h *Handler) DeleteRoom(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required room id"))
+		return
+	}
+	result, err := h.svc.DeleteRoom(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -3,6 +3,8 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"github.com/sugaml/lms-api/internal/adaptor/export"
+	"github.com/sugaml/lms-api/internal/adaptor/metrics"
 	"github.com/sugaml/lms-api/internal/adaptor/storage/uploader"
 	"github.com/sugaml/lms-api/internal/core/auth"
 	"github.com/sugaml/lms-api/internal/core/port"
@@ -10,6 +12,7 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/swaggo/swag/example/basic/docs"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 type Handler struct {
@@ -17,15 +20,19 @@ type Handler struct {
 	config     config.Config
 	tokenMaker auth.Maker
 	uploader   uploader.FileUploader
+	exporter   *export.Exporter
+	metrics    *metrics.Metrics
 }
 
 // NewHandler creates a new Handler instance
-func NewHandler(svc port.Service, config config.Config, tokenMaker auth.Maker, uploader uploader.FileUploader) *Handler {
+func NewHandler(svc port.Service, config config.Config, tokenMaker auth.Maker, uploader uploader.FileUploader, metrics *metrics.Metrics) *Handler {
 	return &Handler{
 		svc,
 		config,
 		tokenMaker,
 		uploader,
+		export.New(config),
+		metrics,
 	}
 }
 
@@ -41,9 +48,24 @@ func NewRouter(config config.Config, handler Handler) (*Router, error) {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	router := gin.Default()
+	// gin.New leaves out gin's plain text logger and recovery, the access log is JSON
+	router := gin.New()
+	// handlers hand the gin context to the service, falling back to the request's context lets
+	// the trace and the request's cancellation reach the repositories
+	router.ContextWithFallback = true
 
+	router.Use(otelgin.Middleware(config.APP_NAME, otelgin.WithFilter(tracedRequest)))
+	router.Use(requestContextMiddleware())
+	router.Use(accessLogMiddleware())
+	router.Use(recoveryMiddleware())
 	router.Use(CORSMiddleware())
+	router.Use(handler.metrics.Middleware())
+
+	// probes and metrics are scraped without a token, outside the versioned API
+	router.GET("/healthz", handler.Healthz)
+	router.GET("/readyz", handler.Readyz)
+	router.GET("/metrics", gin.WrapH(handler.metrics.Handler()))
+
 	v1 := router.Group("/api/v1/lms")
 	// setup Swagger
 	docs.SwaggerInfo.Host = config.HOST_PATH
@@ -62,6 +84,18 @@ func NewRouter(config config.Config, handler Handler) (*Router, error) {
 		upload.POST("", handler.UploadFile)
 	}
 
+	// registered ahead of the shared auth middleware so it can also take the token from the query
+	v1.GET("/notifications/stream", queryTokenMiddleware(), authMiddleware(handler.tokenMaker), handler.StreamNotification)
+
+	// calendar apps subscribe to a url and cannot send the Authorization header either, they pass
+	// a calendar token instead
+	calendar := v1.Group("/timetables", calendarAuthMiddleware(handler.tokenMaker, handler.svc))
+	{
+		calendar.GET("/semesters/:id/ics", handler.GetSemesterCalendar)
+		calendar.GET("/teachers/:id/ics", handler.GetTeacherCalendar)
+		calendar.GET("/rooms/:id/ics", handler.GetRoomCalendar)
+	}
+
 	v1.Use(authMiddleware(handler.tokenMaker))
 
 	profile := v1.Group("/profiles")
@@ -73,6 +107,8 @@ func NewRouter(config config.Config, handler Handler) (*Router, error) {
 	{
 		userAuth.GET("", handler.ListUser)
 		userAuth.GET("/:id", handler.GetUser)
+		userAuth.GET("/:id/history", handler.ListUserHistory)
+		userAuth.GET("/:id/timeline", handler.ListPatronHistory)
 		userAuth.PUT("/:id", handler.UpdateUser)
 		userAuth.DELETE("/:id", handler.DeleteUser)
 	}
@@ -93,15 +129,26 @@ func NewRouter(config config.Config, handler Handler) (*Router, error) {
 		program.GET("/:id", handler.GetProgram)
 		program.PUT("/:id", handler.UpdateProgram)
 		program.DELETE("/:id", handler.DeleteProgram)
+		program.GET("/:id/semesters", handler.ListProgramSemester)
+		program.GET("/:id/subjects", handler.ListProgramSubject)
 	}
 
 	auditlog := v1.Group("/auditlog")
 	{
 		auditlog.POST("", handler.CreateAuditLog)
 		auditlog.GET("", handler.ListAuditLog)
+		auditlog.GET("/verify", handler.VerifyAuditLog)
+		auditlog.GET("/checkpoints", handler.ListAuditCheckpoint)
+		auditlog.POST("/checkpoints", handler.CreateAuditCheckpoint)
+		auditlog.GET("/checkpoints/:id/export", handler.ExportAuditCheckpoint)
 		auditlog.GET("/:id", handler.GetAuditLog)
-		auditlog.PUT("/:id", handler.UpdateAuditLog)
-		auditlog.DELETE("/:id", handler.DeleteAuditLog)
+	}
+
+	archive := v1.Group("/archives")
+	{
+		archive.GET("", handler.ListArchive)
+		archive.GET("/:id", handler.GetArchive)
+		archive.POST("/:id/restore", handler.RestoreArchive)
 	}
 
 	book := v1.Group("/books")
@@ -110,6 +157,7 @@ func NewRouter(config config.Config, handler Handler) (*Router, error) {
 		book.GET("", handler.ListBook)
 		book.GET("/:id", handler.GetBook)
 		book.GET("/:id/book-copies", handler.ListBookCopyByBookId)
+		book.GET("/:id/history", handler.ListBookHistory)
 		book.PUT("/:id", handler.UpdateBook)
 		book.DELETE("/:id", handler.DeleteBook)
 	}
@@ -119,6 +167,7 @@ func NewRouter(config config.Config, handler Handler) (*Router, error) {
 		bookCopies.POST("", handler.CreateBookCopy)
 		bookCopies.GET("", handler.ListBookCopy)
 		bookCopies.GET("/:id", handler.GetBookCopy)
+		bookCopies.GET("/:id/history", handler.ListBookCopyHistory)
 		bookCopies.PUT("/:id", handler.UpdateBookCopy)
 		bookCopies.DELETE("/:id", handler.DeleteBookCopy)
 	}
@@ -139,12 +188,25 @@ func NewRouter(config config.Config, handler Handler) (*Router, error) {
 		report.GET("borrowedbookstats", handler.GetBorrowedBookStats)
 		report.GET("program-stats", handler.GetBookProgramstats)
 		report.GET("inventory-stats", handler.GetInventorystats)
+		report.GET("teacher-workload", handler.GetTeacherWorkload)
+		report.GET("room-utilization", handler.GetRoomUtilization)
+		report.GET("top-books", handler.GetTopBooks)
+		report.GET("top-categories", handler.GetTopCategories)
+		report.GET("inactive-stock", handler.ListInactiveStock)
+		report.GET("loan-duration", handler.GetLoanDuration)
+		report.GET("borrowing-rates", handler.GetBorrowingRates)
+		report.GET("top-readers", handler.GetTopReaders)
+		report.GET("fine-collection", handler.GetFineCollection)
+		report.GET("fine-aging", handler.GetFineAging)
+		report.GET("fine-aging/students", handler.ListStudentFineAging)
+		report.GET("fine-statement", handler.GetFineStatement)
 	}
 	borrow := v1.Group("/borrows")
 	{
 		borrow.POST("", handler.CreateBorrow)
 		borrow.GET("", handler.ListBorrow)
 		borrow.GET("/:id", handler.GetBorrow)
+		borrow.GET("/:id/history", handler.ListBorrowHistory)
 		borrow.PUT("/:id", handler.UpdateBorrow)
 		borrow.DELETE("/:id", handler.DeleteBorrow)
 	}
@@ -154,8 +216,11 @@ func NewRouter(config config.Config, handler Handler) (*Router, error) {
 		fine.POST("", handler.CreateFine)
 		fine.GET("", handler.ListFine)
 		fine.GET("/:id", handler.GetFine)
+		fine.GET("/:id/history", handler.ListFineHistory)
 		fine.PUT("/:id", handler.UpdateFine)
 		fine.DELETE("/:id", handler.DeleteFine)
+		fine.POST("/:id/pay", handler.PayFine)
+		fine.POST("/:id/waive", handler.WaiveFine)
 	}
 
 	notification := v1.Group("/notifications")
@@ -163,17 +228,193 @@ func NewRouter(config config.Config, handler Handler) (*Router, error) {
 		notification.POST("", handler.CreateNotification)
 		notification.POST("read-all", handler.ReadAllNotification)
 		notification.GET("", handler.ListNotification)
+		notification.GET("/unread-count", handler.CountUnreadNotification)
 		notification.GET("/:id", handler.GetNotification)
+		notification.POST("/:id/read", handler.ReadNotification)
+		notification.DELETE("/:id/read", handler.UnreadNotification)
 		notification.PUT("/:id", handler.UpdateNotification)
 		notification.DELETE("/:id", handler.DeleteNotification)
 	}
 
+	preference := v1.Group("/notification-preferences")
+	{
+		preference.GET("", handler.ListNotificationPreference)
+		preference.PUT("", handler.UpdateNotificationPreference)
+	}
+
+	announcement := v1.Group("/announcements")
+	{
+		announcement.POST("", handler.CreateAnnouncement)
+		announcement.GET("", handler.ListAnnouncement)
+		announcement.GET("/feed", handler.ListAnnouncementFeed)
+		announcement.GET("/:id", handler.GetAnnouncement)
+		announcement.PUT("/:id", handler.UpdateAnnouncement)
+		announcement.DELETE("/:id", handler.DeleteAnnouncement)
+		announcement.POST("/:id/read", handler.ReadAnnouncement)
+		announcement.GET("/:id/receipts", handler.ListAnnouncementReceipt)
+		announcement.POST("/:id/attachments", handler.AddAnnouncementAttachment)
+		announcement.DELETE("/:id/attachments/:attachmentId", handler.DeleteAnnouncementAttachment)
+	}
+
+	campus := v1.Group("/campuses")
+	{
+		campus.POST("", handler.CreateCampus)
+		campus.GET("", handler.ListCampus)
+		campus.GET("/:id", handler.GetCampus)
+		campus.GET("/:id/buildings", handler.ListCampusBuilding)
+		campus.PUT("/:id", handler.UpdateCampus)
+		campus.DELETE("/:id", handler.DeleteCampus)
+	}
+
+	building := v1.Group("/buildings")
+	{
+		building.POST("", handler.CreateBuilding)
+		building.GET("", handler.ListBuilding)
+		building.GET("/:id", handler.GetBuilding)
+		building.GET("/:id/floors", handler.ListBuildingFloor)
+		building.PUT("/:id", handler.UpdateBuilding)
+		building.DELETE("/:id", handler.DeleteBuilding)
+	}
+
+	floor := v1.Group("/floors")
+	{
+		floor.POST("", handler.CreateFloor)
+		floor.GET("", handler.ListFloor)
+		floor.GET("/:id", handler.GetFloor)
+		floor.GET("/:id/rooms", handler.ListFloorRoom)
+		floor.PUT("/:id", handler.UpdateFloor)
+		floor.DELETE("/:id", handler.DeleteFloor)
+	}
+
+	room := v1.Group("/rooms")
+	{
+		room.POST("", handler.CreateRoom)
+		room.GET("", handler.ListRoom)
+		room.GET("/:id", handler.GetRoom)
+		room.PUT("/:id", handler.UpdateRoom)
+		room.DELETE("/:id", handler.DeleteRoom)
+	}
+
+	faculty := v1.Group("/faculties")
+	{
+		faculty.POST("", handler.CreateFaculty)
+		faculty.GET("", handler.ListFaculty)
+		faculty.GET("/:id", handler.GetFaculty)
+		faculty.PUT("/:id", handler.UpdateFaculty)
+		faculty.DELETE("/:id", handler.DeleteFaculty)
+		faculty.GET("/:id/programs", handler.ListFacultyProgram)
+	}
+
+	semester := v1.Group("/semesters")
+	{
+		semester.POST("", handler.CreateSemester)
+		semester.GET("", handler.ListSemester)
+		semester.GET("/:id", handler.GetSemester)
+		semester.PUT("/:id", handler.UpdateSemester)
+		semester.DELETE("/:id", handler.DeleteSemester)
+	}
+
+	subject := v1.Group("/subjects")
+	{
+		subject.POST("", handler.CreateSubject)
+		subject.GET("", handler.ListSubject)
+		subject.GET("/:id", handler.GetSubject)
+		subject.PUT("/:id", handler.UpdateSubject)
+		subject.DELETE("/:id", handler.DeleteSubject)
+	}
+
+	timeSlot := v1.Group("/time-slots")
+	{
+		timeSlot.POST("", handler.CreateTimeSlot)
+		timeSlot.GET("", handler.ListTimeSlot)
+		timeSlot.GET("/:id", handler.GetTimeSlot)
+		timeSlot.PUT("/:id", handler.UpdateTimeSlot)
+		timeSlot.DELETE("/:id", handler.DeleteTimeSlot)
+	}
+
+	routine := v1.Group("/routines")
+	{
+		routine.POST("", handler.CreateRoutine)
+		routine.POST("/check", handler.CheckRoutine)
+		routine.GET("", handler.ListRoutine)
+		routine.GET("/:id", handler.GetRoutine)
+		routine.PUT("/:id", handler.UpdateRoutine)
+		routine.DELETE("/:id", handler.DeleteRoutine)
+	}
+
+	unavailability := v1.Group("/teacher-unavailabilities")
+	{
+		unavailability.POST("", handler.CreateTeacherUnavailability)
+		unavailability.GET("", handler.ListTeacherUnavailability)
+		unavailability.DELETE("/:id", handler.DeleteTeacherUnavailability)
+	}
+
+	timetable := v1.Group("/timetables")
+	{
+		timetable.GET("/semesters/:id", handler.GetSemesterTimetable)
+		timetable.GET("/teachers/:id", handler.GetTeacherTimetable)
+		timetable.GET("/rooms/:id", handler.GetRoomTimetable)
+	}
+
+	calendarToken := v1.Group("/calendar-tokens")
+	{
+		calendarToken.GET("", handler.GetCalendarToken)
+		calendarToken.POST("", handler.IssueCalendarToken)
+		calendarToken.DELETE("", handler.RevokeCalendarToken)
+	}
+
+	draft := v1.Group("/timetable-drafts")
+	{
+		draft.POST("", handler.GenerateTimetableDraft)
+		draft.GET("", handler.ListTimetableDraft)
+		draft.GET("/:id", handler.GetTimetableDraft)
+		draft.DELETE("/:id", handler.DeleteTimetableDraft)
+		draft.POST("/:id/commit", handler.CommitTimetableDraft)
+	}
+
+	email := v1.Group("/email-deliveries")
+	{
+		email.GET("", handler.ListEmailDelivery)
+		email.GET("/:id", handler.GetEmailDelivery)
+		email.POST("/:id/retry", handler.RetryEmailDelivery)
+	}
+
+	webhook := v1.Group("/webhooks")
+	{
+		webhook.POST("", handler.CreateWebhookSubscription)
+		webhook.GET("", handler.ListWebhookSubscription)
+		webhook.GET("/events", handler.ListWebhookEventType)
+		webhook.GET("/:id", handler.GetWebhookSubscription)
+		webhook.PUT("/:id", handler.UpdateWebhookSubscription)
+		webhook.DELETE("/:id", handler.DeleteWebhookSubscription)
+		webhook.POST("/:id/ping", handler.PingWebhookSubscription)
+	}
+
+	webhookDelivery := v1.Group("/webhook-deliveries")
+	{
+		webhookDelivery.GET("", handler.ListWebhookDelivery)
+		webhookDelivery.GET("/:id", handler.GetWebhookDelivery)
+		webhookDelivery.POST("/:id/replay", handler.ReplayWebhookDelivery)
+	}
+
+	sms := v1.Group("/sms-deliveries")
+	{
+		sms.GET("", handler.ListSMSDelivery)
+		sms.GET("/usage", handler.GetSMSUsage)
+		sms.GET("/:id", handler.GetSMSDelivery)
+		sms.POST("/:id/retry", handler.RetrySMSDelivery)
+	}
+
+	academicYear := v1.Group("/academic-years")
+	{
+		academicYear.POST("", handler.CreateAcademicYear)
+		academicYear.GET("", handler.ListAcademicYear)
+		academicYear.GET("/:id", handler.GetAcademicYear)
+		academicYear.PUT("/:id", handler.UpdateAcademicYear)
+		academicYear.DELETE("/:id", handler.DeleteAcademicYear)
+	}
+
 	return &Router{
 		router,
 	}, nil
 }
-
-// Serve starts the HTTP server
-func (r *Router) Serve(listenAddr string) error {
-	return r.Run(listenAddr)
-}
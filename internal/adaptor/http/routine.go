@@ -0,0 +1,247 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// routineErrorResponse answers scheduling conflicts with 409 and the list of clashes
+func routineErrorResponse(ctx *gin.Context, err error) {
+	var conflictErr *domain.RoutineConflictError
+	if errors.As(err, &conflictErr) {
+		ctx.JSON(http.StatusConflict, responseData{
+			Error:     http.StatusConflict,
+			Code:      "routine_conflict",
+			Message:   "routine conflicts with existing schedule",
+			RequestID: ctx.GetString(requestIDKey),
+			Data:      conflictErr.Conflicts,
+		})
+		return
+	}
+	ErrorResponse(ctx, http.StatusBadRequest, err)
+}
+
+// AddRoutine		godoc
+// @Summary			Add a new ClassRoutine
+// @Description		Add a new ClassRoutine, rejected with 409 and a conflict list when it clashes
+// @Tags			Routine
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			ClassRoutineRequest		body		domain.ClassRoutineRequest		true		"Add ClassRoutine Request"
+// @Success			200						{object}	domain.ClassRoutineResponse					"ClassRoutine created"
+// @Failure			409						{array}		domain.RoutineConflict						"Scheduling conflicts"
+// @Router			/routines 				[post]
+func (h *Handler) CreateRoutine(ctx *gin.Context) {
+	var req *domain.ClassRoutineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateRoutine(ctx, req)
+	if err != nil {
+		routineErrorResponse(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// CheckRoutine		godoc
+// @Summary			Check ClassRoutine conflicts
+// @Description		Check a ClassRoutine for room, teacher, semester, capacity, lab and availability conflicts without saving it
+// @Tags			Routine
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			ClassRoutineRequest		body		domain.ClassRoutineRequest		true		"ClassRoutine Request"
+// @Success			200						{array}		domain.RoutineConflict
+// @Router			/routines/check 		[post]
+func (h *Handler) CheckRoutine(ctx *gin.Context) {
+	var req *domain.ClassRoutineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CheckRoutine(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListRoutine 		godoc
+// @Summary 		List ClassRoutine
+// @Description 	List ClassRoutine
+// @Tags 			Routine
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			semester_id 				query 		string 		false 	"Semester id"
+// @Param 			teacher_id 					query 		string 		false 	"Teacher id"
+// @Param 			room_id 					query 		string 		false 	"Room id"
+// @Param 			day_of_week 				query 		string 		false 	"MON, TUE, WED, THU, FRI, SAT"
+// @Param 			academic_year 				query 		string 		false 	"Academic year"
+// @Success 		200 		{array} 		domain.ClassRoutineResponse
+// @Router 			/routines	 	[get]
+func (h *Handler) ListRoutine(ctx *gin.Context) {
+	var req domain.ListClassRoutineRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListRoutine(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetRoutine 		godoc
+// @Summary 		Get ClassRoutine
+// @Description 	Get ClassRoutine from Id
+// @Tags 			Routine
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Routine id"
+// @Success 		200 {object} domain.ClassRoutineResponse
+// @Router 			/routines/{id} [get]
+func (h *Handler) GetRoutine(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetRoutine(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateRoutine		godoc
+// @Summary 			Update ClassRoutine
+// @Description 		Update ClassRoutine from Id, rejected with 409 and a conflict list when it clashes
+// @Tags 				Routine
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 								path 		string 								true 	"Routine id"
+// @Param 				UpdateClassRoutineRequest	 	body 		domain.UpdateClassRoutineRequest 	true 	"Update ClassRoutine Request"
+// @Success 			200 							{object} 	domain.ClassRoutineResponse
+// @Failure				409								{array}		domain.RoutineConflict
+// @Router 				/routines/{id} 					[put]
+func (h *Handler) UpdateRoutine(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateClassRoutineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateRoutine(ctx, id, req)
+	if err != nil {
+		routineErrorResponse(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteRoutine 		godoc
+// @Summary 			Delete ClassRoutine
+// @Description 		Delete ClassRoutine from Id
+// @Tags 				Routine
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Routine id"
+// @Success 			200 					{object} 	domain.ClassRoutineResponse
+// @Router 				/routines/{id} 			[delete]
+func (h *Handler) DeleteRoutine(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required routine id"))
+		return
+	}
+	result, err := h.svc.DeleteRoutine(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// AddTeacherUnavailability	godoc
+// @Summary			Add teacher unavailability
+// @Description		Mark a teacher unavailable for a day or a single time slot
+// @Tags			Routine
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			TeacherUnavailabilityRequest	body		domain.TeacherUnavailabilityRequest		true		"Add TeacherUnavailability Request"
+// @Success			200								{object}	domain.TeacherUnavailabilityResponse
+// @Router			/teacher-unavailabilities 		[post]
+func (h *Handler) CreateTeacherUnavailability(ctx *gin.Context) {
+	var req *domain.TeacherUnavailabilityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateTeacherUnavailability(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListTeacherUnavailability 	godoc
+// @Summary 		List teacher unavailability
+// @Description 	List teacher unavailability
+// @Tags 			Routine
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			teacher_id 					query 		string 		false 	"Teacher id"
+// @Success 		200 		{array} 		domain.TeacherUnavailabilityResponse
+// @Router 			/teacher-unavailabilities	[get]
+func (h *Handler) ListTeacherUnavailability(ctx *gin.Context) {
+	var req domain.ListTeacherUnavailabilityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListTeacherUnavailability(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// DeleteTeacherUnavailability 	godoc
+// @Summary 			Delete teacher unavailability
+// @Description 		Delete teacher unavailability from Id
+// @Tags 				Routine
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Unavailability id"
+// @Success 			200 					{object} 	domain.TeacherUnavailabilityResponse
+// @Router 				/teacher-unavailabilities/{id} 	[delete]
+func (h *Handler) DeleteTeacherUnavailability(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required unavailability id"))
+		return
+	}
+	result, err := h.svc.DeleteTeacherUnavailability(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
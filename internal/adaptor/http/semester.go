@@ -0,0 +1,129 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AddSemester		godoc
+// @Summary			Add a new Semester
+// @Description		Add a new Semester to a Program, semester_no is unique within the program
+// @Tags			Semester
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			SemesterRequest			body		domain.SemesterRequest		true		"Add Semester Request"
+// @Success			200						{object}	domain.SemesterResponse				"Semester created"
+// @Router			/semesters 				[post]
+func (h *Handler) CreateSemester(ctx *gin.Context) {
+	var req *domain.SemesterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateSemester(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListSemester 	godoc
+// @Summary 		List Semester
+// @Description 	List Semester
+// @Tags 			Semester
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 						query 		string 		false 	"query"
+// @Param 			program_id 					query 		string 		false 	"Program id"
+// @Success 		200 		{array} 		domain.SemesterResponse
+// @Router 			/semesters	 	[get]
+func (h *Handler) ListSemester(ctx *gin.Context) {
+	var req domain.ListSemesterRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListSemester(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetSemester 		godoc
+// @Summary 		Get Semester
+// @Description 	Get Semester from Id
+// @Tags 			Semester
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Semester id"
+// @Success 		200 {object} domain.SemesterResponse
+// @Router 			/semesters/{id} [get]
+func (h *Handler) GetSemester(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetSemester(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateSemester		godoc
+// @Summary 			Update Semester
+// @Description 		Update Semester from Id
+// @Tags 				Semester
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 							true 	"Semester id"
+// @Param 				UpdateSemesterRequest	 	body 		domain.UpdateSemesterRequest 	true 	"Update Semester Request"
+// @Success 			200 						{object} 	domain.SemesterResponse
+// @Router 				/semesters/{id} 			[put]
+func (h *Handler) UpdateSemester(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateSemesterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateSemester(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteSemester 		godoc
+// @Summary 			Delete Semester
+// @Description 		Delete Semester from Id, refused while subjects or routines still reference it
+// @Tags 				Semester
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Semester id"
+// @Success 			200 					{object} 	domain.SemesterResponse
+// @Router 				/semesters/{id} 		[delete]
+func (h *Handler) DeleteSemester(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required semester id"))
+		return
+	}
+	result, err := h.svc.DeleteSemester(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/adaptor/config"
+)
+
+// serverClosingKey holds a channel in every request context that is closed once the server
+// starts shutting down
+type serverClosingKey struct{}
+
+// Server serves the router over HTTP, or HTTPS when a certificate is configured
+type Server struct {
+	server   *http.Server
+	certFile string
+	keyFile  string
+}
+
+// NewServer listens on APP_PORT with the timeouts and header limit from the config
+func NewServer(config config.Config, router *Router) (*Server, error) {
+	readHeaderTimeout, err := parseTimeout("HTTP_READ_HEADER_TIMEOUT", config.HTTP_READ_HEADER_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	readTimeout, err := parseTimeout("HTTP_READ_TIMEOUT", config.HTTP_READ_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	writeTimeout, err := parseTimeout("HTTP_WRITE_TIMEOUT", config.HTTP_WRITE_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	idleTimeout, err := parseTimeout("HTTP_IDLE_TIMEOUT", config.HTTP_IDLE_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	maxHeaderBytes, err := strconv.Atoi(config.HTTP_MAX_HEADER_BYTES)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP_MAX_HEADER_BYTES: %w", err)
+	}
+	if (config.TLS_CERT_FILE == "") != (config.TLS_KEY_FILE == "") {
+		return nil, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	// notification streams never go idle, they end when closing is done instead of holding the
+	// shutdown up until its timeout
+	closing, stopStreams := context.WithCancel(context.Background())
+	server := &http.Server{
+		Addr:              ":" + config.APP_PORT,
+		Handler:           router,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		MaxHeaderBytes:    maxHeaderBytes,
+		ErrorLog:          log.New(logrus.StandardLogger().WriterLevel(logrus.WarnLevel), "", 0),
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), serverClosingKey{}, closing.Done())
+		},
+	}
+	if config.TLS_CERT_FILE != "" {
+		server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	server.RegisterOnShutdown(stopStreams)
+	return &Server{server: server, certFile: config.TLS_CERT_FILE, keyFile: config.TLS_KEY_FILE}, nil
+}
+
+func parseTimeout(name, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return d, nil
+}
+
+// ListenAndServe blocks until the server fails or is shut down, a shutdown is not an error
+func (s *Server) ListenAndServe() error {
+	var err error
+	if s.certFile != "" {
+		logrus.Infof("Starting the HTTPS server in port %s", s.server.Addr)
+		err = s.server.ListenAndServeTLS(s.certFile, s.keyFile)
+	} else {
+		logrus.Infof("Starting the HTTP server in port %s", s.server.Addr)
+		err = s.server.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting connections and waits for the requests in flight to finish until ctx
+// is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// serverClosing is closed once the server starts shutting down, it is nil outside of Server
+func serverClosing(ctx context.Context) <-chan struct{} {
+	closing, _ := ctx.Value(serverClosingKey{}).(<-chan struct{})
+	return closing
+}
+
+// clearWriteDeadline lets a response that is written for as long as it takes, a stream or a
+// large export, outlive HTTP_WRITE_TIMEOUT
+func clearWriteDeadline(ctx *gin.Context) {
+	if err := http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logrus.WithError(err).Debug("could not clear the write deadline")
+	}
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// ListSMSDelivery 	godoc
+// @Summary 			List SMSDelivery
+// @Description 		List queued, sent, failed, rejected and rate limited text messages
+// @Tags 				SMSDelivery
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				user_id 					query 		string 		false 	"User id"
+// @Param 				template 					query 		string 		false 	"due_reminder | overdue | hold_ready | fine_issued | account_created"
+// @Param 				status 						query 		string 		false 	"queued | sent | failed | rejected | limited"
+// @Success 			200 		{array} 		domain.SMSDeliveryResponse
+// @Router 				/sms-deliveries	 	[get]
+func (h *Handler) ListSMSDelivery(ctx *gin.Context) {
+	var req domain.ListSMSDeliveryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListSMSDelivery(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetSMSUsage 		godoc
+// @Summary 			SMS usage
+// @Description 		Sent messages, segments and cost in paisa per template, the last month by default
+// @Tags 				SMSDelivery
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				start_date 					query 		string 		false 	"Start date (YYYY-MM-DD)"
+// @Param 				end_date 					query 		string 		false 	"End date (YYYY-MM-DD)"
+// @Success 			200 		{array} 		domain.SMSUsage
+// @Router 				/sms-deliveries/usage 	[get]
+func (h *Handler) GetSMSUsage(ctx *gin.Context) {
+	var req domain.SMSUsageRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.GetSMSUsage(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// GetSMSDelivery 	godoc
+// @Summary 			Get SMSDelivery
+// @Description 		Get SMSDelivery from Id
+// @Tags 				SMSDelivery
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id path string true "SMSDelivery id"
+// @Success 			200 {object} domain.SMSDeliveryResponse
+// @Router 				/sms-deliveries/{id} [get]
+func (h *Handler) GetSMSDelivery(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetSMSDelivery(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// RetrySMSDelivery 	godoc
+// @Summary 			Retry SMSDelivery
+// @Description 		Queue a failed, rejected or rate limited message again, it is still held during quiet hours
+// @Tags 				SMSDelivery
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 								path 		string 		true 	"SMSDelivery id"
+// @Success 			200 							{object} 	domain.SMSDeliveryResponse
+// @Router 				/sms-deliveries/{id}/retry 		[post]
+func (h *Handler) RetrySMSDelivery(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.RetrySMSDelivery(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
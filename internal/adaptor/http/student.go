@@ -23,7 +23,7 @@ func (h *Handler) CreateStudent(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	result, err := h.svc.CreateUser(req)
+	result, err := h.svc.CreateUser(ctx, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -47,7 +47,7 @@ func (h *Handler) CreateBulkStudent(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	result, err := h.svc.CreateBulkUser(req)
+	result, err := h.svc.CreateBulkUser(ctx, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
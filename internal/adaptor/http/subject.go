@@ -0,0 +1,131 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AddSubject		godoc
+// @Summary			Add a new Subject
+// @Description		Add a new Subject to a Program, code is unique
+// @Tags			Subject
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			SubjectRequest			body		domain.SubjectRequest		true		"Add Subject Request"
+// @Success			200						{object}	domain.SubjectResponse				"Subject created"
+// @Router			/subjects 				[post]
+func (h *Handler) CreateSubject(ctx *gin.Context) {
+	var req *domain.SubjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateSubject(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListSubject 	godoc
+// @Summary 		List Subject
+// @Description 	List Subject
+// @Tags 			Subject
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 						query 		string 		false 	"query"
+// @Param 			program_id 					query 		string 		false 	"Program id"
+// @Param 			semester_id 				query 		string 		false 	"Semester id"
+// @Param 			is_lab 						query 		bool 		false 	"Lab subjects only"
+// @Success 		200 		{array} 		domain.SubjectResponse
+// @Router 			/subjects	 	[get]
+func (h *Handler) ListSubject(ctx *gin.Context) {
+	var req domain.ListSubjectRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListSubject(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetSubject 		godoc
+// @Summary 		Get Subject
+// @Description 	Get Subject from Id
+// @Tags 			Subject
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Subject id"
+// @Success 		200 {object} domain.SubjectResponse
+// @Router 			/subjects/{id} [get]
+func (h *Handler) GetSubject(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetSubject(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateSubject		godoc
+// @Summary 			Update Subject
+// @Description 		Update Subject from Id
+// @Tags 				Subject
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 							true 	"Subject id"
+// @Param 				UpdateSubjectRequest	 	body 		domain.UpdateSubjectRequest 	true 	"Update Subject Request"
+// @Success 			200 						{object} 	domain.SubjectResponse
+// @Router 				/subjects/{id} 			[put]
+func (h *Handler) UpdateSubject(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateSubjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateSubject(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteSubject 		godoc
+// @Summary 			Delete Subject
+// @Description 		Delete Subject from Id, refused while routines still reference it
+// @Tags 				Subject
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"Subject id"
+// @Success 			200 					{object} 	domain.SubjectResponse
+// @Router 				/subjects/{id} 		[delete]
+func (h *Handler) DeleteSubject(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required subject id"))
+		return
+	}
+	result, err := h.svc.DeleteSubject(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AddTimeSlot		godoc
+// @Summary			Add a new TimeSlot
+// @Description		Add a new TimeSlot, HH:MM times that must not overlap an existing slot
+// @Tags			TimeSlot
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			TimeSlotRequest			body		domain.TimeSlotRequest		true		"Add TimeSlot Request"
+// @Success			200						{object}	domain.TimeSlotResponse				"TimeSlot created"
+// @Router			/time-slots 				[post]
+func (h *Handler) CreateTimeSlot(ctx *gin.Context) {
+	var req *domain.TimeSlotRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateTimeSlot(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListTimeSlot 	godoc
+// @Summary 		List TimeSlot
+// @Description 	List TimeSlot
+// @Tags 			TimeSlot
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 						query 		string 		false 	"query"
+// @Success 		200 		{array} 		domain.TimeSlotResponse
+// @Router 			/time-slots	 	[get]
+func (h *Handler) ListTimeSlot(ctx *gin.Context) {
+	var req domain.ListTimeSlotRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.SortColumn == "" {
+		req.SortColumn = "start_time"
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListTimeSlot(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetTimeSlot 		godoc
+// @Summary 		Get TimeSlot
+// @Description 	Get TimeSlot from Id
+// @Tags 			TimeSlot
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "TimeSlot id"
+// @Success 		200 {object} domain.TimeSlotResponse
+// @Router 			/time-slots/{id} [get]
+func (h *Handler) GetTimeSlot(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetTimeSlot(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateTimeSlot		godoc
+// @Summary 			Update TimeSlot
+// @Description 		Update TimeSlot from Id
+// @Tags 				TimeSlot
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 							path 		string 							true 	"TimeSlot id"
+// @Param 				UpdateTimeSlotRequest	 	body 		domain.UpdateTimeSlotRequest 	true 	"Update TimeSlot Request"
+// @Success 			200 						{object} 	domain.TimeSlotResponse
+// @Router 				/time-slots/{id} 			[put]
+func (h *Handler) UpdateTimeSlot(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateTimeSlotRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateTimeSlot(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteTimeSlot 		godoc
+// @Summary 			Delete TimeSlot
+// @Description 		Delete TimeSlot from Id, refused while routines still reference it
+// @Tags 				TimeSlot
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 						true 	"TimeSlot id"
+// @Success 			200 					{object} 	domain.TimeSlotResponse
+// @Router 				/time-slots/{id} 		[delete]
+func (h *Handler) DeleteTimeSlot(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required time slot id"))
+		return
+	}
+	result, err := h.svc.DeleteTimeSlot(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// GetSemesterTimetable	godoc
+// @Summary 			Semester Timetable
+// @Description 		Weekly DayOfWeek × TimeSlot grid of a Semester's routines
+// @Tags 				Timetable
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Semester id"
+// @Param 				section 				query 		string 		false 	"Section"
+// @Param 				academic_year 			query 		string 		false 	"Academic year"
+// @Success 			200 					{object} 	domain.TimetableResponse
+// @Router 				/timetables/semesters/{id} 	[get]
+func (h *Handler) GetSemesterTimetable(ctx *gin.Context) {
+	h.getTimetable(ctx, domain.TimetableSemester)
+}
+
+// GetTeacherTimetable	godoc
+// @Summary 			Teacher Timetable
+// @Description 		Weekly DayOfWeek × TimeSlot grid of a Teacher's routines
+// @Tags 				Timetable
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Teacher id"
+// @Param 				academic_year 			query 		string 		false 	"Academic year"
+// @Success 			200 					{object} 	domain.TimetableResponse
+// @Router 				/timetables/teachers/{id} 	[get]
+func (h *Handler) GetTeacherTimetable(ctx *gin.Context) {
+	h.getTimetable(ctx, domain.TimetableTeacher)
+}
+
+// GetRoomTimetable		godoc
+// @Summary 			Room Timetable
+// @Description 		Weekly DayOfWeek × TimeSlot grid of a Room's routines
+// @Tags 				Timetable
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Room id"
+// @Param 				academic_year 			query 		string 		false 	"Academic year"
+// @Success 			200 					{object} 	domain.TimetableResponse
+// @Router 				/timetables/rooms/{id} 	[get]
+func (h *Handler) GetRoomTimetable(ctx *gin.Context) {
+	h.getTimetable(ctx, domain.TimetableRoom)
+}
+
+// GetSemesterCalendar	godoc
+// @Summary 			Semester Timetable Calendar
+// @Description 		Semester timetable as weekly recurring iCalendar events, defaults to the current academic year
+// @Tags 				Timetable
+// @Produce  			text/calendar
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Semester id"
+// @Param 				section 				query 		string 		false 	"Section"
+// @Param 				academic_year 			query 		string 		false 	"Academic year"
+// @Param 				token 					query 		string 		false 	"Calendar token, for calendar apps that cannot set the Authorization header"
+// @Success 			200 					{file} 		file
+// @Router 				/timetables/semesters/{id}/ics 	[get]
+func (h *Handler) GetSemesterCalendar(ctx *gin.Context) {
+	h.getTimetableCalendar(ctx, domain.TimetableSemester)
+}
+
+// GetTeacherCalendar	godoc
+// @Summary 			Teacher Timetable Calendar
+// @Description 		Teacher timetable as weekly recurring iCalendar events, defaults to the current academic year
+// @Tags 				Timetable
+// @Produce  			text/calendar
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Teacher id"
+// @Param 				academic_year 			query 		string 		false 	"Academic year"
+// @Param 				token 					query 		string 		false 	"Calendar token, for calendar apps that cannot set the Authorization header"
+// @Success 			200 					{file} 		file
+// @Router 				/timetables/teachers/{id}/ics 	[get]
+func (h *Handler) GetTeacherCalendar(ctx *gin.Context) {
+	h.getTimetableCalendar(ctx, domain.TimetableTeacher)
+}
+
+// GetRoomCalendar		godoc
+// @Summary 			Room Timetable Calendar
+// @Description 		Room timetable as weekly recurring iCalendar events, defaults to the current academic year
+// @Tags 				Timetable
+// @Produce  			text/calendar
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Room id"
+// @Param 				academic_year 			query 		string 		false 	"Academic year"
+// @Param 				token 					query 		string 		false 	"Calendar token, for calendar apps that cannot set the Authorization header"
+// @Success 			200 					{file} 		file
+// @Router 				/timetables/rooms/{id}/ics 	[get]
+func (h *Handler) GetRoomCalendar(ctx *gin.Context) {
+	h.getTimetableCalendar(ctx, domain.TimetableRoom)
+}
+
+func (h *Handler) getTimetable(ctx *gin.Context, view string) {
+	var req domain.TimetableRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.View = view
+	req.ID = ctx.Param("id")
+	result, err := h.svc.GetTimetable(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+func (h *Handler) getTimetableCalendar(ctx *gin.Context, view string) {
+	var req domain.TimetableRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.View = view
+	req.ID = ctx.Param("id")
+	result, err := h.svc.GetTimetableCalendar(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-%s.ics", view, req.ID))
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", result)
+}
+
+// IssueCalendarToken	godoc
+// @Summary 			Issue my calendar token
+// @Description 		New read-only token for subscribing to the timetable calendar feeds with ?token=, it replaces the one issued before and is shown this once
+// @Tags 				Timetable
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Success 			200 					{object} 	domain.CalendarTokenResponse
+// @Router 				/calendar-tokens 		[post]
+func (h *Handler) IssueCalendarToken(ctx *gin.Context) {
+	result, err := h.svc.IssueCalendarToken(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// GetCalendarToken		godoc
+// @Summary 			Get my calendar token
+// @Description 		When the caller's calendar token was issued, the token itself is not shown again
+// @Tags 				Timetable
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Success 			200 					{object} 	domain.CalendarTokenResponse
+// @Router 				/calendar-tokens 		[get]
+func (h *Handler) GetCalendarToken(ctx *gin.Context) {
+	result, err := h.svc.GetCalendarToken(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// RevokeCalendarToken	godoc
+// @Summary 			Revoke my calendar token
+// @Description 		Stop the caller's calendar token from reading the timetable calendar feeds
+// @Tags 				Timetable
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Success 			200 					{object} 	domain.CalendarTokenResponse
+// @Router 				/calendar-tokens 		[delete]
+func (h *Handler) RevokeCalendarToken(ctx *gin.Context) {
+	result, err := h.svc.RevokeCalendarToken(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// GenerateTimetableDraft	godoc
+// @Summary			Generate a timetable draft
+// @Description		Propose a week of ClassRoutines for a semester section from its subjects, teacher assignments, rooms and time slots. The draft is saved for review, sessions that cannot be placed are listed with a reason.
+// @Tags			TimetableDraft
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			GenerateTimetableRequest	body		domain.GenerateTimetableRequest		true		"Generate Timetable Request"
+// @Success			200							{object}	domain.TimetableDraftResponse				"Timetable draft"
+// @Router			/timetable-drafts 			[post]
+func (h *Handler) GenerateTimetableDraft(ctx *gin.Context) {
+	var req *domain.GenerateTimetableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.GenerateTimetableDraft(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListTimetableDraft 	godoc
+// @Summary 			List TimetableDraft
+// @Description 		List TimetableDraft
+// @Tags 				TimetableDraft
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				semester_id 				query 		string 		false 	"Semester id"
+// @Param 				status 						query 		string 		false 	"draft | committed"
+// @Success 			200 		{array} 		domain.TimetableDraftResponse
+// @Router 				/timetable-drafts	 	[get]
+func (h *Handler) ListTimetableDraft(ctx *gin.Context) {
+	var req domain.ListTimetableDraftRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListTimetableDraft(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetTimetableDraft 	godoc
+// @Summary 			Get TimetableDraft
+// @Description 		Get TimetableDraft from Id
+// @Tags 				TimetableDraft
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id path string true "TimetableDraft id"
+// @Success 			200 {object} domain.TimetableDraftResponse
+// @Router 				/timetable-drafts/{id} [get]
+func (h *Handler) GetTimetableDraft(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetTimetableDraft(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteTimetableDraft 	godoc
+// @Summary 				Discard TimetableDraft
+// @Description 			Discard TimetableDraft from Id
+// @Tags 					TimetableDraft
+// @Accept  				json
+// @Produce  				json
+// @Security 				ApiKeyAuth
+// @Param 					id 						path 		string 						true 	"TimetableDraft id"
+// @Success 				200 					{object} 	domain.TimetableDraftResponse
+// @Router 					/timetable-drafts/{id} 	[delete]
+func (h *Handler) DeleteTimetableDraft(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required timetable draft id"))
+		return
+	}
+	result, err := h.svc.DeleteTimetableDraft(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// CommitTimetableDraft 	godoc
+// @Summary 				Commit TimetableDraft
+// @Description 			Create the draft's ClassRoutines in one go, rejected with 409 and a conflict list when the schedule changed since it was generated
+// @Tags 					TimetableDraft
+// @Accept  				json
+// @Produce  				json
+// @Security 				ApiKeyAuth
+// @Param 					id 								path 		string 		true 	"TimetableDraft id"
+// @Success 				200 							{array} 	domain.ClassRoutineResponse
+// @Failure					409								{array}		domain.RoutineConflict		"Scheduling conflicts"
+// @Router 					/timetable-drafts/{id}/commit 	[post]
+func (h *Handler) CommitTimetableDraft(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.CommitTimetableDraft(ctx, id)
+	if err != nil {
+		routineErrorResponse(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
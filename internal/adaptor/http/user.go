@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
 )
 
 func (h *Handler) Ping(ctx *gin.Context) {
@@ -29,7 +30,7 @@ func (h *Handler) CreateUser(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	result, err := h.svc.CreateUser(req)
+	result, err := h.svc.CreateUser(ctx, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -53,7 +54,7 @@ func (h *Handler) LoginUser(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	result, err := h.svc.LoginUser(req)
+	result, err := h.svc.LoginUser(ctx, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -78,7 +79,7 @@ func (h *Handler) ListUser(ctx *gin.Context) {
 		return
 	}
 	req.Prepare()
-	result, count, err := h.svc.ListUser(&req)
+	result, count, err := h.svc.ListUser(ctx, &req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -91,9 +92,12 @@ func (h *Handler) ListUser(ctx *gin.Context) {
 // @Description 	List User
 // @Tags 			User
 // @Accept  		json
-// @Produce  		json
+// @Produce  		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
 // @Security 		ApiKeyAuth
 // @Param 			query 						query 		string 		false 	"query"
+// @Param 			format 						query 		string 		false 	"json (default), csv, xlsx or pdf"
+// @Param 			columns 					query 		string 		false 	"Comma separated columns to export, all when empty"
+// @Param 			lang 						query 		string 		false 	"Header language of the export, en or ne, defaults to Accept-Language"
 // @Success 		200 		{array} 		domain.StudentResponse
 // @Router 			/users	 	[get]
 func (h *Handler) ListStudent(ctx *gin.Context) {
@@ -109,7 +113,18 @@ func (h *Handler) ListStudent(ctx *gin.Context) {
 		return
 	}
 	req.Prepare()
-	result, count, err := h.svc.ListStudent(&req)
+	exp, err := exportRequest(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if exp.IsExport() {
+		h.export(ctx, "students", exp, func(w port.ExportWriter) error {
+			return h.svc.ExportStudent(ctx, &req, exp, w)
+		})
+		return
+	}
+	result, count, err := h.svc.ListStudent(ctx, &req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -129,7 +144,7 @@ func (h *Handler) ListStudent(ctx *gin.Context) {
 // @Router 			/users/{id} [get]
 func (h *Handler) GetUser(ctx *gin.Context) {
 	id := ctx.Param("id")
-	result, err := h.svc.GetUser(id)
+	result, err := h.svc.GetUser(ctx, id)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -153,7 +168,7 @@ func (h *Handler) GetProfile(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, errors.New("authorization user id not found"))
 		return
 	}
-	result, err := h.svc.GetUser(id.(string))
+	result, err := h.svc.GetUser(ctx, id.(string))
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -179,7 +194,7 @@ func (h *Handler) UpdateUser(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
 	}
-	data, err := h.svc.UpdateUser(id, req)
+	data, err := h.svc.UpdateUser(ctx, id, req)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -204,7 +219,7 @@ func (ch *Handler) DeleteUser(ctx *gin.Context) {
 		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required User id"))
 		return
 	}
-	result, err := ch.svc.DeleteUser(id)
+	result, err := ch.svc.DeleteUser(ctx, id)
 	if err != nil {
 		ErrorResponse(ctx, http.StatusBadRequest, err)
 		return
@@ -0,0 +1,230 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateWebhookSubscription	godoc
+// @Summary			Add a new Webhook subscription
+// @Description		Subscribe a URL to domain events, every delivery is signed with the secret in the X-LMS-Signature header. The secret is generated when empty and only shown in this response.
+// @Tags			Webhooks
+// @Accept			json
+// @Produce			json
+// @Security 		ApiKeyAuth
+// @Param			WebhookSubscriptionRequest		body		domain.WebhookSubscriptionRequest		true		"Add Webhook subscription Request"
+// @Success			200					{object}			domain.WebhookSubscriptionResponse					"Webhook subscription created"
+// @Router			/webhooks				[post]
+func (h *Handler) CreateWebhookSubscription(ctx *gin.Context) {
+	var req *domain.WebhookSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateWebhookSubscription(ctx, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListWebhookSubscription godoc
+// @Summary 		List Webhook subscriptions
+// @Description 	List Webhook subscriptions
+// @Tags 			Webhooks
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			query 								query 		string 		false 	"query"
+// @Param 			is_active 							query 		bool 		false 	"is_active"
+// @Param 			event 								query 		string 		false 	"Subscribed event type"
+// @Success 		200 				{array} 		domain.WebhookSubscriptionResponse
+// @Router 			/webhooks	 	[get]
+func (h *Handler) ListWebhookSubscription(ctx *gin.Context) {
+	var req domain.ListWebhookSubscriptionRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.SortDirection == "" {
+		req.SortDirection = "desc"
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListWebhookSubscription(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// ListWebhookEventType godoc
+// @Summary 		List Webhook event types
+// @Description 	List the event types a subscription can filter on, "*" takes all of them
+// @Tags 			Webhooks
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Success 		200 				{array} 		string
+// @Router 			/webhooks/events	 	[get]
+func (h *Handler) ListWebhookEventType(ctx *gin.Context) {
+	SuccessResponse(ctx, h.svc.ListWebhookEventType(ctx))
+}
+
+// GetWebhookSubscription 	godoc
+// @Summary 		Get Webhook subscription
+// @Description 	Get Webhook subscription from Id
+// @Tags 			Webhooks
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Webhook subscription id"
+// @Success 		200 {object} domain.WebhookSubscriptionResponse
+// @Router 			/webhooks/{id} [get]
+func (h *Handler) GetWebhookSubscription(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateWebhookSubscription	godoc
+// @Summary 			Update Webhook subscription
+// @Description 		Update Webhook subscription from Id, a new secret is shown once in the response
+// @Tags 				Webhooks
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 									path 		string 										true 	"Webhook subscription id"
+// @Param 				UpdateWebhookSubscriptionRequest	body 		domain.UpdateWebhookSubscriptionRequest 	true 	"Update Webhook subscription request"
+// @Success 			200 								{object} 	domain.WebhookSubscriptionResponse
+// @Router 				/webhooks/{id} 						[put]
+func (h *Handler) UpdateWebhookSubscription(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.UpdateWebhookSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	data, err := h.svc.UpdateWebhookSubscription(ctx, id, req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, data)
+}
+
+// DeleteWebhookSubscription 	godoc
+// @Summary 			Delete Webhook subscription
+// @Description 		Delete Webhook subscription from Id along with its delivery log
+// @Tags 				Webhooks
+// @Accept  			json
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 					path 		string 						true 	"Webhook subscription id"
+// @Success 			200 				{object} 	domain.WebhookSubscriptionResponse
+// @Router 				/webhooks/{id} 		[delete]
+func (h *Handler) DeleteWebhookSubscription(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ErrorResponse(ctx, http.StatusBadRequest, errors.New("required webhook subscription id"))
+		return
+	}
+	result, err := h.svc.DeleteWebhookSubscription(ctx, id)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// PingWebhookSubscription 	godoc
+// @Summary 			Ping Webhook subscription
+// @Description 		Send a webhook.ping to the subscription right away and return the delivery
+// @Tags 				Webhooks
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 						path 		string 		true 	"Webhook subscription id"
+// @Success 			200 					{object} 	domain.WebhookDeliveryResponse
+// @Router 				/webhooks/{id}/ping 	[post]
+func (h *Handler) PingWebhookSubscription(ctx *gin.Context) {
+	result, err := h.svc.PingWebhookSubscription(ctx, ctx.Param("id"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListWebhookDelivery godoc
+// @Summary 		List Webhook deliveries
+// @Description 	List the webhook delivery log
+// @Tags 			Webhooks
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			subscription_id 					query 		string 		false 	"subscription_id"
+// @Param 			event_type 							query 		string 		false 	"event_type"
+// @Param 			status 								query 		string 		false 	"pending | delivered | failed"
+// @Success 		200 				{array} 		domain.WebhookDeliveryResponse
+// @Router 			/webhook-deliveries	 	[get]
+func (h *Handler) ListWebhookDelivery(ctx *gin.Context) {
+	var req domain.ListWebhookDeliveryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.SortDirection == "" {
+		req.SortDirection = "desc"
+	}
+	req.Prepare()
+	result, count, err := h.svc.ListWebhookDelivery(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, req.Page, req.Size))
+}
+
+// GetWebhookDelivery 	godoc
+// @Summary 		Get Webhook delivery
+// @Description 	Get Webhook delivery from Id
+// @Tags 			Webhooks
+// @Accept  		json
+// @Produce  		json
+// @Security 		ApiKeyAuth
+// @Param 			id path string true "Webhook delivery id"
+// @Success 		200 {object} domain.WebhookDeliveryResponse
+// @Router 			/webhook-deliveries/{id} [get]
+func (h *Handler) GetWebhookDelivery(ctx *gin.Context) {
+	result, err := h.svc.GetWebhookDelivery(ctx, ctx.Param("id"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ReplayWebhookDelivery 	godoc
+// @Summary 			Replay Webhook delivery
+// @Description 		Queue the same payload to the subscription again as a new delivery
+// @Tags 				Webhooks
+// @Produce  			json
+// @Security 			ApiKeyAuth
+// @Param 				id 									path 		string 		true 	"Webhook delivery id"
+// @Success 			200 								{object} 	domain.WebhookDeliveryResponse
+// @Router 				/webhook-deliveries/{id}/replay 	[post]
+func (h *Handler) ReplayWebhookDelivery(ctx *gin.Context) {
+	result, err := h.svc.ReplayWebhookDelivery(ctx, ctx.Param("id"))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,147 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const dialTimeout = 10 * time.Second
+
+// SMTPMailer sends plain text mail through a single SMTP relay
+type SMTPMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     *mail.Address
+	tlsMode  string
+}
+
+func NewSMTPMailer(config config.Config) (*SMTPMailer, error) {
+	from, err := mail.ParseAddress(config.SMTP_FROM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_FROM: %w", err)
+	}
+	switch config.SMTP_TLS {
+	case "none", "starttls", "tls":
+	default:
+		return nil, fmt.Errorf("invalid SMTP_TLS %q expected none, starttls or tls", config.SMTP_TLS)
+	}
+	return &SMTPMailer{
+		addr:     net.JoinHostPort(config.SMTP_HOST, config.SMTP_PORT),
+		host:     config.SMTP_HOST,
+		username: config.SMTP_USERNAME,
+		password: config.SMTP_PASSWORD,
+		from:     from,
+		tlsMode:  config.SMTP_TLS,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(msg *domain.EmailMessage) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return &domain.EmailBounceError{Code: 553, Message: "invalid recipient " + msg.To}
+	}
+	body, err := m.build(to, msg)
+	if err != nil {
+		return err
+	}
+
+	client, err := m.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return classify(err)
+		}
+	}
+	if err := client.Mail(m.from.Address); err != nil {
+		return classify(err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return classify(err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return classify(err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return classify(err)
+	}
+	return client.Quit()
+}
+
+func (m *SMTPMailer) dial() (*smtp.Client, error) {
+	tlsConfig := &tls.Config{ServerName: m.host}
+	if m.tlsMode == "tls" {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", m.addr, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		return smtp.NewClient(conn, m.host)
+	}
+	conn, err := net.DialTimeout("tcp", m.addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if m.tlsMode == "starttls" {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+func (m *SMTPMailer) build(to *mail.Address, msg *domain.EmailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", uuid.NewString(), m.host)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(msg.Body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// classify turns a permanent 5xx reply into a bounce so the delivery is not retried
+func classify(err error) error {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return &domain.EmailBounceError{Code: tpErr.Code, Message: tpErr.Msg}
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const namespace = "lms"
+
+// unmatchedRoute labels requests no route matched, so that probing random paths cannot grow the
+// number of series
+const unmatchedRoute = "unmatched"
+
+// Metrics is the Prometheus registry of the API: Go runtime and process figures, HTTP requests
+// per route, the database pool, background jobs and the circulation gauges
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.HistogramVec
+	jobs     *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to answer HTTP requests by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		jobs: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "job_duration_seconds",
+			Help:      "Time taken by each pass of a background job.",
+			Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300},
+		}, []string{"job"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.jobs,
+	)
+	return m
+}
+
+// RegisterDB exports the connection pool statistics of db
+func (m *Metrics) RegisterDB(db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// RegisterLoans exports the circulation gauges, they are counted when Prometheus scrapes
+func (m *Metrics) RegisterLoans(gauges func(context.Context) (*domain.LoanGauges, error)) {
+	m.registry.MustRegister(&loanCollector{gauges: gauges})
+}
+
+func (m *Metrics) ObserveJob(job string, took time.Duration) {
+	m.jobs.WithLabelValues(job).Observe(took.Seconds())
+}
+
+// Middleware times every request under the route it matched
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.requests.
+			WithLabelValues(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler serves the metrics, a collector that fails leaves its metrics out instead of failing
+// the scrape
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		Registry:      m.registry,
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+var (
+	activeLoansDesc     = prometheus.NewDesc(namespace+"_active_loans", "Books issued and not returned yet.", nil, nil)
+	overdueLoansDesc    = prometheus.NewDesc(namespace+"_overdue_loans", "Issued books past their due date.", nil, nil)
+	pendingRequestsDesc = prometheus.NewDesc(namespace+"_pending_borrow_requests", "Borrow requests waiting to be issued.", nil, nil)
+)
+
+// loanCountTimeout keeps a slow database from holding up the scrape
+const loanCountTimeout = 5 * time.Second
+
+type loanCollector struct {
+	gauges func(context.Context) (*domain.LoanGauges, error)
+}
+
+func (c *loanCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeLoansDesc
+	ch <- overdueLoansDesc
+	ch <- pendingRequestsDesc
+}
+
+func (c *loanCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), loanCountTimeout)
+	defer cancel()
+	gauges, err := c.gauges(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("could not count loans for metrics")
+		ch <- prometheus.NewInvalidMetric(activeLoansDesc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(activeLoansDesc, prometheus.GaugeValue, float64(gauges.ActiveLoans))
+	ch <- prometheus.MustNewConstMetric(overdueLoansDesc, prometheus.GaugeValue, float64(gauges.OverdueLoans))
+	ch <- prometheus.MustNewConstMetric(pendingRequestsDesc, prometheus.GaugeValue, float64(gauges.PendingRequests))
+}
@@ -0,0 +1,66 @@
+package retention
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const day = 24 * time.Hour
+
+// NewPolicy reads how long audit logs and notifications are kept from the configuration
+func NewPolicy(config config.Config) (*domain.RetentionPolicy, error) {
+	policy := &domain.RetentionPolicy{}
+	var err error
+	if policy.AuditDefault, err = parseDays("AUDIT_RETENTION_DAYS", config.AUDIT_RETENTION_DAYS); err != nil {
+		return nil, err
+	}
+	if policy.AuditActions, err = parseRules("AUDIT_RETENTION_ACTIONS", config.AUDIT_RETENTION_ACTIONS); err != nil {
+		return nil, err
+	}
+	if policy.AuditModules, err = parseRules("AUDIT_RETENTION_MODULES", config.AUDIT_RETENTION_MODULES); err != nil {
+		return nil, err
+	}
+	if policy.Notification, err = parseDays("NOTIFICATION_RETENTION_DAYS", config.NOTIFICATION_RETENTION_DAYS); err != nil {
+		return nil, err
+	}
+	if policy.ReadNotification, err = parseDays("NOTIFICATION_READ_RETENTION_DAYS", config.NOTIFICATION_READ_RETENTION_DAYS); err != nil {
+		return nil, err
+	}
+	if policy.Restore, err = parseDays("ARCHIVE_RESTORE_DAYS", config.ARCHIVE_RESTORE_DAYS); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func parseDays(name, value string) (time.Duration, error) {
+	days, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return time.Duration(days) * day, nil
+}
+
+// parseRules reads "name=days" pairs separated by commas
+func parseRules(name, value string) (map[string]time.Duration, error) {
+	rules := map[string]time.Duration{}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		key, days, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid %s entry %q, want name=days", name, pair)
+		}
+		keep, err := parseDays(name, days)
+		if err != nil {
+			return nil, err
+		}
+		rules[strings.TrimSpace(key)] = keep
+	}
+	return rules, nil
+}
@@ -0,0 +1,88 @@
+package sms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// HTTPSender posts messages as JSON to a generic SMS gateway
+//
+//	POST SMS_GATEWAY_URL
+//	Authorization: Bearer SMS_GATEWAY_TOKEN
+//	{"from": "LMS", "to": "+977...", "message": "..."}
+//
+// A 2xx reply is accepted and may carry the gateway's "id" or "message_id", any other 4xx
+// except 429 is a permanent rejection and everything else is retried.
+type HTTPSender struct {
+	url    string
+	token  string
+	from   string
+	client *http.Client
+}
+
+func NewHTTPSender(config config.Config) (*HTTPSender, error) {
+	if config.SMS_GATEWAY_URL == "" {
+		return nil, errors.New("SMS_GATEWAY_URL is required for the http SMS driver")
+	}
+	return &HTTPSender{
+		url:    config.SMS_GATEWAY_URL,
+		token:  config.SMS_GATEWAY_TOKEN,
+		from:   config.SMS_SENDER_ID,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+type gatewayRequest struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+type gatewayResponse struct {
+	ID        string `json:"id"`
+	MessageID string `json:"message_id"`
+}
+
+func (s *HTTPSender) Send(msg *domain.SMSMessage) (*domain.SMSReceipt, error) {
+	payload, err := json.Marshal(gatewayRequest{From: s.from, To: msg.To, Message: msg.Body})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		var result gatewayResponse
+		_ = json.Unmarshal(body, &result)
+		id := result.ID
+		if id == "" {
+			id = result.MessageID
+		}
+		return &domain.SMSReceipt{ProviderID: id}, nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return nil, &domain.SMSRejectedError{Status: resp.StatusCode, Message: string(body)}
+	default:
+		return nil, fmt.Errorf("sms gateway returned %d: %s", resp.StatusCode, body)
+	}
+}
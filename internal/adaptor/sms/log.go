@@ -0,0 +1,42 @@
+package sms
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// LogSender is the local development driver, messages are appended to a file or, without a
+// path, written to the application log
+type LogSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewLogSender(path string) *LogSender {
+	return &LogSender{path: path}
+}
+
+func (s *LogSender) Send(msg *domain.SMSMessage) (*domain.SMSReceipt, error) {
+	id := uuid.NewString()
+	if s.path == "" {
+		logrus.Infof("sms %s to %s: %s", id, msg.To, msg.Body)
+		return &domain.SMSReceipt{ProviderID: id}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "%s\t%s\t%s\t%q\n", time.Now().Format(time.RFC3339), id, msg.To, msg.Body); err != nil {
+		return nil, err
+	}
+	return &domain.SMSReceipt{ProviderID: id}, nil
+}
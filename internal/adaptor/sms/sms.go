@@ -0,0 +1,52 @@
+package sms
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // the image has no zoneinfo
+
+	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
+)
+
+// NewSender returns the SMS driver selected by SMS_DRIVER
+func NewSender(config config.Config) (port.SMSSender, error) {
+	switch config.SMS_DRIVER {
+	case "log":
+		return NewLogSender(config.SMS_LOG_PATH), nil
+	case "http":
+		return NewHTTPSender(config)
+	default:
+		return nil, fmt.Errorf("invalid SMS_DRIVER %q expected log or http", config.SMS_DRIVER)
+	}
+}
+
+// NewPolicy reads routing, rate limit, quiet hours and pricing from the configuration
+func NewPolicy(config config.Config) (*domain.SMSPolicy, error) {
+	policy := &domain.SMSPolicy{}
+	for _, name := range strings.Split(config.SMS_TEMPLATES, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			policy.Templates = append(policy.Templates, name)
+		}
+	}
+	var err error
+	if policy.DailyLimit, err = strconv.Atoi(config.SMS_DAILY_LIMIT); err != nil || policy.DailyLimit < 0 {
+		return nil, fmt.Errorf("invalid SMS_DAILY_LIMIT %q", config.SMS_DAILY_LIMIT)
+	}
+	if policy.CostPerSegment, err = strconv.Atoi(config.SMS_COST_PER_SEGMENT); err != nil || policy.CostPerSegment < 0 {
+		return nil, fmt.Errorf("invalid SMS_COST_PER_SEGMENT %q", config.SMS_COST_PER_SEGMENT)
+	}
+	if policy.QuietStart, err = domain.ParseClock(config.SMS_QUIET_START); err != nil {
+		return nil, fmt.Errorf("SMS_QUIET_START: %w", err)
+	}
+	if policy.QuietEnd, err = domain.ParseClock(config.SMS_QUIET_END); err != nil {
+		return nil, fmt.Errorf("SMS_QUIET_END: %w", err)
+	}
+	if policy.Location, err = time.LoadLocation(config.SMS_TIMEZONE); err != nil {
+		return nil, fmt.Errorf("SMS_TIMEZONE: %w", err)
+	}
+	return policy, nil
+}
@@ -0,0 +1,65 @@
+package statscache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// Memory is a statistics cache local to the process
+type Memory struct {
+	ttl        time.Duration
+	mu         sync.RWMutex
+	entries    map[string]entry
+	generation int64
+}
+
+func NewMemory(ttl time.Duration) *Memory {
+	return &Memory{ttl: ttl, entries: map[string]entry{}}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+func (m *Memory) Generation(_ context.Context) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.generation, nil
+}
+
+func (m *Memory) Set(_ context.Context, generation int64, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if generation != m.generation {
+		return nil
+	}
+	now := time.Now()
+	// expired entries are dropped here, there are only a handful of keys
+	for k, e := range m.entries {
+		if now.After(e.expiresAt) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = entry{value: value, expiresAt: now.Add(m.ttl)}
+	return nil
+}
+
+func (m *Memory) Invalidate(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = map[string]entry{}
+	m.generation++
+	return nil
+}
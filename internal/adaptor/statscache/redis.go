@@ -0,0 +1,66 @@
+package statscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPrefix     = "lms:stats:"
+	generationKey = keyPrefix + "generation"
+)
+
+// Redis keeps statistics under a generation number, invalidating moves every instance of the
+// API to the next generation at once and the values of the old one expire. A value is written
+// under the generation it was computed in, so one computed across an invalidation is never read.
+type Redis struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRedis(client *redis.Client, ttl time.Duration) *Redis {
+	return &Redis{client: client, ttl: ttl}
+}
+
+func (r *Redis) key(generation int64, key string) string {
+	return fmt.Sprintf("%s%d:%s", keyPrefix, generation, key)
+}
+
+func (r *Redis) Generation(ctx context.Context) (int64, error) {
+	generation, err := r.client.Get(ctx, generationKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	return generation, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	generation, err := r.Generation(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	value, err := r.client.Get(ctx, r.key(generation, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, generation int64, key string, value []byte) error {
+	return r.client.Set(ctx, r.key(generation, key), value, r.ttl).Err()
+}
+
+func (r *Redis) Invalidate(ctx context.Context) error {
+	return r.client.Incr(ctx, generationKey).Err()
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
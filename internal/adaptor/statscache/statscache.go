@@ -0,0 +1,88 @@
+package statscache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"github.com/sugaml/lms-api/internal/core/port"
+)
+
+const pingTimeout = 3 * time.Second
+
+// New keeps statistics in Redis at REDIS_ADDR for STATS_CACHE_TTL. Without a reachable Redis, or
+// when REDIS_ADDR is empty, they are kept in memory, and Redis errors later on fall back to memory
+// until it answers again.
+func New(config config.Config) (port.StatsCache, error) {
+	ttl, err := time.ParseDuration(config.STATS_CACHE_TTL)
+	if err != nil {
+		return nil, fmt.Errorf("STATS_CACHE_TTL: %w", err)
+	}
+	memory := NewMemory(ttl)
+	if config.REDIS_ADDR == "" {
+		logrus.Info("REDIS_ADDR is not set, statistics are cached in memory")
+		return memory, nil
+	}
+	client := redis.NewClient(&redis.Options{Addr: config.REDIS_ADDR, Password: config.REDIS_PASSWORD})
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		logrus.WithError(err).Warnf("Redis at %s is not reachable, statistics are cached in memory until it is", config.REDIS_ADDR)
+	}
+	return &fallback{primary: NewRedis(client, ttl), secondary: memory}, nil
+}
+
+// fallback reads and writes the primary cache and the secondary one when the primary fails.
+// Invalidation goes to both so that neither serves stale values once the primary is back. The
+// generations of the secondary are handed out as negative numbers, a value is only written to the
+// cache whose generation it was computed in.
+type fallback struct {
+	primary   port.StatsCache
+	secondary port.StatsCache
+}
+
+func (f *fallback) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, ok, err := f.primary.Get(ctx, key)
+	if err != nil {
+		logrus.WithError(err).Debug("statistics cache is unavailable, using memory")
+		return f.secondary.Get(ctx, key)
+	}
+	return value, ok, nil
+}
+
+func (f *fallback) Generation(ctx context.Context) (int64, error) {
+	generation, err := f.primary.Generation(ctx)
+	if err != nil {
+		logrus.WithError(err).Debug("statistics cache is unavailable, using memory")
+		generation, err = f.secondary.Generation(ctx)
+		return -generation - 1, err
+	}
+	return generation, nil
+}
+
+func (f *fallback) Set(ctx context.Context, generation int64, key string, value []byte) error {
+	if generation < 0 {
+		return f.secondary.Set(ctx, -generation-1, key, value)
+	}
+	return f.primary.Set(ctx, generation, key, value)
+}
+
+func (f *fallback) Invalidate(ctx context.Context) error {
+	err := f.primary.Invalidate(ctx)
+	if err := f.secondary.Invalidate(ctx); err != nil {
+		return err
+	}
+	return err
+}
+
+// Close closes the Redis connections, the memory cache has nothing to close
+func (f *fallback) Close() error {
+	if closer, ok := f.primary.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
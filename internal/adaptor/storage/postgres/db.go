@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -29,6 +31,13 @@ func NewDB(config config.Config) (*gorm.DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	// a span for every statement under the request that made it
+	if err := db.Use(tracingPlugin{}); err != nil {
+		return nil, err
+	}
+	if err := db.Use(errorsPlugin{}); err != nil {
+		return nil, err
+	}
 	if config.DB_DEBUG == "true" {
 		db = db.Debug()
 	}
@@ -38,6 +47,9 @@ func NewDB(config config.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 	if config.DB_AUTO_MIGRATE != "false" {
+		if err := migrateLegacyForeignKeys(db); err != nil {
+			return nil, err
+		}
 		err = db.AutoMigrate(
 			&domain.User{},
 			&domain.Role{},
@@ -48,14 +60,28 @@ func NewDB(config config.Config) (*gorm.DB, error) {
 			&domain.TimeSlot{},
 			&domain.Subject{},
 			&domain.Faculty{},
+			&domain.Campus{},
 			&domain.Building{},
 			&domain.Floor{},
 			&domain.Room{},
 			&domain.ClassRoutine{},
+			&domain.TeacherUnavailability{},
+			&domain.AcademicYear{},
+			&domain.CalendarToken{},
+			&domain.TimetableDraft{},
+			&domain.TimetableDraftEntry{},
+			&domain.EmailDelivery{},
+			&domain.SMSDelivery{},
+			&domain.WebhookSubscription{},
+			&domain.WebhookDelivery{},
+			&domain.NotificationPreference{},
+			&domain.NotificationDispatch{},
 			&domain.StudentProfile{},
 			&domain.TeacherProfile{},
 			&domain.StaffProfile{},
 			&domain.AuditLog{},
+			&domain.AuditCheckpoint{},
+			&domain.Archive{},
 			&domain.Book{},
 			&domain.BookCopy{},
 			&domain.Fine{},
@@ -63,10 +89,24 @@ func NewDB(config config.Config) (*gorm.DB, error) {
 			&domain.Category{},
 			&domain.Program{},
 			&domain.Notification{},
+			&domain.NotificationRead{},
+			&domain.Announcement{},
+			&domain.AnnouncementAttachment{},
+			&domain.AnnouncementReceipt{},
 		)
 		if err != nil {
 			return nil, err
 		}
+		if err := migrateNotificationReads(db); err != nil {
+			return nil, err
+		}
+		if err := migrateAuditChain(db); err != nil {
+			return nil, err
+		}
+		// receipt numbers of fine payments, see PayFine
+		if err := db.Exec("CREATE SEQUENCE IF NOT EXISTS fine_receipt_seq").Error; err != nil {
+			return nil, err
+		}
 		// db.Raw("CREATE EXTENSION IF NOT EXISTS pg_trgm;")
 	}
 	db.Migrator().CreateConstraint(&domain.ClassRoutine{}, "unique_room_time")
@@ -93,3 +133,181 @@ func NewDB(config config.Config) (*gorm.DB, error) {
 	logrus.Infof("Successfully connected to the database :: %s", dbName)
 	return db, nil
 }
+
+// migrationLock is held by the migrations that rewrite rows, so replicas that boot together run
+// them one after the other and the later ones find nothing left to do
+const migrationLock = 7_420_000
+
+// legacyForeignKeys are references the baseline schema declared as uint, so AutoMigrate made them
+// bigint, while the ids they point at are uuids. Postgres cannot cast bigint to uuid, so a uuid
+// column is added next to the old one instead of altering it.
+var legacyForeignKeys = []struct {
+	table  string
+	column string
+}{
+	{"semesters", "program_id"},
+	{"subjects", "program_id"},
+	{"buildings", "campus_id"},
+	{"floors", "building_id"},
+	{"rooms", "floor_id"},
+	{"class_routines", "faculty_id"},
+	{"class_routines", "program_id"},
+	{"class_routines", "semester_id"},
+	{"class_routines", "subject_id"},
+	{"class_routines", "teacher_id"},
+	{"class_routines", "room_id"},
+	{"class_routines", "time_slot_id"},
+}
+
+// migrateLegacyForeignKeys turns the bigint references into uuid ones ahead of AutoMigrate. The old
+// column is kept as <column>_legacy and the new one starts out null: every id these tables point at
+// has been a uuid from the start, so no bigint maps onto one. Rows left with a reference to map by
+// hand are logged, nothing is deleted. It does nothing once the columns are uuid.
+func migrateLegacyForeignKeys(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLock).Error; err != nil {
+			return err
+		}
+		for _, fk := range legacyForeignKeys {
+			var dataType string
+			err := tx.Raw(`
+				SELECT data_type FROM information_schema.columns
+				WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`,
+				fk.table, fk.column).Scan(&dataType).Error
+			if err != nil {
+				return err
+			}
+			if dataType == "" || dataType == "uuid" {
+				continue
+			}
+			legacy := fk.column + "_legacy"
+			// indexes over the old column would stop the ones over the new column being created
+			var indexes []string
+			err = tx.Raw(`
+				SELECT i.relname FROM pg_index x
+				JOIN pg_class i ON i.oid = x.indexrelid
+				JOIN pg_attribute a ON a.attrelid = x.indrelid AND a.attnum = ANY(x.indkey)
+				WHERE x.indrelid = ?::regclass AND a.attname = ?`,
+				fk.table, fk.column).Scan(&indexes).Error
+			if err != nil {
+				return err
+			}
+			for _, index := range indexes {
+				if err := tx.Exec(fmt.Sprintf("DROP INDEX IF EXISTS %q", index)).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Migrator().RenameColumn(fk.table, fk.column, legacy); err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s uuid", fk.table, fk.column)).Error; err != nil {
+				return err
+			}
+			var unmapped int64
+			err = tx.Table(fk.table).Where(fmt.Sprintf("%s IS NOT NULL AND %s <> 0", legacy, legacy)).Count(&unmapped).Error
+			if err != nil {
+				return err
+			}
+			logrus.Infof("migrated %s.%s from %s to uuid, the old values are kept in %s", fk.table, fk.column, dataType, legacy)
+			if unmapped > 0 {
+				logrus.Warnf("%d %s have no %s, their old one in %s has to be mapped by hand", unmapped, fk.table, fk.column, legacy)
+			}
+		}
+		return nil
+	})
+}
+
+// migrateNotificationReads moves the read flag notifications used to carry into per-recipient
+// read marks, it does nothing once the old column is gone
+func migrateNotificationReads(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("notifications", "is_read") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO notification_reads (notification_id, user_id, read_at)
+			SELECT id, user_id, updated_at FROM notifications
+			WHERE is_read AND user_id <> ''
+			ON CONFLICT DO NOTHING`).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn("notifications", "is_read")
+	})
+}
+
+// auditChainLock is the lock repository.CreateAuditLog appends under
+const auditChainLock = 7_420_001
+
+// migrateAuditChain stops the audit log from being changed or emptied other than by archival,
+// then links the entries written without a sequence onto the end of the hash chain in the order
+// they were made. Those are the ones from before the chain and, during a rolling deploy, the ones
+// replicas still on the old version append, so the trigger is already there and lets only this
+// transaction update them.
+func migrateAuditChain(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+			return err
+		}
+		err := tx.Exec(`
+			CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+			BEGIN
+				-- the archival job deletes the entries it has written to an archive file
+				IF TG_OP = 'DELETE' AND current_setting('lms.audit_archive', true) = 'on' THEN
+					RETURN OLD;
+				END IF;
+				-- migrateAuditChain links the entries written without a sequence
+				IF TG_OP = 'UPDATE' AND current_setting('lms.audit_relink', true) = 'on' THEN
+					RETURN NEW;
+				END IF;
+				RAISE EXCEPTION 'audit_logs is append-only, % is not allowed', TG_OP;
+			END;
+			$$ LANGUAGE plpgsql;
+
+			DROP TRIGGER IF EXISTS audit_logs_no_change ON audit_logs;
+			CREATE TRIGGER audit_logs_no_change BEFORE UPDATE OR DELETE ON audit_logs
+				FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+
+			DROP TRIGGER IF EXISTS audit_logs_no_truncate ON audit_logs;
+			CREATE TRIGGER audit_logs_no_truncate BEFORE TRUNCATE ON audit_logs
+				FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
+		`).Error
+		if err != nil {
+			return err
+		}
+		var legacy []*domain.AuditLog
+		if err := tx.Where("sequence IS NULL").Order("created_at asc, id asc").Find(&legacy).Error; err != nil {
+			return err
+		}
+		if len(legacy) == 0 {
+			return nil
+		}
+		if err := tx.Exec("SET LOCAL lms.audit_relink = 'on'").Error; err != nil {
+			return err
+		}
+		var last domain.AuditLog
+		err = tx.Where("sequence IS NOT NULL").Order("sequence desc").Take(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		prev := &last
+		for _, data := range legacy {
+			data.Sequence = prev.Sequence + 1
+			data.PrevHash = prev.Hash
+			data.CreatedAt = domain.AuditTime(data.CreatedAt)
+			data.Hash = data.ComputeHash()
+			err := tx.Model(&domain.AuditLog{}).Where("id = ?", data.ID).UpdateColumns(map[string]interface{}{
+				"sequence":   data.Sequence,
+				"prev_hash":  data.PrevHash,
+				"hash":       data.Hash,
+				"created_at": data.CreatedAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+			prev = data
+		}
+		logrus.Infof("linked %d audit log entries into the hash chain", len(legacy))
+		return nil
+	})
+}
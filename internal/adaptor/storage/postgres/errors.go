@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// pgErrors are the PostgreSQL error codes a client can cause, with what they are told. Any other
+// database error is internal, its message is logged but never sent.
+var pgErrors = map[string]struct {
+	kind          domain.ErrorKind
+	code, message string
+}{
+	"23505": {domain.ErrorConflict, "already_exists", "a record with the same values already exists"},
+	"23503": {domain.ErrorConflict, "reference_violation", "the record refers to a record that does not exist or is still in use"},
+	"23502": {domain.ErrorValidation, "missing_value", "a required value is missing"},
+	"23514": {domain.ErrorValidation, "invalid_value", "a value is out of the allowed range"},
+	"22P02": {domain.ErrorValidation, "invalid_value", "a value is not in the expected format"},
+	"22001": {domain.ErrorValidation, "value_too_long", "a value is too long"},
+}
+
+// errorsPlugin turns the errors of every statement into domain errors so that handlers answer
+// them with the right status and raw database messages never reach the client. The original
+// error stays in the chain for errors.Is and errors.As.
+type errorsPlugin struct{}
+
+func (errorsPlugin) Name() string {
+	return "errors"
+}
+
+func (p errorsPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, register := range []func(string, func(*gorm.DB)) error{
+		cb.Create().After("*").Register,
+		cb.Query().After("*").Register,
+		cb.Update().After("*").Register,
+		cb.Delete().After("*").Register,
+		cb.Row().After("*").Register,
+		cb.Raw().After("*").Register,
+	} {
+		if err := register("errors:translate", translateError); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func translateError(tx *gorm.DB) {
+	err := tx.Error
+	if err == nil {
+		return
+	}
+	if _, ok := domain.AsError(err); ok {
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Error = &domain.Error{Kind: domain.ErrorNotFound, Code: "not_found", Message: "record not found", Cause: err}
+		return
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if known, ok := pgErrors[pgErr.Code]; ok {
+			tx.Error = &domain.Error{Kind: known.kind, Code: known.code, Message: known.message, Cause: err}
+			return
+		}
+	}
+	tx.Error = domain.NewInternalError(err)
+}
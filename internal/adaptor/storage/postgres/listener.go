@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const (
+	listenRetryMin = time.Second
+	listenRetryMax = 30 * time.Second
+	// subscriberBuffer signals are only wake ups, a full buffer already has one pending
+	subscriberBuffer = 1
+)
+
+// NotificationHub keeps a dedicated connection LISTENing on domain.NotificationChannel and hands
+// each signal to the streams of the user it concerns on this replica
+type NotificationHub struct {
+	source      string
+	mu          sync.Mutex
+	subscribers map[string]map[chan *domain.NotificationSignal]struct{}
+}
+
+func NewNotificationHub(source string) *NotificationHub {
+	return &NotificationHub{
+		source:      source,
+		subscribers: map[string]map[chan *domain.NotificationSignal]struct{}{},
+	}
+}
+
+// Subscribe returns the signals for a user and a function that stops them
+func (h *NotificationHub) Subscribe(userID string) (<-chan *domain.NotificationSignal, func()) {
+	ch := make(chan *domain.NotificationSignal, subscriberBuffer)
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = map[chan *domain.NotificationSignal]struct{}{}
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers[userID], ch)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+func (h *NotificationHub) publish(signal *domain.NotificationSignal) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for userID, chans := range h.subscribers {
+		if signal.UserID != "" && signal.UserID != userID {
+			continue
+		}
+		for ch := range chans {
+			select {
+			case ch <- signal:
+			default:
+			}
+		}
+	}
+}
+
+// Listen keeps the LISTEN connection open until ctx is done, reconnecting with backoff. After a
+// reconnect every stream is told to resync since signals may have been missed meanwhile.
+func (h *NotificationHub) Listen(ctx context.Context) {
+	retry := listenRetryMin
+	connected := false
+	for ctx.Err() == nil {
+		err := h.listen(ctx, func() {
+			if connected {
+				h.publish(&domain.NotificationSignal{Op: "resync"})
+			}
+			connected = true
+			retry = listenRetryMin
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		logrus.WithError(err).Warnf("notification listener disconnected, retrying in %s", retry)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		retry = min(retry*2, listenRetryMax)
+	}
+}
+
+func (h *NotificationHub) listen(ctx context.Context, onListen func()) error {
+	conn, err := pgx.Connect(ctx, h.source)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{domain.NotificationChannel}.Sanitize()); err != nil {
+		return err
+	}
+	onListen()
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var signal domain.NotificationSignal
+		if err := json.Unmarshal([]byte(n.Payload), &signal); err != nil {
+			logrus.WithError(err).Warnf("invalid notification signal %q", n.Payload)
+			continue
+		}
+		h.publish(&signal)
+	}
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// GetTeacherWorkload aggregates weekly classes and contact hours per teacher, and the credit
+// hours of every distinct subject, semester and section they teach
+func (r *Repository) GetTeacherWorkload(ctx context.Context, req *domain.AcademicReportRequest) ([]domain.TeacherWorkload, error) {
+	var results []domain.TeacherWorkload
+	err := r.db.WithContext(ctx).Raw(`
+		WITH routines AS (
+			SELECT * FROM class_routines
+			WHERE @year = '' OR academic_year = @year
+		),
+		contact AS (
+			SELECT
+				r.teacher_id,
+				COUNT(*) AS classes,
+				SUM(EXTRACT(EPOCH FROM (ts.end_time - ts.start_time)) / 3600) AS contact_hours
+			FROM routines r
+			JOIN time_slots ts ON ts.id = r.time_slot_id
+			GROUP BY r.teacher_id
+		),
+		courses AS (
+			SELECT
+				c.teacher_id,
+				COUNT(*) AS courses,
+				SUM(s.credit_hours) AS credit_hours
+			FROM (SELECT DISTINCT teacher_id, subject_id, semester_id, section FROM routines) c
+			JOIN subjects s ON s.id = c.subject_id
+			GROUP BY c.teacher_id
+		)
+		SELECT
+			u.id AS teacher_id,
+			u.full_name AS teacher_name,
+			contact.classes,
+			ROUND(contact.contact_hours::numeric, 2) AS contact_hours,
+			COALESCE(courses.courses, 0) AS courses,
+			COALESCE(courses.credit_hours, 0) AS credit_hours
+		FROM contact
+		JOIN users u ON u.id = contact.teacher_id
+		LEFT JOIN courses ON courses.teacher_id = contact.teacher_id
+		ORDER BY contact.contact_hours DESC, u.full_name
+	`, map[string]interface{}{"year": req.AcademicYear}).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetRoomUtilization counts the routines booked in every active room, available slots are
+// the teaching days times the defined time slots
+func (r *Repository) GetRoomUtilization(ctx context.Context, req *domain.AcademicReportRequest) ([]domain.RoomUtilization, error) {
+	var slots int64
+	if err := r.db.WithContext(ctx).Model(&domain.TimeSlot{}).Count(&slots).Error; err != nil {
+		return nil, err
+	}
+	var results []domain.RoomUtilization
+	err := r.db.WithContext(ctx).Raw(`
+		WITH occupied AS (
+			SELECT room_id, COUNT(*) AS occupied
+			FROM class_routines
+			WHERE @year = '' OR academic_year = @year
+			GROUP BY room_id
+		)
+		SELECT
+			rm.id AS room_id,
+			rm.room_code,
+			rm.room_type,
+			rm.capacity,
+			b.id AS building_id,
+			b.name AS building_name,
+			COALESCE(o.occupied, 0) AS occupied_slots
+		FROM rooms rm
+		JOIN floors f ON f.id = rm.floor_id
+		JOIN buildings b ON b.id = f.building_id
+		LEFT JOIN occupied o ON o.room_id = rm.id
+		WHERE rm.status = 'ACTIVE'
+		ORDER BY b.name, rm.room_code
+	`, map[string]interface{}{"year": req.AcademicYear}).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	available := slots * int64(len(domain.Days))
+	for i := range results {
+		results[i].AvailableSlots = available
+		if available > 0 {
+			results[i].Utilization = domain.Percent(results[i].OccupiedSlots, available)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// announcementStatus filters on the status shown to clients, which accounts for expiry
+func announcementStatus(status string, now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch status {
+		case domain.AnnouncementExpired:
+			return db.Where("announcements.expire_at <= ?", now)
+		case domain.AnnouncementScheduled, domain.AnnouncementPublished:
+			return db.Where("announcements.status = ? AND (announcements.expire_at IS NULL OR announcements.expire_at > ?)", status, now)
+		}
+		return db
+	}
+}
+
+// announcementRecipients selects the ids of the active users an announcement is addressed to
+func announcementRecipients(db *gorm.DB, data *domain.Announcement) *gorm.DB {
+	f := db.Model(&domain.User{}).Where("users.is_active")
+	switch data.Audience {
+	case domain.AnnouncementRole:
+		f = f.Where("users.id IN (?)", db.Table("user_roles").
+			Select("user_roles.user_id").
+			Joins("JOIN roles ON roles.id = user_roles.role_id").
+			Where("LOWER(roles.name) = ?", data.Role))
+	case domain.AnnouncementProgram:
+		f = f.Where("users.program_id = ?", data.ProgramID)
+	case domain.AnnouncementBatch:
+		f = f.Where("users.batch = ?", data.Batch)
+		if data.ProgramID != "" {
+			f = f.Where("users.program_id = ?", data.ProgramID)
+		}
+		if data.Section != "" {
+			f = f.Where("users.section = ?", data.Section)
+		}
+	case domain.AnnouncementUsers:
+		f = f.Where("users.id::text IN ?", data.UserIDs)
+	}
+	return f
+}
+
+func (r *Repository) CreateAnnouncement(ctx context.Context, data *domain.Announcement) (*domain.Announcement, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Announcement{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListAnnouncement(ctx context.Context, req *domain.ListAnnouncementRequest) ([]*domain.Announcement, int64, error) {
+	var datas []*domain.Announcement
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Announcement{}).Scopes(announcementStatus(req.Status, time.Now()))
+	if req.Query != "" {
+		f = f.Where("title ILIKE ?", "%"+req.Query+"%")
+	}
+	if req.Audience != "" {
+		f = f.Where("audience = ?", req.Audience)
+	}
+	if req.IsPinned != nil {
+		f = f.Where("is_pinned = ?", *req.IsPinned)
+	}
+	err := f.Count(&count).
+		Preload("Attachments").
+		Order("is_pinned desc, " + req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) ListAnnouncementFeed(ctx context.Context, userID string, req *domain.ListAnnouncementRequest) ([]*domain.Announcement, int64, error) {
+	var datas []*domain.Announcement
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Announcement{}).
+		Joins("JOIN announcement_receipts ON announcement_receipts.announcement_id = announcements.id AND announcement_receipts.user_id = ?", userID).
+		Joins("LEFT JOIN notification_reads ON notification_reads.notification_id = announcement_receipts.notification_id AND notification_reads.user_id = announcement_receipts.user_id").
+		Scopes(announcementStatus(domain.AnnouncementPublished, time.Now()))
+	if req.Query != "" {
+		f = f.Where("announcements.title ILIKE ?", "%"+req.Query+"%")
+	}
+	if req.IsPinned != nil {
+		f = f.Where("announcements.is_pinned = ?", *req.IsPinned)
+	}
+	err := f.Count(&count).
+		Select("announcements.*, notification_reads.user_id IS NOT NULL AS is_read").
+		Preload("Attachments").
+		Order("announcements.is_pinned desc, announcements." + req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetAnnouncement(ctx context.Context, id string) (*domain.Announcement, error) {
+	var data domain.Announcement
+	if err := r.db.WithContext(ctx).Model(&domain.Announcement{}).
+		Preload("Attachments").
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateAnnouncement(ctx context.Context, id string, req domain.Map) (*domain.Announcement, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required announcement id")
+	}
+	data := &domain.Announcement{}
+	err := r.db.WithContext(ctx).Model(&domain.Announcement{}).Where("id = ?", id).Updates(req.ToMap()).Preload("Attachments").Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// DeleteAnnouncement removes the announcement together with the notifications it was
+// published as
+func (r *Repository) DeleteAnnouncement(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		notifications := tx.Model(&domain.AnnouncementReceipt{}).
+			Distinct("notification_id").
+			Where("announcement_id = ?", id)
+		if err := tx.Where("notification_id IN (?)", notifications).Delete(&domain.NotificationRead{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN (?)", notifications).Delete(&domain.Notification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("announcement_id = ?", id).Delete(&domain.AnnouncementReceipt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("announcement_id = ?", id).Delete(&domain.AnnouncementAttachment{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Announcement{}).Where("id = ?", id).Delete(&domain.Announcement{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	r.publishNotification(ctx, "", "deleted")
+	return nil
+}
+
+func (r *Repository) ListDueAnnouncementIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	var ids []string
+	if err := r.db.WithContext(ctx).Model(&domain.Announcement{}).
+		Where("status = ? AND publish_at <= ?", domain.AnnouncementScheduled, now).
+		Order("publish_at asc").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// PublishAnnouncement locks the announcement so only one replica publishes it, role and all
+// audiences share one notification while the others get a notification each
+func (r *Repository) PublishAnnouncement(ctx context.Context, id string, now time.Time) (*domain.Announcement, int64, error) {
+	var data domain.Announcement
+	var recipients int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.Announcement{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Take(&data, "id = ? AND status = ?", id, domain.AnnouncementScheduled).Error
+		if err != nil {
+			return err
+		}
+		users := announcementRecipients(tx, &data)
+		var result *gorm.DB
+		switch data.Audience {
+		case domain.AnnouncementAll, domain.AnnouncementRole:
+			notification := &domain.Notification{
+				Audience:    data.Audience,
+				Role:        data.Role,
+				Title:       data.Title,
+				Description: data.Body,
+				Module:      "announcement",
+				Action:      "create",
+				Type:        "announcement",
+				IsActive:    true,
+			}
+			if err := tx.Model(&domain.Notification{}).Create(notification).Error; err != nil {
+				return err
+			}
+			data.NotificationID = notification.ID
+			result = tx.Exec("INSERT INTO announcement_receipts (announcement_id, user_id, notification_id, delivered_at) ? ON CONFLICT DO NOTHING",
+				users.Select("?::uuid, users.id::text, ?::uuid, ?::timestamptz", data.ID, notification.ID, now))
+		default:
+			notifications := users.Select("?, users.id::text, ?, ?, 'announcement', 'create', 'announcement', true, ?::timestamptz, ?::timestamptz",
+				domain.AudienceUser, data.Title, data.Body, now, now)
+			result = tx.Exec(`
+				WITH sent AS (
+					INSERT INTO notifications (audience, user_id, title, description, module, action, type, is_active, created_at, updated_at) ?
+					RETURNING id, user_id
+				)
+				INSERT INTO announcement_receipts (announcement_id, user_id, notification_id, delivered_at)
+				SELECT ?::uuid, sent.user_id, sent.id, ?::timestamptz FROM sent
+				ON CONFLICT DO NOTHING`, notifications, data.ID, now)
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		recipients = result.RowsAffected
+		data.Status = domain.AnnouncementPublished
+		data.PublishedAt = &now
+		return tx.Model(&domain.Announcement{}).Where("id = ?", data.ID).Updates(domain.Map{
+			"status":          data.Status,
+			"published_at":    now,
+			"notification_id": data.NotificationID,
+		}.ToMap()).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	r.publishNotification(ctx, "", "created")
+	return &data, recipients, nil
+}
+
+func (r *Repository) GetAnnouncementReceipt(ctx context.Context, announcementID, userID string) (*domain.AnnouncementReceipt, error) {
+	var data domain.AnnouncementReceipt
+	if err := r.db.WithContext(ctx).Model(&domain.AnnouncementReceipt{}).
+		Take(&data, "announcement_id = ? AND user_id = ?", announcementID, userID).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) ListAnnouncementReceipt(ctx context.Context, announcementID string, req *domain.ListAnnouncementReceiptRequest) ([]*domain.AnnouncementReceiptResponse, int64, error) {
+	var datas []*domain.AnnouncementReceiptResponse
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.AnnouncementReceipt{}).
+		Joins("JOIN users ON users.id::text = announcement_receipts.user_id").
+		Joins("LEFT JOIN notification_reads ON notification_reads.notification_id = announcement_receipts.notification_id AND notification_reads.user_id = announcement_receipts.user_id").
+		Where("announcement_receipts.announcement_id = ?", announcementID)
+	if req.Query != "" {
+		f = f.Where("users.full_name ILIKE ?", "%"+req.Query+"%")
+	}
+	if req.IsRead != nil {
+		if *req.IsRead {
+			f = f.Where("notification_reads.user_id IS NOT NULL")
+		} else {
+			f = f.Where("notification_reads.user_id IS NULL")
+		}
+	}
+	err := f.Count(&count).
+		Select("announcement_receipts.user_id, users.full_name, users.username, announcement_receipts.delivered_at, notification_reads.read_at").
+		Order("users.full_name asc").
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Scan(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) SummarizeAnnouncementReceipt(ctx context.Context, announcementID string) (*domain.AnnouncementReceiptSummary, error) {
+	var data domain.AnnouncementReceiptSummary
+	if err := r.db.WithContext(ctx).Model(&domain.AnnouncementReceipt{}).
+		Joins("LEFT JOIN notification_reads ON notification_reads.notification_id = announcement_receipts.notification_id AND notification_reads.user_id = announcement_receipts.user_id").
+		Where("announcement_receipts.announcement_id = ?", announcementID).
+		Select("COUNT(*) AS recipients, COUNT(notification_reads.user_id) AS read").
+		Scan(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) CreateAnnouncementAttachment(ctx context.Context, data *domain.AnnouncementAttachment) (*domain.AnnouncementAttachment, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.AnnouncementAttachment{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) GetAnnouncementAttachment(ctx context.Context, id string) (*domain.AnnouncementAttachment, error) {
+	var data domain.AnnouncementAttachment
+	if err := r.db.WithContext(ctx).Model(&domain.AnnouncementAttachment{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) DeleteAnnouncementAttachment(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.AnnouncementAttachment{}).Where("id = ?", id).Delete(&domain.AnnouncementAttachment{}).Error
+}
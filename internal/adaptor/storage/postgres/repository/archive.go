@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// archivalLock is held through an archival pass so replicas never archive the same rows
+const archivalLock = 7_420_003
+
+func (r *Repository) WithArchivalLock(ctx context.Context, fn func()) (bool, error) {
+	var locked bool
+	// a session lock is released by the connection that took it, so one is kept for the pass
+	err := r.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", archivalLock).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+		fn()
+		// the pass may have ended because ctx was cancelled, the lock is still given back
+		return conn.WithContext(context.WithoutCancel(ctx)).Exec("SELECT pg_advisory_unlock(?)", archivalLock).Error
+	})
+	return locked, err
+}
+
+func (r *Repository) ListAuditLogForArchive(ctx context.Context, scope *domain.AuditArchiveScope, now time.Time, limit int) ([]*domain.AuditLog, error) {
+	var datas []*domain.AuditLog
+	f := r.db.WithContext(ctx).Model(&domain.AuditLog{}).
+		Where("created_at < ?", scope.Before).
+		Where("retain_until IS NULL OR retain_until < ?", now).
+		// the head stays, the next entry is chained onto it
+		Where("sequence < (SELECT max(sequence) FROM audit_logs)")
+	if scope.Action != "" {
+		f = f.Where("action = ?", scope.Action)
+	}
+	if scope.Module != "" {
+		f = f.Where("entity_type = ?", scope.Module)
+	}
+	if len(scope.ExceptActions) > 0 {
+		f = f.Where("action NOT IN ?", scope.ExceptActions)
+	}
+	if len(scope.ExceptModules) > 0 {
+		f = f.Where("entity_type NOT IN ?", scope.ExceptModules)
+	}
+	if err := f.Order("sequence asc").Limit(limit).Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+// ArchiveAuditLogs deletes under lms.audit_archive, the one setting the append-only trigger lets
+// a delete through with
+func (r *Repository) ArchiveAuditLogs(ctx context.Context, data *domain.Archive, ids []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL lms.audit_archive = 'on'").Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN ?", ids).Delete(&domain.AuditLog{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(ids)) {
+			return fmt.Errorf("%d of %d audit log entries were already archived", int64(len(ids))-result.RowsAffected, len(ids))
+		}
+		return tx.Model(&domain.Archive{}).Create(&data).Error
+	})
+}
+
+func (r *Repository) ListNotificationForArchive(ctx context.Context, before time.Time, limit int) ([]*domain.ArchivedNotification, error) {
+	var notifications []*domain.Notification
+	if err := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Where("created_at < ?", before).
+		Order("created_at asc").
+		Limit(limit).
+		Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	if len(notifications) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.ID
+	}
+	var reads []domain.NotificationRead
+	if err := r.db.WithContext(ctx).Model(&domain.NotificationRead{}).Where("notification_id IN ?", ids).Find(&reads).Error; err != nil {
+		return nil, err
+	}
+	byNotification := map[string][]domain.NotificationRead{}
+	for _, read := range reads {
+		byNotification[read.NotificationID] = append(byNotification[read.NotificationID], read)
+	}
+	datas := make([]*domain.ArchivedNotification, len(notifications))
+	for i, notification := range notifications {
+		datas[i] = &domain.ArchivedNotification{Notification: *notification, Reads: byNotification[notification.ID]}
+	}
+	return datas, nil
+}
+
+func (r *Repository) ArchiveNotifications(ctx context.Context, data *domain.Archive, ids []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("notification_id IN ?", ids).Delete(&domain.NotificationRead{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN ?", ids).Delete(&domain.Notification{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(ids)) {
+			return fmt.Errorf("%d of %d notifications were already archived", int64(len(ids))-result.RowsAffected, len(ids))
+		}
+		return tx.Model(&domain.Archive{}).Create(&data).Error
+	})
+}
+
+func (r *Repository) PurgeReadNotifications(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		err := tx.Model(&domain.NotificationRead{}).
+			Joins("JOIN notifications ON notifications.id = notification_reads.notification_id").
+			Where("notifications.audience = ? AND notification_reads.user_id = notifications.user_id", domain.AudienceUser).
+			Where("notification_reads.read_at < ?", before).
+			Pluck("notification_reads.notification_id", &ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := tx.Where("notification_id IN ?", ids).Delete(&domain.NotificationRead{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN ?", ids).Delete(&domain.Notification{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+func (r *Repository) ListArchive(ctx context.Context, req *domain.ListArchiveRequest) ([]*domain.Archive, int64, error) {
+	var datas []*domain.Archive
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Archive{})
+	if req.Kind != "" {
+		f = f.Where("kind = ?", req.Kind)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetArchive(ctx context.Context, id string) (*domain.Archive, error) {
+	var data domain.Archive
+	if err := r.db.WithContext(ctx).Model(&domain.Archive{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) ListArchivedAuditSequences(ctx context.Context) (domain.ArchivedSequences, error) {
+	var datas []*domain.Archive
+	if err := r.db.WithContext(ctx).Model(&domain.Archive{}).
+		Select("sequences").
+		Where("kind = ?", domain.ArchiveAuditLog).
+		Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	var ranges [][2]int64
+	for _, data := range datas {
+		ranges = append(ranges, data.Sequences...)
+	}
+	return domain.NewArchivedSequences(ranges), nil
+}
+
+func (r *Repository) RestoreAuditLogs(ctx context.Context, archive *domain.Archive, datas []*domain.AuditLog, restoredBy string) (int64, error) {
+	var restored int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(datas) > 0 {
+			result := tx.Model(&domain.AuditLog{}).
+				Clauses(clause.OnConflict{DoNothing: true}).
+				CreateInBatches(&datas, 500)
+			if result.Error != nil {
+				return result.Error
+			}
+			restored = result.RowsAffected
+		}
+		now := time.Now()
+		archive.RestoredAt, archive.RestoredBy = &now, restoredBy
+		return tx.Model(&domain.Archive{}).Where("id = ?", archive.ID).Updates(domain.Map{
+			"restored_at": now,
+			"restored_by": restoredBy,
+		}.ToMap()).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return restored, nil
+}
@@ -1,22 +1,45 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
 )
 
-func (r *Repository) CreateAuditLog(data *domain.AuditLog) (*domain.AuditLog, error) {
-	if err := r.db.Model(&domain.AuditLog{}).Create(&data).Error; err != nil {
+// auditChainLock serialises appends so every entry links to the one before it
+const auditChainLock = 7_420_001
+
+func (r *Repository) CreateAuditLog(ctx context.Context, data *domain.AuditLog) (*domain.AuditLog, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+			return err
+		}
+		var last domain.AuditLog
+		err := tx.Model(&domain.AuditLog{}).Order("sequence desc").Take(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		data.ID = uuid.NewString()
+		data.Sequence = last.Sequence + 1
+		data.PrevHash = last.Hash
+		data.CreatedAt = domain.AuditTime(time.Now())
+		data.Hash = data.ComputeHash()
+		return tx.Model(&domain.AuditLog{}).Create(&data).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *Repository) ListAuditLog(req *domain.ListAuditLogRequest) ([]*domain.AuditLog, int64, error) {
+func (r *Repository) ListAuditLog(ctx context.Context, req *domain.ListAuditLogRequest) ([]*domain.AuditLog, int64, error) {
 	var datas []*domain.AuditLog
 	var count int64
-	f := r.db.Model(&domain.AuditLog{})
+	f := r.db.WithContext(ctx).Model(&domain.AuditLog{})
 	if req.Query != "" {
 		f = f.Where("lower(title) LIKE lower(?)", "%"+req.Query+"%")
 	}
@@ -26,6 +49,18 @@ func (r *Repository) ListAuditLog(req *domain.ListAuditLogRequest) ([]*domain.Au
 	if req.Action != "" {
 		f = f.Where("action = ?", req.Action)
 	}
+	if req.PerformedBy != "" {
+		f = f.Where("performed_by = ?", req.PerformedBy)
+	}
+	if req.EntityType != "" {
+		f = f.Where("entity_type = ?", req.EntityType)
+	}
+	if req.EntityID != "" {
+		f = f.Where("entity_id = ?", req.EntityID)
+	}
+	if req.RequestID != "" {
+		f = f.Where("request_id = ?", req.RequestID)
+	}
 	err := f.Count(&count).
 		Order(req.SortColumn + " " + req.SortDirection).
 		Limit(req.Size).
@@ -37,27 +72,110 @@ func (r *Repository) ListAuditLog(req *domain.ListAuditLogRequest) ([]*domain.Au
 	return datas, count, nil
 }
 
-func (r *Repository) GetAuditLog(id string) (*domain.AuditLog, error) {
+func (r *Repository) GetAuditLog(ctx context.Context, id string) (*domain.AuditLog, error) {
 	var data domain.AuditLog
-	if err := r.db.Model(&domain.AuditLog{}).
+	if err := r.db.WithContext(ctx).Model(&domain.AuditLog{}).
 		Take(&data, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
 
-func (r *Repository) UpdateAuditLog(id string, req domain.Map) (*domain.AuditLog, error) {
-	if id == "" {
-		return nil, errors.New("required AuditLog id")
+// GetAuditLogBySequence is nil when there is no entry with the sequence
+func (r *Repository) GetAuditLogBySequence(ctx context.Context, sequence int64) (*domain.AuditLog, error) {
+	var data domain.AuditLog
+	err := r.db.WithContext(ctx).Model(&domain.AuditLog{}).
+		Take(&data, "sequence = ?", sequence).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
 	}
-	data := &domain.AuditLog{}
-	err := r.db.Model(&domain.AuditLog{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
 	if err != nil {
 		return nil, err
 	}
+	return &data, nil
+}
+
+// GetLastAuditLog is nil while the chain is empty
+func (r *Repository) GetLastAuditLog(ctx context.Context) (*domain.AuditLog, error) {
+	var data domain.AuditLog
+	err := r.db.WithContext(ctx).Model(&domain.AuditLog{}).
+		Order("sequence desc").
+		Take(&data).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) ListAuditLogChain(ctx context.Context, after, to int64, limit int) ([]*domain.AuditLog, error) {
+	var datas []*domain.AuditLog
+	f := r.db.WithContext(ctx).Model(&domain.AuditLog{}).Where("sequence > ?", after)
+	if to > 0 {
+		f = f.Where("sequence <= ?", to)
+	}
+	if err := f.Order("sequence asc").Limit(limit).Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) CreateAuditCheckpoint(ctx context.Context, data *domain.AuditCheckpoint) (*domain.AuditCheckpoint, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.AuditCheckpoint{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
 	return data, nil
 }
 
-func (r *Repository) DeleteAuditLog(id string) error {
-	return r.db.Model(&domain.AuditLog{}).Where("id = ?", id).Delete(&domain.AuditLog{}).Error
+func (r *Repository) ListAuditCheckpoint(ctx context.Context, req *domain.ListAuditCheckpointRequest) ([]*domain.AuditCheckpoint, int64, error) {
+	var datas []*domain.AuditCheckpoint
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.AuditCheckpoint{}).
+		Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetAuditCheckpoint(ctx context.Context, id string) (*domain.AuditCheckpoint, error) {
+	var data domain.AuditCheckpoint
+	if err := r.db.WithContext(ctx).Model(&domain.AuditCheckpoint{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetLastAuditCheckpoint is nil before the first checkpoint
+func (r *Repository) GetLastAuditCheckpoint(ctx context.Context) (*domain.AuditCheckpoint, error) {
+	var data domain.AuditCheckpoint
+	err := r.db.WithContext(ctx).Model(&domain.AuditCheckpoint{}).
+		Order("sequence desc, created_at desc").
+		Take(&data).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) ListAuditCheckpointBetween(ctx context.Context, from, to int64) ([]*domain.AuditCheckpoint, error) {
+	var datas []*domain.AuditCheckpoint
+	f := r.db.WithContext(ctx).Model(&domain.AuditCheckpoint{}).Where("sequence >= ?", from)
+	if to > 0 {
+		f = f.Where("sequence <= ?", to)
+	}
+	if err := f.Order("sequence asc").Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
 }
@@ -1,22 +1,22 @@
 package repository
 
 import (
-	"errors"
+	"context"
 
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
-func (r *Repository) CreateBook(data *domain.Book) (*domain.Book, error) {
-	if err := r.db.Model(&domain.Book{}).Create(&data).Error; err != nil {
+func (r *Repository) CreateBook(ctx context.Context, data *domain.Book) (*domain.Book, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Book{}).Create(&data).Error; err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *Repository) ListBook(req *domain.BookListRequest) ([]*domain.Book, int64, error) {
+func (r *Repository) ListBook(ctx context.Context, req *domain.BookListRequest) ([]*domain.Book, int64, error) {
 	var datas []*domain.Book
 	var count int64
-	f := r.db.Model(&domain.Book{})
+	f := r.db.WithContext(ctx).Model(&domain.Book{})
 	if req.Query != "" {
 		req.SortColumn = "score desc, " + req.SortColumn
 	}
@@ -34,9 +34,9 @@ func (r *Repository) ListBook(req *domain.BookListRequest) ([]*domain.Book, int6
 	return datas, count, nil
 }
 
-func (r *Repository) GetBook(id string) (*domain.Book, error) {
+func (r *Repository) GetBook(ctx context.Context, id string) (*domain.Book, error) {
 	var data domain.Book
-	if err := r.db.Model(&domain.Book{}).
+	if err := r.db.WithContext(ctx).Model(&domain.Book{}).
 		Preload("Copies").
 		Preload("Category").
 		Take(&data, "id = ?", id).Error; err != nil {
@@ -45,18 +45,18 @@ func (r *Repository) GetBook(id string) (*domain.Book, error) {
 	return &data, nil
 }
 
-func (r *Repository) UpdateBook(id string, req domain.Map) (*domain.Book, error) {
+func (r *Repository) UpdateBook(ctx context.Context, id string, req domain.Map) (*domain.Book, error) {
 	if id == "" {
-		return nil, errors.New("required book id")
+		return nil, domain.NewValidationError("id_required", "required book id")
 	}
 	data := &domain.Book{}
-	err := r.db.Model(&domain.Book{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	err := r.db.WithContext(ctx).Model(&domain.Book{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
 	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *Repository) DeleteBook(id string) error {
-	return r.db.Model(&domain.Book{}).Where("id = ?", id).Delete(&domain.Book{}).Error
+func (r *Repository) DeleteBook(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Book{}).Where("id = ?", id).Delete(&domain.Book{}).Error
 }
@@ -1,22 +1,22 @@
 package repository
 
 import (
-	"errors"
+	"context"
 
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
-func (r *Repository) CreateBookCopy(copy *domain.BookCopy) (*domain.BookCopy, error) {
-	if err := r.db.Model(&domain.BookCopy{}).Create(&copy).Error; err != nil {
+func (r *Repository) CreateBookCopy(ctx context.Context, copy *domain.BookCopy) (*domain.BookCopy, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.BookCopy{}).Create(&copy).Error; err != nil {
 		return nil, err
 	}
 	return copy, nil
 }
 
-func (r *Repository) ListBookCopies(req *domain.BookCopyListRequest) ([]*domain.BookCopy, int64, error) {
+func (r *Repository) ListBookCopies(ctx context.Context, req *domain.BookCopyListRequest) ([]*domain.BookCopy, int64, error) {
 	var copies []*domain.BookCopy
 	var count int64
-	f := r.db.Model(&domain.BookCopy{})
+	f := r.db.WithContext(ctx).Model(&domain.BookCopy{})
 
 	if req.Query != "" {
 		req.SortColumn = "created_at desc, " + req.SortColumn
@@ -41,18 +41,18 @@ func (r *Repository) ListBookCopies(req *domain.BookCopyListRequest) ([]*domain.
 	return copies, count, nil
 }
 
-func (r *Repository) IsBookCopiesByBookId(bookId string) (bool, error) {
+func (r *Repository) IsBookCopiesByBookId(ctx context.Context, bookId string) (bool, error) {
 	var count int64
-	if err := r.db.Model(&domain.BookCopy{}).Where("book_id = ?", bookId).Count(&count).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&domain.BookCopy{}).Where("book_id = ?", bookId).Count(&count).Error; err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *Repository) ListBookCopiesByBookId(bookId string, req *domain.BookCopyListRequest) ([]*domain.BookCopy, int64, error) {
+func (r *Repository) ListBookCopiesByBookId(ctx context.Context, bookId string, req *domain.BookCopyListRequest) ([]*domain.BookCopy, int64, error) {
 	var copies []*domain.BookCopy
 	var count int64
-	f := r.db.Model(&domain.BookCopy{})
+	f := r.db.WithContext(ctx).Model(&domain.BookCopy{})
 	if req.Query != "" {
 		req.SortColumn = "created_at desc, " + req.SortColumn
 	}
@@ -70,21 +70,21 @@ func (r *Repository) ListBookCopiesByBookId(bookId string, req *domain.BookCopyL
 	return copies, count, nil
 }
 
-func (r *Repository) GetBookCopy(id string) (*domain.BookCopy, error) {
+func (r *Repository) GetBookCopy(ctx context.Context, id string) (*domain.BookCopy, error) {
 	var copy domain.BookCopy
-	if err := r.db.Model(&domain.BookCopy{}).Preload("Book").
+	if err := r.db.WithContext(ctx).Model(&domain.BookCopy{}).Preload("Book").
 		Take(&copy, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &copy, nil
 }
 
-func (r *Repository) UpdateBookCopy(id string, req domain.Map) (*domain.BookCopy, error) {
+func (r *Repository) UpdateBookCopy(ctx context.Context, id string, req domain.Map) (*domain.BookCopy, error) {
 	if id == "" {
-		return nil, errors.New("required book copy id")
+		return nil, domain.NewValidationError("id_required", "required book copy id")
 	}
 	copy := &domain.BookCopy{}
-	err := r.db.Model(&domain.BookCopy{}).
+	err := r.db.WithContext(ctx).Model(&domain.BookCopy{}).
 		Where("id = ?", id).
 		Updates(req.ToMap()).
 		Take(&copy).Error
@@ -94,13 +94,13 @@ func (r *Repository) UpdateBookCopy(id string, req domain.Map) (*domain.BookCopy
 	return copy, nil
 }
 
-func (r *Repository) DeleteBookCopy(id string) error {
-	return r.db.Model(&domain.BookCopy{}).Where("id = ?", id).Delete(&domain.BookCopy{}).Error
+func (r *Repository) DeleteBookCopy(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.BookCopy{}).Where("id = ?", id).Delete(&domain.BookCopy{}).Error
 }
 
-func (r *Repository) CountBorrowedCopyID(bookCopyID string) (int64, error) {
+func (r *Repository) CountBorrowedCopyID(ctx context.Context, bookCopyID string) (int64, error) {
 	var count int64
-	err := r.db.Model(&domain.BorrowedBook{}).
+	err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("book_copy_id = ? AND status IN ?", bookCopyID, []string{"borrowed", "pending", "overdue"}).
 		Count(&count).Error
 	if err != nil {
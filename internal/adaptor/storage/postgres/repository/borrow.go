@@ -1,23 +1,53 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
 )
 
-func (r *Repository) CreateBorrow(data *domain.BorrowedBook) (*domain.BorrowedBook, error) {
-	if err := r.db.Model(&domain.BorrowedBook{}).Create(&data).Error; err != nil {
+func (r *Repository) CreateBorrow(ctx context.Context, data *domain.BorrowedBook) (*domain.BorrowedBook, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).Create(&data).Error; err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *Repository) ListBorrow(req *domain.ListBorrowedBookRequest) ([]*domain.BorrowedBook, int64, error) {
+func (r *Repository) ListBorrow(ctx context.Context, req *domain.ListBorrowedBookRequest) ([]*domain.BorrowedBook, int64, error) {
 	var datas []*domain.BorrowedBook
 	var count int64
-	f := r.db.Model(&domain.BorrowedBook{})
+	err := r.borrowQuery(ctx, req).
+		Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Preload("Student").
+		Preload("BookCopy").
+		Preload("BookCopy.Book").
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+// EachBorrow hands the borrows created in the request period to fn a page at a time
+func (r *Repository) EachBorrow(ctx context.Context, req *domain.ListBorrowedBookRequest, fn func([]*domain.BorrowedBook) error) error {
+	f := r.borrowQuery(ctx, req).
+		Where("created_at BETWEEN ? AND ?", req.StartDate, req.EndDate).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Order("id").
+		Preload("Student").
+		Preload("BookCopy").
+		Preload("BookCopy.Book")
+	return eachBatch(f, fn)
+}
+
+func (r *Repository) borrowQuery(ctx context.Context, req *domain.ListBorrowedBookRequest) *gorm.DB {
+	f := r.db.WithContext(ctx).Model(&domain.BorrowedBook{})
 	if req.Query != "" {
 		req.SortColumn = "score desc, " + req.SortColumn
 	}
@@ -36,23 +66,12 @@ func (r *Repository) ListBorrow(req *domain.ListBorrowedBookRequest) ([]*domain.
 	if req.DueDate != (time.Time{}) {
 		f = f.Where("due_date = ?", req.DueDate)
 	}
-	err := f.Count(&count).
-		Order(req.SortColumn + " " + req.SortDirection).
-		Limit(req.Size).
-		Offset(req.Size * (req.Page - 1)).
-		Preload("Student").
-		Preload("BookCopy").
-		Preload("BookCopy.Book").
-		Find(&datas).Error
-	if err != nil {
-		return nil, count, err
-	}
-	return datas, count, nil
+	return f
 }
 
-func (r *Repository) GetBorrow(id string) (*domain.BorrowedBook, error) {
+func (r *Repository) GetBorrow(ctx context.Context, id string) (*domain.BorrowedBook, error) {
 	var data domain.BorrowedBook
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Preload("BookCopy").
 		Preload("Student").
 		Take(&data, "id = ?", id).Error; err != nil {
@@ -61,9 +80,9 @@ func (r *Repository) GetBorrow(id string) (*domain.BorrowedBook, error) {
 	return &data, nil
 }
 
-func (r *Repository) GetBookBorrowByUserID(user_id string) ([]*domain.BorrowedBook, error) {
+func (r *Repository) GetBookBorrowByUserID(ctx context.Context, user_id string) ([]*domain.BorrowedBook, error) {
 	var data []*domain.BorrowedBook
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Preload("BookCopy").
 		Preload("BookCopy.Book").
 		Preload("Student").
@@ -73,16 +92,16 @@ func (r *Repository) GetBookBorrowByUserID(user_id string) ([]*domain.BorrowedBo
 	return data, nil
 }
 
-func (r *Repository) GetAvailableCopies(bookID string) (uint, error) {
+func (r *Repository) GetAvailableCopies(ctx context.Context, bookID string) (uint, error) {
 	// First, load the book
 	var book domain.Book
-	if err := r.db.First(&book, "id = ?", bookID).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&book, "id = ?", bookID).Error; err != nil {
 		return 0, err
 	}
 
 	// Count currently borrowed copies
 	var borrowedCount int64
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("book_copy_id = ? AND status = ?", bookID, "borrowed").
 		Count(&borrowedCount).Error; err != nil {
 		return 0, err
@@ -92,10 +111,10 @@ func (r *Repository) GetAvailableCopies(bookID string) (uint, error) {
 	return availableCopies, nil
 }
 
-func (r *Repository) CountAllBookBorrwedCopies() (int64, error) {
+func (r *Repository) CountAllBookBorrwedCopies(ctx context.Context) (int64, error) {
 	// Count currently borrowed copies
 	var borrowedCount int64
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("status = ?", "borrowed").
 		Count(&borrowedCount).Error; err != nil {
 		return 0, err
@@ -103,10 +122,10 @@ func (r *Repository) CountAllBookBorrwedCopies() (int64, error) {
 	return borrowedCount, nil
 }
 
-func (r *Repository) CountBorrwedCopiesBookID(bookID string) (int64, error) {
+func (r *Repository) CountBorrwedCopiesBookID(ctx context.Context, bookID string) (int64, error) {
 	// Count currently borrowed copies
 	var borrowedCount int64
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("book_id = ? AND status = ?", bookID, "borrowed").
 		Count(&borrowedCount).Error; err != nil {
 		return 0, err
@@ -114,10 +133,10 @@ func (r *Repository) CountBorrwedCopiesBookID(bookID string) (int64, error) {
 	return borrowedCount, nil
 }
 
-func (r *Repository) CountBorrwedCopiesUserID(userID string) (int64, error) {
+func (r *Repository) CountBorrwedCopiesUserID(ctx context.Context, userID string) (int64, error) {
 	// Count currently borrowed copies
 	var borrowedCount int64
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("user_id = ? AND status = ?", userID, "borrowed").
 		Count(&borrowedCount).Error; err != nil {
 		return 0, err
@@ -125,9 +144,9 @@ func (r *Repository) CountBorrwedCopiesUserID(userID string) (int64, error) {
 	return borrowedCount, nil
 }
 
-func (r *Repository) IsBookBorrowByUserID(userID string, bookID string) bool {
+func (r *Repository) IsBookBorrowByUserID(ctx context.Context, userID string, bookID string) bool {
 	var count int64
-	err := r.db.Model(&domain.BorrowedBook{}).
+	err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("user_id = ? AND book_id = ? AND returned_date IS NULL", userID, bookID).
 		Count(&count).Error
 
@@ -137,15 +156,15 @@ func (r *Repository) IsBookBorrowByUserID(userID string, bookID string) bool {
 	return count > 0
 }
 
-func (r *Repository) UpdateBorrow(id string, req domain.Map) (*domain.BorrowedBook, error) {
+func (r *Repository) UpdateBorrow(ctx context.Context, id string, req domain.Map) (*domain.BorrowedBook, error) {
 	data := &domain.BorrowedBook{}
-	err := r.db.Model(&domain.BorrowedBook{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
 	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *Repository) DeleteBorrow(id string) error {
-	return r.db.Model(&domain.BorrowedBook{}).Where("id = ?", id).Delete(&domain.BorrowedBook{}).Error
+func (r *Repository) DeleteBorrow(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).Where("id = ?", id).Delete(&domain.BorrowedBook{}).Error
 }
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (r *Repository) CreateBuilding(ctx context.Context, data *domain.Building) (*domain.Building, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Building{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListBuilding(ctx context.Context, req *domain.ListBuildingRequest) ([]*domain.Building, int64, error) {
+	var datas []*domain.Building
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Building{})
+	if req.Query != "" {
+		f = f.Where("name ILIKE ? OR code ILIKE ?", "%"+req.Query+"%", "%"+req.Query+"%")
+	}
+	if req.CampusID != "" {
+		f = f.Where("campus_id = ?", req.CampusID)
+	}
+	if req.Code != "" {
+		f = f.Where("code = ?", req.Code)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Preload("Campus").
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetBuilding(ctx context.Context, id string) (*domain.Building, error) {
+	var data domain.Building
+	if err := r.db.WithContext(ctx).Model(&domain.Building{}).
+		Preload("Campus").
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateBuilding(ctx context.Context, id string, req domain.Map) (*domain.Building, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required building id")
+	}
+	data := &domain.Building{}
+	err := r.db.WithContext(ctx).Model(&domain.Building{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) DeleteBuilding(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Building{}).Where("id = ?", id).Delete(&domain.Building{}).Error
+}
+
+func (r *Repository) CountBuildingFloors(ctx context.Context, buildingID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Floor{}).
+		Where("building_id = ?", buildingID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
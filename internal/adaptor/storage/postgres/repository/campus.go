@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (r *Repository) CreateCampus(ctx context.Context, data *domain.Campus) (*domain.Campus, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Campus{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListCampus(ctx context.Context, req *domain.ListCampusRequest) ([]*domain.Campus, int64, error) {
+	var datas []*domain.Campus
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Campus{})
+	if req.Query != "" {
+		f = f.Where("name ILIKE ? OR location ILIKE ?", "%"+req.Query+"%", "%"+req.Query+"%")
+	}
+	if req.Name != "" {
+		f = f.Where("name ILIKE ?", "%"+req.Name+"%")
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetCampus(ctx context.Context, id string) (*domain.Campus, error) {
+	var data domain.Campus
+	if err := r.db.WithContext(ctx).Model(&domain.Campus{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateCampus(ctx context.Context, id string, req domain.Map) (*domain.Campus, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required campus id")
+	}
+	data := &domain.Campus{}
+	err := r.db.WithContext(ctx).Model(&domain.Campus{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) DeleteCampus(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Campus{}).Where("id = ?", id).Delete(&domain.Campus{}).Error
+}
+
+func (r *Repository) CountCampusBuildings(ctx context.Context, campusID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Building{}).
+		Where("campus_id = ?", campusID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
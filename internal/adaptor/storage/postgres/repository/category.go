@@ -8,7 +8,7 @@ import (
 
 // CreateCategory creates a new Category record in the database
 func (r *Repository) Create(ctx context.Context, data *domain.Category) (*domain.Category, error) {
-	err := r.db.Model(&domain.Category{}).Create(&data).Take(data).Error
+	err := r.db.WithContext(ctx).Model(&domain.Category{}).Create(&data).Take(data).Error
 	if err != nil {
 		return nil, err
 	}
@@ -17,7 +17,7 @@ func (r *Repository) Create(ctx context.Context, data *domain.Category) (*domain
 
 func (r *Repository) GetbyName(ctx context.Context, name string) (*domain.Category, error) {
 	data := &domain.Category{}
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Model(&domain.Category{}).
 		Select("id, name, created_at, updated_at, weight, is_active").
 		Where("name = ? AND is_active = true", name).
@@ -30,7 +30,7 @@ func (r *Repository) GetbyName(ctx context.Context, name string) (*domain.Catego
 
 func (r *Repository) Get(ctx context.Context, id string) (*domain.Category, error) {
 	data := &domain.Category{}
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Model(&domain.Category{}).
 		Select("id, name, created_at, updated_at, weight, is_active").
 		Where("id = ? AND is_active = true", id).
@@ -45,7 +45,7 @@ func (r *Repository) Get(ctx context.Context, id string) (*domain.Category, erro
 func (r *Repository) List(ctx context.Context, req *domain.ListCategoryRequest) ([]*domain.Category, int64, error) {
 	var categories []*domain.Category
 	count := 0
-	f := r.db.Model(&domain.Category{})
+	f := r.db.WithContext(ctx).Model(&domain.Category{})
 	f = f.Where("lower(name) LIKE lower(?)", "%"+req.Query+"%")
 	err := f.Order(req.SortColumn + " " + req.SortDirection).
 		Limit(req.Size).
@@ -60,7 +60,7 @@ func (r *Repository) List(ctx context.Context, req *domain.ListCategoryRequest)
 // 4. Update Category
 func (r *Repository) Update(ctx context.Context, id string, req domain.Map) error {
 	category := &domain.Category{}
-	err := r.db.Model(&domain.Category{}).Where("id = ?", id).Updates(category).Error
+	err := r.db.WithContext(ctx).Model(&domain.Category{}).Where("id = ?", id).Updates(category).Error
 	if err != nil {
 		return err
 	}
@@ -69,5 +69,5 @@ func (r *Repository) Update(ctx context.Context, id string, req domain.Map) erro
 
 // 5. Delete Category
 func (r *Repository) Delete(ctx context.Context, id string) error {
-	return r.db.Model(&domain.Category{}).Where("id = ?", id).Delete(&domain.Category{}).Error
+	return r.db.WithContext(ctx).Model(&domain.Category{}).Where("id = ?", id).Delete(&domain.Category{}).Error
 }
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// loansCTE is every borrow that was issued with the time it went out. Borrows created as
+// borrowed keep a zero borrowed_date, their creation time is used for them instead.
+const loansCTE = `
+	loans AS (
+		SELECT bb.*,
+			CASE WHEN bb.borrowed_date > '0001-01-02' THEN bb.borrowed_date ELSE bb.created_at END AS loaned_at
+		FROM borrowed_books bb
+		WHERE bb.status IN ('borrowed', 'returned', 'overdue')
+	),
+	period_loans AS (
+		SELECT * FROM loans WHERE loaned_at >= @from AND loaned_at < @to
+	)`
+
+func circulationArgs(req *domain.CirculationRequest) map[string]interface{} {
+	return map[string]interface{}{"from": req.From, "to": req.To, "limit": req.Limit}
+}
+
+func (r *Repository) GetTopBooks(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopBook, error) {
+	var results []domain.TopBook
+	err := r.db.WithContext(ctx).Raw(`
+		WITH `+loansCTE+`
+		SELECT
+			b.id AS book_id,
+			b.title,
+			b.author,
+			COALESCE(c.name, '') AS category_name,
+			COUNT(*) AS loans,
+			COUNT(DISTINCT l.user_id) AS borrowers
+		FROM period_loans l
+		JOIN book_copies bc ON bc.id::text = l.book_copy_id
+		JOIN books b ON b.id::text = bc.book_id
+		LEFT JOIN categories c ON c.id::text = b.category_id
+		GROUP BY b.id, b.title, b.author, c.name
+		ORDER BY loans DESC, b.title
+		LIMIT @limit
+	`, circulationArgs(req)).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *Repository) GetTopCategories(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopCategory, error) {
+	var results []domain.TopCategory
+	err := r.db.WithContext(ctx).Raw(`
+		WITH `+loansCTE+`
+		SELECT
+			COALESCE(c.id::text, '') AS category_id,
+			COALESCE(c.name, '') AS category_name,
+			COUNT(*) AS loans,
+			COUNT(DISTINCT b.id) AS titles
+		FROM period_loans l
+		JOIN book_copies bc ON bc.id::text = l.book_copy_id
+		JOIN books b ON b.id::text = bc.book_id
+		LEFT JOIN categories c ON c.id::text = b.category_id
+		GROUP BY c.id, c.name
+		ORDER BY loans DESC, category_name
+		LIMIT @limit
+	`, circulationArgs(req)).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// inactiveStockQuery selects the active titles added before the cutoff whose copies were not
+// lent since, never lent titles included
+func (r *Repository) inactiveStockQuery(ctx context.Context, req *domain.InactiveStockRequest) *gorm.DB {
+	lastLoans := r.db.WithContext(ctx).Table("borrowed_books bb").
+		Select("bc.book_id, MAX(CASE WHEN bb.borrowed_date > '0001-01-02' THEN bb.borrowed_date ELSE bb.created_at END) AS last_loaned_at").
+		Joins("JOIN book_copies bc ON bc.id::text = bb.book_copy_id").
+		Where("bb.status IN ?", []string{"borrowed", "returned", "overdue"}).
+		Group("bc.book_id")
+	copies := r.db.WithContext(ctx).Table("book_copies").
+		Select("book_id, COUNT(*) AS copies").
+		Group("book_id")
+	f := r.db.WithContext(ctx).Table("books b").
+		Joins("LEFT JOIN categories c ON c.id::text = b.category_id").
+		Joins("LEFT JOIN (?) cp ON cp.book_id = b.id::text", copies).
+		Joins("LEFT JOIN (?) ll ON ll.book_id = b.id::text", lastLoans).
+		Where("b.is_active AND b.created_at < ?", req.Before).
+		Where("ll.last_loaned_at IS NULL OR ll.last_loaned_at < ?", req.Before)
+	if req.CategoryID != "" {
+		f = f.Where("b.category_id = ?", req.CategoryID)
+	}
+	return f
+}
+
+const inactiveStockColumns = `b.id AS book_id, b.title, b.author, COALESCE(c.name, '') AS category_name,
+	COALESCE(cp.copies, 0) AS copies, ll.last_loaned_at, b.created_at AS added_at`
+
+// ListInactiveStock lists the weeding candidates, never lent titles first, then the longest idle
+func (r *Repository) ListInactiveStock(ctx context.Context, req *domain.InactiveStockRequest) ([]*domain.InactiveBook, int64, error) {
+	var datas []*domain.InactiveBook
+	var count int64
+	err := r.inactiveStockQuery(ctx, req).
+		Count(&count).
+		Select(inactiveStockColumns).
+		Order("ll.last_loaned_at ASC NULLS FIRST, b.title").
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) EachInactiveStock(ctx context.Context, req *domain.InactiveStockRequest, fn func([]*domain.InactiveBook) error) error {
+	f := r.inactiveStockQuery(ctx, req).
+		Select(inactiveStockColumns).
+		Order("ll.last_loaned_at ASC NULLS FIRST, b.title, b.id")
+	return eachBatch(f, fn)
+}
+
+// GetLoanDuration measures the loans issued in the period from issue to return in days
+func (r *Repository) GetLoanDuration(ctx context.Context, req *domain.CirculationRequest) (*domain.LoanDuration, error) {
+	var result domain.LoanDuration
+	err := r.db.WithContext(ctx).Raw(`
+		WITH `+loansCTE+`,
+		durations AS (
+			SELECT
+				returned_date,
+				due_date,
+				EXTRACT(EPOCH FROM (returned_date - loaned_at)) / 86400 AS days
+			FROM period_loans
+		)
+		SELECT
+			COUNT(*) AS loans,
+			COUNT(returned_date) AS returned,
+			COUNT(*) FILTER (WHERE returned_date > due_date) AS late_returns,
+			COALESCE(ROUND(AVG(days)::numeric, 2), 0) AS average_days,
+			COALESCE(ROUND((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY days))::numeric, 2), 0) AS median_days,
+			COALESCE(ROUND(MAX(days)::numeric, 2), 0) AS longest_days
+		FROM durations
+	`, circulationArgs(req)).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBorrowingRates counts students, borrowers and loans per program and per batch of a program
+// in one pass
+func (r *Repository) GetBorrowingRates(ctx context.Context, req *domain.CirculationRequest) (*domain.BorrowingRates, error) {
+	type rate struct {
+		domain.BorrowingRate
+		ProgramTotal bool
+	}
+	var rows []rate
+	err := r.db.WithContext(ctx).Raw(`
+		WITH `+loansCTE+`,
+		borrowers AS (
+			SELECT user_id, COUNT(*) AS loans FROM period_loans GROUP BY user_id
+		)
+		SELECT
+			COALESCE(p.id::text, '') AS program_id,
+			COALESCE(p.name, '') AS program_name,
+			COALESCE(u.batch, '') AS batch,
+			GROUPING(u.batch) = 1 AS program_total,
+			COUNT(*) AS students,
+			COUNT(bw.user_id) AS borrowers,
+			COALESCE(SUM(bw.loans), 0) AS loans
+		FROM users u
+		LEFT JOIN programs p ON p.id::text = u.program_id
+		LEFT JOIN borrowers bw ON bw.user_id = u.id::text
+		WHERE u.id IN (
+			SELECT user_roles.user_id FROM user_roles
+			JOIN roles ON roles.id = user_roles.role_id
+			WHERE LOWER(roles.name) = @role
+		)
+		GROUP BY GROUPING SETS ((p.id, p.name), (p.id, p.name, u.batch))
+		ORDER BY program_name, program_id, batch
+	`, map[string]interface{}{"from": req.From, "to": req.To, "role": domain.RoleStudent}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.BorrowingRates{}
+	for _, row := range rows {
+		if row.ProgramTotal {
+			row.Batch = ""
+			result.Programs = append(result.Programs, row.BorrowingRate)
+		} else {
+			result.Batches = append(result.Batches, row.BorrowingRate)
+		}
+	}
+	return result, nil
+}
+
+func (r *Repository) GetTopReaders(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopReader, error) {
+	var results []domain.TopReader
+	err := r.db.WithContext(ctx).Raw(`
+		WITH `+loansCTE+`
+		SELECT
+			u.id AS user_id,
+			u.full_name,
+			u.username,
+			COALESCE(p.name, '') AS program_name,
+			COALESCE(u.batch, '') AS batch,
+			COUNT(*) AS loans,
+			COUNT(DISTINCT bc.book_id) AS titles
+		FROM period_loans l
+		JOIN users u ON u.id::text = l.user_id
+		JOIN book_copies bc ON bc.id::text = l.book_copy_id
+		LEFT JOIN programs p ON p.id::text = u.program_id
+		GROUP BY u.id, u.full_name, u.username, p.name, u.batch
+		ORDER BY loans DESC, u.full_name
+		LIMIT @limit
+	`, circulationArgs(req)).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) CreateEmailDelivery(ctx context.Context, data *domain.EmailDelivery) (*domain.EmailDelivery, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.EmailDelivery{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListEmailDelivery(ctx context.Context, req *domain.ListEmailDeliveryRequest) ([]*domain.EmailDelivery, int64, error) {
+	var datas []*domain.EmailDelivery
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.EmailDelivery{})
+	if req.UserID != "" {
+		f = f.Where("user_id = ?", req.UserID)
+	}
+	if req.Template != "" {
+		f = f.Where("template = ?", req.Template)
+	}
+	if req.Status != "" {
+		f = f.Where("status = ?", req.Status)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetEmailDelivery(ctx context.Context, id string) (*domain.EmailDelivery, error) {
+	var data domain.EmailDelivery
+	if err := r.db.WithContext(ctx).Model(&domain.EmailDelivery{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateEmailDelivery(ctx context.Context, id string, req domain.Map) (*domain.EmailDelivery, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required email delivery id")
+	}
+	data := &domain.EmailDelivery{}
+	err := r.db.WithContext(ctx).Model(&domain.EmailDelivery{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ClaimDueEmailDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.EmailDelivery, error) {
+	var datas []*domain.EmailDelivery
+	due := r.db.WithContext(ctx).Model(&domain.EmailDelivery{}).
+		Select("id").
+		Where("status = ? AND next_attempt_at <= ?", domain.EmailQueued, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	if err := r.db.WithContext(ctx).Raw("UPDATE email_deliveries SET next_attempt_at = ? WHERE id IN (?) RETURNING *", now.Add(lease), due).
+		Scan(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) ListBorrowsDueBefore(ctx context.Context, before time.Time) ([]*domain.BorrowedBook, error) {
+	var datas []*domain.BorrowedBook
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
+		Preload("BookCopy").
+		Preload("BookCopy.Book").
+		Where("status IN ? AND returned_date IS NULL AND due_date < ?", []string{"borrowed", "overdue"}, before).
+		Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (r *Repository) CreateFaculty(ctx context.Context, data *domain.Faculty) (*domain.Faculty, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Faculty{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListFaculty(ctx context.Context, req *domain.ListFacultyRequest) ([]*domain.Faculty, int64, error) {
+	var datas []*domain.Faculty
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Faculty{})
+	if req.Query != "" {
+		f = f.Where("name ILIKE ? OR code ILIKE ?", "%"+req.Query+"%", "%"+req.Query+"%")
+	}
+	if req.Name != "" {
+		f = f.Where("name ILIKE ?", "%"+req.Name+"%")
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetFaculty(ctx context.Context, id string) (*domain.Faculty, error) {
+	var data domain.Faculty
+	if err := r.db.WithContext(ctx).Model(&domain.Faculty{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateFaculty(ctx context.Context, id string, req domain.Map) (*domain.Faculty, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required faculty id")
+	}
+	data := &domain.Faculty{}
+	err := r.db.WithContext(ctx).Model(&domain.Faculty{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) DeleteFaculty(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Faculty{}).Where("id = ?", id).Delete(&domain.Faculty{}).Error
+}
+
+func (r *Repository) CountFacultyPrograms(ctx context.Context, facultyID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Program{}).
+		Where("faculty_id = ?", facultyID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
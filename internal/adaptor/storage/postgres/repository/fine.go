@@ -1,22 +1,22 @@
 package repository
 
 import (
-	"errors"
+	"context"
 
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
-func (r *Repository) CreateFine(data *domain.Fine) (*domain.Fine, error) {
-	if err := r.db.Model(&domain.Fine{}).Create(&data).Error; err != nil {
+func (r *Repository) CreateFine(ctx context.Context, data *domain.Fine) (*domain.Fine, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Fine{}).Create(&data).Error; err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *Repository) ListFine(req *domain.ListFineRequest) ([]*domain.Fine, int64, error) {
+func (r *Repository) ListFine(ctx context.Context, req *domain.ListFineRequest) ([]*domain.Fine, int64, error) {
 	var datas []*domain.Fine
 	var count int64
-	f := r.db.Model(&domain.Fine{})
+	f := r.db.WithContext(ctx).Model(&domain.Fine{})
 	if req.Query != "" {
 		req.SortColumn = "score desc, " + req.SortColumn
 	}
@@ -31,28 +31,36 @@ func (r *Repository) ListFine(req *domain.ListFineRequest) ([]*domain.Fine, int6
 	return datas, count, nil
 }
 
-func (r *Repository) GetFine(id string) (*domain.Fine, error) {
+// EachFine hands the fines raised in the request period to fn a page at a time
+func (r *Repository) EachFine(ctx context.Context, req *domain.ListFineRequest, fn func([]*domain.Fine) error) error {
+	f := r.db.WithContext(ctx).Model(&domain.Fine{}).
+		Where("created_at BETWEEN ? AND ?", req.StartDate, req.EndDate).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Order("id")
+	return eachBatch(f, fn)
+}
+
+func (r *Repository) GetFine(ctx context.Context, id string) (*domain.Fine, error) {
 	var data domain.Fine
-	if err := r.db.Model(&domain.Fine{}).
-		Preload("Fine").
+	if err := r.db.WithContext(ctx).Model(&domain.Fine{}).
 		Take(&data, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
 
-func (r *Repository) UpdateFine(id string, req domain.Map) (*domain.Fine, error) {
+func (r *Repository) UpdateFine(ctx context.Context, id string, req domain.Map) (*domain.Fine, error) {
 	if id == "" {
-		return nil, errors.New("required Fine id")
+		return nil, domain.NewValidationError("id_required", "required Fine id")
 	}
 	data := &domain.Fine{}
-	err := r.db.Model(&domain.Fine{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	err := r.db.WithContext(ctx).Model(&domain.Fine{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
 	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *Repository) DeleteFine(id string) error {
-	return r.db.Model(&domain.Fine{}).Where("id = ?", id).Delete(&domain.Fine{}).Error
+func (r *Repository) DeleteFine(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Fine{}).Where("id = ?", id).Delete(&domain.Fine{}).Error
 }
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// fineEventsCTE is every fine being issued, paid and waived with when it happened, who handled it
+// and the receipt of a payment. Payments and waivers made before they were recorded with a time
+// are left out.
+const fineEventsCTE = `
+	fine_events AS (
+		SELECT id::text AS fine_id, user_id, 'issued' AS entry, created_at AS at, COALESCE(issued_by, '') AS staff_id,
+			amount, reason, '' AS receipt_number
+		FROM fines
+		UNION ALL
+		SELECT id::text, user_id, 'paid', paid_at, COALESCE(collected_by, ''), amount, reason, COALESCE(receipt_number, '')
+		FROM fines WHERE status = 'paid' AND paid_at IS NOT NULL
+		UNION ALL
+		SELECT id::text, user_id, 'waived', waived_at, COALESCE(waived_by, ''), amount,
+			COALESCE(NULLIF(waiver_reason, ''), reason), ''
+		FROM fines WHERE status = 'waived' AND waived_at IS NOT NULL
+	)`
+
+// PayFine settles a pending fine and gives it the next receipt number, the receipt sequence is
+// created by the migration
+func (r *Repository) PayFine(ctx context.Context, id, collectedBy string, paidAt time.Time) (*domain.Fine, error) {
+	res := r.db.WithContext(ctx).Exec(`
+		UPDATE fines SET
+			status = @paid,
+			paid_at = @at,
+			collected_by = @by,
+			receipt_number = @prefix || LPAD(nextval('fine_receipt_seq')::text, 6, '0'),
+			updated_at = @at
+		WHERE id = @id AND status = @pending
+	`, map[string]interface{}{
+		"id":      id,
+		"by":      collectedBy,
+		"at":      paidAt,
+		"prefix":  fmt.Sprintf("RCP-%d-", paidAt.Year()),
+		"paid":    domain.FinePaid,
+		"pending": domain.FinePending,
+	})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, domain.NewConflictError("fine_not_pending", "only a pending fine can be paid")
+	}
+	return r.GetFine(ctx, id)
+}
+
+func (r *Repository) WaiveFine(ctx context.Context, id, waivedBy, reason string, waivedAt time.Time) (*domain.Fine, error) {
+	res := r.db.WithContext(ctx).Model(&domain.Fine{}).
+		Where("id = ? AND status = ?", id, domain.FinePending).
+		Updates(map[string]interface{}{
+			"status":        domain.FineWaived,
+			"waived_at":     waivedAt,
+			"waived_by":     waivedBy,
+			"waiver_reason": reason,
+		})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, domain.NewConflictError("fine_not_pending", "only a pending fine can be waived")
+	}
+	return r.GetFine(ctx, id)
+}
+
+// GetOutstandingFines is the amount of the fines still pending in paisa
+func (r *Repository) GetOutstandingFines(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&domain.Fine{}).
+		Where("status = ?", domain.FinePending).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// fineCollectionGroups are the group by keys of the collection report
+var fineCollectionGroups = map[string]struct{ columns, join, group string }{
+	domain.FineGroupDay: {
+		columns: "TO_CHAR(e.at, 'YYYY-MM-DD') AS period",
+		group:   "period",
+	},
+	domain.FineGroupMonth: {
+		columns: "TO_CHAR(e.at, 'YYYY-MM') AS period",
+		group:   "period",
+	},
+	domain.FineGroupCollector: {
+		columns: "e.staff_id AS collector_id, COALESCE(u.full_name, '') AS collector_name",
+		join:    "LEFT JOIN users u ON u.id::text = e.staff_id",
+		group:   "e.staff_id, u.full_name",
+	},
+}
+
+func (r *Repository) GetFineCollection(ctx context.Context, req *domain.FineCollectionRequest) ([]domain.FineCollection, error) {
+	group, ok := fineCollectionGroups[req.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported group_by %q", req.GroupBy)
+	}
+	var results []domain.FineCollection
+	err := r.db.WithContext(ctx).Raw(`
+		WITH `+fineEventsCTE+`
+		SELECT
+			`+group.columns+`,
+			COUNT(*) FILTER (WHERE e.entry = 'issued') AS issued,
+			COALESCE(SUM(e.amount) FILTER (WHERE e.entry = 'issued'), 0) AS issued_amount,
+			COUNT(*) FILTER (WHERE e.entry = 'paid') AS collected,
+			COALESCE(SUM(e.amount) FILTER (WHERE e.entry = 'paid'), 0) AS collected_amount,
+			COUNT(*) FILTER (WHERE e.entry = 'waived') AS waived,
+			COALESCE(SUM(e.amount) FILTER (WHERE e.entry = 'waived'), 0) AS waived_amount
+		FROM fine_events e
+		`+group.join+`
+		WHERE e.at >= @from AND e.at < @to
+		GROUP BY `+group.group+`
+		ORDER BY `+group.group+`
+	`, map[string]interface{}{"from": req.From, "to": req.To}).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// fineAgeBucket puts a pending fine into its age bucket by the days since it was issued
+const fineAgeBucket = `CASE
+	WHEN CURRENT_DATE - f.created_at::date <= 30 THEN '0-30'
+	WHEN CURRENT_DATE - f.created_at::date <= 60 THEN '31-60'
+	ELSE '60+' END`
+
+func (r *Repository) GetFineAging(ctx context.Context) ([]domain.FineAgingBucket, error) {
+	var results []domain.FineAgingBucket
+	err := r.db.WithContext(ctx).Table("fines f").
+		Select(fineAgeBucket+" AS bucket, COUNT(*) AS fines, COUNT(DISTINCT f.user_id) AS students, SUM(f.amount) AS amount").
+		Where("f.status = ?", domain.FinePending).
+		Group("bucket").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *Repository) studentFineAgingQuery(ctx context.Context, req *domain.ListRequest) *gorm.DB {
+	f := r.db.WithContext(ctx).Table("fines f").
+		Joins("LEFT JOIN users u ON u.id::text = f.user_id").
+		Where("f.status = ?", domain.FinePending)
+	if req.Query != "" {
+		f = f.Where("u.full_name ILIKE @q OR u.username ILIKE @q", map[string]interface{}{"q": "%" + req.Query + "%"})
+	}
+	return f.Group("f.user_id, u.full_name, u.username")
+}
+
+const studentFineAgingColumns = `f.user_id, COALESCE(u.full_name, '') AS full_name, COALESCE(u.username, '') AS username,
+	COALESCE(SUM(f.amount) FILTER (WHERE CURRENT_DATE - f.created_at::date <= 30), 0) AS days0_to30,
+	COALESCE(SUM(f.amount) FILTER (WHERE CURRENT_DATE - f.created_at::date BETWEEN 31 AND 60), 0) AS days31_to60,
+	COALESCE(SUM(f.amount) FILTER (WHERE CURRENT_DATE - f.created_at::date > 60), 0) AS over60_days,
+	SUM(f.amount) AS total`
+
+// ListStudentFineAging lists the students who owe fines, the largest and then oldest debts first
+func (r *Repository) ListStudentFineAging(ctx context.Context, req *domain.ListRequest) ([]*domain.StudentFineAging, int64, error) {
+	var datas []*domain.StudentFineAging
+	var count int64
+	err := r.db.WithContext(ctx).Table("(?) s", r.studentFineAgingQuery(ctx, req).Select("f.user_id")).Count(&count).Error
+	if err != nil {
+		return nil, count, err
+	}
+	err = r.studentFineAgingQuery(ctx, req).
+		Select(studentFineAgingColumns).
+		Order("total DESC, over60_days DESC, f.user_id").
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Scan(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) EachStudentFineAging(ctx context.Context, req *domain.ListRequest, fn func([]*domain.StudentFineAging) error) error {
+	f := r.studentFineAgingQuery(ctx, req).
+		Select(studentFineAgingColumns).
+		Order("total DESC, over60_days DESC, f.user_id")
+	return eachBatch(f, fn)
+}
+
+// ListFineEvents lists what happened to a user's fines before to, oldest first and an issue before
+// the payment or waiver of the same fine
+func (r *Repository) ListFineEvents(ctx context.Context, userID string, to time.Time) ([]domain.FineEvent, error) {
+	var results []domain.FineEvent
+	err := r.db.WithContext(ctx).Raw(`
+		WITH `+fineEventsCTE+`
+		SELECT fine_id, entry, at, staff_id, amount, reason, receipt_number
+		FROM fine_events
+		WHERE user_id = @user AND at < @to
+		ORDER BY at, fine_id, entry = 'issued' DESC
+	`, map[string]interface{}{"user": userID, "to": to}).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
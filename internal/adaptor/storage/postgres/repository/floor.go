@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (r *Repository) CreateFloor(ctx context.Context, data *domain.Floor) (*domain.Floor, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Floor{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListFloor(ctx context.Context, req *domain.ListFloorRequest) ([]*domain.Floor, int64, error) {
+	var datas []*domain.Floor
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Floor{})
+	if req.Query != "" {
+		f = f.Where("description ILIKE ?", "%"+req.Query+"%")
+	}
+	if req.BuildingID != "" {
+		f = f.Where("building_id = ?", req.BuildingID)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Preload("Building").
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetFloor(ctx context.Context, id string) (*domain.Floor, error) {
+	var data domain.Floor
+	if err := r.db.WithContext(ctx).Model(&domain.Floor{}).
+		Preload("Building").
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateFloor(ctx context.Context, id string, req domain.Map) (*domain.Floor, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required floor id")
+	}
+	data := &domain.Floor{}
+	err := r.db.WithContext(ctx).Model(&domain.Floor{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) DeleteFloor(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Floor{}).Where("id = ?", id).Delete(&domain.Floor{}).Error
+}
+
+func (r *Repository) CountFloorRooms(ctx context.Context, floorID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Room{}).
+		Where("floor_id = ?", floorID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (r *Repository) Ping(ctx context.Context) error {
+	db, err := r.db.WithContext(ctx).DB()
+	if err != nil {
+		return err
+	}
+	return db.PingContext(ctx)
+}
+
+// GetLoanGauges counts the loans out, overdue and requested in one pass over the borrows
+func (r *Repository) GetLoanGauges(ctx context.Context) (*domain.LoanGauges, error) {
+	var result domain.LoanGauges
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(*) FILTER (WHERE status IN ('borrowed', 'overdue') AND returned_date IS NULL) AS active_loans,
+			COUNT(*) FILTER (WHERE status IN ('borrowed', 'overdue') AND returned_date IS NULL AND due_date < @now) AS overdue_loans,
+			COUNT(*) FILTER (WHERE status = 'pending') AS pending_requests
+		FROM borrowed_books
+	`, map[string]interface{}{"now": time.Now()}).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
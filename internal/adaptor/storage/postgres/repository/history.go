@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) ListEntityHistory(ctx context.Context, entityType, entityID string, req *domain.ListHistoryRequest) ([]*domain.AuditLog, int64, error) {
+	return r.listHistory(ctx, r.db.WithContext(ctx).Model(&domain.AuditLog{}).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID), req)
+}
+
+// ListPatronHistory finds the loans and fines of the patron through their tables, and through the
+// user_id recorded in the diff for those that have since been deleted
+func (r *Repository) ListPatronHistory(ctx context.Context, userID string, req *domain.ListHistoryRequest) ([]*domain.AuditLog, int64, error) {
+	owned := func(entityType, table string) *gorm.DB {
+		return r.db.WithContext(ctx).Where("entity_type = ?", entityType).
+			Where(r.db.WithContext(ctx).Where("entity_id IN (?)", r.db.WithContext(ctx).Table(table).Select("id::text").Where("user_id = ?", userID)).
+				Or("changes->'user_id'->>'before' = ?", userID).
+				Or("changes->'user_id'->>'after' = ?", userID))
+	}
+	return r.listHistory(ctx, r.db.WithContext(ctx).Model(&domain.AuditLog{}).
+		Where(owned(domain.HistoryBorrow, "borrowed_books").Or(owned(domain.HistoryFine, "fines"))), req)
+}
+
+func (r *Repository) listHistory(ctx context.Context, f *gorm.DB, req *domain.ListHistoryRequest) ([]*domain.AuditLog, int64, error) {
+	var datas []*domain.AuditLog
+	var count int64
+	if req.Action != "" {
+		f = f.Where("action = ?", req.Action)
+	}
+	err := f.Count(&count).
+		Order("sequence " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
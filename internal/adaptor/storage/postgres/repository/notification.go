@@ -1,27 +1,88 @@
 package repository
 
 import (
-	"errors"
+	"context"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func (r *Repository) CreateNotification(data *domain.Notification) (*domain.Notification, error) {
-	if err := r.db.Model(&domain.Notification{}).Create(&data).Error; err != nil {
+// publishNotification tells every replica listening on domain.NotificationChannel that a user's
+// notifications changed, a lost signal only delays a stream until the next one
+func (r *Repository) publishNotification(ctx context.Context, userID, op string) {
+	payload := domain.ConvertToJson(&domain.NotificationSignal{UserID: userID, Op: op})
+	if err := r.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", domain.NotificationChannel, string(payload)).Error; err != nil {
+		logrus.WithError(err).Warn("could not publish notification signal")
+	}
+}
+
+// signalUser is who a change to the notification concerns, everybody unless it is addressed
+// to a single user
+func signalUser(data *domain.Notification) string {
+	if data.Audience == domain.AudienceUser {
+		return data.UserID
+	}
+	return ""
+}
+
+// addressedTo limits notifications to the ones the recipient can see
+func addressedTo(recipient *domain.NotificationRecipient) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"(notifications.audience = ? AND notifications.user_id = ?) OR "+
+				"((notifications.audience = ? OR (notifications.audience = ? AND notifications.role IN ?)) AND notifications.created_at >= ?)",
+			domain.AudienceUser, recipient.UserID,
+			domain.AudienceAll, domain.AudienceRole, recipient.Roles, recipient.Since,
+		)
+	}
+}
+
+// withReadState joins the recipient's read marks, the query must then select readState
+func withReadState(recipient *domain.NotificationRecipient) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Joins("LEFT JOIN notification_reads ON notification_reads.notification_id = notifications.id AND notification_reads.user_id = ?", recipient.UserID)
+	}
+}
+
+const readState = "notifications.*, notification_reads.user_id IS NOT NULL AS is_read"
+
+func (r *Repository) CreateNotification(ctx context.Context, data *domain.Notification) (*domain.Notification, error) {
+	if data.Audience == "" {
+		data.Audience = domain.AudienceUser
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.Notification{}).Create(&data).Error; err != nil {
 		return nil, err
 	}
+	r.publishNotification(ctx, signalUser(data), "created")
 	return data, nil
 }
 
-func (r *Repository) ListNotification(req *domain.ListNotificationRequest) ([]*domain.Notification, int64, error) {
+func (r *Repository) ListNotification(ctx context.Context, recipient *domain.NotificationRecipient, req *domain.ListNotificationRequest) ([]*domain.Notification, int64, error) {
 	var datas []*domain.Notification
 	var count int64
-	f := r.db.Model(&domain.Notification{})
+	f := r.db.WithContext(ctx).Model(&domain.Notification{}).Scopes(addressedTo(recipient), withReadState(recipient))
 	if req.Query != "" {
-		req.SortColumn = "score desc, " + req.SortColumn
+		f = f.Where("notifications.title ILIKE ?", "%"+req.Query+"%")
+	}
+	if req.Type != "" {
+		f = f.Where("notifications.type = ?", req.Type)
+	}
+	if req.Module != "" {
+		f = f.Where("notifications.module = ?", req.Module)
+	}
+	if req.IsRead != nil {
+		if *req.IsRead {
+			f = f.Where("notification_reads.user_id IS NOT NULL")
+		} else {
+			f = f.Where("notification_reads.user_id IS NULL")
+		}
 	}
 	err := f.Count(&count).
-		Order(req.SortColumn + " " + req.SortDirection).
+		Select(readState).
+		Order("notifications." + req.SortColumn + " " + req.SortDirection).
 		Limit(req.Size).
 		Offset(req.Size * (req.Page - 1)).
 		Find(&datas).Error
@@ -31,37 +92,116 @@ func (r *Repository) ListNotification(req *domain.ListNotificationRequest) ([]*d
 	return datas, count, nil
 }
 
-func (r *Repository) GetNotification(id string) (*domain.Notification, error) {
+func (r *Repository) GetNotification(ctx context.Context, id string) (*domain.Notification, error) {
 	var data domain.Notification
-	if err := r.db.Model(&domain.Notification{}).
-		Preload("Notification").
+	if err := r.db.WithContext(ctx).Model(&domain.Notification{}).
 		Take(&data, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
 
-func (r *Repository) UpdateNotification(id string, req domain.Map) (*domain.Notification, error) {
+func (r *Repository) GetRecipientNotification(ctx context.Context, id string, recipient *domain.NotificationRecipient) (*domain.Notification, error) {
+	var data domain.Notification
+	if err := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Scopes(addressedTo(recipient), withReadState(recipient)).
+		Select(readState).
+		Take(&data, "notifications.id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateNotification(ctx context.Context, id string, req domain.Map) (*domain.Notification, error) {
 	if id == "" {
-		return nil, errors.New("required notification id")
+		return nil, domain.NewValidationError("id_required", "required notification id")
 	}
 	data := &domain.Notification{}
-	err := r.db.Model(&domain.Notification{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	err := r.db.WithContext(ctx).Model(&domain.Notification{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
 	if err != nil {
 		return nil, err
 	}
+	r.publishNotification(ctx, signalUser(data), "updated")
 	return data, nil
 }
 
-func (r *Repository) ReadAllNotification(req domain.Map) (*domain.Notification, error) {
-	data := &domain.Notification{}
-	err := r.db.Model(&domain.Notification{}).Where("is_read = ?", false).Updates(req.ToMap()).Take(&data).Error
+func (r *Repository) MarkNotificationRead(ctx context.Context, id, userID string) error {
+	err := r.db.WithContext(ctx).Model(&domain.NotificationRead{}).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.NotificationRead{NotificationID: id, UserID: userID, ReadAt: time.Now()}).Error
+	if err != nil {
+		return err
+	}
+	r.publishNotification(ctx, userID, "read")
+	return nil
+}
+
+func (r *Repository) MarkNotificationUnread(ctx context.Context, id, userID string) error {
+	err := r.db.WithContext(ctx).Where("notification_id = ? AND user_id = ?", id, userID).
+		Delete(&domain.NotificationRead{}).Error
+	if err != nil {
+		return err
+	}
+	r.publishNotification(ctx, userID, "unread")
+	return nil
+}
+
+// ReadAllNotification marks everything addressed to the recipient as read by them and returns
+// how many were newly marked
+func (r *Repository) ReadAllNotification(ctx context.Context, recipient *domain.NotificationRecipient) (int64, error) {
+	unread := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Scopes(addressedTo(recipient), withReadState(recipient)).
+		Where("notification_reads.user_id IS NULL").
+		Select("notifications.id, ?::text, ?::timestamptz", recipient.UserID, time.Now())
+	result := r.db.WithContext(ctx).Exec("INSERT INTO notification_reads (notification_id, user_id, read_at) ? ON CONFLICT DO NOTHING", unread)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected > 0 {
+		r.publishNotification(ctx, recipient.UserID, "read")
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *Repository) DeleteNotification(ctx context.Context, id string) error {
+	var data domain.Notification
+	if err := r.db.WithContext(ctx).Model(&domain.Notification{}).Select("audience", "user_id").Take(&data, "id = ?", id).Error; err != nil {
+		return err
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("notification_id = ?", id).Delete(&domain.NotificationRead{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Notification{}).Where("id = ?", id).Delete(&domain.Notification{}).Error
+	})
 	if err != nil {
+		return err
+	}
+	r.publishNotification(ctx, signalUser(&data), "deleted")
+	return nil
+}
+
+func (r *Repository) ListNotificationSince(ctx context.Context, recipient *domain.NotificationRecipient, since time.Time, limit int) ([]*domain.Notification, error) {
+	var datas []*domain.Notification
+	if err := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Scopes(addressedTo(recipient), withReadState(recipient)).
+		Select(readState).
+		Where("notifications.created_at > ?", since).
+		Order("notifications.created_at asc").
+		Limit(limit).
+		Find(&datas).Error; err != nil {
 		return nil, err
 	}
-	return data, nil
+	return datas, nil
 }
 
-func (r *Repository) DeleteNotification(id string) error {
-	return r.db.Model(&domain.Notification{}).Where("id = ?", id).Delete(&domain.Notification{}).Error
+func (r *Repository) CountUnreadNotification(ctx context.Context, recipient *domain.NotificationRecipient) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Scopes(addressedTo(recipient), withReadState(recipient)).
+		Where("notification_reads.user_id IS NULL").
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
 }
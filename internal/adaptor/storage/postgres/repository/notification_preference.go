@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) ListUserNotificationPreference(ctx context.Context, userID string) ([]*domain.NotificationPreference, error) {
+	var datas []*domain.NotificationPreference
+	if err := r.db.WithContext(ctx).Model(&domain.NotificationPreference{}).
+		Where("user_id = ?", userID).
+		Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) SaveNotificationPreferences(ctx context.Context, datas []*domain.NotificationPreference) error {
+	return r.db.WithContext(ctx).Model(&domain.NotificationPreference{}).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "event_type"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "target", "updated_at"}),
+		}).
+		Create(&datas).Error
+}
+
+func (r *Repository) ClaimNotificationDispatch(ctx context.Context, data *domain.NotificationDispatch) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.NotificationDispatch{}).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&data)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
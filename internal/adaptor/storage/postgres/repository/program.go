@@ -8,7 +8,7 @@ import (
 
 // CreateProgram creates a new Program record in the database
 func (r *Repository) CreateProgram(ctx context.Context, data *domain.Program) (*domain.Program, error) {
-	err := r.db.Model(&domain.Program{}).Create(&data).Take(data).Error
+	err := r.db.WithContext(ctx).Model(&domain.Program{}).Create(&data).Take(data).Error
 	if err != nil {
 		return nil, err
 	}
@@ -17,9 +17,9 @@ func (r *Repository) CreateProgram(ctx context.Context, data *domain.Program) (*
 
 func (r *Repository) GetbyNameProgram(ctx context.Context, name string) (*domain.Program, error) {
 	data := &domain.Program{}
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Model(&domain.Program{}).
-		Select("id, name, created_at, updated_at, weight, is_active").
+		Select("id, name, created_at, updated_at, weight, is_active, faculty_id, code, duration_years").
 		Where("name = ? AND is_active = true", name).
 		Take(&data).Error
 	if err != nil {
@@ -30,9 +30,9 @@ func (r *Repository) GetbyNameProgram(ctx context.Context, name string) (*domain
 
 func (r *Repository) GetProgram(ctx context.Context, id string) (*domain.Program, error) {
 	data := &domain.Program{}
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Model(&domain.Program{}).
-		Select("id, name, created_at, updated_at, weight, is_active").
+		Select("id, name, created_at, updated_at, weight, is_active, faculty_id, code, duration_years").
 		Where("id = ? AND is_active = true", id).
 		Take(&data).Error
 	if err != nil {
@@ -44,23 +44,25 @@ func (r *Repository) GetProgram(ctx context.Context, id string) (*domain.Program
 // 3. List All Categories
 func (r *Repository) ListProgram(ctx context.Context, req *domain.ListProgramRequest) ([]*domain.Program, int64, error) {
 	var categories []*domain.Program
-	count := 0
-	f := r.db.Model(&domain.Program{})
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Program{})
 	f = f.Where("lower(name) LIKE lower(?)", "%"+req.Query+"%")
-	err := f.Order(req.SortColumn + " " + req.SortDirection).
+	if req.FacultyID != "" {
+		f = f.Where("faculty_id = ?", req.FacultyID)
+	}
+	err := f.Count(&count).Order(req.SortColumn + " " + req.SortDirection).
 		Limit(req.Size).
 		Offset(req.Size * (req.Page - 1)).
 		Find(&categories).Error
 	if err != nil {
-		return nil, int64(count), err
+		return nil, count, err
 	}
-	return categories, int64(count), nil
+	return categories, count, nil
 }
 
 // 4. Update Program
 func (r *Repository) UpdateProgram(ctx context.Context, id string, req domain.Map) error {
-	Program := &domain.Program{}
-	err := r.db.Model(&domain.Program{}).Where("id = ?", id).Updates(Program).Error
+	err := r.db.WithContext(ctx).Model(&domain.Program{}).Where("id = ?", id).Updates(req.ToMap()).Error
 	if err != nil {
 		return err
 	}
@@ -69,5 +71,5 @@ func (r *Repository) UpdateProgram(ctx context.Context, id string, req domain.Ma
 
 // 5. Delete Program
 func (r *Repository) DeleteProgram(ctx context.Context, id string) error {
-	return r.db.Model(&domain.Program{}).Where("id = ?", id).Delete(&domain.Program{}).Error
+	return r.db.WithContext(ctx).Model(&domain.Program{}).Where("id = ?", id).Delete(&domain.Program{}).Error
 }
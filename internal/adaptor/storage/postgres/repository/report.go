@@ -1,61 +1,69 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
-func (r *Repository) GetLibraryDashboardStats() (*domain.LibraryDashboardStats, error) {
+func (r *Repository) GetLibraryDashboardStats(ctx context.Context) (*domain.LibraryDashboardStats, error) {
 	var stats domain.LibraryDashboardStats
 	now := time.Now()
 
 	//Count total students
-	if err := r.db.Model(&domain.User{}).
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).
 		Where("role = ?", "student").
 		Count(&stats.TotalStudents).Error; err != nil {
 		return nil, err
 	}
 
 	//Count active students
-	if err := r.db.Model(&domain.User{}).
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).
 		Where("role = ? AND is_active = ?", "student", true).
 		Count(&stats.ActiveStudents).Error; err != nil {
 		return nil, err
 	}
 
 	//Count total active books
-	if err := r.db.Model(&domain.Book{}).
+	if err := r.db.WithContext(ctx).Model(&domain.Book{}).
 		Count(&stats.TotalBooks).Error; err != nil {
 		return nil, err
 	}
 
 	// Count total pending books
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("status = ? AND returned_date IS NULL AND is_active = ?", "borrowed", true).
 		Count(&stats.PendingRequests).Error; err != nil {
 		return nil, err
 	}
 
 	// Count total borrowed books
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("status = ? AND due_date < ? AND returned_date IS NULL AND is_active = ?", "borrowed", now, true).
 		Count(&stats.BorrowedBooks).Error; err != nil {
 		return nil, err
 	}
 
 	// Count overdue books
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("status = ? AND due_date < ? AND returned_date IS NULL AND is_active = ?", "borrowed", now, true).
 		Count(&stats.OverdueBooks).Error; err != nil {
 		return nil, err
 	}
 
+	// Sum outstanding fines
+	totalFines, err := r.GetOutstandingFines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.TotalFines = totalFines
+
 	return &stats, nil
 }
 
-func (r *Repository) GetMonthlyChartData() ([]domain.ChartData, error) {
+func (r *Repository) GetMonthlyChartData(ctx context.Context) ([]domain.ChartData, error) {
 	var results []domain.ChartData
 
 	type TempData struct {
@@ -71,7 +79,7 @@ func (r *Repository) GetMonthlyChartData() ([]domain.ChartData, error) {
 
 	var data []TempData
 
-	err := r.db.Raw(`
+	err := r.db.WithContext(ctx).Raw(`
 			WITH borrow_summary AS (
 				SELECT 
 					TO_CHAR(borrowed_date, 'Mon') AS month,
@@ -132,7 +140,7 @@ func (r *Repository) GetMonthlyChartData() ([]domain.ChartData, error) {
 
 	return results, nil
 }
-func (r *Repository) GetDailyChartData(req *domain.ChartRequest) ([]domain.ChartData, error) {
+func (r *Repository) GetDailyChartData(ctx context.Context, req *domain.ChartRequest) ([]domain.ChartData, error) {
 	var startDate, endDate time.Time
 	var err error
 
@@ -260,7 +268,7 @@ func (r *Repository) GetDailyChartData(req *domain.ChartRequest) ([]domain.Chart
 	}
 	var rawData []Temp
 
-	err = r.db.Raw(
+	err = r.db.WithContext(ctx).Raw(
 		query,
 		startDateStr, endDateStr, // borrow
 		startDateStr, endDateStr, // students
@@ -387,25 +395,25 @@ func formatGroupKey(d time.Time, rng string) string {
 	}
 }
 
-func (r *Repository) GetBorrowedBookStats() (*domain.BorrowedBookStats, error) {
+func (r *Repository) GetBorrowedBookStats(ctx context.Context) (*domain.BorrowedBookStats, error) {
 	var stats domain.BorrowedBookStats
 	now := time.Now()
 
 	// Count total borrowed books
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Count(&stats.TotalBorrowedBooks).Error; err != nil {
 		return nil, err
 	}
 
 	// Count overdue books
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("status = ? AND due_date < ? AND returned_date IS NULL AND is_active = ?", "borrowed", now, true).
 		Count(&stats.TotalOverdueBooks).Error; err != nil {
 		return nil, err
 	}
 
 	// Count pending requests
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("status = ? AND is_active = ?", "pending", true).
 		Count(&stats.PendingRequests).Error; err != nil {
 		return nil, err
@@ -413,7 +421,7 @@ func (r *Repository) GetBorrowedBookStats() (*domain.BorrowedBookStats, error) {
 
 	// Count due soon (within 3 days)
 	threeDaysLater := now.Add(72 * time.Hour)
-	if err := r.db.Model(&domain.BorrowedBook{}).
+	if err := r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).
 		Where("status = ? AND due_date BETWEEN ? AND ? AND is_active = ?", "borrowed", now, threeDaysLater, true).
 		Count(&stats.DueSoon).Error; err != nil {
 		return nil, err
@@ -422,10 +430,10 @@ func (r *Repository) GetBorrowedBookStats() (*domain.BorrowedBookStats, error) {
 	return &stats, nil
 }
 
-func (r *Repository) GetBookProgramstats() (*[]domain.BookProgramstats, error) {
+func (r *Repository) GetBookProgramstats(ctx context.Context) (*[]domain.BookProgramstats, error) {
 	var stats []domain.BookProgramstats
 
-	if err := r.db.Model(&domain.User{}).
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).
 		Select("program as program_name, count(*) as count").
 		Where("role = ?", "student").
 		Group("program").
@@ -436,7 +444,7 @@ func (r *Repository) GetBookProgramstats() (*[]domain.BookProgramstats, error) {
 	return &stats, nil
 }
 
-func (r *Repository) GetInventorystats() (*domain.InventoryStats, error) {
+func (r *Repository) GetInventorystats(ctx context.Context) (*domain.InventoryStats, error) {
 	var stats domain.InventoryStats
 	var totalBooks int64
 	var borrowedBooks int64
@@ -444,20 +452,22 @@ func (r *Repository) GetInventorystats() (*domain.InventoryStats, error) {
 	var totalStudents int64
 	var activeStudents int64
 	var pendingRequests int64
-	var totalFines int64
 
 	// Queries
-	r.db.Model(&domain.Book{}).Count(&totalBooks)
-	r.db.Model(&domain.BorrowedBook{}).Where("status = ?", "borrowed").Count(&borrowedBooks)
-	r.db.Model(&domain.BorrowedBook{}).Where("status = ?", "overdue").Count(&overdueBooks)
-	r.db.Model(&domain.User{}).Where("role = ?", "student").Count(&totalStudents)
-	r.db.Model(&domain.User{}).Where("role = ? AND is_active = ?", "student", true).Count(&activeStudents)
-	r.db.Model(&domain.BorrowedBook{}).Where("status = ?", "pending").Count(&pendingRequests)
-	// r.db.Model(&domain.Fine{}).Where("status = ?", "pending").Select("SUM(amount)").Scan(&totalFines)
+	r.db.WithContext(ctx).Model(&domain.Book{}).Count(&totalBooks)
+	r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).Where("status = ?", "borrowed").Count(&borrowedBooks)
+	r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).Where("status = ?", "overdue").Count(&overdueBooks)
+	r.db.WithContext(ctx).Model(&domain.User{}).Where("role = ?", "student").Count(&totalStudents)
+	r.db.WithContext(ctx).Model(&domain.User{}).Where("role = ? AND is_active = ?", "student", true).Count(&activeStudents)
+	r.db.WithContext(ctx).Model(&domain.BorrowedBook{}).Where("status = ?", "pending").Count(&pendingRequests)
+	totalFines, err := r.GetOutstandingFines(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// Available books = sum of all book copies - borrowed books
 	var availableBooks int64
-	r.db.Model(&domain.Book{}).Select("SUM(total_copies)").Scan(&availableBooks)
+	r.db.WithContext(ctx).Model(&domain.Book{}).Select("SUM(total_copies)").Scan(&availableBooks)
 	availableBooks = availableBooks - borrowedBooks
 
 	stats = domain.InventoryStats{
@@ -15,3 +15,26 @@ func NewRepository(db *gorm.DB) port.Repository {
 		db,
 	}
 }
+
+// exportBatchSize is how many rows an export reads at a time
+const exportBatchSize = 500
+
+// eachBatch pages through the query and hands every page to fn, so that an export never loads
+// the whole result
+func eachBatch[T any](f *gorm.DB, fn func([]*T) error) error {
+	f = f.Session(&gorm.Session{})
+	for offset := 0; ; offset += exportBatchSize {
+		var datas []*T
+		if err := f.Limit(exportBatchSize).Offset(offset).Find(&datas).Error; err != nil {
+			return err
+		}
+		if len(datas) > 0 {
+			if err := fn(datas); err != nil {
+				return err
+			}
+		}
+		if len(datas) < exportBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (r *Repository) CreateRoom(ctx context.Context, data *domain.Room) (*domain.Room, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Room{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListRoom(ctx context.Context, req *domain.ListRoomRequest) ([]*domain.Room, int64, error) {
+	var datas []*domain.Room
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Room{})
+	if req.Query != "" {
+		f = f.Where("room_number ILIKE ? OR room_code ILIKE ?", "%"+req.Query+"%", "%"+req.Query+"%")
+	}
+	if req.FloorID != "" {
+		f = f.Where("floor_id = ?", req.FloorID)
+	}
+	if req.BuildingID != "" {
+		f = f.Where("floor_id IN (?)", r.db.WithContext(ctx).Model(&domain.Floor{}).Select("id").Where("building_id = ?", req.BuildingID))
+	}
+	if req.RoomType != "" {
+		f = f.Where("room_type = ?", req.RoomType)
+	}
+	if req.MinCapacity > 0 {
+		f = f.Where("capacity >= ?", req.MinCapacity)
+	}
+	if req.MaxCapacity > 0 {
+		f = f.Where("capacity <= ?", req.MaxCapacity)
+	}
+	if req.HasProjector != nil {
+		f = f.Where("has_projector = ?", *req.HasProjector)
+	}
+	if req.HasAC != nil {
+		f = f.Where("has_ac = ?", *req.HasAC)
+	}
+	if req.Status != "" {
+		f = f.Where("status = ?", req.Status)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Preload("Floor").
+		Preload("Floor.Building").
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetRoom(ctx context.Context, id string) (*domain.Room, error) {
+	var data domain.Room
+	if err := r.db.WithContext(ctx).Model(&domain.Room{}).
+		Preload("Floor").
+		Preload("Floor.Building").
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateRoom(ctx context.Context, id string, req domain.Map) (*domain.Room, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required room id")
+	}
+	data := &domain.Room{}
+	err := r.db.WithContext(ctx).Model(&domain.Room{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) DeleteRoom(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Room{}).Where("id = ?", id).Delete(&domain.Room{}).Error
+}
+
+func (r *Repository) CountRoomRoutines(ctx context.Context, roomID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).
+		Where("room_id = ?", roomID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
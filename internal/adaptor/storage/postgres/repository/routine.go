@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// routineIndexConflicts maps the class_routines unique indexes to conflict types
+var routineIndexConflicts = map[string]string{
+	"idx_room_time":     domain.ConflictRoom,
+	"idx_teacher_time":  domain.ConflictTeacher,
+	"idx_semester_time": domain.ConflictSemester,
+}
+
+// routineError turns a unique index violation into a RoutineConflictError so a
+// clash that slipped past the pre-insert checks is not reported as a raw database error
+func routineError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		if kind, ok := routineIndexConflicts[pgErr.ConstraintName]; ok {
+			return &domain.RoutineConflictError{Conflicts: []domain.RoutineConflict{{
+				Type:    kind,
+				Message: kind + " is already booked on this day and time slot",
+			}}}
+		}
+	}
+	return err
+}
+
+func preloadRoutine(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Program").
+		Preload("Semester").
+		Preload("Subject").
+		Preload("Teacher").
+		Preload("Room").
+		Preload("Room.Floor").
+		Preload("Room.Floor.Building").
+		Preload("Room.Floor.Building.Campus").
+		Preload("TimeSlot")
+}
+
+func (r *Repository) CreateRoutine(ctx context.Context, data *domain.ClassRoutine) (*domain.ClassRoutine, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).Create(&data).Error; err != nil {
+		return nil, routineError(err)
+	}
+	return r.GetRoutine(ctx, data.ID)
+}
+
+func (r *Repository) ListRoutine(ctx context.Context, req *domain.ListClassRoutineRequest) ([]*domain.ClassRoutine, int64, error) {
+	var datas []*domain.ClassRoutine
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.ClassRoutine{})
+	if req.ProgramID != "" {
+		f = f.Where("program_id = ?", req.ProgramID)
+	}
+	if req.SemesterID != "" {
+		f = f.Where("semester_id = ?", req.SemesterID)
+	}
+	if req.SubjectID != "" {
+		f = f.Where("subject_id = ?", req.SubjectID)
+	}
+	if req.TeacherID != "" {
+		f = f.Where("teacher_id = ?", req.TeacherID)
+	}
+	if req.RoomID != "" {
+		f = f.Where("room_id = ?", req.RoomID)
+	}
+	if req.TimeSlotID != "" {
+		f = f.Where("time_slot_id = ?", req.TimeSlotID)
+	}
+	if req.Section != "" {
+		f = f.Where("section = ?", req.Section)
+	}
+	if req.DayOfWeek != "" {
+		f = f.Where("day_of_week = ?", req.DayOfWeek)
+	}
+	if req.AcademicYear != "" {
+		f = f.Where("academic_year = ?", req.AcademicYear)
+	}
+	err := preloadRoutine(f.Count(&count)).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetRoutine(ctx context.Context, id string) (*domain.ClassRoutine, error) {
+	var data domain.ClassRoutine
+	if err := preloadRoutine(r.db.WithContext(ctx).Model(&domain.ClassRoutine{})).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateRoutine(ctx context.Context, id string, req domain.Map) (*domain.ClassRoutine, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required routine id")
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).Where("id = ?", id).Updates(req.ToMap()).Error; err != nil {
+		return nil, routineError(err)
+	}
+	return r.GetRoutine(ctx, id)
+}
+
+func (r *Repository) DeleteRoutine(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).Where("id = ?", id).Delete(&domain.ClassRoutine{}).Error
+}
+
+// ListClashingRoutines returns routines sharing the day and time slot with the given routine
+// in the same room, with the same teacher or for the same semester. These mirror the
+// idx_room_time, idx_teacher_time and idx_semester_time unique indexes.
+func (r *Repository) ListClashingRoutines(ctx context.Context, data *domain.ClassRoutine) ([]*domain.ClassRoutine, error) {
+	var datas []*domain.ClassRoutine
+	f := r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).
+		Where("day_of_week = ? AND time_slot_id = ?", data.DayOfWeek, data.TimeSlotID).
+		Where("room_id = ? OR teacher_id = ? OR semester_id = ?", data.RoomID, data.TeacherID, data.SemesterID)
+	if data.ID != "" {
+		f = f.Where("id <> ?", data.ID)
+	}
+	if err := preloadRoutine(f).Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) CreateTeacherUnavailability(ctx context.Context, data *domain.TeacherUnavailability) (*domain.TeacherUnavailability, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.TeacherUnavailability{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListTeacherUnavailability(ctx context.Context, req *domain.ListTeacherUnavailabilityRequest) ([]*domain.TeacherUnavailability, int64, error) {
+	var datas []*domain.TeacherUnavailability
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.TeacherUnavailability{})
+	if req.TeacherID != "" {
+		f = f.Where("teacher_id = ?", req.TeacherID)
+	}
+	if req.DayOfWeek != "" {
+		f = f.Where("day_of_week = ?", req.DayOfWeek)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetTeacherUnavailability(ctx context.Context, id string) (*domain.TeacherUnavailability, error) {
+	var data domain.TeacherUnavailability
+	if err := r.db.WithContext(ctx).Model(&domain.TeacherUnavailability{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) DeleteTeacherUnavailability(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.TeacherUnavailability{}).Where("id = ?", id).Delete(&domain.TeacherUnavailability{}).Error
+}
+
+func (r *Repository) IsTeacherUnavailable(ctx context.Context, teacherID string, day domain.DayOfWeek, timeSlotID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.TeacherUnavailability{}).
+		Where("teacher_id = ? AND day_of_week = ?", teacherID, day).
+		Where("time_slot_id IS NULL OR time_slot_id = ?", timeSlotID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
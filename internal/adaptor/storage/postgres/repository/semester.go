@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (r *Repository) CreateSemester(ctx context.Context, data *domain.Semester) (*domain.Semester, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Semester{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListSemester(ctx context.Context, req *domain.ListSemesterRequest) ([]*domain.Semester, int64, error) {
+	var datas []*domain.Semester
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Semester{})
+	if req.Query != "" {
+		f = f.Where("name ILIKE ?", "%"+req.Query+"%")
+	}
+	if req.ProgramID != "" {
+		f = f.Where("program_id = ?", req.ProgramID)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetSemester(ctx context.Context, id string) (*domain.Semester, error) {
+	var data domain.Semester
+	if err := r.db.WithContext(ctx).Model(&domain.Semester{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateSemester(ctx context.Context, id string, req domain.Map) (*domain.Semester, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required semester id")
+	}
+	data := &domain.Semester{}
+	err := r.db.WithContext(ctx).Model(&domain.Semester{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) DeleteSemester(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Semester{}).Where("id = ?", id).Delete(&domain.Semester{}).Error
+}
+
+func (r *Repository) CountProgramSemesters(ctx context.Context, programID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Semester{}).
+		Where("program_id = ?", programID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *Repository) CountSemesterSubjects(ctx context.Context, semesterID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Subject{}).
+		Where("semester_id = ?", semesterID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *Repository) CountSemesterRoutines(ctx context.Context, semesterID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).
+		Where("semester_id = ?", semesterID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) CreateSMSDelivery(ctx context.Context, data *domain.SMSDelivery) (*domain.SMSDelivery, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.SMSDelivery{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListSMSDelivery(ctx context.Context, req *domain.ListSMSDeliveryRequest) ([]*domain.SMSDelivery, int64, error) {
+	var datas []*domain.SMSDelivery
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.SMSDelivery{})
+	if req.UserID != "" {
+		f = f.Where("user_id = ?", req.UserID)
+	}
+	if req.Template != "" {
+		f = f.Where("template = ?", req.Template)
+	}
+	if req.Status != "" {
+		f = f.Where("status = ?", req.Status)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetSMSDelivery(ctx context.Context, id string) (*domain.SMSDelivery, error) {
+	var data domain.SMSDelivery
+	if err := r.db.WithContext(ctx).Model(&domain.SMSDelivery{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateSMSDelivery(ctx context.Context, id string, req domain.Map) (*domain.SMSDelivery, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required sms delivery id")
+	}
+	data := &domain.SMSDelivery{}
+	err := r.db.WithContext(ctx).Model(&domain.SMSDelivery{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ClaimDueSMSDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.SMSDelivery, error) {
+	var datas []*domain.SMSDelivery
+	due := r.db.WithContext(ctx).Model(&domain.SMSDelivery{}).
+		Select("id").
+		Where("status = ? AND next_attempt_at <= ?", domain.SMSQueued, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	if err := r.db.WithContext(ctx).Raw("UPDATE sms_deliveries SET next_attempt_at = ? WHERE id IN (?) RETURNING *", now.Add(lease), due).
+		Scan(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+// smsLimitLock with the user id hashed serialises queueing messages to one user
+const smsLimitLock = 7_420_002
+
+// CreateSMSDeliveryWithinLimit queues data unless the user already has limit messages queued or
+// sent since, then it is recorded as limited. The ones dropped or refused do not count.
+func (r *Repository) CreateSMSDeliveryWithinLimit(ctx context.Context, data *domain.SMSDelivery, since time.Time, limit int) (*domain.SMSDelivery, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", smsLimitLock, data.UserID).Error; err != nil {
+			return err
+		}
+		var count int64
+		err := tx.Model(&domain.SMSDelivery{}).
+			Where("user_id = ? AND status IN ? AND created_at >= ?", data.UserID, []string{domain.SMSQueued, domain.SMSSent}, since).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count >= int64(limit) {
+			data.MarkLimited(limit)
+		}
+		return tx.Model(&domain.SMSDelivery{}).Create(&data).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) GetSMSUsage(ctx context.Context, req *domain.SMSUsageRequest) ([]*domain.SMSUsage, error) {
+	var datas []*domain.SMSUsage
+	if err := r.db.WithContext(ctx).Model(&domain.SMSDelivery{}).
+		Select("template, COUNT(*) AS messages, COALESCE(SUM(segments), 0) AS segments, COALESCE(SUM(cost), 0) AS cost").
+		Where("status = ? AND created_at BETWEEN ? AND ?", domain.SMSSent, req.StartDate, req.EndDate).
+		Group("template").
+		Order("cost desc").
+		Scan(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
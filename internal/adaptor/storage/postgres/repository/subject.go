@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (r *Repository) CreateSubject(ctx context.Context, data *domain.Subject) (*domain.Subject, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Subject{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListSubject(ctx context.Context, req *domain.ListSubjectRequest) ([]*domain.Subject, int64, error) {
+	var datas []*domain.Subject
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.Subject{})
+	if req.Query != "" {
+		f = f.Where("name ILIKE ? OR code ILIKE ?", "%"+req.Query+"%", "%"+req.Query+"%")
+	}
+	if req.ProgramID != "" {
+		f = f.Where("program_id = ?", req.ProgramID)
+	}
+	if req.SemesterID != "" {
+		f = f.Where("semester_id = ?", req.SemesterID)
+	}
+	if req.Code != "" {
+		f = f.Where("code = ?", req.Code)
+	}
+	if req.IsLab != nil {
+		f = f.Where("is_lab = ?", *req.IsLab)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetSubject(ctx context.Context, id string) (*domain.Subject, error) {
+	var data domain.Subject
+	if err := r.db.WithContext(ctx).Model(&domain.Subject{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateSubject(ctx context.Context, id string, req domain.Map) (*domain.Subject, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required subject id")
+	}
+	data := &domain.Subject{}
+	err := r.db.WithContext(ctx).Model(&domain.Subject{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) DeleteSubject(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Subject{}).Where("id = ?", id).Delete(&domain.Subject{}).Error
+}
+
+func (r *Repository) CountProgramSubjects(ctx context.Context, programID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Subject{}).
+		Where("program_id = ?", programID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *Repository) CountSubjectRoutines(ctx context.Context, subjectID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).
+		Where("subject_id = ?", subjectID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
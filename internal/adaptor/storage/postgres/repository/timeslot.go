@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (r *Repository) CreateTimeSlot(ctx context.Context, data *domain.TimeSlot) (*domain.TimeSlot, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.TimeSlot{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListTimeSlot(ctx context.Context, req *domain.ListTimeSlotRequest) ([]*domain.TimeSlot, int64, error) {
+	var datas []*domain.TimeSlot
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.TimeSlot{})
+	if req.Query != "" {
+		f = f.Where("name ILIKE ?", "%"+req.Query+"%")
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetTimeSlot(ctx context.Context, id string) (*domain.TimeSlot, error) {
+	var data domain.TimeSlot
+	if err := r.db.WithContext(ctx).Model(&domain.TimeSlot{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateTimeSlot(ctx context.Context, id string, req domain.Map) (*domain.TimeSlot, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required time slot id")
+	}
+	data := &domain.TimeSlot{}
+	err := r.db.WithContext(ctx).Model(&domain.TimeSlot{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) DeleteTimeSlot(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.TimeSlot{}).Where("id = ?", id).Delete(&domain.TimeSlot{}).Error
+}
+
+// ListOverlappingTimeSlots returns the slots sharing any minute with [start, end), touching slots do not overlap
+func (r *Repository) ListOverlappingTimeSlots(ctx context.Context, start, end time.Time, excludeID string) ([]*domain.TimeSlot, error) {
+	var datas []*domain.TimeSlot
+	f := r.db.WithContext(ctx).Model(&domain.TimeSlot{}).
+		Where("start_time < ? AND end_time > ?", end, start)
+	if excludeID != "" {
+		f = f.Where("id <> ?", excludeID)
+	}
+	if err := f.Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) CountTimeSlotRoutines(ctx context.Context, timeSlotID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).
+		Where("time_slot_id = ?", timeSlotID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) ListTimetableRoutines(ctx context.Context, req *domain.TimetableRequest) ([]*domain.ClassRoutine, error) {
+	var datas []*domain.ClassRoutine
+	f := r.db.WithContext(ctx).Model(&domain.ClassRoutine{})
+	switch req.View {
+	case domain.TimetableSemester:
+		f = f.Where("semester_id = ?", req.ID)
+		if req.Section != "" {
+			f = f.Where("section = ?", req.Section)
+		}
+	case domain.TimetableTeacher:
+		f = f.Where("teacher_id = ?", req.ID)
+	case domain.TimetableRoom:
+		f = f.Where("room_id = ?", req.ID)
+	default:
+		return nil, errors.New("unsupported timetable view " + req.View)
+	}
+	if req.AcademicYear != "" {
+		f = f.Where("academic_year = ?", req.AcademicYear)
+	}
+	if err := preloadRoutine(f).Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) ListAllTimeSlot(ctx context.Context) ([]*domain.TimeSlot, error) {
+	var datas []*domain.TimeSlot
+	if err := r.db.WithContext(ctx).Model(&domain.TimeSlot{}).
+		Order("start_time asc").
+		Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) CreateAcademicYear(ctx context.Context, data *domain.AcademicYear) (*domain.AcademicYear, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.AcademicYear{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListAcademicYear(ctx context.Context, req *domain.ListAcademicYearRequest) ([]*domain.AcademicYear, int64, error) {
+	var datas []*domain.AcademicYear
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.AcademicYear{})
+	if req.Name != "" {
+		f = f.Where("name = ?", req.Name)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetAcademicYear(ctx context.Context, id string) (*domain.AcademicYear, error) {
+	var data domain.AcademicYear
+	if err := r.db.WithContext(ctx).Model(&domain.AcademicYear{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) GetAcademicYearByName(ctx context.Context, name string) (*domain.AcademicYear, error) {
+	var data domain.AcademicYear
+	if err := r.db.WithContext(ctx).Model(&domain.AcademicYear{}).
+		Take(&data, "name = ?", name).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) GetCurrentAcademicYear(ctx context.Context) (*domain.AcademicYear, error) {
+	var data domain.AcademicYear
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Model(&domain.AcademicYear{}).
+		Where("start_date <= ? AND end_date >= ?", now, now).
+		Order("start_date desc").
+		Take(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateAcademicYear(ctx context.Context, id string, req domain.Map) (*domain.AcademicYear, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required academic year id")
+	}
+	data := &domain.AcademicYear{}
+	err := r.db.WithContext(ctx).Model(&domain.AcademicYear{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) DeleteAcademicYear(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.AcademicYear{}).Where("id = ?", id).Delete(&domain.AcademicYear{}).Error
+}
+
+func (r *Repository) CountAcademicYearRoutines(ctx context.Context, name string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).
+		Where("academic_year = ?", name).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *Repository) SaveCalendarToken(ctx context.Context, data *domain.CalendarToken) (*domain.CalendarToken, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", data.UserID).Delete(&domain.CalendarToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.CalendarToken{}).Create(&data).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) GetCalendarToken(ctx context.Context, userID string) (*domain.CalendarToken, error) {
+	var data domain.CalendarToken
+	if err := r.db.WithContext(ctx).Model(&domain.CalendarToken{}).
+		Take(&data, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) GetCalendarTokenByHash(ctx context.Context, hash string) (*domain.CalendarToken, error) {
+	var data domain.CalendarToken
+	if err := r.db.WithContext(ctx).Model(&domain.CalendarToken{}).
+		Take(&data, "token_hash = ?", hash).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) DeleteCalendarToken(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.CalendarToken{}).Error
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func preloadDraft(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Entries").
+		Preload("Entries.Subject").
+		Preload("Entries.Teacher").
+		Preload("Entries.Room").
+		Preload("Entries.Room.Floor").
+		Preload("Entries.Room.Floor.Building").
+		Preload("Entries.Room.Floor.Building.Campus").
+		Preload("Entries.TimeSlot")
+}
+
+func (r *Repository) CreateTimetableDraft(ctx context.Context, data *domain.TimetableDraft) (*domain.TimetableDraft, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.TimetableDraft{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return r.GetTimetableDraft(ctx, data.ID)
+}
+
+func (r *Repository) ListTimetableDraft(ctx context.Context, req *domain.ListTimetableDraftRequest) ([]*domain.TimetableDraft, int64, error) {
+	var datas []*domain.TimetableDraft
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.TimetableDraft{})
+	if req.SemesterID != "" {
+		f = f.Where("semester_id = ?", req.SemesterID)
+	}
+	if req.Status != "" {
+		f = f.Where("status = ?", req.Status)
+	}
+	err := preloadDraft(f.Count(&count)).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetTimetableDraft(ctx context.Context, id string) (*domain.TimetableDraft, error) {
+	var data domain.TimetableDraft
+	if err := preloadDraft(r.db.WithContext(ctx).Model(&domain.TimetableDraft{})).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) DeleteTimetableDraft(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("draft_id = ?", id).Delete(&domain.TimetableDraftEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.TimetableDraft{}).Error
+	})
+}
+
+// CommitTimetableDraft inserts the draft's routines and marks it committed, all or nothing
+func (r *Repository) CommitTimetableDraft(ctx context.Context, draft *domain.TimetableDraft, routines []*domain.ClassRoutine) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(routines) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&routines).Error; err != nil {
+				return routineError(err)
+			}
+		}
+		return tx.Model(&domain.TimetableDraft{}).
+			Where("id = ?", draft.ID).
+			Update("status", domain.DraftCommitted).Error
+	})
+}
+
+// ListScheduledRoutines returns every routine without associations, the solver only needs the occupied keys
+func (r *Repository) ListScheduledRoutines(ctx context.Context) ([]*domain.ClassRoutine, error) {
+	var datas []*domain.ClassRoutine
+	if err := r.db.WithContext(ctx).Model(&domain.ClassRoutine{}).
+		Select("id, semester_id, teacher_id, room_id, time_slot_id, day_of_week").
+		Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) ListSchedulableRooms(ctx context.Context, ids []string) ([]*domain.Room, error) {
+	var datas []*domain.Room
+	f := r.db.WithContext(ctx).Model(&domain.Room{}).Where("status = ?", "ACTIVE")
+	if len(ids) > 0 {
+		f = f.Where("id IN ?", ids)
+	}
+	if err := f.Order("capacity asc, room_code asc").Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) ListTeachersUnavailability(ctx context.Context, teacherIDs []string) ([]*domain.TeacherUnavailability, error) {
+	var datas []*domain.TeacherUnavailability
+	if len(teacherIDs) == 0 {
+		return datas, nil
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.TeacherUnavailability{}).
+		Where("teacher_id IN ?", teacherIDs).
+		Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
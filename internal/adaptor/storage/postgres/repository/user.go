@@ -1,22 +1,23 @@
 package repository
 
 import (
-	"errors"
+	"context"
 
 	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
 )
 
-func (r *Repository) CreateUser(data *domain.User) (*domain.User, error) {
-	if err := r.db.Model(&domain.User{}).Create(&data).Error; err != nil {
+func (r *Repository) CreateUser(ctx context.Context, data *domain.User) (*domain.User, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).Create(&data).Error; err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *Repository) ListUser(req *domain.UserListRequest) ([]*domain.User, int64, error) {
+func (r *Repository) ListUser(ctx context.Context, req *domain.UserListRequest) ([]*domain.User, int64, error) {
 	var datas []*domain.User
 	var count int64
-	f := r.db.Model(&domain.User{})
+	f := r.db.WithContext(ctx).Model(&domain.User{})
 	if req.Query != "" {
 		req.SortColumn = "score desc, " + req.SortColumn
 	}
@@ -31,10 +32,31 @@ func (r *Repository) ListUser(req *domain.UserListRequest) ([]*domain.User, int6
 	return datas, count, nil
 }
 
-func (r *Repository) ListStudent(req *domain.UserListRequest) ([]*domain.User, int64, error) {
+func (r *Repository) ListStudent(ctx context.Context, req *domain.UserListRequest) ([]*domain.User, int64, error) {
 	var datas []*domain.User
 	var count int64
-	f := r.db.Model(&domain.User{}).Where("role = ?", "student")
+	err := r.studentQuery(ctx, req).
+		Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+// EachStudent hands the students matching the filters to fn a page at a time
+func (r *Repository) EachStudent(ctx context.Context, req *domain.UserListRequest, fn func([]*domain.User) error) error {
+	f := r.studentQuery(ctx, req).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Order("id")
+	return eachBatch(f, fn)
+}
+
+func (r *Repository) studentQuery(ctx context.Context, req *domain.UserListRequest) *gorm.DB {
+	f := r.db.WithContext(ctx).Model(&domain.User{}).Where("role = ?", "student")
 	if req.Query != "" {
 		req.SortColumn = "score desc, " + req.SortColumn
 	}
@@ -56,56 +78,59 @@ func (r *Repository) ListStudent(req *domain.UserListRequest) ([]*domain.User, i
 	if req.Username != "" {
 		f = f.Where("username = ?", req.Username)
 	}
-	err := f.Count(&count).
-		Order(req.SortColumn + " " + req.SortDirection).
-		Limit(req.Size).
-		Offset(req.Size * (req.Page - 1)).
-		Find(&datas).Error
-	if err != nil {
-		return nil, count, err
-	}
-	return datas, count, nil
+	return f
 }
 
-func (r *Repository) GetUser(id string) (*domain.User, error) {
+func (r *Repository) GetUser(ctx context.Context, id string) (*domain.User, error) {
 	var data domain.User
-	if err := r.db.Model(&domain.User{}).Preload("Roles").
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).Preload("Roles").
 		Take(&data, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
 
-func (r *Repository) GetStudentbyID(studentID string) (*domain.User, error) {
+func (r *Repository) ListUserByIDs(ctx context.Context, ids []string) ([]*domain.User, error) {
+	var datas []*domain.User
+	if len(ids) == 0 {
+		return datas, nil
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).Where("id IN ?", ids).Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) GetStudentbyID(ctx context.Context, studentID string) (*domain.User, error) {
 	var data domain.User
-	if err := r.db.Model(&domain.User{}).
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).
 		Take(&data, "student_id = ? and role = ?", studentID, "student").Error; err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
 
-func (r *Repository) GetUserbyUsername(username string) (*domain.User, error) {
+func (r *Repository) GetUserbyUsername(ctx context.Context, username string) (*domain.User, error) {
 	var data domain.User
-	if err := r.db.Model(&domain.User{}).Preload("Roles").
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).Preload("Roles").
 		Take(&data, "username = ?", username).Error; err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
 
-func (r *Repository) UpdateUser(id string, req domain.Map) (*domain.User, error) {
+func (r *Repository) UpdateUser(ctx context.Context, id string, req domain.Map) (*domain.User, error) {
 	if id == "" {
-		return nil, errors.New("required user id")
+		return nil, domain.NewValidationError("id_required", "required user id")
 	}
 	data := &domain.User{}
-	err := r.db.Model(&domain.User{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	err := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
 	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (r *Repository) DeleteUser(id string) error {
-	return r.db.Model(&domain.User{}).Where("id = ?", id).Delete(&domain.User{}).Error
+func (r *Repository) DeleteUser(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Delete(&domain.User{}).Error
 }
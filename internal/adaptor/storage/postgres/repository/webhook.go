@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) CreateWebhookSubscription(ctx context.Context, data *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.WebhookSubscription{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListWebhookSubscription(ctx context.Context, req *domain.ListWebhookSubscriptionRequest) ([]*domain.WebhookSubscription, int64, error) {
+	var datas []*domain.WebhookSubscription
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.WebhookSubscription{})
+	if req.Query != "" {
+		f = f.Where("name ILIKE ?", "%"+req.Query+"%")
+	}
+	if req.IsActive != nil {
+		f = f.Where("is_active = ?", *req.IsActive)
+	}
+	if req.Event != "" {
+		f = f.Scopes(subscribesTo(req.Event))
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+// subscribesTo keeps the subscriptions whose event filter takes the event type
+func subscribesTo(eventType string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(events IS NULL OR events = '[]'::jsonb OR events = 'null'::jsonb OR events @> ?::jsonb OR events @> ?::jsonb)",
+			string(domain.ConvertToJson([]string{eventType})), string(domain.ConvertToJson([]string{domain.AllEventTypes})))
+	}
+}
+
+func (r *Repository) ListEventWebhookSubscription(ctx context.Context, eventType string) ([]*domain.WebhookSubscription, error) {
+	var datas []*domain.WebhookSubscription
+	if err := r.db.WithContext(ctx).Model(&domain.WebhookSubscription{}).
+		Where("is_active").
+		Scopes(subscribesTo(eventType)).
+		Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *Repository) GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	var data domain.WebhookSubscription
+	if err := r.db.WithContext(ctx).Model(&domain.WebhookSubscription{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateWebhookSubscription(ctx context.Context, id string, req domain.Map) (*domain.WebhookSubscription, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required webhook subscription id")
+	}
+	data := &domain.WebhookSubscription{}
+	err := r.db.WithContext(ctx).Model(&domain.WebhookSubscription{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// DeleteWebhookSubscription removes the subscription and its delivery log
+func (r *Repository) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.WebhookSubscription{}).Where("id = ?", id).Delete(&domain.WebhookSubscription{}).Error
+	})
+}
+
+func (r *Repository) CreateWebhookDelivery(ctx context.Context, data *domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ListWebhookDelivery(ctx context.Context, req *domain.ListWebhookDeliveryRequest) ([]*domain.WebhookDelivery, int64, error) {
+	var datas []*domain.WebhookDelivery
+	var count int64
+	f := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{})
+	if req.SubscriptionID != "" {
+		f = f.Where("subscription_id = ?", req.SubscriptionID)
+	}
+	if req.EventType != "" {
+		f = f.Where("event_type = ?", req.EventType)
+	}
+	if req.Status != "" {
+		f = f.Where("status = ?", req.Status)
+	}
+	err := f.Count(&count).
+		Order(req.SortColumn + " " + req.SortDirection).
+		Limit(req.Size).
+		Offset(req.Size * (req.Page - 1)).
+		Find(&datas).Error
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+func (r *Repository) GetWebhookDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	var data domain.WebhookDelivery
+	if err := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *Repository) UpdateWebhookDelivery(ctx context.Context, id string, req domain.Map) (*domain.WebhookDelivery, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required webhook delivery id")
+	}
+	data := &domain.WebhookDelivery{}
+	err := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).Where("id = ?", id).Updates(req.ToMap()).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *Repository) ClaimDueWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	var datas []*domain.WebhookDelivery
+	due := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).
+		Select("id").
+		Where("status = ? AND next_attempt_at <= ?", domain.WebhookPending, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	if err := r.db.WithContext(ctx).Raw("UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id IN (?) RETURNING *", now.Add(lease), due).
+		Scan(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
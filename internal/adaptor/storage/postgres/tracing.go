@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+var tracer = otel.Tracer("github.com/sugaml/lms-api/internal/adaptor/storage/postgres")
+
+// tracingPlugin opens a span around every statement under the span of the caller's context. The
+// statement is recorded with its placeholders, the values can hold personal data.
+type tracingPlugin struct{}
+
+func (tracingPlugin) Name() string {
+	return "tracing"
+}
+
+// spanContext is the statement's context while its span is open, parent is put back afterwards so
+// that the preloads that follow are not nested under a finished span
+type spanContext struct {
+	context.Context
+	parent context.Context
+}
+
+func (p tracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		name          string
+		before, after func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, hook := range hooks {
+		if err := hook.before("tracing:before_"+hook.name, p.start("gorm."+hook.name)); err != nil {
+			return err
+		}
+		if err := hook.after("tracing:after_"+hook.name, p.end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tracingPlugin) start(name string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		ctx, _ := tracer.Start(parent, name, trace.WithSpanKind(trace.SpanKindClient))
+		tx.Statement.Context = spanContext{ctx, parent}
+	}
+}
+
+func (tracingPlugin) end(tx *gorm.DB) {
+	ctx, ok := tx.Statement.Context.(spanContext)
+	if !ok {
+		return
+	}
+	tx.Statement.Context = ctx.parent
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(
+		semconv.DBSystemPostgreSQL,
+		semconv.DBQueryText(tx.Statement.SQL.String()),
+		semconv.DBCollectionName(tx.Statement.Table),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	// a missing row is an answer, not a failure of the query
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+}
@@ -0,0 +1,54 @@
+package uploader
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/textproto"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/port"
+)
+
+// ArchiveStore keeps archive files with the configured file uploader, under archive/<kind>/
+type ArchiveStore struct {
+	uploader FileUploader
+}
+
+// NewArchiveStore is nil when there is no file storage, archival is then skipped
+func NewArchiveStore(uploader FileUploader) port.ArchiveStore {
+	if uploader == nil {
+		return nil
+	}
+	return &ArchiveStore{uploader: uploader}
+}
+
+// memoryFile lets an in-memory file go through UploadFile
+type memoryFile struct {
+	*bytes.Reader
+}
+
+func (memoryFile) Close() error { return nil }
+
+func (s *ArchiveStore) Put(kind, name string, data []byte) (string, error) {
+	header := &multipart.FileHeader{
+		Filename: name,
+		Size:     int64(len(data)),
+		Header:   textproto.MIMEHeader{"Content-Type": {"application/gzip"}},
+	}
+	return s.uploader.UploadFile(&FileDetails{
+		FileType:   FileTypeArchive,
+		EntityID:   kind,
+		File:       memoryFile{bytes.NewReader(data)},
+		FileHeader: header,
+		Metadata: FileMetadata{
+			FileName:    name,
+			Size:        header.Size,
+			ContentType: "application/gzip",
+			UploadedAt:  time.Now(),
+		},
+	})
+}
+
+func (s *ArchiveStore) Get(kind, name string) ([]byte, error) {
+	return s.uploader.DownloadFile(FileTypeArchive, kind, name)
+}
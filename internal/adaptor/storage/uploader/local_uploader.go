@@ -1,6 +1,8 @@
 package uploader
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -40,3 +42,18 @@ func (u *LocalUploader) UploadFile(file *FileDetails) (string, error) {
 func (u *LocalUploader) GetFileURL(fileType FileType, entityID, fileName string) (string, error) {
 	return filepath.Join(u.BasePath, string(fileType), entityID, fileName), nil
 }
+
+func (u *LocalUploader) DownloadFile(fileType FileType, entityID, fileName string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(u.BasePath, string(fileType), entityID, fileName))
+}
+
+func (u *LocalUploader) Ping(ctx context.Context) error {
+	info, err := os.Stat(u.BasePath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", u.BasePath)
+	}
+	return nil
+}
@@ -69,3 +69,21 @@ func (u *S3Uploader) GetFileURL(fileType FileType, entityID, fileName string) (s
 	key := fmt.Sprintf("%s/%s/%s", fileType, entityID, fileName)
 	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", u.Bucket, key), nil
 }
+
+func (u *S3Uploader) DownloadFile(fileType FileType, entityID, fileName string) ([]byte, error) {
+	key := fmt.Sprintf("%s/%s/%s", fileType, entityID, fileName)
+	out, err := u.Client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(u.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (u *S3Uploader) Ping(ctx context.Context) error {
+	_, err := u.Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(u.Bucket)})
+	return err
+}
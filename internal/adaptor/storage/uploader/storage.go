@@ -1,6 +1,7 @@
 package uploader
 
 import (
+	"context"
 	"fmt"
 	"mime/multipart"
 	"os"
@@ -15,6 +16,8 @@ const (
 	FileTypeBookPhoto    FileType = "book_photo"
 	FileTypeIDCard       FileType = "id_card"
 	FileTypeDocument     FileType = "document"
+	FileTypeAnnouncement FileType = "announcement"
+	FileTypeArchive      FileType = "archive"
 )
 
 type FileMetadata struct {
@@ -37,6 +40,9 @@ type FileDetails struct {
 type FileUploader interface {
 	UploadFile(file *FileDetails) (string, error)
 	GetFileURL(fileType FileType, entityID, fileName string) (string, error)
+	DownloadFile(fileType FileType, entityID, fileName string) ([]byte, error)
+	// Ping checks that the storage backend can be reached
+	Ping(ctx context.Context) error
 }
 
 func GetUploader() (FileUploader, error) {
@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/adaptor/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Shutdown flushes the spans that are still buffered
+type Shutdown func(ctx context.Context) error
+
+// New installs the global tracer provider and the W3C trace context propagator. TRACING_EXPORTER
+// picks where spans go: stdout prints them, which works offline, otlp sends them over OTLP/HTTP to
+// OTEL_EXPORTER_OTLP_ENDPOINT and none only passes the callers' trace context on.
+func New(ctx context.Context, config config.Config) (Shutdown, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch config.TRACING_EXPORTER {
+	case "", "none":
+		logrus.Info("TRACING_EXPORTER is none, spans are not exported")
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(config.OTEL_EXPORTER_OTLP_ENDPOINT))
+	default:
+		return nil, fmt.Errorf("unsupported TRACING_EXPORTER %q, use none, stdout or otlp", config.TRACING_EXPORTER)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("TRACING_EXPORTER %s: %w", config.TRACING_EXPORTER, err)
+	}
+	ratio, err := strconv.ParseFloat(config.TRACING_SAMPLE_RATIO, 64)
+	if err != nil {
+		return nil, fmt.Errorf("TRACING_SAMPLE_RATIO: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(config.APP_NAME),
+		semconv.DeploymentEnvironment(config.APP_ENV),
+	))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// a trace started by the caller is kept or dropped as the caller decided
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	logrus.Infof("Exporting traces to %s", config.TRACING_EXPORTER)
+	return provider.Shutdown, nil
+}
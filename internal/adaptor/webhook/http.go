@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const (
+	postTimeout = 10 * time.Second
+	dialTimeout = 5 * time.Second
+)
+
+// HTTPPoster posts JSON payloads to webhook targets. Users choose the targets, so it only
+// connects to public addresses whatever a name resolves to, redirects included.
+type HTTPPoster struct {
+	client *http.Client
+}
+
+func NewHTTPPoster() *HTTPPoster {
+	dialer := &net.Dialer{Timeout: dialTimeout, Control: publicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be the only address checked, the target behind it never is
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &HTTPPoster{client: &http.Client{Timeout: postTimeout, Transport: transport}}
+}
+
+// publicOnly runs once the address is resolved and before connecting to it
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !domain.IsPublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("webhook target %s is an internal address", addrPort.Addr())
+	}
+	return nil
+}
+
+func (p *HTTPPoster) Post(url string, payload []byte, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lms-api-webhook")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &domain.WebhookStatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// AcademicYear bounds the weeks in which class routines are held
+type AcademicYear struct {
+	BaseModel
+	Name      string    `gorm:"size:20;not null;unique" json:"name"` // matches ClassRoutine.AcademicYear
+	StartDate time.Time `gorm:"not null" json:"start_date"`
+	EndDate   time.Time `gorm:"not null" json:"end_date"`
+}
+
+type AcademicYearRequest struct {
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+type UpdateAcademicYearRequest struct {
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+type ListAcademicYearRequest struct {
+	ListRequest
+	Name string `form:"name"`
+}
+
+type AcademicYearResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+func (r *AcademicYearRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("academic year name is required")
+	}
+	if r.StartDate.IsZero() || r.EndDate.IsZero() {
+		return errors.New("start date and end date are required")
+	}
+	if !r.EndDate.After(r.StartDate) {
+		return errors.New("end date must be after start date")
+	}
+	return nil
+}
+
+func (r *UpdateAcademicYearRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.Name != "" {
+		mp["name"] = r.Name
+	}
+	if !r.StartDate.IsZero() {
+		mp["start_date"] = r.StartDate
+	}
+	if !r.EndDate.IsZero() {
+		mp["end_date"] = r.EndDate
+	}
+	return mp
+}
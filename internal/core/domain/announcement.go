@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Announcement audiences, role and all reuse the notification audiences
+const (
+	AnnouncementAll     = AudienceAll
+	AnnouncementRole    = AudienceRole
+	AnnouncementProgram = "program"
+	AnnouncementBatch   = "batch"
+	AnnouncementUsers   = "users"
+)
+
+// Announcement statuses, an announcement is expired once ExpireAt has passed whatever its status
+const (
+	AnnouncementScheduled = "scheduled"
+	AnnouncementPublished = "published"
+	AnnouncementExpired   = "expired"
+)
+
+// Announcement is a notice posted to an audience, it is fanned out into notifications once
+// PublishAt is reached and every recipient gets a receipt that records when they read it
+type Announcement struct {
+	BaseModel
+	Title       string     `gorm:"not null" json:"title"`
+	Body        string     `gorm:"type:text" json:"body"`
+	Audience    string     `gorm:"size:10;not null;index" json:"audience"`
+	Role        string     `gorm:"size:50" json:"role"`                        // role audience, lower case
+	ProgramID   string     `gorm:"index" json:"program_id"`                    // program audience, optional for batch
+	Batch       string     `gorm:"size:20" json:"batch"`                       // batch audience
+	Section     string     `gorm:"size:20" json:"section"`                     // batch audience, every section when empty
+	UserIDs     []string   `gorm:"serializer:json;type:jsonb" json:"user_ids"` // users audience
+	IsPinned    bool       `gorm:"default:false;index" json:"is_pinned"`
+	Status      string     `gorm:"size:20;not null;index" json:"status"`
+	PublishAt   time.Time  `gorm:"not null;index" json:"publish_at"`
+	ExpireAt    *time.Time `gorm:"index" json:"expire_at"`
+	PublishedAt *time.Time `json:"published_at"`
+	// NotificationID is the single notification a role or all announcement is published as
+	NotificationID string                   `json:"notification_id"`
+	CreatedBy      string                   `gorm:"index" json:"created_by"`
+	Attachments    []AnnouncementAttachment `gorm:"foreignKey:AnnouncementID" json:"attachments"`
+	// IsRead is filled in when the announcement is loaded for a recipient's feed
+	IsRead bool `gorm:"->;-:migration" json:"is_read"`
+}
+
+// AnnouncementAttachment is a file stored through the uploader
+type AnnouncementAttachment struct {
+	BaseModel
+	AnnouncementID string `gorm:"type:uuid;not null;index" json:"announcement_id"`
+	FileName       string `gorm:"not null" json:"file_name"`
+	URL            string `gorm:"not null" json:"url"`
+	ContentType    string `json:"content_type"`
+	Size           int64  `json:"size"`
+}
+
+// AnnouncementReceipt is written for every recipient when an announcement is published, whether
+// it was read comes from the read mark of its notification
+type AnnouncementReceipt struct {
+	AnnouncementID string    `gorm:"type:uuid;primaryKey" json:"announcement_id"`
+	UserID         string    `gorm:"primaryKey;index" json:"user_id"`
+	NotificationID string    `gorm:"type:uuid;not null;index" json:"notification_id"`
+	DeliveredAt    time.Time `gorm:"not null" json:"delivered_at"`
+}
+
+// State is the status shown to clients, it accounts for expiry
+func (a *Announcement) State(now time.Time) string {
+	if a.ExpireAt != nil && !a.ExpireAt.After(now) {
+		return AnnouncementExpired
+	}
+	return a.Status
+}
+
+type AnnouncementRequest struct {
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Audience  string     `json:"audience"` // 'all' | 'role' | 'program' | 'batch' | 'users'
+	Role      string     `json:"role"`
+	ProgramID string     `json:"program_id"`
+	Batch     string     `json:"batch"`
+	Section   string     `json:"section"`
+	UserIDs   []string   `json:"user_ids"`
+	IsPinned  bool       `json:"is_pinned"`
+	PublishAt *time.Time `json:"publish_at"` // now when empty
+	ExpireAt  *time.Time `json:"expire_at"`
+}
+
+// UpdateAnnouncementRequest changes an announcement, the audience and PublishAt can only be
+// changed while it is scheduled
+type UpdateAnnouncementRequest struct {
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	IsPinned  *bool      `json:"is_pinned"`
+	PublishAt *time.Time `json:"publish_at"`
+	ExpireAt  *time.Time `json:"expire_at"`
+}
+
+type ListAnnouncementRequest struct {
+	ListRequest
+	Audience string `form:"audience"`
+	Status   string `form:"status"` // 'scheduled' | 'published' | 'expired'
+	IsPinned *bool  `form:"is_pinned"`
+}
+
+// AnnouncementAttachmentRequest describes a file that has been uploaded for an announcement
+type AnnouncementAttachmentRequest struct {
+	FileName    string `json:"file_name"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+type AnnouncementResponse struct {
+	ID          string                   `json:"id"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+	Title       string                   `json:"title"`
+	Body        string                   `json:"body"`
+	Audience    string                   `json:"audience"`
+	Role        string                   `json:"role"`
+	ProgramID   string                   `json:"program_id"`
+	Batch       string                   `json:"batch"`
+	Section     string                   `json:"section"`
+	UserIDs     []string                 `json:"user_ids,omitempty"`
+	IsPinned    bool                     `json:"is_pinned"`
+	Status      string                   `json:"status"`
+	PublishAt   time.Time                `json:"publish_at"`
+	ExpireAt    *time.Time               `json:"expire_at"`
+	PublishedAt *time.Time               `json:"published_at"`
+	CreatedBy   string                   `json:"created_by"`
+	Attachments []AnnouncementAttachment `json:"attachments"`
+	IsRead      bool                     `json:"is_read"`
+	// Receipts is only shown to those who manage announcements
+	Receipts *AnnouncementReceiptSummary `json:"receipts,omitempty"`
+}
+
+// AnnouncementReceiptResponse is one recipient's receipt, ReadAt is nil until they read it
+type AnnouncementReceiptResponse struct {
+	UserID      string     `json:"user_id"`
+	FullName    string     `json:"full_name"`
+	Username    string     `json:"username"`
+	DeliveredAt time.Time  `json:"delivered_at"`
+	ReadAt      *time.Time `json:"read_at"`
+}
+
+type ListAnnouncementReceiptRequest struct {
+	ListRequest
+	IsRead *bool `form:"is_read"`
+}
+
+// AnnouncementReceiptSummary counts the receipts of an announcement
+type AnnouncementReceiptSummary struct {
+	Recipients int64 `json:"recipients"`
+	Read       int64 `json:"read"`
+}
+
+func (r *AnnouncementRequest) Validate() error {
+	if r.Title == "" {
+		return errors.New("title is required")
+	}
+	switch r.Audience {
+	case AnnouncementAll:
+	case AnnouncementRole:
+		if r.Role == "" {
+			return errors.New("role is required for the role audience")
+		}
+		r.Role = strings.ToLower(r.Role)
+	case AnnouncementProgram:
+		if r.ProgramID == "" {
+			return errors.New("program_id is required for the program audience")
+		}
+	case AnnouncementBatch:
+		if r.Batch == "" {
+			return errors.New("batch is required for the batch audience")
+		}
+	case AnnouncementUsers:
+		if len(r.UserIDs) == 0 {
+			return errors.New("user_ids is required for the users audience")
+		}
+	default:
+		return fmt.Errorf("audience %q must be all, role, program, batch or users", r.Audience)
+	}
+	if r.PublishAt == nil {
+		now := time.Now()
+		r.PublishAt = &now
+	}
+	if r.ExpireAt != nil && !r.ExpireAt.After(*r.PublishAt) {
+		return errors.New("expire_at must be after publish_at")
+	}
+	return nil
+}
+
+// NewAnnouncement keeps only the targeting fields the audience uses
+func (r *AnnouncementRequest) NewAnnouncement() *Announcement {
+	data := &Announcement{
+		Title:     r.Title,
+		Body:      r.Body,
+		Audience:  r.Audience,
+		IsPinned:  r.IsPinned,
+		Status:    AnnouncementScheduled,
+		PublishAt: *r.PublishAt,
+		ExpireAt:  r.ExpireAt,
+	}
+	switch r.Audience {
+	case AnnouncementRole:
+		data.Role = r.Role
+	case AnnouncementProgram:
+		data.ProgramID = r.ProgramID
+	case AnnouncementBatch:
+		data.ProgramID, data.Batch, data.Section = r.ProgramID, r.Batch, r.Section
+	case AnnouncementUsers:
+		data.UserIDs = r.UserIDs
+	}
+	return data
+}
+
+func (r *UpdateAnnouncementRequest) NewUpdate() Map {
+	mp := Map{}
+	if r.Title != "" {
+		mp["title"] = r.Title
+	}
+	if r.Body != "" {
+		mp["body"] = r.Body
+	}
+	if r.IsPinned != nil {
+		mp["is_pinned"] = *r.IsPinned
+	}
+	if r.PublishAt != nil {
+		mp["publish_at"] = *r.PublishAt
+	}
+	if r.ExpireAt != nil {
+		mp["expire_at"] = *r.ExpireAt
+	}
+	return mp
+}
+
+func (r *AnnouncementAttachmentRequest) Validate() error {
+	if r.FileName == "" || r.URL == "" {
+		return errors.New("file name and url are required")
+	}
+	return nil
+}
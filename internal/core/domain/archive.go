@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// Archive kinds
+const (
+	ArchiveAuditLog     = "audit_log"
+	ArchiveNotification = "notification"
+)
+
+// Archive is one gzip compressed JSONL file of rows the retention job moved out of the database.
+// Sequences lists the archived audit log entries as [first, last] ranges so that verification
+// can tell archived entries from missing ones.
+type Archive struct {
+	BaseModel
+	Kind       string     `gorm:"size:20;not null;index" json:"kind"`
+	FileName   string     `gorm:"not null" json:"file_name"`
+	URL        string     `gorm:"not null" json:"url"`
+	Count      int        `json:"count"`
+	Size       int64      `json:"size"`
+	Checksum   string     `gorm:"size:64;not null" json:"checksum"` // hex sha256 of the file
+	OldestAt   time.Time  `json:"oldest_at"`
+	NewestAt   time.Time  `json:"newest_at"`
+	Sequences  [][2]int64 `gorm:"serializer:json;type:jsonb" json:"sequences"`
+	RestoredAt *time.Time `json:"restored_at"`
+	RestoredBy string     `json:"restored_by"`
+}
+
+// ArchivedNotification is one line of a notification archive
+type ArchivedNotification struct {
+	Notification
+	Reads []NotificationRead `json:"reads"`
+}
+
+// SequenceRanges collapses sequences into sorted [first, last] ranges
+func SequenceRanges(sequences []int64) [][2]int64 {
+	sorted := slices.Clone(sequences)
+	slices.Sort(sorted)
+	var ranges [][2]int64
+	for _, sequence := range sorted {
+		if n := len(ranges); n > 0 && sequence <= ranges[n-1][1]+1 {
+			ranges[n-1][1] = max(ranges[n-1][1], sequence)
+			continue
+		}
+		ranges = append(ranges, [2]int64{sequence, sequence})
+	}
+	return ranges
+}
+
+// ArchivedSequences is every archived audit log sequence as merged ranges
+type ArchivedSequences [][2]int64
+
+// NewArchivedSequences merges the ranges of several archives
+func NewArchivedSequences(ranges [][2]int64) ArchivedSequences {
+	sorted := slices.Clone(ranges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+	var merged ArchivedSequences
+	for _, r := range sorted {
+		if n := len(merged); n > 0 && r[0] <= merged[n-1][1]+1 {
+			merged[n-1][1] = max(merged[n-1][1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// Covers tells whether every sequence from first to last was archived
+func (a ArchivedSequences) Covers(first, last int64) bool {
+	i := sort.Search(len(a), func(i int) bool { return a[i][1] >= first })
+	return i < len(a) && a[i][0] <= first && a[i][1] >= last
+}
+
+// RetentionPolicy says how long rows stay in the database before the archival job moves them to
+// files, zero keeps them forever. An audit log entry is kept for the time set for its action,
+// otherwise for the time set for its entity type, otherwise for AuditDefault.
+type RetentionPolicy struct {
+	AuditDefault time.Duration
+	AuditActions map[string]time.Duration
+	AuditModules map[string]time.Duration
+	Notification time.Duration
+	// ReadNotification is how long a user's notification is kept once read, it is deleted
+	// without being archived
+	ReadNotification time.Duration
+	// Restore is how long restored audit log entries are left out of archival
+	Restore time.Duration
+}
+
+// AuditArchiveScope is the audit log entries that one retention rule sends to the archive
+type AuditArchiveScope struct {
+	Action        string
+	Module        string
+	ExceptActions []string // actions with a rule of their own
+	ExceptModules []string // modules with a rule of their own
+	Before        time.Time
+}
+
+// AuditScopes lists what is due for archival at now, one scope per rule that does not keep
+// entries forever
+func (p *RetentionPolicy) AuditScopes(now time.Time) []AuditArchiveScope {
+	actions := sortedKeys(p.AuditActions)
+	modules := sortedKeys(p.AuditModules)
+	var scopes []AuditArchiveScope
+	for _, action := range actions {
+		if keep := p.AuditActions[action]; keep > 0 {
+			scopes = append(scopes, AuditArchiveScope{Action: action, Before: now.Add(-keep)})
+		}
+	}
+	for _, module := range modules {
+		if keep := p.AuditModules[module]; keep > 0 {
+			scopes = append(scopes, AuditArchiveScope{Module: module, ExceptActions: actions, Before: now.Add(-keep)})
+		}
+	}
+	if p.AuditDefault > 0 {
+		scopes = append(scopes, AuditArchiveScope{ExceptActions: actions, ExceptModules: modules, Before: now.Add(-p.AuditDefault)})
+	}
+	return scopes
+}
+
+func sortedKeys(m map[string]time.Duration) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+type ListArchiveRequest struct {
+	ListRequest
+	Kind string `form:"kind"`
+}
+
+type ArchiveResponse struct {
+	ID         string     `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Kind       string     `json:"kind"`
+	FileName   string     `json:"file_name"`
+	URL        string     `json:"url"`
+	Count      int        `json:"count"`
+	Size       int64      `json:"size"`
+	Checksum   string     `json:"checksum"`
+	OldestAt   time.Time  `json:"oldest_at"`
+	NewestAt   time.Time  `json:"newest_at"`
+	Sequences  [][2]int64 `json:"sequences,omitempty"`
+	RestoredAt *time.Time `json:"restored_at"`
+	RestoredBy string     `json:"restored_by"`
+}
+
+// RestoreArchiveResponse tells how many archived entries were put back, entries still in the
+// database are skipped
+type RestoreArchiveResponse struct {
+	Archive     ArchiveResponse `json:"archive"`
+	Restored    int64           `json:"restored"`
+	RetainUntil time.Time       `json:"retain_until"`
+}
@@ -1,20 +1,128 @@
 package domain
 
 import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
 	"time"
 )
 
-// Define the AuditLog struct with JSON tags
+// Audit actions shared by most entities, others like "login" or "publish" name themselves
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditSystem is the performer of actions taken by background jobs
+const AuditSystem = "system"
+
+// auditRedacted replaces the values of secret fields in a diff
+const auditRedacted = "[redacted]"
+
+// Define the AuditLog struct with JSON tags. Entries are append-only and chained, Hash covers the
+// entry and the Hash of the entry before it so an edit, insert or removal breaks the chain.
 type AuditLog struct {
 	BaseModel
+	Sequence    int64   `gorm:"uniqueIndex" json:"sequence"`
+	PrevHash    string  `gorm:"size:64" json:"prev_hash"`
+	Hash        string  `gorm:"size:64" json:"hash"`
 	Title       string  `json:"title"`
-	UserID      *string `json:"user_id"`
-	Action      string  `json:"action"`       // The action performed (e.g., "CREATE", "UPDATE", "DELETE")
-	PerformedBy string  `json:"performed_by"` // The user or system that performed the action
-	Data        string  `json:"data"`
-	Details     string  `json:"details"` // Additional details about the action (optional)
-	Remarks     string  `json:"remarks"`
-	IsActive    bool    `json:"is_active"`
+	UserID      *string `gorm:"index" json:"user_id"` // The actor, nil for the system
+	Action      string  `gorm:"index" json:"action"`  // The action performed (e.g., "CREATE", "UPDATE", "DELETE")
+	PerformedBy string  `json:"performed_by"`         // The user or system that performed the action
+	EntityType  string  `gorm:"size:50;index:idx_audit_log_entity" json:"entity_type"`
+	EntityID    string  `gorm:"index:idx_audit_log_entity" json:"entity_id"`
+	IP          string  `gorm:"size:64" json:"ip"`
+	UserAgent   string  `json:"user_agent"`
+	RequestID   string  `gorm:"size:128;index" json:"request_id"`
+	// Changes holds the fields that changed, before is null on create and after is null on delete
+	Changes  map[string]AuditChange `gorm:"serializer:json;type:jsonb" json:"changes"`
+	Data     string                 `json:"data"`
+	Details  string                 `json:"details"` // Additional details about the action (optional)
+	Remarks  string                 `json:"remarks"`
+	IsActive bool                   `json:"is_active"`
+	// RetainUntil keeps a restored entry out of archival, it is not part of the hash
+	RetainUntil *time.Time `gorm:"index" json:"retain_until,omitempty"`
+}
+
+// AuditChange is the value of one field before and after the action
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditDiff compares the JSON form of two records and returns the fields that differ, either may
+// be nil. Nested records are loaded relations and are left out, as is updated_at, and fields
+// named like a password or secret only show that they changed.
+func AuditDiff(before, after interface{}) map[string]AuditChange {
+	previous, current := auditFields(before), auditFields(after)
+	changes := map[string]AuditChange{}
+	for key, value := range current {
+		if old, ok := previous[key]; !ok || !reflect.DeepEqual(old, value) {
+			changes[key] = AuditChange{Before: previous[key], After: value}
+		}
+	}
+	for key, value := range previous {
+		if _, ok := current[key]; !ok {
+			changes[key] = AuditChange{Before: value}
+		}
+	}
+	for key, change := range changes {
+		if isSecretField(key) {
+			if change.Before != nil {
+				change.Before = auditRedacted
+			}
+			if change.After != nil {
+				change.After = auditRedacted
+			}
+			changes[key] = change
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func auditFields(record interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if record == nil || (reflect.ValueOf(record).Kind() == reflect.Pointer && reflect.ValueOf(record).IsNil()) {
+		return fields
+	}
+	raw, err := json.Marshal(record)
+	if err != nil || json.Unmarshal(raw, &fields) != nil {
+		return map[string]interface{}{}
+	}
+	for key, value := range fields {
+		if value == nil || key == "updated_at" || key == "UpdatedAt" || isNestedRecord(value) {
+			delete(fields, key)
+		}
+	}
+	return fields
+}
+
+func isNestedRecord(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return true
+	case []interface{}:
+		for _, item := range v {
+			if _, ok := item.(map[string]interface{}); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isSecretField(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "secret") || strings.Contains(key, "token")
 }
 
 type ListAuditLogRequest struct {
@@ -22,12 +130,9 @@ type ListAuditLogRequest struct {
 	UserID      string `form:"user_id"`
 	Action      string `form:"action"`
 	PerformedBy string `form:"performed_by"`
-}
-
-type AuditLogUpdateRequest struct {
-	Action      string `json:"action"`
-	PerformedBy string `json:"performed_by"`
-	Remarks     string `json:"remarks"`
+	EntityType  string `form:"entity_type"`
+	EntityID    string `form:"entity_id"`
+	RequestID   string `form:"request_id"`
 }
 
 // Define the AuditLogRequest struct with JSON tags
@@ -43,23 +148,176 @@ type AuditLogRequest struct {
 
 // Define the AuditLogResponse struct with JSON tags
 type AuditLogResponse struct {
-	ID          string    `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	Module      string    `json:"module,omitempty"`
-	Title       string    `json:"title,omitempty"`
-	UserID      string    `json:"user_id,omitempty"`
-	Action      string    `json:"action,omitempty"`       // The action performed (e.g., "CREATE", "UPDATE", "DELETE")
-	PerformedBy string    `json:"performed_by,omitempty"` // The user or system that performed the action
-	Data        string    `json:"data,omitempty"`
-	Details     string    `json:"details,omitempty"` // Additional details about the action (optional)
-	Remarks     string    `json:"remarks,omitempty"`
-	IsActive    bool      `json:"is_active,omitempty"`
-}
-
-func (a *AuditLogUpdateRequest) NewUpdate() Map {
-	return nil
+	ID          string                 `json:"id"`
+	CreatedAt   time.Time              `json:"created_at"`
+	Sequence    int64                  `json:"sequence"`
+	PrevHash    string                 `json:"prev_hash"`
+	Hash        string                 `json:"hash"`
+	Module      string                 `json:"module,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	UserID      string                 `json:"user_id,omitempty"`
+	Action      string                 `json:"action,omitempty"`       // The action performed (e.g., "CREATE", "UPDATE", "DELETE")
+	PerformedBy string                 `json:"performed_by,omitempty"` // The user or system that performed the action
+	EntityType  string                 `json:"entity_type,omitempty"`
+	EntityID    string                 `json:"entity_id,omitempty"`
+	IP          string                 `json:"ip,omitempty"`
+	UserAgent   string                 `json:"user_agent,omitempty"`
+	RequestID   string                 `json:"request_id,omitempty"`
+	Changes     map[string]AuditChange `json:"changes,omitempty"`
+	Data        string                 `json:"data,omitempty"`
+	Details     string                 `json:"details,omitempty"` // Additional details about the action (optional)
+	Remarks     string                 `json:"remarks,omitempty"`
+	IsActive    bool                   `json:"is_active,omitempty"`
 }
 
 func (a *AuditLog) Validate() error {
 	return nil
 }
+
+// auditHashContent is what an entry's hash is taken over, times are in UTC to the microsecond
+// as that is what Postgres keeps
+type auditHashContent struct {
+	ID          string                 `json:"id"`
+	Sequence    int64                  `json:"sequence"`
+	PrevHash    string                 `json:"prev_hash"`
+	CreatedAt   string                 `json:"created_at"`
+	Title       string                 `json:"title"`
+	UserID      *string                `json:"user_id"`
+	Action      string                 `json:"action"`
+	PerformedBy string                 `json:"performed_by"`
+	EntityType  string                 `json:"entity_type"`
+	EntityID    string                 `json:"entity_id"`
+	IP          string                 `json:"ip"`
+	UserAgent   string                 `json:"user_agent"`
+	RequestID   string                 `json:"request_id"`
+	Changes     map[string]AuditChange `json:"changes"`
+	Data        string                 `json:"data"`
+	Details     string                 `json:"details"`
+	Remarks     string                 `json:"remarks"`
+}
+
+// AuditTime rounds a time the way Postgres stores it so the hash survives a round trip
+func AuditTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// ComputeHash is the hex sha256 of the entry's content, PrevHash included
+func (a *AuditLog) ComputeHash() string {
+	sum := sha256.Sum256(ConvertToJson(auditHashContent{
+		ID:          a.ID,
+		Sequence:    a.Sequence,
+		PrevHash:    a.PrevHash,
+		CreatedAt:   AuditTime(a.CreatedAt).Format(time.RFC3339Nano),
+		Title:       a.Title,
+		UserID:      a.UserID,
+		Action:      a.Action,
+		PerformedBy: a.PerformedBy,
+		EntityType:  a.EntityType,
+		EntityID:    a.EntityID,
+		IP:          a.IP,
+		UserAgent:   a.UserAgent,
+		RequestID:   a.RequestID,
+		Changes:     a.Changes,
+		Data:        a.Data,
+		Details:     a.Details,
+		Remarks:     a.Remarks,
+	}))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditCheckpoint pins the head of the audit chain at a point in time with a signature, an
+// exported checkpoint lets an auditor prove later that the chain up to Sequence was not rewritten
+type AuditCheckpoint struct {
+	BaseModel
+	Sequence  int64  `gorm:"not null;index" json:"sequence"`
+	Hash      string `gorm:"size:64;not null" json:"hash"`
+	Algorithm string `gorm:"size:20;not null" json:"algorithm"`
+	PublicKey string `gorm:"not null" json:"public_key"`
+	Signature string `gorm:"not null" json:"signature"`
+}
+
+// auditCheckpointContent is the signed part of a checkpoint
+type auditCheckpointContent struct {
+	ID        string `json:"id"`
+	Sequence  int64  `json:"sequence"`
+	Hash      string `json:"hash"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SignedPayload is the exact bytes the checkpoint signature is over
+func (c *AuditCheckpoint) SignedPayload() []byte {
+	return ConvertToJson(auditCheckpointContent{
+		ID:        c.ID,
+		Sequence:  c.Sequence,
+		Hash:      c.Hash,
+		CreatedAt: AuditTime(c.CreatedAt).Format(time.RFC3339Nano),
+	})
+}
+
+// AuditSignatureEd25519 is the algorithm checkpoints are signed with
+const AuditSignatureEd25519 = "ed25519"
+
+// VerifySignature checks the signature with the public key stored on the checkpoint, so the
+// checkpoints signed before the signing key was rotated still verify
+func (c *AuditCheckpoint) VerifySignature() bool {
+	if c.Algorithm != AuditSignatureEd25519 {
+		return false
+	}
+	key, err := base64.StdEncoding.DecodeString(c.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+	signature, err := base64.StdEncoding.DecodeString(c.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(ed25519.PublicKey(key), c.SignedPayload(), signature)
+}
+
+type ListAuditCheckpointRequest struct {
+	ListRequest
+}
+
+type AuditCheckpointResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Sequence  int64     `json:"sequence"`
+	Hash      string    `json:"hash"`
+	Algorithm string    `json:"algorithm"`
+	PublicKey string    `json:"public_key"`
+	Signature string    `json:"signature"`
+}
+
+// AuditCheckpointExport is the document handed to auditors, Payload is what was signed and is
+// checked with the base64 PublicKey and Signature without needing the database
+type AuditCheckpointExport struct {
+	Checkpoint AuditCheckpointResponse `json:"checkpoint"`
+	Payload    string                  `json:"payload"`
+	ExportedAt time.Time               `json:"exported_at"`
+}
+
+type VerifyAuditLogRequest struct {
+	FromSequence int64 `form:"from_sequence"`
+	ToSequence   int64 `form:"to_sequence"` // up to the head when empty
+}
+
+// AuditIssue is one break found in the chain
+type AuditIssue struct {
+	Sequence int64  `json:"sequence"`
+	ID       string `json:"id,omitempty"`
+	Problem  string `json:"problem"`
+}
+
+type VerifyAuditLogResponse struct {
+	Valid         bool   `json:"valid"`
+	Checked       int64  `json:"checked"`
+	FirstSequence int64  `json:"first_sequence"`
+	LastSequence  int64  `json:"last_sequence"`
+	HeadHash      string `json:"head_hash"`
+	Checkpoints   int    `json:"checkpoints"`
+	// Archived counts the entries in the range that were moved to archive files
+	Archived int64        `json:"archived"`
+	Issues   []AuditIssue `json:"issues"`
+	// Truncated is set when there were more issues than listed
+	Truncated bool `json:"truncated"`
+}
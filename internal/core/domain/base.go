@@ -64,6 +64,15 @@ func IsValidName(str string) error {
 	return nil
 }
 
+// OptionalID is an optional uuid reference as it is stored, nil when it is left out or empty since
+// a uuid column cannot hold ""
+func OptionalID(id *string) *string {
+	if id == nil || *id == "" {
+		return nil
+	}
+	return id
+}
+
 // Response is a generic function to convert a struct to a response type.
 func Convert[I, O any](input *I) *O {
 	var output O
@@ -1,10 +1,71 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
 type Building struct {
 	BaseModel
-	CampusID uint
-	Campus   Campus
+	CampusID string  `gorm:"type:uuid;index" json:"campus_id"`
+	Campus   *Campus `gorm:"foreignKey:CampusID" json:"campus,omitempty"`
+
+	Name   string  `gorm:"size:100;not null" json:"name"`
+	Code   string  `gorm:"size:10;not null;unique" json:"code"`
+	Floors []Floor `gorm:"foreignKey:BuildingID" json:"floors,omitempty"`
+}
+
+type BuildingRequest struct {
+	CampusID string `json:"campus_id"`
+	Name     string `json:"name"`
+	Code     string `json:"code"`
+}
+
+type UpdateBuildingRequest struct {
+	CampusID string `json:"campus_id"`
+	Name     string `json:"name"`
+	Code     string `json:"code"`
+}
+
+type ListBuildingRequest struct {
+	ListRequest
+	CampusID string `form:"campus_id"`
+	Code     string `form:"code"`
+}
+
+type BuildingResponse struct {
+	ID        string          `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	CampusID  string          `json:"campus_id"`
+	Name      string          `json:"name"`
+	Code      string          `json:"code"`
+	Campus    *CampusResponse `json:"campus,omitempty"`
+}
+
+func (r *BuildingRequest) Validate() error {
+	if r.CampusID == "" {
+		return errors.New("campus id is required")
+	}
+	if r.Name == "" {
+		return errors.New("building name is required")
+	}
+	if r.Code == "" {
+		return errors.New("building code is required")
+	}
+	return nil
+}
 
-	Name string `gorm:"size:100;not null"`
-	Code string `gorm:"size:10;not null;unique"`
+func (r *UpdateBuildingRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.CampusID != "" {
+		mp["campus_id"] = r.CampusID
+	}
+	if r.Name != "" {
+		mp["name"] = r.Name
+	}
+	if r.Code != "" {
+		mp["code"] = r.Code
+	}
+	return mp
 }
@@ -1,7 +1,54 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
 type Campus struct {
 	BaseModel
-	Name     string `gorm:"size:100;not null;unique"`
-	Location string `gorm:"size:255"`
+	Name      string     `gorm:"size:100;not null;unique" json:"name"`
+	Location  string     `gorm:"size:255" json:"location"`
+	Buildings []Building `gorm:"foreignKey:CampusID" json:"buildings,omitempty"`
+}
+
+type CampusRequest struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+type UpdateCampusRequest struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+type ListCampusRequest struct {
+	ListRequest
+	Name string `form:"name"`
+}
+
+type CampusResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	Location  string    `json:"location"`
+}
+
+func (r *CampusRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("campus name is required")
+	}
+	return nil
+}
+
+func (r *UpdateCampusRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.Name != "" {
+		mp["name"] = r.Name
+	}
+	if r.Location != "" {
+		mp["location"] = r.Location
+	}
+	return mp
 }
@@ -0,0 +1,112 @@
+package domain
+
+import "time"
+
+const (
+	defaultCirculationLimit = 10
+	maxCirculationLimit     = 100
+	defaultInactiveMonths   = 12
+)
+
+// CirculationRequest filters the circulation reports by when a loan was issued
+type CirculationRequest struct {
+	ReportPeriod
+	Limit int `form:"limit" example:"10"` // rows of the top lists, at most 100
+}
+
+func (r *CirculationRequest) Prepare() error {
+	if err := r.ReportPeriod.Prepare(); err != nil {
+		return err
+	}
+	if r.Limit <= 0 {
+		r.Limit = defaultCirculationLimit
+	}
+	r.Limit = min(r.Limit, maxCirculationLimit)
+	return nil
+}
+
+// InactiveStockRequest lists titles without a loan in Months months, titles added since then
+// are left out
+type InactiveStockRequest struct {
+	ListRequest
+	Months     int       `form:"months" example:"12"`
+	CategoryID string    `form:"category_id"`
+	Before     time.Time `form:"-" json:"-"`
+}
+
+func (r *InactiveStockRequest) Prepare() {
+	r.ListRequest.Prepare()
+	if r.Months <= 0 {
+		r.Months = defaultInactiveMonths
+	}
+	r.Before = time.Now().AddDate(0, -r.Months, 0)
+}
+
+// TopBook is a title ranked by the loans of its copies in the period
+type TopBook struct {
+	BookID       string `json:"book_id"`
+	Title        string `json:"title"`
+	Author       string `json:"author"`
+	CategoryName string `json:"category_name"`
+	Loans        int64  `json:"loans"`
+	Borrowers    int64  `json:"borrowers"` // distinct patrons
+}
+
+// TopCategory is a category ranked by the loans of its titles in the period
+type TopCategory struct {
+	CategoryID   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Loans        int64  `json:"loans"`
+	Titles       int64  `json:"titles"` // distinct titles lent
+}
+
+// InactiveBook is a weeding candidate, LastLoanedAt is empty when it was never lent
+type InactiveBook struct {
+	BookID       string     `json:"book_id"`
+	Title        string     `json:"title"`
+	Author       string     `json:"author"`
+	CategoryName string     `json:"category_name"`
+	Copies       int64      `json:"copies"`
+	LastLoanedAt *time.Time `json:"last_loaned_at"`
+	AddedAt      time.Time  `json:"added_at"`
+}
+
+// LoanDuration sums up how long the loans issued in the period were kept, in days, over the
+// loans already returned
+type LoanDuration struct {
+	Loans       int64   `json:"loans"`
+	Returned    int64   `json:"returned"`
+	LateReturns int64   `json:"late_returns"` // returned after the due date
+	AverageDays float64 `json:"average_days"`
+	MedianDays  float64 `json:"median_days"`
+	LongestDays float64 `json:"longest_days"`
+}
+
+// BorrowingRate compares the students of a program, or of a batch in it, with those who
+// borrowed in the period
+type BorrowingRate struct {
+	ProgramID       string  `json:"program_id"`
+	ProgramName     string  `json:"program_name"`
+	Batch           string  `json:"batch,omitempty"`
+	Students        int64   `json:"students"`
+	Borrowers       int64   `json:"borrowers"`
+	Loans           int64   `json:"loans"`
+	LoansPerStudent float64 `json:"loans_per_student"`
+	BorrowerRate    float64 `json:"borrower_rate"` // percent of students who borrowed
+}
+
+type BorrowingRates struct {
+	Programs []BorrowingRate `json:"programs"`
+	Batches  []BorrowingRate `json:"batches"`
+}
+
+// TopReader is a patron ranked by loans in the period
+type TopReader struct {
+	UserID      string `json:"user_id"`
+	FullName    string `json:"full_name"`
+	Username    string `json:"username"`
+	ProgramName string `json:"program_name"`
+	Batch       string `json:"batch"`
+	Loans       int64  `json:"loans"`
+	Titles      int64  `json:"titles"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Email delivery statuses
+const (
+	EmailQueued  = "queued"
+	EmailSent    = "sent"
+	EmailFailed  = "failed"  // gave up after MaxEmailAttempts transient errors
+	EmailBounced = "bounced" // rejected permanently by the receiving server
+)
+
+// MaxEmailAttempts is how many times a delivery is tried before it is marked failed
+const MaxEmailAttempts = 5
+
+// EmailMessage is a rendered plain text email ready to be sent
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailDelivery tracks one email through the queue, ReferenceID ties it to the record it is about
+// so scheduled reminders are sent only once
+type EmailDelivery struct {
+	BaseModel
+	UserID        string     `gorm:"not null;index" json:"user_id"`
+	To            string     `gorm:"not null" json:"to"`
+	Template      string     `gorm:"size:50;not null;index:idx_email_reference" json:"template"`
+	ReferenceID   string     `gorm:"index:idx_email_reference" json:"reference_id"`
+	Subject       string     `gorm:"not null" json:"subject"`
+	Body          string     `gorm:"type:text" json:"body"`
+	Status        string     `gorm:"size:20;not null;index" json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+type ListEmailDeliveryRequest struct {
+	ListRequest
+	UserID   string `form:"user_id"`
+	Template string `form:"template"`
+	Status   string `form:"status"`
+}
+
+type EmailDeliveryResponse struct {
+	ID            string     `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UserID        string     `json:"user_id"`
+	To            string     `json:"to"`
+	Template      string     `json:"template"`
+	ReferenceID   string     `json:"reference_id"`
+	Subject       string     `json:"subject"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// EmailBounceError is a permanent rejection from the mail server, the delivery is not retried
+type EmailBounceError struct {
+	Code    int
+	Message string
+}
+
+func (e *EmailBounceError) Error() string {
+	return fmt.Sprintf("mail rejected %d: %s", e.Code, e.Message)
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "errors"
+
+// ErrorKind says what went wrong with a request, the HTTP layer answers every kind with its own
+// status code
+type ErrorKind string
+
+const (
+	ErrorValidation      ErrorKind = "validation"
+	ErrorUnauthorized    ErrorKind = "unauthorized"
+	ErrorForbidden       ErrorKind = "forbidden"
+	ErrorNotFound        ErrorKind = "not_found"
+	ErrorConflict        ErrorKind = "conflict"
+	ErrorPolicyViolation ErrorKind = "policy_violation"
+	ErrorInternal        ErrorKind = "internal"
+)
+
+// Error is an error reported to the client. Code is stable and machine-readable, such as
+// book_already_borrowed, and Message is meant for people. The cause is logged, never sent.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Cause   error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func NewValidationError(code, message string) error {
+	return &Error{Kind: ErrorValidation, Code: code, Message: message}
+}
+
+func NewUnauthorizedError(code, message string) error {
+	return &Error{Kind: ErrorUnauthorized, Code: code, Message: message}
+}
+
+func NewForbiddenError(code, message string) error {
+	return &Error{Kind: ErrorForbidden, Code: code, Message: message}
+}
+
+func NewNotFoundError(code, message string) error {
+	return &Error{Kind: ErrorNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code, message string) error {
+	return &Error{Kind: ErrorConflict, Code: code, Message: message}
+}
+
+// NewPolicyViolationError is a request that is well formed but breaks a rule of the library,
+// like deleting the admin user
+func NewPolicyViolationError(code, message string) error {
+	return &Error{Kind: ErrorPolicyViolation, Code: code, Message: message}
+}
+
+// NewInternalError hides cause from the client, who is only told that the request failed
+func NewInternalError(cause error) error {
+	return &Error{Kind: ErrorInternal, Code: "internal_error", Message: "internal server error", Cause: cause}
+}
+
+// AsError finds the Error in err's chain
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
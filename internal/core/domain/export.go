@@ -0,0 +1,362 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Export formats, anything else is answered with JSON
+const (
+	ExportCSV  = "csv"
+	ExportXLSX = "xlsx"
+	ExportPDF  = "pdf"
+)
+
+// Export languages, headers fall back to English
+const (
+	LangEnglish = "en"
+	LangNepali  = "ne"
+)
+
+// ExportRequest is read next to the filters of every exportable endpoint
+type ExportRequest struct {
+	Format  string `form:"format"`  // json (default) | csv | xlsx | pdf
+	Columns string `form:"columns"` // comma separated column keys, every column when empty
+	Lang    string `form:"lang"`    // en | ne, from Accept-Language when empty
+}
+
+// IsExport tells whether a file was asked for instead of JSON
+func (r *ExportRequest) IsExport() bool {
+	return r.Format == ExportCSV || r.Format == ExportXLSX || r.Format == ExportPDF
+}
+
+// ExportMeta is the report header written above the table
+type ExportMeta struct {
+	Title       string // label key, see ExportLabel
+	Subject     string // who the report is about, shown under the title when set
+	Lang        string
+	From        string // YYYY-MM-DD, no period is shown when empty
+	To          string
+	GeneratedAt time.Time
+}
+
+// ExportColumn is one column an export offers, Key names it in ?columns= and in the header labels
+type ExportColumn[T any] struct {
+	Key   string
+	Value func(T) any
+}
+
+// SelectColumns picks the requested columns in the requested order
+func SelectColumns[T any](columns []ExportColumn[T], keys string) ([]ExportColumn[T], error) {
+	if strings.TrimSpace(keys) == "" {
+		return columns, nil
+	}
+	var selected []ExportColumn[T]
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		i := slices.IndexFunc(columns, func(c ExportColumn[T]) bool { return c.Key == key })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q", key)
+		}
+		selected = append(selected, columns[i])
+	}
+	return selected, nil
+}
+
+// ExportStat is one figure of a summary report, exported as a label and value row
+type ExportStat struct {
+	Metric string
+	Value  any
+}
+
+// StatColumns are the columns of a summary report
+var StatColumns = []ExportColumn[ExportStat]{
+	{Key: "metric", Value: func(s ExportStat) any { return s.Metric }},
+	{Key: "value", Value: func(s ExportStat) any { return s.Value }},
+}
+
+// ExportLang picks the language of the header labels from ?lang= or an Accept-Language header
+func ExportLang(lang, acceptLanguage string) string {
+	for _, tag := range append([]string{lang}, strings.Split(acceptLanguage, ",")...) {
+		tag, _, _ = strings.Cut(strings.TrimSpace(tag), ";")
+		tag, _, _ = strings.Cut(strings.ToLower(tag), "-")
+		if _, ok := exportLabels[tag]; ok {
+			return tag
+		}
+	}
+	return LangEnglish
+}
+
+// ExportLabel translates a column key, report title or header caption, unknown keys are
+// returned as they are
+func ExportLabel(lang, key string) string {
+	if label, ok := exportLabels[lang][key]; ok {
+		return label
+	}
+	if label, ok := exportLabels[LangEnglish][key]; ok {
+		return label
+	}
+	return key
+}
+
+var exportLabels = map[string]map[string]string{
+	LangEnglish: {
+		// header captions
+		"period":    "Period",
+		"generated": "Generated",
+		"truncated": "%d more rows are left out, export as csv or xlsx for every row",
+		// report titles
+		"borrows":            "Borrowed Books",
+		"fines":              "Fines",
+		"students":           "Students",
+		"dashboard-stats":    "Library Dashboard",
+		"chart-stats":        "Circulation Chart",
+		"borrowedbookstats":  "Borrowed Book Statistics",
+		"program-stats":      "Books by Program",
+		"inventory-stats":    "Inventory",
+		"teacher-workload":   "Teacher Workload",
+		"room-utilization":   "Room Utilization",
+		"top-books":          "Most Borrowed Titles",
+		"top-categories":     "Most Borrowed Categories",
+		"inactive-stock":     "Inactive Stock",
+		"loan-duration":      "Loan Duration",
+		"borrowing-rates":    "Borrowing Rates",
+		"top-readers":        "Top Readers",
+		"fine-collection":    "Fine Collection",
+		"fine-aging":         "Outstanding Fines by Age",
+		"student-fine-aging": "Outstanding Fines by Student",
+		"fine-statement":     "Fine Statement",
+		"metric":             "Metric",
+		"value":              "Value",
+		"activeStudents":     "Active students",
+		"availableBooks":     "Available books",
+		"borrowedBooks":      "Borrowed books",
+		"overdueBooks":       "Overdue books",
+		"pendingRequests":    "Pending requests",
+		"totalBooks":         "Total books",
+		"totalFines":         "Outstanding fines (Rs.)",
+		"totalStudents":      "Total students",
+		"totalBorrowedBooks": "Total borrowed books",
+		"totalOverdueBooks":  "Total overdue books",
+		"dueSoon":            "Due soon",
+		// columns
+		"id":                "ID",
+		"created_at":        "Created at",
+		"user_id":           "User ID",
+		"student_name":      "Student",
+		"username":          "Username",
+		"book_title":        "Book",
+		"accession_number":  "Accession number",
+		"borrowed_date":     "Borrowed on",
+		"due_date":          "Due on",
+		"returned_date":     "Returned on",
+		"renewal_count":     "Renewals",
+		"status":            "Status",
+		"remarks":           "Remarks",
+		"borrowed_book_id":  "Borrow ID",
+		"amount":            "Amount (Rs.)",
+		"reason":            "Reason",
+		"paid_at":           "Paid on",
+		"full_name":         "Full name",
+		"gender":            "Gender",
+		"dob":               "Date of birth",
+		"email":             "Email",
+		"mobile_number":     "Mobile number",
+		"batch":             "Batch",
+		"section":           "Section",
+		"date":              "Date",
+		"month":             "Month",
+		"borrowed":          "Borrowed",
+		"returned":          "Returned",
+		"due":               "Due",
+		"requests":          "Requests",
+		"booksAdded":        "Books added",
+		"program_id":        "Program ID",
+		"program_name":      "Program",
+		"count":             "Books",
+		"teacher_id":        "Teacher ID",
+		"teacher_name":      "Teacher",
+		"classes":           "Classes per week",
+		"contact_hours":     "Contact hours",
+		"courses":           "Courses",
+		"credit_hours":      "Credit hours",
+		"building":          "Building",
+		"room_code":         "Room",
+		"room_type":         "Room type",
+		"capacity":          "Capacity",
+		"occupied_slots":    "Occupied slots",
+		"available_slots":   "Available slots",
+		"utilization":       "Utilization (%)",
+		"underused_lab":     "Under-used lab",
+		"book_id":           "Book ID",
+		"title":             "Title",
+		"author":            "Author",
+		"category_id":       "Category ID",
+		"category_name":     "Category",
+		"loans":             "Loans",
+		"borrowers":         "Borrowers",
+		"titles":            "Titles",
+		"copies":            "Copies",
+		"last_loaned_at":    "Last loaned on",
+		"added_at":          "Added on",
+		"late_returns":      "Late returns",
+		"average_days":      "Average days",
+		"median_days":       "Median days",
+		"longest_days":      "Longest days",
+		"loans_per_student": "Loans per student",
+		"borrower_rate":     "Borrower rate (%)",
+		"collector_id":      "Collector ID",
+		"collector_name":    "Collector",
+		"issued":            "Issued",
+		"issued_amount":     "Issued (Rs.)",
+		"collected":         "Collected",
+		"collected_amount":  "Collected (Rs.)",
+		"waived":            "Waived",
+		"waived_amount":     "Waived (Rs.)",
+		"total":             "Total",
+		"bucket":            "Days outstanding",
+		"days_0_30":         "0-30 days (Rs.)",
+		"days_31_60":        "31-60 days (Rs.)",
+		"over_60_days":      "Over 60 days (Rs.)",
+		"fine_id":           "Fine ID",
+		"entry":             "Entry",
+		"receipt_number":    "Receipt number",
+		"debit":             "Debit (Rs.)",
+		"credit":            "Credit (Rs.)",
+		"balance":           "Balance (Rs.)",
+		"opening_balance":   "Opening balance",
+		"closing_balance":   "Closing balance",
+		"collected_by":      "Collected by",
+		"waived_at":         "Waived on",
+		"waiver_reason":     "Waiver reason",
+	},
+	LangNepali: {
+		"period":             "अवधि",
+		"generated":          "तयार मिति",
+		"truncated":          "थप %d पङ्क्ति छुटाइएको छ, सबै पङ्क्तिका लागि csv वा xlsx मा निर्यात गर्नुहोस्",
+		"borrows":            "उधारो पुस्तकहरू",
+		"fines":              "जरिवाना",
+		"students":           "विद्यार्थीहरू",
+		"dashboard-stats":    "पुस्तकालय ड्यासबोर्ड",
+		"chart-stats":        "पुस्तक आदानप्रदान चार्ट",
+		"borrowedbookstats":  "उधारो पुस्तक तथ्याङ्क",
+		"program-stats":      "कार्यक्रम अनुसार पुस्तक",
+		"inventory-stats":    "मौज्दात",
+		"teacher-workload":   "शिक्षक कार्यभार",
+		"room-utilization":   "कोठा उपयोग",
+		"top-books":          "धेरै उधारो लिइएका पुस्तक",
+		"top-categories":     "धेरै उधारो लिइएका वर्ग",
+		"inactive-stock":     "निष्क्रिय मौज्दात",
+		"loan-duration":      "उधारो अवधि",
+		"borrowing-rates":    "उधारो दर",
+		"top-readers":        "शीर्ष पाठक",
+		"fine-collection":    "जरिवाना सङ्कलन",
+		"fine-aging":         "अवधि अनुसार बाँकी जरिवाना",
+		"student-fine-aging": "विद्यार्थी अनुसार बाँकी जरिवाना",
+		"fine-statement":     "जरिवाना विवरण",
+		"metric":             "विवरण",
+		"value":              "सङ्ख्या",
+		"activeStudents":     "सक्रिय विद्यार्थी",
+		"availableBooks":     "उपलब्ध पुस्तक",
+		"borrowedBooks":      "उधारो पुस्तक",
+		"overdueBooks":       "म्याद नाघेका पुस्तक",
+		"pendingRequests":    "बाँकी अनुरोध",
+		"totalBooks":         "जम्मा पुस्तक",
+		"totalFines":         "बाँकी जरिवाना (रु.)",
+		"totalStudents":      "जम्मा विद्यार्थी",
+		"totalBorrowedBooks": "जम्मा उधारो पुस्तक",
+		"totalOverdueBooks":  "जम्मा म्याद नाघेका पुस्तक",
+		"dueSoon":            "म्याद नजिकिएका",
+		"id":                 "आईडी",
+		"created_at":         "सिर्जना मिति",
+		"user_id":            "प्रयोगकर्ता आईडी",
+		"student_name":       "विद्यार्थी",
+		"username":           "प्रयोगकर्ता नाम",
+		"book_title":         "पुस्तक",
+		"accession_number":   "दर्ता नम्बर",
+		"borrowed_date":      "उधारो मिति",
+		"due_date":           "फिर्ता गर्नुपर्ने मिति",
+		"returned_date":      "फिर्ता मिति",
+		"renewal_count":      "नवीकरण",
+		"status":             "अवस्था",
+		"remarks":            "कैफियत",
+		"borrowed_book_id":   "उधारो आईडी",
+		"amount":             "रकम (रु.)",
+		"reason":             "कारण",
+		"paid_at":            "भुक्तानी मिति",
+		"full_name":          "पूरा नाम",
+		"gender":             "लिङ्ग",
+		"dob":                "जन्म मिति",
+		"email":              "इमेल",
+		"mobile_number":      "मोबाइल नम्बर",
+		"batch":              "ब्याच",
+		"section":            "सेक्सन",
+		"date":               "मिति",
+		"month":              "महिना",
+		"borrowed":           "उधारो",
+		"returned":           "फिर्ता",
+		"due":                "म्याद",
+		"requests":           "अनुरोध",
+		"booksAdded":         "थपिएका पुस्तक",
+		"program_id":         "कार्यक्रम आईडी",
+		"program_name":       "कार्यक्रम",
+		"count":              "पुस्तक",
+		"teacher_id":         "शिक्षक आईडी",
+		"teacher_name":       "शिक्षक",
+		"classes":            "साप्ताहिक कक्षा",
+		"contact_hours":      "सम्पर्क घण्टा",
+		"courses":            "विषय",
+		"credit_hours":       "क्रेडिट घण्टा",
+		"building":           "भवन",
+		"room_code":          "कोठा",
+		"room_type":          "कोठाको प्रकार",
+		"capacity":           "क्षमता",
+		"occupied_slots":     "प्रयोग भएका स्लट",
+		"available_slots":    "उपलब्ध स्लट",
+		"utilization":        "उपयोग (%)",
+		"underused_lab":      "कम प्रयोग भएको ल्याब",
+		"book_id":            "पुस्तक आईडी",
+		"title":              "शीर्षक",
+		"author":             "लेखक",
+		"category_id":        "वर्ग आईडी",
+		"category_name":      "वर्ग",
+		"loans":              "उधारो सङ्ख्या",
+		"borrowers":          "उधारो लिने",
+		"titles":             "शीर्षकहरू",
+		"copies":             "प्रति",
+		"last_loaned_at":     "अन्तिम उधारो मिति",
+		"added_at":           "थपिएको मिति",
+		"late_returns":       "ढिलो फिर्ता",
+		"average_days":       "औसत दिन",
+		"median_days":        "मध्यक दिन",
+		"longest_days":       "सबैभन्दा लामो दिन",
+		"loans_per_student":  "प्रति विद्यार्थी उधारो",
+		"borrower_rate":      "उधारो लिने दर (%)",
+		"collector_id":       "सङ्कलक आईडी",
+		"collector_name":     "सङ्कलक",
+		"issued":             "जारी",
+		"issued_amount":      "जारी (रु.)",
+		"collected":          "सङ्कलित",
+		"collected_amount":   "सङ्कलित (रु.)",
+		"waived":             "मिनाहा",
+		"waived_amount":      "मिनाहा (रु.)",
+		"total":              "जम्मा",
+		"bucket":             "बाँकी दिन",
+		"days_0_30":          "०-३० दिन (रु.)",
+		"days_31_60":         "३१-६० दिन (रु.)",
+		"over_60_days":       "६० दिनभन्दा बढी (रु.)",
+		"fine_id":            "जरिवाना आईडी",
+		"entry":              "प्रविष्टि",
+		"receipt_number":     "रसिद नम्बर",
+		"debit":              "डेबिट (रु.)",
+		"credit":             "क्रेडिट (रु.)",
+		"balance":            "बाँकी (रु.)",
+		"opening_balance":    "सुरु बाँकी",
+		"closing_balance":    "अन्तिम बाँकी",
+		"collected_by":       "सङ्कलन गर्ने",
+		"waived_at":          "मिनाहा मिति",
+		"waiver_reason":      "मिनाहाको कारण",
+	},
+}
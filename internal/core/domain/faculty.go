@@ -1,6 +1,64 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
 type Faculty struct {
 	BaseModel
-	Name string `gorm:"size:100;not null;unique"`
+	Name        string    `gorm:"size:100;not null;unique" json:"name"`
+	Code        string    `gorm:"size:20;not null;unique" json:"code"`
+	Description string    `json:"description"`
+	Programs    []Program `gorm:"foreignKey:FacultyID" json:"programs,omitempty"`
+}
+
+type FacultyRequest struct {
+	Name        string `json:"name"`
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+type UpdateFacultyRequest struct {
+	Name        string `json:"name"`
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+type ListFacultyRequest struct {
+	ListRequest
+	Name string `form:"name"`
+}
+
+type FacultyResponse struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name"`
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+}
+
+func (r *FacultyRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("faculty name is required")
+	}
+	if r.Code == "" {
+		return errors.New("faculty code is required")
+	}
+	return nil
+}
+
+func (r *UpdateFacultyRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.Name != "" {
+		mp["name"] = r.Name
+	}
+	if r.Code != "" {
+		mp["code"] = r.Code
+	}
+	if r.Description != "" {
+		mp["description"] = r.Description
+	}
+	return mp
 }
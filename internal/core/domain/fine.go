@@ -1,57 +1,84 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+const (
+	FinePending = "pending"
+	FinePaid    = "paid"
+	FineWaived  = "waived"
+)
 
 type Fine struct {
 	BaseModel
-	UserID         int        `gorm:"not null" json:"user_id"`
-	BorrowedBookID int        `gorm:"column:borrowed_book_id;not null" json:"borrowed_book_id"`
+	UserID         string     `gorm:"not null;index" json:"user_id"`
+	BorrowedBookID string     `gorm:"column:borrowed_book_id;not null" json:"borrowed_book_id"`
 	Amount         int        `gorm:"not null" json:"amount"` // in paisa
 	Reason         string     `gorm:"not null" json:"reason"`
-	Status         string     `gorm:"not null" json:"status"` // 'pending' | 'paid'
+	Status         string     `gorm:"not null" json:"status"` // 'pending' | 'paid' | 'waived'
 	PaidAt         *time.Time `gorm:"column:paid_at" json:"paid_at"`
 	IsActive       bool       `gorm:"column:is_active;default:false" json:"is_active"`
+	// IssuedBy, CollectedBy and WaivedBy are the staff who issued, collected and waived the fine
+	IssuedBy      string     `gorm:"column:issued_by;index" json:"issued_by"`
+	CollectedBy   string     `gorm:"column:collected_by;index" json:"collected_by"`
+	ReceiptNumber *string    `gorm:"column:receipt_number;uniqueIndex" json:"receipt_number"`
+	WaivedBy      string     `gorm:"column:waived_by;index" json:"waived_by"`
+	WaivedAt      *time.Time `gorm:"column:waived_at" json:"waived_at"`
+	WaiverReason  string     `gorm:"column:waiver_reason" json:"waiver_reason"`
 }
 
 type FineRequest struct {
 	ID             string     `json:"id"`
 	CreatedAt      time.Time  `json:"created_at"`
-	UserID         int        `json:"user_id"`
-	BorrowedBookID int        `json:"borrowed_book_id"`
+	UserID         string     `json:"user_id"`
+	BorrowedBookID string     `json:"borrowed_book_id"`
 	Amount         int        `json:"amount"` // in paisa
 	Reason         string     `json:"reason"`
-	Status         string     `json:"status"` // 'pending' | 'paid'
+	Status         string     `json:"status"` // 'pending' | 'paid' | 'waived'
 	PaidAt         *time.Time `json:"paid_at"`
 }
 
 type UpdateFineRequest struct {
-	UserID         int        `json:"user_id"`
-	BorrowedBookID int        `json:"borrowed_book_id"`
+	UserID         string     `json:"user_id"`
+	BorrowedBookID string     `json:"borrowed_book_id"`
 	Amount         int        `json:"amount"` // in paisa
 	Reason         string     `json:"reason"`
-	Status         string     `json:"status"` // 'pending' | 'paid'
+	Status         string     `json:"status"` // 'pending' | 'paid' | 'waived'
 	PaidAt         *time.Time `json:"paid_at"`
 }
 
 type ListFineRequest struct {
 	ListRequest
-	UserID         int        `form:"user_id"`
-	BorrowedBookID int        `form:"borrowed_book_id"`
+	UserID         string     `form:"user_id"`
+	BorrowedBookID string     `form:"borrowed_book_id"`
 	Amount         int        `form:"amount"` // in paisa
 	Reason         string     `form:"reason"`
-	Status         string     `form:"status"` // 'pending' | 'paid'
+	Status         string     `form:"status"` // 'pending' | 'paid' | 'waived'
 	PaidAt         *time.Time `form:"paid_at"`
 }
 
 type FineResponse struct {
 	ID             string     `json:"id"`
 	CreatedAt      time.Time  `json:"created_at"`
-	UserID         int        `json:"user_id"`
-	BorrowedBookID int        `json:"borrowed_book_id"`
+	UserID         string     `json:"user_id"`
+	BorrowedBookID string     `json:"borrowed_book_id"`
 	Amount         int        `json:"amount"` // in paisa
 	Reason         string     `json:"reason"`
-	Status         string     `json:"status"` // 'pending' | 'paid'
+	Status         string     `json:"status"` // 'pending' | 'paid' | 'waived'
 	PaidAt         *time.Time `json:"paid_at"`
+	IssuedBy       string     `json:"issued_by"`
+	CollectedBy    string     `json:"collected_by"`
+	ReceiptNumber  *string    `json:"receipt_number"`
+	WaivedBy       string     `json:"waived_by"`
+	WaivedAt       *time.Time `json:"waived_at"`
+	WaiverReason   string     `json:"waiver_reason"`
+}
+
+// WaiveFineRequest writes a pending fine off, the reason is kept for the finance office
+type WaiveFineRequest struct {
+	Reason string `json:"reason"`
 }
 
 func (u *FineRequest) Validate() error {
@@ -61,3 +88,10 @@ func (u *FineRequest) Validate() error {
 func (r *UpdateFineRequest) NewUpdate() Map {
 	return nil
 }
+
+func (r *WaiveFineRequest) Validate() error {
+	if r.Reason == "" {
+		return errors.New("required waiver reason")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	FineGroupDay       = "day"
+	FineGroupMonth     = "month"
+	FineGroupCollector = "collector"
+)
+
+// Entries of a fine statement, a fine is issued once and then either paid or waived
+const (
+	FineEntryIssued = "issued"
+	FineEntryPaid   = "paid"
+	FineEntryWaived = "waived"
+)
+
+// FineCollectionRequest groups the fines issued, collected and waived in the period by day, by
+// month or by the staff member who handled them
+type FineCollectionRequest struct {
+	ReportPeriod
+	GroupBy string `form:"group_by" example:"day"` // day (default) | month | collector
+}
+
+func (r *FineCollectionRequest) Prepare() error {
+	if err := r.ReportPeriod.Prepare(); err != nil {
+		return err
+	}
+	switch r.GroupBy {
+	case "":
+		r.GroupBy = FineGroupDay
+	case FineGroupDay, FineGroupMonth, FineGroupCollector:
+	default:
+		return fmt.Errorf("unsupported group_by %q, use day, month or collector", r.GroupBy)
+	}
+	return nil
+}
+
+// FineCollection is one day, month or collector of the collection report, amounts are in paisa.
+// A collector row counts the fines the staff member issued, collected and waived.
+type FineCollection struct {
+	Period          string `json:"period,omitempty"` // YYYY-MM-DD by day, YYYY-MM by month
+	CollectorID     string `json:"collector_id,omitempty"`
+	CollectorName   string `json:"collector_name,omitempty"`
+	Issued          int64  `json:"issued"`
+	IssuedAmount    int64  `json:"issued_amount"`
+	Collected       int64  `json:"collected"`
+	CollectedAmount int64  `json:"collected_amount"`
+	Waived          int64  `json:"waived"`
+	WaivedAmount    int64  `json:"waived_amount"`
+}
+
+type FineCollectionReport struct {
+	GroupBy string           `json:"group_by"`
+	Rows    []FineCollection `json:"rows"`
+	Total   FineCollection   `json:"total"`
+}
+
+// Age buckets of outstanding fines, in days since the fine was issued
+const (
+	FineAge0To30  = "0-30"
+	FineAge31To60 = "31-60"
+	FineAgeOver60 = "60+"
+)
+
+// FineAgingBucket sums the fines still pending that were issued within the bucket's days
+type FineAgingBucket struct {
+	Bucket   string `json:"bucket"`
+	Fines    int64  `json:"fines"`
+	Students int64  `json:"students"`
+	Amount   int64  `json:"amount"` // in paisa
+}
+
+type FineAging struct {
+	Buckets     []FineAgingBucket `json:"buckets"`
+	Fines       int64             `json:"fines"`
+	Outstanding int64             `json:"outstanding"` // in paisa
+}
+
+// StudentFineAging is the outstanding balance of a student split by age, amounts are in paisa
+type StudentFineAging struct {
+	UserID     string `json:"user_id"`
+	FullName   string `json:"full_name"`
+	Username   string `json:"username"`
+	Days0To30  int64  `json:"days_0_30"`
+	Days31To60 int64  `json:"days_31_60"`
+	Over60Days int64  `json:"over_60_days"`
+	Total      int64  `json:"total"`
+}
+
+// FineStatementRequest is the fine account of one student over the period
+type FineStatementRequest struct {
+	ReportPeriod
+	UserID string `form:"user_id" binding:"required"`
+}
+
+// FineEvent is a fine being issued, paid or waived, StaffID is who did it
+type FineEvent struct {
+	FineID        string    `json:"fine_id"`
+	Entry         string    `json:"entry"`
+	At            time.Time `json:"at"`
+	StaffID       string    `json:"staff_id"`
+	Amount        int64     `json:"amount"`
+	Reason        string    `json:"reason"`
+	ReceiptNumber string    `json:"receipt_number"`
+}
+
+// FineStatementLine is an entry of a statement, an issued fine is a debit and a payment or
+// waiver a credit. Balance is what the student owes after the entry.
+type FineStatementLine struct {
+	Date          time.Time `json:"date"`
+	FineID        string    `json:"fine_id"`
+	Entry         string    `json:"entry"`
+	Reason        string    `json:"reason"`
+	ReceiptNumber string    `json:"receipt_number,omitempty"`
+	Debit         int64     `json:"debit"`
+	Credit        int64     `json:"credit"`
+	Balance       int64     `json:"balance"`
+}
+
+// FineStatement reconciles a student's fines over the period, amounts are in paisa. The opening
+// balance plus issued, less paid and waived, is the closing balance.
+type FineStatement struct {
+	UserID         string              `json:"user_id"`
+	FullName       string              `json:"full_name"`
+	Username       string              `json:"username"`
+	StartDate      string              `json:"start_date"`
+	EndDate        string              `json:"end_date"`
+	OpeningBalance int64               `json:"opening_balance"`
+	Issued         int64               `json:"issued"`
+	Paid           int64               `json:"paid"`
+	Waived         int64               `json:"waived"`
+	ClosingBalance int64               `json:"closing_balance"`
+	Lines          []FineStatementLine `json:"lines"`
+}
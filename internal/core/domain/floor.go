@@ -1,10 +1,64 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
 type Floor struct {
 	BaseModel
-	BuildingID uint
-	Building   Building
+	BuildingID string    `gorm:"type:uuid;index" json:"building_id"`
+	Building   *Building `gorm:"foreignKey:BuildingID" json:"building,omitempty"`
+
+	FloorNumber int    `json:"floor_number"`
+	Description string `gorm:"size:100" json:"description"`
+	Rooms       []Room `gorm:"foreignKey:FloorID" json:"rooms,omitempty"`
+}
+
+type FloorRequest struct {
+	BuildingID  string `json:"building_id"`
+	FloorNumber int    `json:"floor_number"`
+	Description string `json:"description"`
+}
+
+type UpdateFloorRequest struct {
+	BuildingID  string `json:"building_id"`
+	FloorNumber *int   `json:"floor_number"`
+	Description string `json:"description"`
+}
+
+type ListFloorRequest struct {
+	ListRequest
+	BuildingID string `form:"building_id"`
+}
+
+type FloorResponse struct {
+	ID          string            `json:"id"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	BuildingID  string            `json:"building_id"`
+	FloorNumber int               `json:"floor_number"`
+	Description string            `json:"description"`
+	Building    *BuildingResponse `json:"building,omitempty"`
+}
+
+func (r *FloorRequest) Validate() error {
+	if r.BuildingID == "" {
+		return errors.New("building id is required")
+	}
+	return nil
+}
 
-	FloorNumber int
-	Description string `gorm:"size:100"`
+func (r *UpdateFloorRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.BuildingID != "" {
+		mp["building_id"] = r.BuildingID
+	}
+	if r.FloorNumber != nil {
+		mp["floor_number"] = *r.FloorNumber
+	}
+	if r.Description != "" {
+		mp["description"] = r.Description
+	}
+	return mp
 }
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// Entity types with a history timeline, they match the EntityType of their audit log entries
+const (
+	HistoryBook     = "book"
+	HistoryBookCopy = "book_copy"
+	HistoryBorrow   = "borrowed_book"
+	HistoryUser     = "user"
+	HistoryFine     = "fine"
+)
+
+// Patron timeline events, other entries keep "<entity_type>.<action>"
+const (
+	PatronRequest = "request"
+	PatronLoan    = "loan"
+	PatronReturn  = "return"
+	PatronFine    = "fine"
+	PatronPayment = "payment"
+)
+
+type ListHistoryRequest struct {
+	ListRequest
+	Action string `form:"action"`
+}
+
+// HistoryActor is who made a change, ID is empty when it was the system
+type HistoryActor struct {
+	ID       string `json:"id,omitempty"`
+	Username string `json:"username"`
+	FullName string `json:"full_name,omitempty"`
+}
+
+// HistoryEntry is one change on a timeline, Changes holds the fields that changed with their
+// before and after values
+type HistoryEntry struct {
+	ID         string                 `json:"id"`
+	Sequence   int64                  `json:"sequence"`
+	At         time.Time              `json:"at"`
+	Event      string                 `json:"event,omitempty"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Title      string                 `json:"title"`
+	Actor      HistoryActor           `json:"actor"`
+	Changes    map[string]AuditChange `json:"changes"`
+	RequestID  string                 `json:"request_id,omitempty"`
+}
+
+// PatronEvent names what an audit log entry means on a patron's timeline
+func PatronEvent(a *AuditLog) string {
+	switch {
+	case a.EntityType == HistoryBorrow && a.Action == AuditCreate:
+		return PatronRequest
+	case a.EntityType == HistoryBorrow && a.Action == "issue":
+		return PatronLoan
+	case a.EntityType == HistoryBorrow && a.Action == "return":
+		return PatronReturn
+	case a.EntityType == HistoryFine && a.Action == AuditCreate:
+		return PatronFine
+	case a.EntityType == HistoryFine && a.Action == AuditUpdate && a.Changes["status"].After == "paid":
+		return PatronPayment
+	}
+	return a.EntityType + "." + a.Action
+}
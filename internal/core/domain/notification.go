@@ -1,47 +1,79 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Notification audiences
+const (
+	AudienceUser = "user"
+	AudienceRole = "role"
+	AudienceAll  = "all"
+)
+
+// Notification is addressed to one user, everyone with a role or everybody, whether it has been
+// read is kept per recipient in NotificationRead
 type Notification struct {
 	BaseModel
-	UserID      string `gorm:"not null" json:"user_id"`
+	Audience    string `gorm:"size:10;not null;default:'user';index" json:"audience"`
+	UserID      string `gorm:"index" json:"user_id"`      // set for the user audience
+	Role        string `gorm:"size:50;index" json:"role"` // set for the role audience, lower case
 	Title       string `gorm:"not null" json:"title"`
 	Description string `gorm:"not null" json:"description"`
 	Module      string `gorm:"not null" json:"module"` // 'book' | 'program' | 'user' | 'general'
 	Action      string `gorm:"not null" json:"action"` // 'create' | 'update' | 'delete'
 	Type        string `gorm:"not null" json:"type"`
-	IsRead      bool   `gorm:"column:is_read;default:false" json:"is_read"`
 	IsActive    bool   `gorm:"column:is_active;default:false" json:"is_active"`
+	// IsRead is filled in for the recipient the notification was loaded for
+	IsRead bool `gorm:"->;-:migration" json:"is_read"`
+}
+
+// NotificationRead marks a notification as read by one recipient
+type NotificationRead struct {
+	NotificationID string    `gorm:"type:uuid;primaryKey" json:"notification_id"`
+	UserID         string    `gorm:"primaryKey" json:"user_id"`
+	ReadAt         time.Time `gorm:"not null" json:"read_at"`
+}
+
+// NotificationRecipient is the user notifications are loaded for, role and broadcast
+// notifications from before Since, the user's creation, are not shown
+type NotificationRecipient struct {
+	UserID string
+	Roles  []string
+	Since  time.Time
 }
 
 type NotificationRequest struct {
-	BaseModel
-	UserID      string `json:"user_id" validate:"required"`
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	Type        string `json:"type" validate:"required"` // 'due_reminder' | 'fine' | 'request_approved' | 'general'
-	IsRead      bool   `json:"is_read"`
+	Audience    string `json:"audience"` // 'user' | 'role' | 'all', user when empty
+	UserID      string `json:"user_id"`
+	Role        string `json:"role"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Module      string `json:"module"`
+	Type        string `json:"type"` // 'due_reminder' | 'fine' | 'request_approved' | 'general'
 }
 
 type UpdateNotificationRequest struct {
-	BaseModel
-	UserID      string `json:"user_id"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Type        string `json:"type"` // 'due_reminder' | 'fine' | 'request_approved' | 'general'
-	IsRead      bool   `json:"is_read"`
 }
 
 type ListNotificationRequest struct {
 	ListRequest
-	UserID      string `form:"user_id"`
-	Title       string `form:"title"`
-	Description string `form:"description"`
-	Type        string `form:"type"` // 'due_reminder' | 'fine' | 'request_approved' | 'general'
-	IsRead      bool   `form:"is_read"`
+	Type   string `form:"type"`
+	Module string `form:"module"`
+	IsRead *bool  `form:"is_read"`
 }
 
 type NotificationResponse struct {
 	BaseModel
+	Audience    string `json:"audience"`
 	UserID      string `json:"user_id"`
+	Role        string `json:"role"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Module      string `json:"module"`
@@ -50,10 +82,77 @@ type NotificationResponse struct {
 	IsRead      bool   `json:"is_read"`
 }
 
-func (u *NotificationRequest) Validate() error {
+// ReadNotificationResponse is the result of marking notifications read
+type ReadNotificationResponse struct {
+	Marked int64 `json:"marked"`
+	Unread int64 `json:"unread"`
+}
+
+func (r *NotificationRequest) Validate() error {
+	if r.Audience == "" {
+		r.Audience = AudienceUser
+	}
+	switch r.Audience {
+	case AudienceUser:
+		if r.UserID == "" {
+			return errors.New("user_id is required for the user audience")
+		}
+		r.Role = ""
+	case AudienceRole:
+		if r.Role == "" {
+			return errors.New("role is required for the role audience")
+		}
+		r.Role = strings.ToLower(r.Role)
+		r.UserID = ""
+	case AudienceAll:
+		r.UserID, r.Role = "", ""
+	default:
+		return fmt.Errorf("audience %q must be user, role or all", r.Audience)
+	}
+	if r.Title == "" {
+		return errors.New("title is required")
+	}
+	if r.Type == "" {
+		r.Type = "general"
+	}
+	if r.Module == "" {
+		r.Module = "general"
+	}
 	return nil
 }
 
 func (r *UpdateNotificationRequest) NewUpdate() Map {
-	return nil
+	mp := Map{}
+	if r.Title != "" {
+		mp["title"] = r.Title
+	}
+	if r.Description != "" {
+		mp["description"] = r.Description
+	}
+	if r.Type != "" {
+		mp["type"] = r.Type
+	}
+	return mp
+}
+
+// NotificationChannel is the Postgres LISTEN/NOTIFY channel notification changes are published
+// on so every API replica can push them to its own stream clients
+const NotificationChannel = "lms_notifications"
+
+// NotificationSignal is published whenever a user's notifications change, an empty UserID
+// concerns every user as role and broadcast notifications do
+type NotificationSignal struct {
+	UserID string `json:"user_id"`
+	Op     string `json:"op"` // 'created' | 'updated' | 'read' | 'unread' | 'deleted' | 'resync'
+}
+
+// NotificationStreamEvent is one Server-Sent Event, ID is only set on notification events
+type NotificationStreamEvent struct {
+	ID    string
+	Event string // 'notification' | 'unread'
+	Data  interface{}
+}
+
+type UnreadNotificationCount struct {
+	Unread int64 `json:"unread"`
 }
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Notification delivery channels
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+)
+
+var NotificationChannels = []string{ChannelInApp, ChannelEmail, ChannelSMS, ChannelWebhook}
+
+// Notification event types, the ones with an email or SMS version share the name of their
+// template under service/templates
+const (
+	EventDueReminder     = "due_reminder"
+	EventOverdue         = "overdue"
+	EventHoldReady       = "hold_ready"
+	EventFineIssued      = "fine_issued"
+	EventAccountCreated  = "account_created"
+	EventBorrowRequested = "borrow_requested"
+	EventBorrowReturned  = "borrow_returned"
+	EventBorrowDeleted   = "borrow_deleted"
+	EventBookCreated     = "book_created"
+	EventBookUpdated     = "book_updated"
+	EventBookCopyCreated = "book_copy_created"
+	EventBookCopyUpdated = "book_copy_updated"
+	EventProgramCreated  = "program_created"
+)
+
+// anyRole holds the default channels of roles that are not listed
+const anyRole = "*"
+
+// NotificationEvent is a kind of notification users can subscribe to, Defaults maps a role to
+// the channels it receives until the user sets a preference
+type NotificationEvent struct {
+	Type        string
+	Module      string
+	Action      string
+	Description string
+	Defaults    map[string][]string
+}
+
+// DefaultChannels returns the channels a user with role receives the event on by default
+func (e *NotificationEvent) DefaultChannels(role string) []string {
+	if channels, ok := e.Defaults[strings.ToLower(role)]; ok {
+		return channels
+	}
+	return e.Defaults[anyRole]
+}
+
+var inAppOnly = map[string][]string{anyRole: {ChannelInApp}}
+
+// NotificationEvents is the catalogue of notifications the API sends
+var NotificationEvents = []*NotificationEvent{
+	{Type: EventDueReminder, Module: "borrow", Action: "remind", Description: "A borrowed book is due within a day",
+		Defaults: map[string][]string{anyRole: {ChannelInApp, ChannelEmail, ChannelSMS}}},
+	{Type: EventOverdue, Module: "borrow", Action: "overdue", Description: "A borrowed book is past its due date",
+		Defaults: map[string][]string{anyRole: {ChannelInApp, ChannelEmail, ChannelSMS}}},
+	{Type: EventHoldReady, Module: "borrow", Action: "issue", Description: "A requested book has been issued and is ready to collect",
+		Defaults: map[string][]string{anyRole: {ChannelInApp, ChannelEmail, ChannelSMS}}},
+	{Type: EventFineIssued, Module: "fine", Action: "create", Description: "A fine has been added to the account",
+		Defaults: map[string][]string{anyRole: {ChannelInApp, ChannelEmail}}},
+	{Type: EventAccountCreated, Module: "user", Action: "create", Description: "The library account has been created",
+		Defaults: map[string][]string{anyRole: {ChannelInApp, ChannelEmail}}},
+	{Type: EventBorrowRequested, Module: "borrow", Action: "borrow", Description: "A book has been requested",
+		Defaults: inAppOnly},
+	{Type: EventBorrowReturned, Module: "borrow", Action: "return", Description: "A borrowed book has been returned",
+		Defaults: map[string][]string{RoleStudent: {ChannelInApp, ChannelEmail}, anyRole: {ChannelInApp}}},
+	{Type: EventBorrowDeleted, Module: "borrow", Action: "delete", Description: "A borrow record has been deleted",
+		Defaults: inAppOnly},
+	{Type: EventBookCreated, Module: "book", Action: "create", Description: "A book has been added to the catalogue",
+		Defaults: map[string][]string{RoleStudent: {}, RoleTeacher: {}, anyRole: {ChannelInApp}}},
+	{Type: EventBookUpdated, Module: "book", Action: "update", Description: "A book's details have changed",
+		Defaults: map[string][]string{RoleStudent: {}, RoleTeacher: {}, anyRole: {ChannelInApp}}},
+	{Type: EventBookCopyCreated, Module: "book_copy", Action: "create", Description: "A copy of a book has been added",
+		Defaults: map[string][]string{RoleStudent: {}, RoleTeacher: {}, anyRole: {ChannelInApp}}},
+	{Type: EventBookCopyUpdated, Module: "book_copy", Action: "update", Description: "A book copy's details have changed",
+		Defaults: map[string][]string{RoleStudent: {}, RoleTeacher: {}, anyRole: {ChannelInApp}}},
+	{Type: EventProgramCreated, Module: "program", Action: "create", Description: "A program has been added",
+		Defaults: inAppOnly},
+}
+
+// GetNotificationEvent looks up an event in the catalogue
+func GetNotificationEvent(eventType string) (*NotificationEvent, error) {
+	for _, event := range NotificationEvents {
+		if event.Type == eventType {
+			return event, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown notification event %s", eventType)
+}
+
+// NotificationPreference overrides a role default for one event on one channel, Target is the
+// URL notifications are posted to on the webhook channel
+type NotificationPreference struct {
+	BaseModel
+	UserID    string `gorm:"not null;uniqueIndex:idx_notification_preference" json:"user_id"`
+	EventType string `gorm:"size:50;not null;uniqueIndex:idx_notification_preference" json:"event_type"`
+	Channel   string `gorm:"size:20;not null;uniqueIndex:idx_notification_preference" json:"channel"`
+	Enabled   bool   `gorm:"not null" json:"enabled"`
+	Target    string `json:"target"`
+}
+
+type NotificationPreferenceRequest struct {
+	EventType string `json:"event_type"`
+	Channel   string `json:"channel"`
+	Enabled   bool   `json:"enabled"`
+	Target    string `json:"target"`
+}
+
+type UpdateNotificationPreferenceRequest struct {
+	Preferences []NotificationPreferenceRequest `json:"preferences"`
+}
+
+// NotificationPreferenceResponse is the effective setting of one event and channel, IsDefault
+// is true while it still comes from the user's role
+type NotificationPreferenceResponse struct {
+	EventType   string     `json:"event_type"`
+	Description string     `json:"description"`
+	Channel     string     `json:"channel"`
+	Enabled     bool       `json:"enabled"`
+	Target      string     `json:"target"`
+	IsDefault   bool       `json:"is_default"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+func (r *UpdateNotificationPreferenceRequest) Validate() error {
+	if len(r.Preferences) == 0 {
+		return errors.New("preferences are required")
+	}
+	for _, p := range r.Preferences {
+		if _, err := GetNotificationEvent(p.EventType); err != nil {
+			return err
+		}
+		if !slices.Contains(NotificationChannels, p.Channel) {
+			return fmt.Errorf("channel %q must be one of %s", p.Channel, strings.Join(NotificationChannels, ", "))
+		}
+		if p.Channel == ChannelWebhook && p.Enabled {
+			if err := validateWebhookURL(p.Target); err != nil {
+				return fmt.Errorf("webhook %s: %w", p.EventType, err)
+			}
+		}
+	}
+	return nil
+}
+
+// NotificationDispatch records that an event about a record was sent to a user so it is sent
+// at most once, whatever the channels
+type NotificationDispatch struct {
+	BaseModel
+	UserID      string `gorm:"not null;uniqueIndex:idx_notification_dispatch" json:"user_id"`
+	EventType   string `gorm:"size:50;not null;uniqueIndex:idx_notification_dispatch" json:"event_type"`
+	ReferenceID string `gorm:"not null;uniqueIndex:idx_notification_dispatch" json:"reference_id"`
+}
@@ -1,20 +1,21 @@
 package domain
 
 import (
+	"errors"
 	"time"
 )
 
-type ProgramNew struct {
+// Program is an academic program owned by a Faculty. FacultyID and Code are optional so that
+// programs created before the academic catalogue keep working.
+type Program struct {
 	BaseModel
-	FacultyID uint
-	Faculty   Faculty
+	FacultyID *string  `gorm:"type:uuid;index" json:"faculty_id"`
+	Faculty   *Faculty `gorm:"foreignKey:FacultyID" json:"faculty,omitempty"`
 
-	Name          string `gorm:"size:100;not null"`
-	DurationYears int
-}
+	Code          string     `gorm:"size:20;uniqueIndex:idx_program_code,where:code <> ''" json:"code"`
+	DurationYears int        `json:"duration_years"`
+	Semesters     []Semester `gorm:"foreignKey:ProgramID" json:"semesters,omitempty"`
 
-type Program struct {
-	BaseModel
 	Name     string `gorm:"name"`
 	Slug     string `gorm:"slug"`
 	Weight   int    `gorm:"weight"`
@@ -25,6 +26,10 @@ type Program struct {
 }
 
 type ProgramRequest struct {
+	FacultyID     *string `json:"faculty_id"`
+	Code          string  `json:"code"`
+	DurationYears int     `json:"duration_years"`
+
 	Name     string `json:"name"`
 	Slug     string `json:"slug"`
 	Type     string `json:"type"`
@@ -36,11 +41,16 @@ type ProgramRequest struct {
 
 type ListProgramRequest struct {
 	ListRequest
-	Weight   int  `form:"weight"`
-	IsActive bool `form:"is_active"`
+	FacultyID string `form:"faculty_id"`
+	Weight    int    `form:"weight"`
+	IsActive  bool   `form:"is_active"`
 }
 
 type ProgramUpdateRequest struct {
+	FacultyID     *string `json:"faculty_id"` // "" takes the program out of its faculty
+	Code          string  `json:"code"`
+	DurationYears int     `json:"duration_years"`
+
 	Name     string `json:"name"`
 	Tags     string `json:"tags"`
 	Labels   string `json:"labels"`
@@ -56,16 +66,26 @@ type ProgramResponse struct {
 	Slug      string    `json:"slug"`
 	Weight    int       `json:"weight"`
 	IsActive  bool      `json:"is_active"`
+
+	FacultyID     *string `json:"faculty_id"`
+	Code          string  `json:"code"`
+	DurationYears int     `json:"duration_years"`
 }
 
 func (r *ProgramRequest) Validate() error {
 	if err := IsValidName(r.Name); err != nil {
 		return err
 	}
+	if r.DurationYears < 0 {
+		return errors.New("duration years cannot be negative")
+	}
 	return nil
 }
 
 func (c *Program) NewProgram(req *ProgramRequest) {
+	c.FacultyID = req.FacultyID
+	c.Code = req.Code
+	c.DurationYears = req.DurationYears
 	c.Name = req.Name
 	c.Labels = req.Labels
 	c.Tags = req.Tags
@@ -77,6 +97,15 @@ func (r *ProgramUpdateRequest) NewUpdateRequest() Map {
 	if r.Name != "" {
 		mp["name"] = r.Name
 	}
+	if r.FacultyID != nil {
+		mp["faculty_id"] = OptionalID(r.FacultyID)
+	}
+	if r.Code != "" {
+		mp["code"] = r.Code
+	}
+	if r.DurationYears != 0 {
+		mp["duration_years"] = r.DurationYears
+	}
 	if r.Weight != 0 {
 		mp["weight"] = r.Weight
 	}
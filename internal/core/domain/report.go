@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"fmt"
+	"time"
+)
+
 type LibraryDashboardStats struct {
 	ActiveStudents  int64 `json:"activeStudents"`
 	AvailableBooks  int64 `json:"availableBooks"`
@@ -7,10 +12,24 @@ type LibraryDashboardStats struct {
 	OverdueBooks    int64 `json:"overdueBooks"`
 	PendingRequests int64 `json:"pendingRequests"`
 	TotalBooks      int64 `json:"totalBooks"`
-	TotalFines      int64 `json:"totalFines"`
+	TotalFines      int64 `json:"totalFines"` // outstanding, in paisa
 	TotalStudents   int64 `json:"totalStudents"`
 }
 
+// LoanGauges are the current circulation figures exported as metrics
+type LoanGauges struct {
+	ActiveLoans     int64 // issued and not returned yet
+	OverdueLoans    int64 // active loans past their due date
+	PendingRequests int64 // borrow requests not issued yet
+}
+
+// Freshness tells how old cached statistics are, Cached is false when they were just computed
+type Freshness struct {
+	ComputedAt time.Time `json:"computed_at"`
+	AgeSeconds int64     `json:"age_seconds"`
+	Cached     bool      `json:"cached"`
+}
+
 type ChartRequest struct {
 	StartDate string `form:"start_date"`
 	EndDate   string `form:"end_date"`
@@ -49,7 +68,7 @@ type InventoryStats struct {
 	TotalStudents   int64 `json:"totalStudents"`
 	ActiveStudents  int64 `json:"activeStudents"`
 	PendingRequests int64 `json:"pendingRequests"`
-	TotalFines      int64 `json:"totalFines"`
+	TotalFines      int64 `json:"totalFines"` // outstanding, in paisa
 }
 
 type DashboardStats struct {
@@ -62,3 +81,96 @@ type DashboardStats struct {
 	PendingRequests int `json:"pendingRequests"`
 	TotalFines      int `json:"totalFines"`
 }
+
+// ReportPeriod is the date range of a report, a month up to today by default
+type ReportPeriod struct {
+	StartDate string `form:"start_date" example:"2025-01-01"`
+	EndDate   string `form:"end_date" example:"2025-02-01"`
+	// From and To are the parsed period, To is the start of the day after EndDate
+	From time.Time `form:"-" json:"-"`
+	To   time.Time `form:"-" json:"-"`
+}
+
+func (r *ReportPeriod) Prepare() error {
+	today := time.Now().Truncate(24 * time.Hour)
+	r.From, r.To = today.AddDate(0, -1, 0), today
+	var err error
+	if r.StartDate != "" {
+		if r.From, err = time.Parse("2006-01-02", r.StartDate); err != nil {
+			return fmt.Errorf("invalid start date %q", r.StartDate)
+		}
+	}
+	if r.EndDate != "" {
+		if r.To, err = time.Parse("2006-01-02", r.EndDate); err != nil {
+			return fmt.Errorf("invalid end date %q", r.EndDate)
+		}
+	}
+	if r.To.Before(r.From) {
+		return fmt.Errorf("end date %s is before start date %s", r.To.Format("2006-01-02"), r.From.Format("2006-01-02"))
+	}
+	r.StartDate, r.EndDate = r.From.Format("2006-01-02"), r.To.Format("2006-01-02")
+	r.To = r.To.AddDate(0, 0, 1)
+	return nil
+}
+
+// AcademicReportRequest filters the routine based reports
+type AcademicReportRequest struct {
+	AcademicYear string  `form:"academic_year"`
+	Format       string  `form:"format"`        // json (default) | csv
+	LabThreshold float64 `form:"lab_threshold"` // labs below this utilization percent are reported as under-used, defaults to 30
+}
+
+// TeacherWorkload is the weekly teaching load of a teacher
+type TeacherWorkload struct {
+	TeacherID    string  `json:"teacher_id"`
+	TeacherName  string  `json:"teacher_name"`
+	Classes      int     `json:"classes"`       // routines per week
+	ContactHours float64 `json:"contact_hours"` // hours in class per week
+	Courses      int     `json:"courses"`       // distinct subject, semester and section taught
+	CreditHours  int     `json:"credit_hours"`  // credit hours of those courses
+}
+
+// RoomUtilization compares the routines booked in a room with the weekly slots it offers
+type RoomUtilization struct {
+	RoomID         string   `json:"room_id"`
+	RoomCode       string   `json:"room_code"`
+	RoomType       RoomType `json:"room_type"`
+	Capacity       int      `json:"capacity"`
+	BuildingID     string   `json:"building_id"`
+	BuildingName   string   `json:"building_name"`
+	OccupiedSlots  int64    `json:"occupied_slots"`
+	AvailableSlots int64    `json:"available_slots"`
+	Utilization    float64  `json:"utilization"` // percent
+}
+
+type BuildingUtilization struct {
+	BuildingID     string  `json:"building_id"`
+	BuildingName   string  `json:"building_name"`
+	Rooms          int     `json:"rooms"`
+	OccupiedSlots  int64   `json:"occupied_slots"`
+	AvailableSlots int64   `json:"available_slots"`
+	Utilization    float64 `json:"utilization"` // percent
+}
+
+type RoomUtilizationReport struct {
+	AcademicYear  string                `json:"academic_year"`
+	Rooms         []RoomUtilization     `json:"rooms"`
+	Buildings     []BuildingUtilization `json:"buildings"`
+	UnderusedLabs []RoomUtilization     `json:"underused_labs"`
+}
+
+// Percent is part of total as a percentage rounded down to two decimals
+func Percent(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part*10000/total) / 100
+}
+
+// Ratio is part over total rounded down to two decimals
+func Ratio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part*100/total) / 100
+}
@@ -1,5 +1,17 @@
 package domain
 
+import "strings"
+
+// Role names as seen by the application, the seeded rows are upper case
+const (
+	RoleStudent   = "student"
+	RoleTeacher   = "teacher"
+	RoleAdmin     = "admin"
+	RoleDirector  = "director"
+	RoleLibrarian = "librarian"
+	RoleStaff     = "staff"
+)
+
 type Role struct {
 	BaseModel
 	Name string `gorm:"unique;not null"`
@@ -14,3 +26,12 @@ type UserRole struct {
 	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 	Role Role `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE"`
 }
+
+// RoleNames returns the user's roles in lower case, Roles must be preloaded
+func (u *User) RoleNames() []string {
+	names := make([]string, 0, len(u.Roles))
+	for _, role := range u.Roles {
+		names = append(names, strings.ToLower(role.Name))
+	}
+	return names
+}
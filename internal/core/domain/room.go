@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
 type RoomType string
 
 const (
@@ -9,17 +15,146 @@ const (
 	Auditorium  RoomType = "AUDITORIUM"
 )
 
+func (t RoomType) IsValid() bool {
+	switch t {
+	case ClassRoom, LabRoom, SeminarRoom, Auditorium:
+		return true
+	}
+	return false
+}
+
 type Room struct {
 	BaseModel
 
-	FloorID uint
-	Floor   Floor
+	FloorID string `gorm:"type:uuid;index" json:"floor_id"`
+	Floor   *Floor `gorm:"foreignKey:FloorID" json:"floor,omitempty"`
+
+	RoomNumber   string   `gorm:"size:20" json:"room_number"`
+	RoomCode     string   `gorm:"size:20;unique" json:"room_code"`
+	RoomType     RoomType `gorm:"type:varchar(20);not null" json:"room_type"`
+	Capacity     int      `json:"capacity"`
+	HasProjector bool     `json:"has_projector"`
+	HasAC        bool     `json:"has_ac"`
+	Status       string   `gorm:"default:'ACTIVE'" json:"status"`
+}
+
+type RoomRequest struct {
+	FloorID      string   `json:"floor_id"`
+	RoomNumber   string   `json:"room_number"`
+	RoomCode     string   `json:"room_code"`
+	RoomType     RoomType `json:"room_type"`
+	Capacity     int      `json:"capacity"`
+	HasProjector bool     `json:"has_projector"`
+	HasAC        bool     `json:"has_ac"`
+	Status       string   `json:"status"`
+}
+
+type UpdateRoomRequest struct {
+	FloorID      string   `json:"floor_id"`
+	RoomNumber   string   `json:"room_number"`
+	RoomCode     string   `json:"room_code"`
+	RoomType     RoomType `json:"room_type"`
+	Capacity     *int     `json:"capacity"`
+	HasProjector *bool    `json:"has_projector"`
+	HasAC        *bool    `json:"has_ac"`
+	Status       string   `json:"status"`
+}
+
+type ListRoomRequest struct {
+	ListRequest
+	FloorID      string   `form:"floor_id"`
+	BuildingID   string   `form:"building_id"`
+	RoomType     RoomType `form:"room_type"`
+	MinCapacity  int      `form:"min_capacity"`
+	MaxCapacity  int      `form:"max_capacity"`
+	HasProjector *bool    `form:"has_projector"`
+	HasAC        *bool    `form:"has_ac"`
+	Status       string   `form:"status"`
+}
+
+type RoomResponse struct {
+	ID           string         `json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	FloorID      string         `json:"floor_id"`
+	RoomNumber   string         `json:"room_number"`
+	RoomCode     string         `json:"room_code"`
+	RoomType     RoomType       `json:"room_type"`
+	Capacity     int            `json:"capacity"`
+	HasProjector bool           `json:"has_projector"`
+	HasAC        bool           `json:"has_ac"`
+	Status       string         `json:"status"`
+	Floor        *FloorResponse `json:"floor,omitempty"`
+}
+
+// Location describes where the room is, e.g. "R-101, Floor 1, Main Block, Kathmandu Campus"
+func (r *Room) Location() string {
+	location := r.RoomCode
+	if r.Floor == nil {
+		return location
+	}
+	location += fmt.Sprintf(", Floor %d", r.Floor.FloorNumber)
+	if r.Floor.Building == nil {
+		return location
+	}
+	location += ", " + r.Floor.Building.Name
+	if r.Floor.Building.Campus != nil {
+		location += ", " + r.Floor.Building.Campus.Name
+	}
+	return location
+}
+
+func (r *RoomRequest) Validate() error {
+	if r.FloorID == "" {
+		return errors.New("floor id is required")
+	}
+	if r.RoomCode == "" {
+		return errors.New("room code is required")
+	}
+	if !r.RoomType.IsValid() {
+		return fmt.Errorf("invalid room type %s", r.RoomType)
+	}
+	if r.Capacity < 0 {
+		return errors.New("capacity cannot be negative")
+	}
+	return nil
+}
+
+func (r *UpdateRoomRequest) Validate() error {
+	if r.RoomType != "" && !r.RoomType.IsValid() {
+		return fmt.Errorf("invalid room type %s", r.RoomType)
+	}
+	if r.Capacity != nil && *r.Capacity < 0 {
+		return errors.New("capacity cannot be negative")
+	}
+	return nil
+}
 
-	RoomNumber   string   `gorm:"size:20"`
-	RoomCode     string   `gorm:"size:20;unique"`
-	RoomType     RoomType `gorm:"type:varchar(20);not null"`
-	Capacity     int
-	HasProjector bool
-	HasAC        bool
-	Status       string `gorm:"default:'ACTIVE'"`
+func (r *UpdateRoomRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.FloorID != "" {
+		mp["floor_id"] = r.FloorID
+	}
+	if r.RoomNumber != "" {
+		mp["room_number"] = r.RoomNumber
+	}
+	if r.RoomCode != "" {
+		mp["room_code"] = r.RoomCode
+	}
+	if r.RoomType != "" {
+		mp["room_type"] = r.RoomType
+	}
+	if r.Capacity != nil {
+		mp["capacity"] = *r.Capacity
+	}
+	if r.HasProjector != nil {
+		mp["has_projector"] = *r.HasProjector
+	}
+	if r.HasAC != nil {
+		mp["has_ac"] = *r.HasAC
+	}
+	if r.Status != "" {
+		mp["status"] = r.Status
+	}
+	return mp
 }
@@ -1,5 +1,12 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
 type DayOfWeek string
 
 const (
@@ -11,29 +18,342 @@ const (
 	Saturday  DayOfWeek = "SAT"
 )
 
+func (d DayOfWeek) IsValid() bool {
+	switch d {
+	case Monday, Tuesday, Wednesday, Thursday, Friday, Saturday:
+		return true
+	}
+	return false
+}
+
+// Index is the position of the day in Days, -1 when it is not a teaching day
+func (d DayOfWeek) Index() int {
+	for i, day := range Days {
+		if day == d {
+			return i
+		}
+	}
+	return -1
+}
+
+// Conflict types reported by routine scheduling checks
+const (
+	ConflictRoom               = "room"
+	ConflictTeacher            = "teacher"
+	ConflictSemester           = "semester"
+	ConflictCapacity           = "capacity"
+	ConflictLabRoom            = "lab_room"
+	ConflictTeacherUnavailable = "teacher_unavailable"
+)
+
 type ClassRoutine struct {
 	BaseModel
 
-	FacultyID uint
-	Faculty   Faculty
+	FacultyID *string  `gorm:"type:uuid" json:"faculty_id"`
+	Faculty   *Faculty `gorm:"foreignKey:FacultyID" json:"faculty,omitempty"`
+
+	ProgramID string   `gorm:"type:uuid;index" json:"program_id"`
+	Program   *Program `gorm:"foreignKey:ProgramID" json:"program,omitempty"`
+
+	SemesterID string    `gorm:"type:uuid;index" json:"semester_id"`
+	Semester   *Semester `gorm:"foreignKey:SemesterID" json:"semester,omitempty"`
+
+	SubjectID string   `gorm:"type:uuid;index" json:"subject_id"`
+	Subject   *Subject `gorm:"foreignKey:SubjectID" json:"subject,omitempty"`
+
+	TeacherID string `gorm:"type:uuid;index" json:"teacher_id"` // from user table
+	Teacher   *User  `gorm:"foreignKey:TeacherID" json:"teacher,omitempty"`
+
+	RoomID string `gorm:"type:uuid;index" json:"room_id"`
+	Room   *Room  `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+
+	TimeSlotID string    `gorm:"type:uuid;index" json:"time_slot_id"`
+	TimeSlot   *TimeSlot `gorm:"foreignKey:TimeSlotID" json:"time_slot,omitempty"`
+
+	Section      string    `gorm:"size:20" json:"section"`
+	StudentCount int       `json:"student_count"` // expected section size
+	DayOfWeek    DayOfWeek `gorm:"type:varchar(20);not null" json:"day_of_week"`
+	AcademicYear string    `gorm:"size:20" json:"academic_year"`
+}
+
+// TeacherUnavailability marks a day, or a single time slot of a day, when a teacher cannot be scheduled
+type TeacherUnavailability struct {
+	BaseModel
+	TeacherID  string    `gorm:"type:uuid;not null;index" json:"teacher_id"`
+	DayOfWeek  DayOfWeek `gorm:"type:varchar(20);not null" json:"day_of_week"`
+	TimeSlotID *string   `gorm:"type:uuid" json:"time_slot_id"` // nil means the whole day
+	Reason     string    `json:"reason"`
+}
+
+type ClassRoutineRequest struct {
+	FacultyID    *string   `json:"faculty_id"`
+	ProgramID    string    `json:"program_id"`
+	SemesterID   string    `json:"semester_id"`
+	SubjectID    string    `json:"subject_id"`
+	TeacherID    string    `json:"teacher_id"`
+	RoomID       string    `json:"room_id"`
+	TimeSlotID   string    `json:"time_slot_id"`
+	Section      string    `json:"section"`
+	StudentCount int       `json:"student_count"`
+	DayOfWeek    DayOfWeek `json:"day_of_week"`
+	AcademicYear string    `json:"academic_year"`
+}
+
+type UpdateClassRoutineRequest struct {
+	FacultyID    *string   `json:"faculty_id"` // "" clears the faculty
+	ProgramID    string    `json:"program_id"`
+	SemesterID   string    `json:"semester_id"`
+	SubjectID    string    `json:"subject_id"`
+	TeacherID    string    `json:"teacher_id"`
+	RoomID       string    `json:"room_id"`
+	TimeSlotID   string    `json:"time_slot_id"`
+	Section      string    `json:"section"`
+	StudentCount *int      `json:"student_count"`
+	DayOfWeek    DayOfWeek `json:"day_of_week"`
+	AcademicYear string    `json:"academic_year"`
+}
+
+type ListClassRoutineRequest struct {
+	ListRequest
+	ProgramID    string    `form:"program_id"`
+	SemesterID   string    `form:"semester_id"`
+	SubjectID    string    `form:"subject_id"`
+	TeacherID    string    `form:"teacher_id"`
+	RoomID       string    `form:"room_id"`
+	TimeSlotID   string    `form:"time_slot_id"`
+	Section      string    `form:"section"`
+	DayOfWeek    DayOfWeek `form:"day_of_week"`
+	AcademicYear string    `form:"academic_year"`
+}
+
+type ClassRoutineResponse struct {
+	ID           string     `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	FacultyID    *string    `json:"faculty_id"`
+	ProgramID    string     `json:"program_id"`
+	ProgramName  string     `json:"program_name"`
+	SemesterID   string     `json:"semester_id"`
+	SemesterName string     `json:"semester_name"`
+	SubjectID    string     `json:"subject_id"`
+	SubjectCode  string     `json:"subject_code"`
+	SubjectName  string     `json:"subject_name"`
+	IsLab        bool       `json:"is_lab"`
+	TeacherID    string     `json:"teacher_id"`
+	TeacherName  string     `json:"teacher_name"`
+	RoomID       string     `json:"room_id"`
+	RoomCode     string     `json:"room_code"`
+	Location     string     `json:"location"`
+	TimeSlotID   string     `json:"time_slot_id"`
+	StartTime    *time.Time `json:"start_time,omitempty"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	Section      string     `json:"section"`
+	StudentCount int        `json:"student_count"`
+	DayOfWeek    DayOfWeek  `json:"day_of_week"`
+	AcademicYear string     `json:"academic_year"`
+}
+
+type TeacherUnavailabilityRequest struct {
+	TeacherID  string    `json:"teacher_id"`
+	DayOfWeek  DayOfWeek `json:"day_of_week"`
+	TimeSlotID *string   `json:"time_slot_id"`
+	Reason     string    `json:"reason"`
+}
+
+type ListTeacherUnavailabilityRequest struct {
+	ListRequest
+	TeacherID string    `form:"teacher_id"`
+	DayOfWeek DayOfWeek `form:"day_of_week"`
+}
+
+type TeacherUnavailabilityResponse struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	TeacherID  string    `json:"teacher_id"`
+	DayOfWeek  DayOfWeek `json:"day_of_week"`
+	TimeSlotID *string   `json:"time_slot_id"`
+	Reason     string    `json:"reason"`
+}
 
-	ProgramID uint
-	Program   Program
+// RoutineConflict explains why a routine cannot be scheduled
+type RoutineConflict struct {
+	Type      string `json:"type"` // room | teacher | semester | capacity | lab_room | teacher_unavailable
+	Message   string `json:"message"`
+	RoutineID string `json:"routine_id,omitempty"`
+}
 
-	SemesterID uint
-	Semester   Semester
+// RoutineConflictError is returned when a routine clashes with existing schedules or constraints
+type RoutineConflictError struct {
+	Conflicts []RoutineConflict
+}
 
-	SubjectID uint
-	Subject   Subject
+func (e *RoutineConflictError) Error() string {
+	msgs := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		msgs = append(msgs, c.Message)
+	}
+	return fmt.Sprintf("routine has %d conflicts: %s", len(e.Conflicts), strings.Join(msgs, "; "))
+}
 
-	TeacherID uint // from user table
+func (r *ClassRoutineRequest) Validate() error {
+	if r.ProgramID == "" {
+		return errors.New("program id is required")
+	}
+	if r.SemesterID == "" {
+		return errors.New("semester id is required")
+	}
+	if r.SubjectID == "" {
+		return errors.New("subject id is required")
+	}
+	if r.TeacherID == "" {
+		return errors.New("teacher id is required")
+	}
+	if r.RoomID == "" {
+		return errors.New("room id is required")
+	}
+	if r.TimeSlotID == "" {
+		return errors.New("time slot id is required")
+	}
+	if !r.DayOfWeek.IsValid() {
+		return fmt.Errorf("invalid day of week %s", r.DayOfWeek)
+	}
+	if r.StudentCount < 0 {
+		return errors.New("student count cannot be negative")
+	}
+	return nil
+}
 
-	RoomID uint
-	Room   Room
+func (r *UpdateClassRoutineRequest) Validate() error {
+	if r.DayOfWeek != "" && !r.DayOfWeek.IsValid() {
+		return fmt.Errorf("invalid day of week %s", r.DayOfWeek)
+	}
+	if r.StudentCount != nil && *r.StudentCount < 0 {
+		return errors.New("student count cannot be negative")
+	}
+	return nil
+}
 
-	TimeSlotID uint
-	TimeSlot   TimeSlot
+// Apply copies the changed fields onto an existing routine so it can be checked before saving
+func (r *UpdateClassRoutineRequest) Apply(c *ClassRoutine) {
+	if r.FacultyID != nil {
+		c.FacultyID = OptionalID(r.FacultyID)
+	}
+	if r.ProgramID != "" {
+		c.ProgramID = r.ProgramID
+	}
+	if r.SemesterID != "" {
+		c.SemesterID = r.SemesterID
+	}
+	if r.SubjectID != "" {
+		c.SubjectID = r.SubjectID
+	}
+	if r.TeacherID != "" {
+		c.TeacherID = r.TeacherID
+	}
+	if r.RoomID != "" {
+		c.RoomID = r.RoomID
+	}
+	if r.TimeSlotID != "" {
+		c.TimeSlotID = r.TimeSlotID
+	}
+	if r.Section != "" {
+		c.Section = r.Section
+	}
+	if r.StudentCount != nil {
+		c.StudentCount = *r.StudentCount
+	}
+	if r.DayOfWeek != "" {
+		c.DayOfWeek = r.DayOfWeek
+	}
+	if r.AcademicYear != "" {
+		c.AcademicYear = r.AcademicYear
+	}
+}
+
+func (r *UpdateClassRoutineRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.FacultyID != nil {
+		mp["faculty_id"] = OptionalID(r.FacultyID)
+	}
+	if r.ProgramID != "" {
+		mp["program_id"] = r.ProgramID
+	}
+	if r.SemesterID != "" {
+		mp["semester_id"] = r.SemesterID
+	}
+	if r.SubjectID != "" {
+		mp["subject_id"] = r.SubjectID
+	}
+	if r.TeacherID != "" {
+		mp["teacher_id"] = r.TeacherID
+	}
+	if r.RoomID != "" {
+		mp["room_id"] = r.RoomID
+	}
+	if r.TimeSlotID != "" {
+		mp["time_slot_id"] = r.TimeSlotID
+	}
+	if r.Section != "" {
+		mp["section"] = r.Section
+	}
+	if r.StudentCount != nil {
+		mp["student_count"] = *r.StudentCount
+	}
+	if r.DayOfWeek != "" {
+		mp["day_of_week"] = r.DayOfWeek
+	}
+	if r.AcademicYear != "" {
+		mp["academic_year"] = r.AcademicYear
+	}
+	return mp
+}
+
+func (r *TeacherUnavailabilityRequest) Validate() error {
+	if r.TeacherID == "" {
+		return errors.New("teacher id is required")
+	}
+	if !r.DayOfWeek.IsValid() {
+		return fmt.Errorf("invalid day of week %s", r.DayOfWeek)
+	}
+	return nil
+}
 
-	DayOfWeek    DayOfWeek `gorm:"type:varchar(20);not null"`
-	AcademicYear string    `gorm:"size:20"`
+func (c *ClassRoutine) RoutineResponse() *ClassRoutineResponse {
+	data := &ClassRoutineResponse{
+		ID:           c.ID,
+		CreatedAt:    c.CreatedAt,
+		FacultyID:    c.FacultyID,
+		ProgramID:    c.ProgramID,
+		SemesterID:   c.SemesterID,
+		SubjectID:    c.SubjectID,
+		TeacherID:    c.TeacherID,
+		RoomID:       c.RoomID,
+		TimeSlotID:   c.TimeSlotID,
+		Section:      c.Section,
+		StudentCount: c.StudentCount,
+		DayOfWeek:    c.DayOfWeek,
+		AcademicYear: c.AcademicYear,
+	}
+	if c.Program != nil {
+		data.ProgramName = c.Program.Name
+	}
+	if c.Semester != nil {
+		data.SemesterName = c.Semester.Name
+	}
+	if c.Subject != nil {
+		data.SubjectCode = c.Subject.Code
+		data.SubjectName = c.Subject.Name
+		data.IsLab = c.Subject.IsLab
+	}
+	if c.Teacher != nil {
+		data.TeacherName = c.Teacher.FullName
+	}
+	if c.Room != nil {
+		data.RoomCode = c.Room.RoomCode
+		data.Location = c.Room.Location()
+	}
+	if c.TimeSlot != nil {
+		data.StartTime = &c.TimeSlot.StartTime
+		data.EndTime = &c.TimeSlot.EndTime
+	}
+	return data
 }
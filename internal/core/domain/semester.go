@@ -1,10 +1,72 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
+// Semester is an ordered term of a Program, SemesterNo is unique within the program
 type Semester struct {
 	BaseModel
-	ProgramID uint
-	Program   Program
+	ProgramID string   `gorm:"type:uuid;uniqueIndex:idx_program_semester_no" json:"program_id"`
+	Program   *Program `gorm:"foreignKey:ProgramID" json:"program,omitempty"`
+
+	Name       string `gorm:"size:50" json:"name"`
+	SemesterNo int    `gorm:"uniqueIndex:idx_program_semester_no" json:"semester_no"`
+}
+
+type SemesterRequest struct {
+	ProgramID  string `json:"program_id"`
+	Name       string `json:"name"`
+	SemesterNo int    `json:"semester_no"`
+}
+
+type UpdateSemesterRequest struct {
+	Name       string `json:"name"`
+	SemesterNo int    `json:"semester_no"`
+}
+
+type ListSemesterRequest struct {
+	ListRequest
+	ProgramID string `form:"program_id"`
+}
+
+type SemesterResponse struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	ProgramID  string    `json:"program_id"`
+	Name       string    `json:"name"`
+	SemesterNo int       `json:"semester_no"`
+}
+
+func (r *SemesterRequest) Validate() error {
+	if r.ProgramID == "" {
+		return errors.New("program id is required")
+	}
+	if r.Name == "" {
+		return errors.New("semester name is required")
+	}
+	if r.SemesterNo < 1 {
+		return errors.New("semester number must be at least 1")
+	}
+	return nil
+}
+
+func (r *UpdateSemesterRequest) Validate() error {
+	if r.SemesterNo < 0 {
+		return errors.New("semester number must be at least 1")
+	}
+	return nil
+}
 
-	Name       string `gorm:"size:50"`
-	SemesterNo int
+func (r *UpdateSemesterRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.Name != "" {
+		mp["name"] = r.Name
+	}
+	if r.SemesterNo != 0 {
+		mp["semester_no"] = r.SemesterNo
+	}
+	return mp
 }
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// SMS delivery statuses
+const (
+	SMSQueued   = "queued"
+	SMSSent     = "sent"
+	SMSFailed   = "failed"   // gave up after MaxSMSAttempts transient errors
+	SMSRejected = "rejected" // refused by the gateway, not retried
+	SMSLimited  = "limited"  // dropped by the per user daily limit
+)
+
+// MaxSMSAttempts is how many times an SMS is tried before it is marked failed
+const MaxSMSAttempts = 3
+
+// SMSMessage is a rendered text message ready to be sent
+type SMSMessage struct {
+	To   string
+	Body string
+}
+
+// SMSReceipt is what the gateway tells us about an accepted message
+type SMSReceipt struct {
+	ProviderID string
+}
+
+// SMSDelivery tracks one text message through the queue along with what it cost
+type SMSDelivery struct {
+	BaseModel
+	UserID        string     `gorm:"not null;index" json:"user_id"`
+	To            string     `gorm:"size:20;not null" json:"to"`
+	Template      string     `gorm:"size:50;not null;index:idx_sms_reference" json:"template"`
+	ReferenceID   string     `gorm:"index:idx_sms_reference" json:"reference_id"`
+	Body          string     `gorm:"type:text" json:"body"`
+	Segments      int        `json:"segments"`
+	Cost          int        `json:"cost"` // in paisa
+	Status        string     `gorm:"size:20;not null;index" json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	ProviderID    string     `json:"provider_id"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// MarkLimited drops the message for going over the daily limit of the user
+func (d *SMSDelivery) MarkLimited(limit int) {
+	d.Status = SMSLimited
+	d.Cost = 0
+	d.LastError = fmt.Sprintf("daily limit of %d messages reached", limit)
+}
+
+type ListSMSDeliveryRequest struct {
+	ListRequest
+	UserID   string `form:"user_id"`
+	Template string `form:"template"`
+	Status   string `form:"status"`
+}
+
+type SMSDeliveryResponse struct {
+	ID            string     `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UserID        string     `json:"user_id"`
+	To            string     `json:"to"`
+	Template      string     `json:"template"`
+	ReferenceID   string     `json:"reference_id"`
+	Body          string     `json:"body"`
+	Segments      int        `json:"segments"`
+	Cost          int        `json:"cost"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	ProviderID    string     `json:"provider_id"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// SMSUsageRequest filters sent messages by created_at between StartDate and EndDate
+type SMSUsageRequest struct {
+	StartDate string `form:"start_date" example:"2025-01-01"`
+	EndDate   string `form:"end_date" example:"2025-02-01"`
+}
+
+// SMSUsage is the sent volume and cost of one template
+type SMSUsage struct {
+	Template string `json:"template"`
+	Messages int64  `json:"messages"`
+	Segments int64  `json:"segments"`
+	Cost     int64  `json:"cost"` // in paisa
+}
+
+// SMSRejectedError is a permanent refusal from the gateway, the message is not retried
+type SMSRejectedError struct {
+	Status  int
+	Message string
+}
+
+func (e *SMSRejectedError) Error() string {
+	return fmt.Sprintf("sms rejected %d: %s", e.Status, e.Message)
+}
+
+// SMSPolicy decides which notifications go out as SMS, how many a user may receive a day and
+// when they may not be sent
+type SMSPolicy struct {
+	Templates      []string
+	DailyLimit     int // 0 for no limit
+	QuietStart     time.Time
+	QuietEnd       time.Time
+	Location       *time.Location // quiet hours are wall clock times here
+	CostPerSegment int            // in paisa
+}
+
+// Routes reports whether the named notification is also sent as SMS
+func (p *SMSPolicy) Routes(template string) bool {
+	return slices.Contains(p.Templates, template)
+}
+
+// NextSendTime returns t when it is outside quiet hours, otherwise the time quiet hours end
+func (p *SMSPolicy) NextSendTime(t time.Time) time.Time {
+	if p.Location != nil {
+		t = t.In(p.Location)
+	}
+	start := p.QuietStart.Hour()*60 + p.QuietStart.Minute()
+	end := p.QuietEnd.Hour()*60 + p.QuietEnd.Minute()
+	now := t.Hour()*60 + t.Minute()
+	quiet := false
+	switch {
+	case start < end:
+		quiet = now >= start && now < end
+	case start > end: // spans midnight
+		quiet = now >= start || now < end
+	}
+	if !quiet {
+		return t
+	}
+	next := time.Date(t.Year(), t.Month(), t.Day(), p.QuietEnd.Hour(), p.QuietEnd.Minute(), 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// gsm7 is the GSM 03.38 basic character set, gsm7Extended costs two septets each
+const (
+	gsm7 = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extended = "^{}\\[~]|€\f"
+)
+
+// SMSSegments counts how many billable parts a message is split into, 160/153 characters for
+// GSM-7 text and 70/67 UTF-16 units once any other character is used
+func SMSSegments(body string) int {
+	if body == "" {
+		return 0
+	}
+	septets := 0
+	for _, r := range body {
+		switch {
+		case strings.ContainsRune(gsm7, r):
+			septets++
+		case strings.ContainsRune(gsm7Extended, r):
+			septets += 2
+		default:
+			return segments(len(utf16.Encode([]rune(body))), 70, 67)
+		}
+	}
+	return segments(septets, 160, 153)
+}
+
+func segments(length, single, multi int) int {
+	if length <= single {
+		return 1
+	}
+	return int(math.Ceil(float64(length) / float64(multi)))
+}
@@ -1,12 +1,100 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
 type Subject struct {
 	BaseModel
-	ProgramID uint
-	Program   Program
+	ProgramID string   `gorm:"type:uuid;index" json:"program_id"`
+	Program   *Program `gorm:"foreignKey:ProgramID" json:"program,omitempty"`
+
+	SemesterID *string   `gorm:"type:uuid;index" json:"semester_id"` // nil for electives offered in any semester
+	Semester   *Semester `gorm:"foreignKey:SemesterID" json:"semester,omitempty"`
+
+	Code        string `gorm:"size:20;unique" json:"code"`
+	Name        string `gorm:"size:100;not null" json:"name"`
+	CreditHours int    `json:"credit_hours"`
+	IsLab       bool   `json:"is_lab"`
+}
+
+type SubjectRequest struct {
+	ProgramID   string  `json:"program_id"`
+	SemesterID  *string `json:"semester_id"`
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	CreditHours int     `json:"credit_hours"`
+	IsLab       bool    `json:"is_lab"`
+}
+
+type UpdateSubjectRequest struct {
+	SemesterID  *string `json:"semester_id"` // "" makes the subject an elective of any semester
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	CreditHours int     `json:"credit_hours"`
+	IsLab       *bool   `json:"is_lab"`
+}
+
+type ListSubjectRequest struct {
+	ListRequest
+	ProgramID  string `form:"program_id"`
+	SemesterID string `form:"semester_id"`
+	Code       string `form:"code"`
+	IsLab      *bool  `form:"is_lab"`
+}
+
+type SubjectResponse struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	ProgramID   string    `json:"program_id"`
+	SemesterID  *string   `json:"semester_id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	CreditHours int       `json:"credit_hours"`
+	IsLab       bool      `json:"is_lab"`
+}
+
+func (r *SubjectRequest) Validate() error {
+	if r.ProgramID == "" {
+		return errors.New("program id is required")
+	}
+	if r.Code == "" {
+		return errors.New("subject code is required")
+	}
+	if r.Name == "" {
+		return errors.New("subject name is required")
+	}
+	if r.CreditHours < 0 {
+		return errors.New("credit hours cannot be negative")
+	}
+	return nil
+}
+
+func (r *UpdateSubjectRequest) Validate() error {
+	if r.CreditHours < 0 {
+		return errors.New("credit hours cannot be negative")
+	}
+	return nil
+}
 
-	Code        string `gorm:"size:20;unique"`
-	Name        string `gorm:"size:100;not null"`
-	CreditHours int
-	IsLab       bool
+func (r *UpdateSubjectRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.SemesterID != nil {
+		mp["semester_id"] = OptionalID(r.SemesterID)
+	}
+	if r.Code != "" {
+		mp["code"] = r.Code
+	}
+	if r.Name != "" {
+		mp["name"] = r.Name
+	}
+	if r.CreditHours != 0 {
+		mp["credit_hours"] = r.CreditHours
+	}
+	if r.IsLab != nil {
+		mp["is_lab"] = *r.IsLab
+	}
+	return mp
 }
@@ -1,9 +1,110 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
+// ClockLayout is the wire format of TimeSlot start and end times
+const ClockLayout = "15:04"
+
+// TimeSlot is a daily teaching period, times are stored on a fixed date so only the clock matters
 type TimeSlot struct {
 	BaseModel
-	StartTime time.Time
-	EndTime   time.Time
+	Name      string    `gorm:"size:50" json:"name"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+type TimeSlotRequest struct {
+	Name      string `json:"name"`
+	StartTime string `json:"start_time" example:"09:00"`
+	EndTime   string `json:"end_time" example:"10:30"`
+}
+
+type UpdateTimeSlotRequest struct {
+	Name      string `json:"name"`
+	StartTime string `json:"start_time" example:"09:00"`
+	EndTime   string `json:"end_time" example:"10:30"`
+}
+
+type ListTimeSlotRequest struct {
+	ListRequest
+}
+
+type TimeSlotResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	StartTime string    `json:"start_time"`
+	EndTime   string    `json:"end_time"`
+}
+
+// ParseClock parses an HH:MM clock onto the fixed date TimeSlots are stored on
+func ParseClock(clock string) (time.Time, error) {
+	t, err := time.Parse(ClockLayout, clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+	return time.Date(2000, time.January, 1, t.Hour(), t.Minute(), 0, 0, time.Local), nil
+}
+
+func (r *TimeSlotRequest) Validate() error {
+	_, _, err := r.Clock()
+	return err
+}
+
+// Clock returns the parsed start and end times
+func (r *TimeSlotRequest) Clock() (time.Time, time.Time, error) {
+	start, err := ParseClock(r.StartTime)
+	if err != nil {
+		return start, start, err
+	}
+	end, err := ParseClock(r.EndTime)
+	if err != nil {
+		return start, end, err
+	}
+	if !end.After(start) {
+		return start, end, errors.New("end time must be after start time")
+	}
+	return start, end, nil
+}
+
+func (r *UpdateTimeSlotRequest) Validate() error {
+	for _, clock := range []string{r.StartTime, r.EndTime} {
+		if clock == "" {
+			continue
+		}
+		if _, err := ParseClock(clock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *UpdateTimeSlotRequest) NewUpdate() Map {
+	mp := map[string]interface{}{}
+	if r.Name != "" {
+		mp["name"] = r.Name
+	}
+	if start, err := ParseClock(r.StartTime); err == nil {
+		mp["start_time"] = start
+	}
+	if end, err := ParseClock(r.EndTime); err == nil {
+		mp["end_time"] = end
+	}
+	return mp
+}
+
+func (t *TimeSlot) TimeSlotResponse() *TimeSlotResponse {
+	return &TimeSlotResponse{
+		ID:        t.ID,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+		Name:      t.Name,
+		StartTime: t.StartTime.Format(ClockLayout),
+		EndTime:   t.EndTime.Format(ClockLayout),
+	}
 }
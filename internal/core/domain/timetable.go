@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// Timetable views
+const (
+	TimetableSemester = "semester"
+	TimetableTeacher  = "teacher"
+	TimetableRoom     = "room"
+)
+
+// Days lists the teaching days in timetable order
+var Days = []DayOfWeek{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday}
+
+type TimetableRequest struct {
+	View         string `form:"-"`
+	ID           string `form:"-"`
+	Section      string `form:"section"`
+	AcademicYear string `form:"academic_year"`
+}
+
+// TimetableResponse is a DayOfWeek × TimeSlot grid of routines
+type TimetableResponse struct {
+	View         string          `json:"view"`
+	ID           string          `json:"id"`
+	Title        string          `json:"title"`
+	AcademicYear string          `json:"academic_year"`
+	Days         []DayOfWeek     `json:"days"`
+	Slots        []TimetableSlot `json:"slots"`
+}
+
+type TimetableSlot struct {
+	TimeSlotID string                                `json:"time_slot_id"`
+	StartTime  time.Time                             `json:"start_time"`
+	EndTime    time.Time                             `json:"end_time"`
+	Cells      map[DayOfWeek][]*ClassRoutineResponse `json:"cells"`
+}
+
+// CalendarToken lets a user's calendar app read the timetable feeds without a session token, only
+// a hash of the token is kept. Each user has at most one, issuing a new one revokes the old one.
+type CalendarToken struct {
+	BaseModel
+	UserID    string `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	TokenHash string `gorm:"size:64;not null;uniqueIndex" json:"-"`
+}
+
+// CalendarTokenResponse carries the token only when it is issued, it cannot be read back later
+type CalendarTokenResponse struct {
+	CreatedAt time.Time `json:"created_at"`
+	Token     string    `json:"token,omitempty"`
+}
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Timetable draft statuses
+const (
+	DraftPending   = "draft"
+	DraftCommitted = "committed"
+)
+
+// TimetableDraft is a solver-proposed week of routines for one semester section, reviewed before it is committed
+type TimetableDraft struct {
+	BaseModel
+	ProgramID    string                `gorm:"type:uuid;not null" json:"program_id"`
+	SemesterID   string                `gorm:"type:uuid;not null;index" json:"semester_id"`
+	Section      string                `gorm:"size:20" json:"section"`
+	StudentCount int                   `json:"student_count"`
+	AcademicYear string                `gorm:"size:20" json:"academic_year"`
+	Status       string                `gorm:"size:20;default:'draft'" json:"status"`
+	Score        int                   `json:"score"`                     // soft constraint penalty, lower is better
+	Unplaced     string                `gorm:"type:text" json:"unplaced"` // JSON encoded []UnplacedLesson
+	Entries      []TimetableDraftEntry `gorm:"foreignKey:DraftID" json:"entries,omitempty"`
+}
+
+// TimetableDraftEntry is one proposed class, shaped like a ClassRoutine so it can be reviewed the same way
+type TimetableDraftEntry struct {
+	BaseModel
+	DraftID    string    `gorm:"type:uuid;not null;index" json:"draft_id"`
+	SubjectID  string    `gorm:"type:uuid" json:"subject_id"`
+	Subject    *Subject  `gorm:"foreignKey:SubjectID" json:"subject,omitempty"`
+	TeacherID  string    `gorm:"type:uuid" json:"teacher_id"`
+	Teacher    *User     `gorm:"foreignKey:TeacherID" json:"teacher,omitempty"`
+	RoomID     string    `gorm:"type:uuid" json:"room_id"`
+	Room       *Room     `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	TimeSlotID string    `gorm:"type:uuid" json:"time_slot_id"`
+	TimeSlot   *TimeSlot `gorm:"foreignKey:TimeSlotID" json:"time_slot,omitempty"`
+	DayOfWeek  DayOfWeek `gorm:"type:varchar(20);not null" json:"day_of_week"`
+}
+
+// TeacherAssignment says who teaches a subject and how many sessions it needs per week
+type TeacherAssignment struct {
+	SubjectID       string `json:"subject_id"`
+	TeacherID       string `json:"teacher_id"`
+	SessionsPerWeek int    `json:"sessions_per_week"` // defaults to the subject's credit hours
+}
+
+type GenerateTimetableRequest struct {
+	SemesterID   string              `json:"semester_id"`
+	Section      string              `json:"section"`
+	StudentCount int                 `json:"student_count"`
+	AcademicYear string              `json:"academic_year"`
+	Assignments  []TeacherAssignment `json:"assignments"`
+	RoomIDs      []string            `json:"room_ids"`       // candidate rooms, every active room when empty
+	MaxDailyLoad int                 `json:"max_daily_load"` // classes per teacher per day before the solver penalises, defaults to 4
+}
+
+type ListTimetableDraftRequest struct {
+	ListRequest
+	SemesterID string `form:"semester_id"`
+	Status     string `form:"status"`
+}
+
+// UnplacedLesson explains why the solver could not schedule a session
+type UnplacedLesson struct {
+	SubjectID   string `json:"subject_id"`
+	SubjectCode string `json:"subject_code"`
+	TeacherID   string `json:"teacher_id,omitempty"`
+	Session     int    `json:"session,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+type TimetableDraftResponse struct {
+	ID           string                  `json:"id"`
+	CreatedAt    time.Time               `json:"created_at"`
+	ProgramID    string                  `json:"program_id"`
+	SemesterID   string                  `json:"semester_id"`
+	Section      string                  `json:"section"`
+	StudentCount int                     `json:"student_count"`
+	AcademicYear string                  `json:"academic_year"`
+	Status       string                  `json:"status"`
+	Score        int                     `json:"score"`
+	Entries      []*ClassRoutineResponse `json:"entries"`
+	Unplaced     []UnplacedLesson        `json:"unplaced"`
+}
+
+func (r *GenerateTimetableRequest) Validate() error {
+	if r.SemesterID == "" {
+		return errors.New("semester id is required")
+	}
+	if r.StudentCount < 0 {
+		return errors.New("student count cannot be negative")
+	}
+	if r.MaxDailyLoad < 0 {
+		return errors.New("max daily load cannot be negative")
+	}
+	for _, a := range r.Assignments {
+		if a.SubjectID == "" || a.TeacherID == "" {
+			return errors.New("every assignment needs a subject id and a teacher id")
+		}
+		if a.SessionsPerWeek < 0 {
+			return errors.New("sessions per week cannot be negative")
+		}
+	}
+	return nil
+}
+
+// Routine converts a draft entry into the ClassRoutine it will become on commit
+func (e *TimetableDraftEntry) Routine(d *TimetableDraft) *ClassRoutine {
+	routine := &ClassRoutine{
+		ProgramID:    d.ProgramID,
+		SemesterID:   d.SemesterID,
+		SubjectID:    e.SubjectID,
+		Subject:      e.Subject,
+		TeacherID:    e.TeacherID,
+		Teacher:      e.Teacher,
+		RoomID:       e.RoomID,
+		Room:         e.Room,
+		TimeSlotID:   e.TimeSlotID,
+		TimeSlot:     e.TimeSlot,
+		Section:      d.Section,
+		StudentCount: d.StudentCount,
+		DayOfWeek:    e.DayOfWeek,
+		AcademicYear: d.AcademicYear,
+	}
+	return routine
+}
+
+func (d *TimetableDraft) DraftResponse() *TimetableDraftResponse {
+	data := &TimetableDraftResponse{
+		ID:           d.ID,
+		CreatedAt:    d.CreatedAt,
+		ProgramID:    d.ProgramID,
+		SemesterID:   d.SemesterID,
+		Section:      d.Section,
+		StudentCount: d.StudentCount,
+		AcademicYear: d.AcademicYear,
+		Status:       d.Status,
+		Score:        d.Score,
+		Entries:      []*ClassRoutineResponse{},
+		Unplaced:     []UnplacedLesson{},
+	}
+	for i := range d.Entries {
+		entry := d.Entries[i].Routine(d).RoutineResponse()
+		entry.ID = d.Entries[i].ID
+		entry.CreatedAt = d.Entries[i].CreatedAt
+		data.Entries = append(data.Entries, entry)
+	}
+	sort.SliceStable(data.Entries, func(i, j int) bool {
+		a, b := data.Entries[i], data.Entries[j]
+		if a.DayOfWeek != b.DayOfWeek {
+			return a.DayOfWeek.Index() < b.DayOfWeek.Index()
+		}
+		return a.StartTime != nil && b.StartTime != nil && a.StartTime.Before(*b.StartTime)
+	})
+	if d.Unplaced != "" {
+		_ = json.Unmarshal([]byte(d.Unplaced), &data.Unplaced)
+	}
+	return data
+}
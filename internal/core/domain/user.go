@@ -18,6 +18,10 @@ type User struct {
 	Image        string
 	Roles        []Role `gorm:"many2many:user_roles;"`
 	IsActive     bool   `gorm:"default:true"`
+	// ProgramID, Batch and Section place a student for program and batch announcements
+	ProgramID string `gorm:"index" json:"program_id"`
+	Batch     string `gorm:"size:20;index" json:"batch"`
+	Section   string `gorm:"size:20" json:"section"`
 }
 
 type UserRequest struct {
@@ -90,6 +94,7 @@ type LoginUserResponse struct {
 
 type UserUpdateRequest struct {
 	Username       string `json:"username"`
+	ProgramID      string `json:"program_id"`
 	Password       string `json:"password"`
 	Dob            string `json:"dob"`
 	Gender         string `json:"gender"`
@@ -121,6 +126,7 @@ type UserResponse struct {
 	Email          string    `json:"email"`
 	Image          string    `json:"image"`
 	FullName       string    `json:"full_name"`
+	ProgramID      string    `json:"program_id"`
 	Program        string    `json:"program"`
 	Semester       string    `json:"semester"`
 	StudentID      string    `json:"student_id"`
@@ -221,5 +227,8 @@ func (r *UserUpdateRequest) NewUpdate() Map {
 	if r.Program != "" {
 		mp["program"] = r.Program
 	}
+	if r.ProgramID != "" {
+		mp["program_id"] = r.ProgramID
+	}
 	return mp
 }
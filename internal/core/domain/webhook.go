@@ -0,0 +1,314 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Domain event types published on the event bus and delivered to webhook subscriptions
+const (
+	EventTypeBookCreated     = "book.created"
+	EventTypeBookUpdated     = "book.updated"
+	EventTypeBookDeleted     = "book.deleted"
+	EventTypeBookCopyCreated = "book_copy.created"
+	EventTypeBookCopyUpdated = "book_copy.updated"
+	EventTypeBookCopyDeleted = "book_copy.deleted"
+	EventTypeBookIssued      = "book.issued"
+	EventTypeBookReturned    = "book.returned"
+	EventTypeBookOverdue     = "book.overdue"
+	EventTypeBorrowRequested = "borrow.requested"
+	EventTypeBorrowDeleted   = "borrow.deleted"
+	EventTypeFineIssued      = "fine.issued"
+	EventTypeFineUpdated     = "fine.updated"
+	EventTypeFineDeleted     = "fine.deleted"
+	EventTypeFinePaid        = "fine.paid"
+	EventTypeFineWaived      = "fine.waived"
+	EventTypeUserCreated     = "user.created"
+	EventTypeUserUpdated     = "user.updated"
+	EventTypeUserDeleted     = "user.deleted"
+	// EventTypeWebhookPing is only sent by the ping endpoint
+	EventTypeWebhookPing = "webhook.ping"
+)
+
+// EventTypes is the catalogue a subscription can filter on
+var EventTypes = []string{
+	EventTypeBookCreated,
+	EventTypeBookUpdated,
+	EventTypeBookDeleted,
+	EventTypeBookCopyCreated,
+	EventTypeBookCopyUpdated,
+	EventTypeBookCopyDeleted,
+	EventTypeBookIssued,
+	EventTypeBookReturned,
+	EventTypeBookOverdue,
+	EventTypeBorrowRequested,
+	EventTypeBorrowDeleted,
+	EventTypeFineIssued,
+	EventTypeFineUpdated,
+	EventTypeFineDeleted,
+	EventTypeFinePaid,
+	EventTypeFineWaived,
+	EventTypeUserCreated,
+	EventTypeUserUpdated,
+	EventTypeUserDeleted,
+}
+
+// AllEventTypes subscribes to every event type
+const AllEventTypes = "*"
+
+// DomainEvent is something that happened in the library, ActorID is the user who caused it when
+// known and Data is the affected record
+type DomainEvent struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	ActorID    string      `json:"actor_id,omitempty"`
+	Data       interface{} `json:"data"`
+}
+
+// Webhook delivery statuses
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed" // gave up after MaxWebhookAttempts
+)
+
+// MaxWebhookAttempts is how many times a delivery is tried before it is marked failed, the
+// backoff doubles from WebhookRetryBase up to WebhookRetryMax
+const (
+	MaxWebhookAttempts = 8
+	WebhookRetryBase   = 30 * time.Second
+	WebhookRetryMax    = 6 * time.Hour
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookSignatureHeader = "X-LMS-Signature"
+	WebhookEventHeader     = "X-LMS-Event"
+	WebhookDeliveryHeader  = "X-LMS-Delivery"
+)
+
+// WebhookSubscription posts the events it is subscribed to, an empty Events list or "*" takes
+// every event
+type WebhookSubscription struct {
+	BaseModel
+	Name      string   `gorm:"not null" json:"name"`
+	URL       string   `gorm:"not null" json:"url"`
+	Secret    string   `gorm:"not null" json:"-"`
+	Events    []string `gorm:"serializer:json;type:jsonb" json:"events"`
+	IsActive  bool     `gorm:"default:true;index" json:"is_active"`
+	CreatedBy string   `json:"created_by"`
+}
+
+// Subscribes tells whether the subscription takes the event type
+func (w *WebhookSubscription) Subscribes(eventType string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, AllEventTypes) || slices.Contains(w.Events, eventType)
+}
+
+// WebhookDelivery is one event posted to one subscription, Payload is kept so the delivery can
+// be retried and replayed exactly
+type WebhookDelivery struct {
+	BaseModel
+	SubscriptionID *string    `gorm:"type:uuid;index" json:"subscription_id"`
+	EventID        string     `gorm:"not null;index" json:"event_id"`
+	EventType      string     `gorm:"size:50;not null;index" json:"event_type"`
+	Payload        string     `gorm:"type:text;not null" json:"payload"`
+	Status         string     `gorm:"size:20;not null;index" json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	DurationMs     int64      `json:"duration_ms"`
+	NextAttemptAt  time.Time  `gorm:"index" json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	// ReplayOf is the delivery this one replays
+	ReplayOf string `json:"replay_of"`
+	// UserID and URL are set instead of a subscription for a user's notification webhook
+	UserID string `gorm:"index" json:"user_id,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// WebhookBackoff is the wait before the next attempt after the given number of failed ones
+func WebhookBackoff(attempts int) time.Duration {
+	wait := WebhookRetryBase
+	for i := 1; i < attempts && wait < WebhookRetryMax; i++ {
+		wait *= 2
+	}
+	return min(wait, WebhookRetryMax)
+}
+
+// SignWebhook signs a payload as "t=<unix>,v1=<hex hmac-sha256 of '<unix>.<payload>'>", receivers
+// recompute it with the shared secret and reject old timestamps to stop replays
+func SignWebhook(secret string, timestamp time.Time, payload []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", unix, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// WebhookStatusError is returned by a WebhookPoster when the target answers with a non 2xx status
+type WebhookStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *WebhookStatusError) Error() string {
+	return fmt.Sprintf("webhook returned %d: %s", e.StatusCode, e.Body)
+}
+
+type WebhookSubscriptionRequest struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Secret   string   `json:"secret"` // generated when empty
+	Events   []string `json:"events"` // every event when empty
+	IsActive *bool    `json:"is_active"`
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Secret   string   `json:"secret"`
+	Events   []string `json:"events"`
+	IsActive *bool    `json:"is_active"`
+}
+
+type ListWebhookSubscriptionRequest struct {
+	ListRequest
+	IsActive *bool  `form:"is_active"`
+	Event    string `form:"event"`
+}
+
+type ListWebhookDeliveryRequest struct {
+	ListRequest
+	SubscriptionID string `form:"subscription_id"`
+	EventType      string `form:"event_type"`
+	Status         string `form:"status"`
+}
+
+type WebhookSubscriptionResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"is_active"`
+	CreatedBy string    `json:"created_by"`
+	// Secret is only shown when it is set, keep it to verify signatures
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string     `json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	SubscriptionID string     `json:"subscription_id"`
+	UserID         string     `json:"user_id,omitempty"`
+	URL            string     `json:"url,omitempty"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	DurationMs     int64      `json:"duration_ms"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	ReplayOf       string     `json:"replay_of"`
+}
+
+// internalPrefixes are not covered by the netip checks: "this network", which reaches the host
+// itself, and RFC 6598 carrier-grade NAT that some clouds serve metadata from
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// IsPublicAddress tells whether a webhook may be posted to addr, loopback, private and link-local
+// addresses, where cloud metadata lives at 169.254.169.254, are internal
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateWebhookURL takes absolute http and https urls that do not name an internal host. A name
+// can still resolve to an internal address, the poster checks every address it connects to.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url %q must be an absolute http or https url", raw)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("url %q must not point at an internal address", raw)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddress(addr) {
+		return fmt.Errorf("url %q must not point at an internal address", raw)
+	}
+	return nil
+}
+
+func validateEventTypes(events []string) error {
+	for _, event := range events {
+		if event != AllEventTypes && !slices.Contains(EventTypes, event) {
+			return fmt.Errorf("unknown event type %s", event)
+		}
+	}
+	return nil
+}
+
+func (r *WebhookSubscriptionRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if err := validateWebhookURL(r.URL); err != nil {
+		return err
+	}
+	return validateEventTypes(r.Events)
+}
+
+func (r *UpdateWebhookSubscriptionRequest) Validate() error {
+	if r.URL != "" {
+		if err := validateWebhookURL(r.URL); err != nil {
+			return err
+		}
+	}
+	return validateEventTypes(r.Events)
+}
+
+func (r *UpdateWebhookSubscriptionRequest) NewUpdate() Map {
+	mp := Map{}
+	if r.Name != "" {
+		mp["name"] = r.Name
+	}
+	if r.URL != "" {
+		mp["url"] = r.URL
+	}
+	if r.Secret != "" {
+		mp["secret"] = r.Secret
+	}
+	if r.Events != nil {
+		mp["events"] = string(ConvertToJson(r.Events))
+	}
+	if r.IsActive != nil {
+		mp["is_active"] = *r.IsActive
+	}
+	return mp
+}
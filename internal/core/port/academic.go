@@ -0,0 +1,71 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AcademicRepository is an interface for interacting with faculty, semester, subject and time slot data
+type AcademicRepository interface {
+	CreateFaculty(ctx context.Context, data *domain.Faculty) (*domain.Faculty, error)
+	ListFaculty(ctx context.Context, req *domain.ListFacultyRequest) ([]*domain.Faculty, int64, error)
+	GetFaculty(ctx context.Context, id string) (*domain.Faculty, error)
+	UpdateFaculty(ctx context.Context, id string, req domain.Map) (*domain.Faculty, error)
+	DeleteFaculty(ctx context.Context, id string) error
+	CountFacultyPrograms(ctx context.Context, facultyID string) (int64, error)
+
+	CountProgramSemesters(ctx context.Context, programID string) (int64, error)
+	CountProgramSubjects(ctx context.Context, programID string) (int64, error)
+
+	CreateSemester(ctx context.Context, data *domain.Semester) (*domain.Semester, error)
+	ListSemester(ctx context.Context, req *domain.ListSemesterRequest) ([]*domain.Semester, int64, error)
+	GetSemester(ctx context.Context, id string) (*domain.Semester, error)
+	UpdateSemester(ctx context.Context, id string, req domain.Map) (*domain.Semester, error)
+	DeleteSemester(ctx context.Context, id string) error
+	CountSemesterSubjects(ctx context.Context, semesterID string) (int64, error)
+	CountSemesterRoutines(ctx context.Context, semesterID string) (int64, error)
+
+	CreateSubject(ctx context.Context, data *domain.Subject) (*domain.Subject, error)
+	ListSubject(ctx context.Context, req *domain.ListSubjectRequest) ([]*domain.Subject, int64, error)
+	GetSubject(ctx context.Context, id string) (*domain.Subject, error)
+	UpdateSubject(ctx context.Context, id string, req domain.Map) (*domain.Subject, error)
+	DeleteSubject(ctx context.Context, id string) error
+	CountSubjectRoutines(ctx context.Context, subjectID string) (int64, error)
+
+	CreateTimeSlot(ctx context.Context, data *domain.TimeSlot) (*domain.TimeSlot, error)
+	ListTimeSlot(ctx context.Context, req *domain.ListTimeSlotRequest) ([]*domain.TimeSlot, int64, error)
+	GetTimeSlot(ctx context.Context, id string) (*domain.TimeSlot, error)
+	UpdateTimeSlot(ctx context.Context, id string, req domain.Map) (*domain.TimeSlot, error)
+	DeleteTimeSlot(ctx context.Context, id string) error
+	ListOverlappingTimeSlots(ctx context.Context, start, end time.Time, excludeID string) ([]*domain.TimeSlot, error)
+	CountTimeSlotRoutines(ctx context.Context, timeSlotID string) (int64, error)
+}
+
+// AcademicService is an interface for interacting with faculty, semester, subject and time slot business logic
+type AcademicService interface {
+	CreateFaculty(ctx context.Context, req *domain.FacultyRequest) (*domain.FacultyResponse, error)
+	ListFaculty(ctx context.Context, req *domain.ListFacultyRequest) ([]*domain.FacultyResponse, int64, error)
+	GetFaculty(ctx context.Context, id string) (*domain.FacultyResponse, error)
+	UpdateFaculty(ctx context.Context, id string, req *domain.UpdateFacultyRequest) (*domain.FacultyResponse, error)
+	DeleteFaculty(ctx context.Context, id string) (*domain.FacultyResponse, error)
+
+	CreateSemester(ctx context.Context, req *domain.SemesterRequest) (*domain.SemesterResponse, error)
+	ListSemester(ctx context.Context, req *domain.ListSemesterRequest) ([]*domain.SemesterResponse, int64, error)
+	GetSemester(ctx context.Context, id string) (*domain.SemesterResponse, error)
+	UpdateSemester(ctx context.Context, id string, req *domain.UpdateSemesterRequest) (*domain.SemesterResponse, error)
+	DeleteSemester(ctx context.Context, id string) (*domain.SemesterResponse, error)
+
+	CreateSubject(ctx context.Context, req *domain.SubjectRequest) (*domain.SubjectResponse, error)
+	ListSubject(ctx context.Context, req *domain.ListSubjectRequest) ([]*domain.SubjectResponse, int64, error)
+	GetSubject(ctx context.Context, id string) (*domain.SubjectResponse, error)
+	UpdateSubject(ctx context.Context, id string, req *domain.UpdateSubjectRequest) (*domain.SubjectResponse, error)
+	DeleteSubject(ctx context.Context, id string) (*domain.SubjectResponse, error)
+
+	CreateTimeSlot(ctx context.Context, req *domain.TimeSlotRequest) (*domain.TimeSlotResponse, error)
+	ListTimeSlot(ctx context.Context, req *domain.ListTimeSlotRequest) ([]*domain.TimeSlotResponse, int64, error)
+	GetTimeSlot(ctx context.Context, id string) (*domain.TimeSlotResponse, error)
+	UpdateTimeSlot(ctx context.Context, id string, req *domain.UpdateTimeSlotRequest) (*domain.TimeSlotResponse, error)
+	DeleteTimeSlot(ctx context.Context, id string) (*domain.TimeSlotResponse, error)
+}
@@ -0,0 +1,46 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// AnnouncementRepository is an interface for interacting with announcements and their receipts
+type AnnouncementRepository interface {
+	CreateAnnouncement(ctx context.Context, data *domain.Announcement) (*domain.Announcement, error)
+	ListAnnouncement(ctx context.Context, req *domain.ListAnnouncementRequest) ([]*domain.Announcement, int64, error)
+	// ListAnnouncementFeed lists the published, unexpired announcements the user has a receipt
+	// for, pinned ones first
+	ListAnnouncementFeed(ctx context.Context, userID string, req *domain.ListAnnouncementRequest) ([]*domain.Announcement, int64, error)
+	GetAnnouncement(ctx context.Context, id string) (*domain.Announcement, error)
+	UpdateAnnouncement(ctx context.Context, id string, req domain.Map) (*domain.Announcement, error)
+	DeleteAnnouncement(ctx context.Context, id string) error
+	ListDueAnnouncementIDs(ctx context.Context, now time.Time, limit int) ([]string, error)
+	// PublishAnnouncement fans a scheduled announcement out into notifications and receipts,
+	// it returns nil when the announcement was already published
+	PublishAnnouncement(ctx context.Context, id string, now time.Time) (*domain.Announcement, int64, error)
+	GetAnnouncementReceipt(ctx context.Context, announcementID, userID string) (*domain.AnnouncementReceipt, error)
+	ListAnnouncementReceipt(ctx context.Context, announcementID string, req *domain.ListAnnouncementReceiptRequest) ([]*domain.AnnouncementReceiptResponse, int64, error)
+	SummarizeAnnouncementReceipt(ctx context.Context, announcementID string) (*domain.AnnouncementReceiptSummary, error)
+	CreateAnnouncementAttachment(ctx context.Context, data *domain.AnnouncementAttachment) (*domain.AnnouncementAttachment, error)
+	GetAnnouncementAttachment(ctx context.Context, id string) (*domain.AnnouncementAttachment, error)
+	DeleteAnnouncementAttachment(ctx context.Context, id string) error
+}
+
+// AnnouncementService is an interface for posting announcements and reading them
+type AnnouncementService interface {
+	CreateAnnouncement(ctx context.Context, req *domain.AnnouncementRequest) (*domain.AnnouncementResponse, error)
+	ListAnnouncement(ctx context.Context, req *domain.ListAnnouncementRequest) ([]*domain.AnnouncementResponse, int64, error)
+	ListAnnouncementFeed(ctx context.Context, req *domain.ListAnnouncementRequest) ([]*domain.AnnouncementResponse, int64, error)
+	GetAnnouncement(ctx context.Context, id string) (*domain.AnnouncementResponse, error)
+	UpdateAnnouncement(ctx context.Context, id string, req *domain.UpdateAnnouncementRequest) (*domain.AnnouncementResponse, error)
+	DeleteAnnouncement(ctx context.Context, id string) (*domain.AnnouncementResponse, error)
+	ReadAnnouncement(ctx context.Context, id string) (*domain.AnnouncementResponse, error)
+	ListAnnouncementReceipt(ctx context.Context, id string, req *domain.ListAnnouncementReceiptRequest) ([]*domain.AnnouncementReceiptResponse, int64, error)
+	AddAnnouncementAttachment(ctx context.Context, id string, req *domain.AnnouncementAttachmentRequest) (*domain.AnnouncementResponse, error)
+	DeleteAnnouncementAttachment(ctx context.Context, id, attachmentID string) (*domain.AnnouncementResponse, error)
+	// RunAnnouncementPublisher publishes scheduled announcements as they come due until ctx is done
+	RunAnnouncementPublisher(ctx context.Context)
+}
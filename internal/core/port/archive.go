@@ -0,0 +1,46 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// ArchiveStore keeps archive files out of the database
+type ArchiveStore interface {
+	// Put stores the file and returns where it can be found
+	Put(kind, name string, data []byte) (string, error)
+	Get(kind, name string) ([]byte, error)
+}
+
+// ArchiveRepository moves expired rows out of the live tables and keeps track of the archives
+type ArchiveRepository interface {
+	// WithArchivalLock runs fn while holding the archival lock and reports false without running
+	// it when another replica holds the lock
+	WithArchivalLock(ctx context.Context, fn func()) (bool, error)
+	// ListAuditLogForArchive lists the oldest entries in the scope, restored entries still on
+	// hold and the head of the chain are left out
+	ListAuditLogForArchive(ctx context.Context, scope *domain.AuditArchiveScope, now time.Time, limit int) ([]*domain.AuditLog, error)
+	// ArchiveAuditLogs records the archive and deletes its entries, nothing is deleted when
+	// some of them are already gone
+	ArchiveAuditLogs(ctx context.Context, data *domain.Archive, ids []string) error
+	ListNotificationForArchive(ctx context.Context, before time.Time, limit int) ([]*domain.ArchivedNotification, error)
+	ArchiveNotifications(ctx context.Context, data *domain.Archive, ids []string) error
+	// PurgeReadNotifications deletes the user notifications read before the time
+	PurgeReadNotifications(ctx context.Context, before time.Time) (int64, error)
+	ListArchive(ctx context.Context, req *domain.ListArchiveRequest) ([]*domain.Archive, int64, error)
+	GetArchive(ctx context.Context, id string) (*domain.Archive, error)
+	ListArchivedAuditSequences(ctx context.Context) (domain.ArchivedSequences, error)
+	// RestoreAuditLogs puts archived entries back as they were, entries still present are skipped
+	RestoreAuditLogs(ctx context.Context, archive *domain.Archive, datas []*domain.AuditLog, restoredBy string) (int64, error)
+}
+
+type ArchiveService interface {
+	ListArchive(ctx context.Context, req *domain.ListArchiveRequest) ([]*domain.ArchiveResponse, int64, error)
+	GetArchive(ctx context.Context, id string) (*domain.ArchiveResponse, error)
+	// RestoreArchive puts an audit log archive back in the database for an investigation
+	RestoreArchive(ctx context.Context, id string) (*domain.RestoreArchiveResponse, error)
+	// RunArchival applies the retention policy every interval until ctx is done
+	RunArchival(ctx context.Context, every time.Duration)
+}
@@ -1,21 +1,48 @@
 package port
 
-import "github.com/sugaml/lms-api/internal/core/domain"
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
 
 // type AuditLogRepository interface is an interface for interacting with type AuditLog-related data
 type AuditLogRepository interface {
-	CreateAuditLog(data *domain.AuditLog) (*domain.AuditLog, error)
-	ListAuditLog(req *domain.ListAuditLogRequest) ([]*domain.AuditLog, int64, error)
-	GetAuditLog(id string) (*domain.AuditLog, error)
-	UpdateAuditLog(id string, req domain.Map) (*domain.AuditLog, error)
-	DeleteAuditLog(id string) error
+	// CreateAuditLog appends the entry to the chain, its sequence and hashes are set here
+	CreateAuditLog(ctx context.Context, data *domain.AuditLog) (*domain.AuditLog, error)
+	ListAuditLog(ctx context.Context, req *domain.ListAuditLogRequest) ([]*domain.AuditLog, int64, error)
+	GetAuditLog(ctx context.Context, id string) (*domain.AuditLog, error)
+	GetAuditLogBySequence(ctx context.Context, sequence int64) (*domain.AuditLog, error)
+	GetLastAuditLog(ctx context.Context) (*domain.AuditLog, error)
+	// ListAuditLogChain returns up to limit entries after the sequence, in order
+	ListAuditLogChain(ctx context.Context, after, to int64, limit int) ([]*domain.AuditLog, error)
+	CreateAuditCheckpoint(ctx context.Context, data *domain.AuditCheckpoint) (*domain.AuditCheckpoint, error)
+	ListAuditCheckpoint(ctx context.Context, req *domain.ListAuditCheckpointRequest) ([]*domain.AuditCheckpoint, int64, error)
+	GetAuditCheckpoint(ctx context.Context, id string) (*domain.AuditCheckpoint, error)
+	GetLastAuditCheckpoint(ctx context.Context) (*domain.AuditCheckpoint, error)
+	ListAuditCheckpointBetween(ctx context.Context, from, to int64) ([]*domain.AuditCheckpoint, error)
 }
 
 // type AuditLogService interface is an interface for interacting with type AuditLog-related data
 type AuditLogService interface {
-	CreateAuditLog(data *domain.AuditLogRequest) (*domain.AuditLogResponse, error)
-	ListAuditLog(req *domain.ListAuditLogRequest) ([]*domain.AuditLogResponse, int64, error)
-	GetAuditLog(id string) (*domain.AuditLogResponse, error)
-	UpdateAuditLog(id string, req *domain.AuditLogUpdateRequest) (*domain.AuditLogResponse, error)
-	DeleteAuditLog(id string) error
+	CreateAuditLog(ctx context.Context, data *domain.AuditLogRequest) (*domain.AuditLogResponse, error)
+	ListAuditLog(ctx context.Context, req *domain.ListAuditLogRequest) ([]*domain.AuditLogResponse, int64, error)
+	GetAuditLog(ctx context.Context, id string) (*domain.AuditLogResponse, error)
+	// VerifyAuditLog walks the chain and reports gaps, edited entries and checkpoint mismatches
+	VerifyAuditLog(ctx context.Context, req *domain.VerifyAuditLogRequest) (*domain.VerifyAuditLogResponse, error)
+	CreateAuditCheckpoint(ctx context.Context) (*domain.AuditCheckpointResponse, error)
+	ListAuditCheckpoint(ctx context.Context, req *domain.ListAuditCheckpointRequest) ([]*domain.AuditCheckpointResponse, int64, error)
+	ExportAuditCheckpoint(ctx context.Context, id string) (*domain.AuditCheckpointExport, error)
+	// RunAuditCheckpoint signs a checkpoint of the chain head every interval until ctx is done, it
+	// returns straight away without a signing key
+	RunAuditCheckpoint(ctx context.Context, every time.Duration)
+}
+
+// AuditSigner signs audit checkpoints
+type AuditSigner interface {
+	Algorithm() string
+	// PublicKey is the base64 key a signature is checked with
+	PublicKey() string
+	Sign(payload []byte) (string, error)
 }
@@ -8,22 +8,22 @@ import (
 
 // type BookRepository interface is an interface for interacting with type Announcement-related data
 type BookRepository interface {
-	CreateBook(data *domain.Book) (*domain.Book, error)
-	ListBook(req *domain.BookListRequest) ([]*domain.Book, int64, error)
-	GetBook(id string) (*domain.Book, error)
-	UpdateBook(id string, req domain.Map) (*domain.Book, error)
-	DeleteBook(id string) error
+	CreateBook(ctx context.Context, data *domain.Book) (*domain.Book, error)
+	ListBook(ctx context.Context, req *domain.BookListRequest) ([]*domain.Book, int64, error)
+	GetBook(ctx context.Context, id string) (*domain.Book, error)
+	UpdateBook(ctx context.Context, id string, req domain.Map) (*domain.Book, error)
+	DeleteBook(ctx context.Context, id string) error
 }
 
 type BookCopyRepository interface {
-	CreateBookCopy(copy *domain.BookCopy) (*domain.BookCopy, error)
-	ListBookCopies(req *domain.BookCopyListRequest) ([]*domain.BookCopy, int64, error)
-	IsBookCopiesByBookId(bookId string) (bool, error)
-	ListBookCopiesByBookId(bookId string, req *domain.BookCopyListRequest) ([]*domain.BookCopy, int64, error)
-	GetBookCopy(id string) (*domain.BookCopy, error)
-	CountBorrowedCopyID(bookCopyID string) (int64, error)
-	UpdateBookCopy(id string, req domain.Map) (*domain.BookCopy, error)
-	DeleteBookCopy(id string) error
+	CreateBookCopy(ctx context.Context, copy *domain.BookCopy) (*domain.BookCopy, error)
+	ListBookCopies(ctx context.Context, req *domain.BookCopyListRequest) ([]*domain.BookCopy, int64, error)
+	IsBookCopiesByBookId(ctx context.Context, bookId string) (bool, error)
+	ListBookCopiesByBookId(ctx context.Context, bookId string, req *domain.BookCopyListRequest) ([]*domain.BookCopy, int64, error)
+	GetBookCopy(ctx context.Context, id string) (*domain.BookCopy, error)
+	CountBorrowedCopyID(ctx context.Context, bookCopyID string) (int64, error)
+	UpdateBookCopy(ctx context.Context, id string, req domain.Map) (*domain.BookCopy, error)
+	DeleteBookCopy(ctx context.Context, id string) error
 }
 
 // type BookService interface is an interface for interacting with type Announcement-related data
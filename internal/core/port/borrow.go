@@ -8,17 +8,18 @@ import (
 
 // type BorrowRepository interface is an interface for interacting with type Announcement-related data
 type BorrowRepository interface {
-	CreateBorrow(data *domain.BorrowedBook) (*domain.BorrowedBook, error)
-	ListBorrow(req *domain.ListBorrowedBookRequest) ([]*domain.BorrowedBook, int64, error)
-	GetBorrow(id string) (*domain.BorrowedBook, error)
-	GetAvailableCopies(bookID string) (uint, error)
-	GetBookBorrowByUserID(user_id string) ([]*domain.BorrowedBook, error)
-	IsBookBorrowByUserID(user_id string, book_id string) bool
-	CountAllBookBorrwedCopies() (int64, error)
-	CountBorrwedCopiesBookID(bookID string) (int64, error)
-	CountBorrwedCopiesUserID(userID string) (int64, error)
-	UpdateBorrow(id string, req domain.Map) (*domain.BorrowedBook, error)
-	DeleteBorrow(id string) error
+	CreateBorrow(ctx context.Context, data *domain.BorrowedBook) (*domain.BorrowedBook, error)
+	ListBorrow(ctx context.Context, req *domain.ListBorrowedBookRequest) ([]*domain.BorrowedBook, int64, error)
+	EachBorrow(ctx context.Context, req *domain.ListBorrowedBookRequest, fn func([]*domain.BorrowedBook) error) error
+	GetBorrow(ctx context.Context, id string) (*domain.BorrowedBook, error)
+	GetAvailableCopies(ctx context.Context, bookID string) (uint, error)
+	GetBookBorrowByUserID(ctx context.Context, user_id string) ([]*domain.BorrowedBook, error)
+	IsBookBorrowByUserID(ctx context.Context, user_id string, book_id string) bool
+	CountAllBookBorrwedCopies(ctx context.Context) (int64, error)
+	CountBorrwedCopiesBookID(ctx context.Context, bookID string) (int64, error)
+	CountBorrwedCopiesUserID(ctx context.Context, userID string) (int64, error)
+	UpdateBorrow(ctx context.Context, id string, req domain.Map) (*domain.BorrowedBook, error)
+	DeleteBorrow(ctx context.Context, id string) error
 }
 
 // type BorrowService interface is an interface for interacting with type Announcement-related data
@@ -0,0 +1,65 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CampusRepository is an interface for interacting with campus, building, floor and room data
+type CampusRepository interface {
+	CreateCampus(ctx context.Context, data *domain.Campus) (*domain.Campus, error)
+	ListCampus(ctx context.Context, req *domain.ListCampusRequest) ([]*domain.Campus, int64, error)
+	GetCampus(ctx context.Context, id string) (*domain.Campus, error)
+	UpdateCampus(ctx context.Context, id string, req domain.Map) (*domain.Campus, error)
+	DeleteCampus(ctx context.Context, id string) error
+	CountCampusBuildings(ctx context.Context, campusID string) (int64, error)
+
+	CreateBuilding(ctx context.Context, data *domain.Building) (*domain.Building, error)
+	ListBuilding(ctx context.Context, req *domain.ListBuildingRequest) ([]*domain.Building, int64, error)
+	GetBuilding(ctx context.Context, id string) (*domain.Building, error)
+	UpdateBuilding(ctx context.Context, id string, req domain.Map) (*domain.Building, error)
+	DeleteBuilding(ctx context.Context, id string) error
+	CountBuildingFloors(ctx context.Context, buildingID string) (int64, error)
+
+	CreateFloor(ctx context.Context, data *domain.Floor) (*domain.Floor, error)
+	ListFloor(ctx context.Context, req *domain.ListFloorRequest) ([]*domain.Floor, int64, error)
+	GetFloor(ctx context.Context, id string) (*domain.Floor, error)
+	UpdateFloor(ctx context.Context, id string, req domain.Map) (*domain.Floor, error)
+	DeleteFloor(ctx context.Context, id string) error
+	CountFloorRooms(ctx context.Context, floorID string) (int64, error)
+
+	CreateRoom(ctx context.Context, data *domain.Room) (*domain.Room, error)
+	ListRoom(ctx context.Context, req *domain.ListRoomRequest) ([]*domain.Room, int64, error)
+	GetRoom(ctx context.Context, id string) (*domain.Room, error)
+	UpdateRoom(ctx context.Context, id string, req domain.Map) (*domain.Room, error)
+	DeleteRoom(ctx context.Context, id string) error
+	CountRoomRoutines(ctx context.Context, roomID string) (int64, error)
+}
+
+// CampusService is an interface for interacting with campus, building, floor and room business logic
+type CampusService interface {
+	CreateCampus(ctx context.Context, req *domain.CampusRequest) (*domain.CampusResponse, error)
+	ListCampus(ctx context.Context, req *domain.ListCampusRequest) ([]*domain.CampusResponse, int64, error)
+	GetCampus(ctx context.Context, id string) (*domain.CampusResponse, error)
+	UpdateCampus(ctx context.Context, id string, req *domain.UpdateCampusRequest) (*domain.CampusResponse, error)
+	DeleteCampus(ctx context.Context, id string) (*domain.CampusResponse, error)
+
+	CreateBuilding(ctx context.Context, req *domain.BuildingRequest) (*domain.BuildingResponse, error)
+	ListBuilding(ctx context.Context, req *domain.ListBuildingRequest) ([]*domain.BuildingResponse, int64, error)
+	GetBuilding(ctx context.Context, id string) (*domain.BuildingResponse, error)
+	UpdateBuilding(ctx context.Context, id string, req *domain.UpdateBuildingRequest) (*domain.BuildingResponse, error)
+	DeleteBuilding(ctx context.Context, id string) (*domain.BuildingResponse, error)
+
+	CreateFloor(ctx context.Context, req *domain.FloorRequest) (*domain.FloorResponse, error)
+	ListFloor(ctx context.Context, req *domain.ListFloorRequest) ([]*domain.FloorResponse, int64, error)
+	GetFloor(ctx context.Context, id string) (*domain.FloorResponse, error)
+	UpdateFloor(ctx context.Context, id string, req *domain.UpdateFloorRequest) (*domain.FloorResponse, error)
+	DeleteFloor(ctx context.Context, id string) (*domain.FloorResponse, error)
+
+	CreateRoom(ctx context.Context, req *domain.RoomRequest) (*domain.RoomResponse, error)
+	ListRoom(ctx context.Context, req *domain.ListRoomRequest) ([]*domain.RoomResponse, int64, error)
+	GetRoom(ctx context.Context, id string) (*domain.RoomResponse, error)
+	UpdateRoom(ctx context.Context, id string, req *domain.UpdateRoomRequest) (*domain.RoomResponse, error)
+	DeleteRoom(ctx context.Context, id string) (*domain.RoomResponse, error)
+}
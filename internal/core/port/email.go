@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// Mailer sends a rendered email, a permanent rejection is reported as *domain.EmailBounceError
+type Mailer interface {
+	Send(msg *domain.EmailMessage) error
+}
+
+// EmailRepository is an interface for interacting with the email delivery queue
+type EmailRepository interface {
+	CreateEmailDelivery(ctx context.Context, data *domain.EmailDelivery) (*domain.EmailDelivery, error)
+	ListEmailDelivery(ctx context.Context, req *domain.ListEmailDeliveryRequest) ([]*domain.EmailDelivery, int64, error)
+	GetEmailDelivery(ctx context.Context, id string) (*domain.EmailDelivery, error)
+	UpdateEmailDelivery(ctx context.Context, id string, req domain.Map) (*domain.EmailDelivery, error)
+	// ClaimDueEmailDeliveries pushes the next attempt of due emails out by lease so that only one
+	// replica sends them
+	ClaimDueEmailDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.EmailDelivery, error)
+	ListBorrowsDueBefore(ctx context.Context, before time.Time) ([]*domain.BorrowedBook, error)
+}
+
+// EmailService is an interface for email delivery and its background worker
+type EmailService interface {
+	ListEmailDelivery(ctx context.Context, req *domain.ListEmailDeliveryRequest) ([]*domain.EmailDeliveryResponse, int64, error)
+	GetEmailDelivery(ctx context.Context, id string) (*domain.EmailDeliveryResponse, error)
+	RetryEmailDelivery(ctx context.Context, id string) (*domain.EmailDeliveryResponse, error)
+	// RunEmailDelivery sends queued emails and schedules borrow reminders until ctx is done
+	RunEmailDelivery(ctx context.Context)
+}
@@ -0,0 +1,41 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// ExportWriter writes a report file a row at a time so that exports never hold the whole result
+type ExportWriter interface {
+	// WriteHeader writes the report header and the column headers, it comes before any row
+	WriteHeader(meta *domain.ExportMeta, columns []string) error
+	WriteRow(values []any) error
+	// Close finishes the file, formats that cannot be written as they go are written out here
+	Close() error
+}
+
+// ExportService writes reports and lists as files, columns and header language come from the
+// export request
+type ExportService interface {
+	ExportBorrow(ctx context.Context, req *domain.ListBorrowedBookRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportFine(ctx context.Context, req *domain.ListFineRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportStudent(ctx context.Context, req *domain.UserListRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportLibraryDashboardStats(ctx context.Context, exp *domain.ExportRequest, w ExportWriter) error
+	ExportChartData(ctx context.Context, req *domain.ChartRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportBorrowedBookStats(ctx context.Context, exp *domain.ExportRequest, w ExportWriter) error
+	ExportBookProgramstats(ctx context.Context, exp *domain.ExportRequest, w ExportWriter) error
+	ExportInventorystats(ctx context.Context, exp *domain.ExportRequest, w ExportWriter) error
+	ExportTeacherWorkload(ctx context.Context, req *domain.AcademicReportRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportRoomUtilization(ctx context.Context, req *domain.AcademicReportRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportTopBooks(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportTopCategories(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportInactiveStock(ctx context.Context, req *domain.InactiveStockRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportLoanDuration(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportBorrowingRates(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportTopReaders(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportFineCollection(ctx context.Context, req *domain.FineCollectionRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportFineAging(ctx context.Context, exp *domain.ExportRequest, w ExportWriter) error
+	ExportStudentFineAging(ctx context.Context, req *domain.ListRequest, exp *domain.ExportRequest, w ExportWriter) error
+	ExportFineStatement(ctx context.Context, req *domain.FineStatementRequest, exp *domain.ExportRequest, w ExportWriter) error
+}
@@ -1,23 +1,32 @@
 package port
 
 import (
+	"context"
+	"time"
+
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
 // type FineRepository interface is an interface for interacting with type Announcement-related data
 type FineRepository interface {
-	CreateFine(data *domain.Fine) (*domain.Fine, error)
-	ListFine(req *domain.ListFineRequest) ([]*domain.Fine, int64, error)
-	GetFine(id string) (*domain.Fine, error)
-	UpdateFine(id string, req domain.Map) (*domain.Fine, error)
-	DeleteFine(id string) error
+	CreateFine(ctx context.Context, data *domain.Fine) (*domain.Fine, error)
+	ListFine(ctx context.Context, req *domain.ListFineRequest) ([]*domain.Fine, int64, error)
+	EachFine(ctx context.Context, req *domain.ListFineRequest, fn func([]*domain.Fine) error) error
+	GetFine(ctx context.Context, id string) (*domain.Fine, error)
+	UpdateFine(ctx context.Context, id string, req domain.Map) (*domain.Fine, error)
+	DeleteFine(ctx context.Context, id string) error
+	PayFine(ctx context.Context, id, collectedBy string, paidAt time.Time) (*domain.Fine, error)
+	WaiveFine(ctx context.Context, id, waivedBy, reason string, waivedAt time.Time) (*domain.Fine, error)
+	GetOutstandingFines(ctx context.Context) (int64, error)
 }
 
 // type FineService interface is an interface for interacting with type Announcement-related data
 type FineService interface {
-	CreateFine(data *domain.FineRequest) (*domain.FineResponse, error)
-	ListFine(req *domain.ListFineRequest) ([]*domain.FineResponse, int64, error)
-	GetFine(id string) (*domain.FineResponse, error)
-	UpdateFine(id string, req *domain.UpdateFineRequest) (*domain.FineResponse, error)
-	DeleteFine(id string) (*domain.FineResponse, error)
+	CreateFine(ctx context.Context, data *domain.FineRequest) (*domain.FineResponse, error)
+	ListFine(ctx context.Context, req *domain.ListFineRequest) ([]*domain.FineResponse, int64, error)
+	GetFine(ctx context.Context, id string) (*domain.FineResponse, error)
+	UpdateFine(ctx context.Context, id string, req *domain.UpdateFineRequest) (*domain.FineResponse, error)
+	DeleteFine(ctx context.Context, id string) (*domain.FineResponse, error)
+	PayFine(ctx context.Context, id string) (*domain.FineResponse, error)
+	WaiveFine(ctx context.Context, id string, req *domain.WaiveFineRequest) (*domain.FineResponse, error)
 }
@@ -0,0 +1,25 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// JobObserver records how long each pass of a background job took
+type JobObserver interface {
+	ObserveJob(job string, took time.Duration)
+}
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	GetLoanGauges(ctx context.Context) (*domain.LoanGauges, error)
+}
+
+// HealthService backs the readiness probe and the business metrics
+type HealthService interface {
+	// Ping checks the database answers
+	Ping(ctx context.Context) error
+	GetLoanGauges(ctx context.Context) (*domain.LoanGauges, error)
+}
@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// HistoryRepository reads entity timelines out of the audit log
+type HistoryRepository interface {
+	ListEntityHistory(ctx context.Context, entityType, entityID string, req *domain.ListHistoryRequest) ([]*domain.AuditLog, int64, error)
+	// ListPatronHistory lists the loan and fine entries of the patron, those of deleted loans
+	// and fines included
+	ListPatronHistory(ctx context.Context, userID string, req *domain.ListHistoryRequest) ([]*domain.AuditLog, int64, error)
+}
+
+// HistoryService builds chronological timelines with the actors resolved
+type HistoryService interface {
+	ListEntityHistory(ctx context.Context, entityType, entityID string, req *domain.ListHistoryRequest) ([]*domain.HistoryEntry, int64, error)
+	ListPatronHistory(ctx context.Context, userID string, req *domain.ListHistoryRequest) ([]*domain.HistoryEntry, int64, error)
+}
@@ -1,25 +1,46 @@
 package port
 
 import (
+	"context"
+	"time"
+
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
+// NotificationHub fans notification signals out to the streams open on this replica
+type NotificationHub interface {
+	Subscribe(userID string) (<-chan *domain.NotificationSignal, func())
+}
+
 // type NotificationRepository interface is an interface for interacting with type Announcement-related data
 type NotificationRepository interface {
-	CreateNotification(data *domain.Notification) (*domain.Notification, error)
-	ListNotification(req *domain.ListNotificationRequest) ([]*domain.Notification, int64, error)
-	GetNotification(id string) (*domain.Notification, error)
-	UpdateNotification(id string, req domain.Map) (*domain.Notification, error)
-	ReadAllNotification(req domain.Map) (*domain.Notification, error)
-	DeleteNotification(id string) error
+	CreateNotification(ctx context.Context, data *domain.Notification) (*domain.Notification, error)
+	ListNotification(ctx context.Context, recipient *domain.NotificationRecipient, req *domain.ListNotificationRequest) ([]*domain.Notification, int64, error)
+	GetNotification(ctx context.Context, id string) (*domain.Notification, error)
+	// GetRecipientNotification returns the notification only when it is addressed to the recipient
+	GetRecipientNotification(ctx context.Context, id string, recipient *domain.NotificationRecipient) (*domain.Notification, error)
+	UpdateNotification(ctx context.Context, id string, req domain.Map) (*domain.Notification, error)
+	MarkNotificationRead(ctx context.Context, id, userID string) error
+	MarkNotificationUnread(ctx context.Context, id, userID string) error
+	ReadAllNotification(ctx context.Context, recipient *domain.NotificationRecipient) (int64, error)
+	DeleteNotification(ctx context.Context, id string) error
+	ListNotificationSince(ctx context.Context, recipient *domain.NotificationRecipient, since time.Time, limit int) ([]*domain.Notification, error)
+	CountUnreadNotification(ctx context.Context, recipient *domain.NotificationRecipient) (int64, error)
 }
 
 // type NotificationService interface is an interface for interacting with type Announcement-related data
 type NotificationService interface {
-	CreateNotification(data *domain.NotificationRequest) (*domain.NotificationResponse, error)
-	ListNotification(req *domain.ListNotificationRequest) ([]*domain.NotificationResponse, int64, error)
-	GetNotification(id string) (*domain.NotificationResponse, error)
-	UpdateNotification(id string, req *domain.UpdateNotificationRequest) (*domain.NotificationResponse, error)
-	ReadAllNotification() (*domain.NotificationResponse, error)
-	DeleteNotification(id string) (*domain.NotificationResponse, error)
+	CreateNotification(ctx context.Context, req *domain.NotificationRequest) (*domain.NotificationResponse, error)
+	// ListNotification lists the caller's notifications along with how many are unread
+	ListNotification(ctx context.Context, req *domain.ListNotificationRequest) ([]*domain.NotificationResponse, int64, int64, error)
+	CountUnreadNotification(ctx context.Context) (*domain.UnreadNotificationCount, error)
+	GetNotification(ctx context.Context, id string) (*domain.NotificationResponse, error)
+	UpdateNotification(ctx context.Context, id string, req *domain.UpdateNotificationRequest) (*domain.NotificationResponse, error)
+	ReadNotification(ctx context.Context, id string) (*domain.ReadNotificationResponse, error)
+	UnreadNotification(ctx context.Context, id string) (*domain.ReadNotificationResponse, error)
+	ReadAllNotification(ctx context.Context) (*domain.ReadNotificationResponse, error)
+	DeleteNotification(ctx context.Context, id string) (*domain.NotificationResponse, error)
+	// StreamNotification pushes the caller's notifications created after lastEventID and their
+	// unread count until ctx is done
+	StreamNotification(ctx context.Context, lastEventID string) (<-chan *domain.NotificationStreamEvent, error)
 }
@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// WebhookPoster posts a JSON payload to a URL, a non 2xx reply is a *domain.WebhookStatusError
+type WebhookPoster interface {
+	Post(url string, payload []byte, headers map[string]string) error
+}
+
+// NotificationPreferenceRepository is an interface for interacting with notification preferences
+type NotificationPreferenceRepository interface {
+	ListUserNotificationPreference(ctx context.Context, userID string) ([]*domain.NotificationPreference, error)
+	SaveNotificationPreferences(ctx context.Context, datas []*domain.NotificationPreference) error
+	// ClaimNotificationDispatch records a dispatch and reports false when it already existed
+	ClaimNotificationDispatch(ctx context.Context, data *domain.NotificationDispatch) (bool, error)
+}
+
+// NotificationPreferenceService is an interface for the caller's notification preferences and
+// the dispatcher that applies them
+type NotificationPreferenceService interface {
+	ListNotificationPreference(ctx context.Context) ([]*domain.NotificationPreferenceResponse, error)
+	UpdateNotificationPreference(ctx context.Context, req *domain.UpdateNotificationPreferenceRequest) ([]*domain.NotificationPreferenceResponse, error)
+	// RunNotifier dispatches notices until ctx is done, then the ones still queued
+	RunNotifier(ctx context.Context)
+}
@@ -10,7 +10,20 @@ type Repository interface {
 	FineRepository
 	BorrowRepository
 	ReportRepository
+	HealthRepository
 	NotificationRepository
+	CampusRepository
+	AcademicRepository
+	RoutineRepository
+	TimetableRepository
+	TimetableDraftRepository
+	EmailRepository
+	SMSRepository
+	NotificationPreferenceRepository
+	AnnouncementRepository
+	WebhookRepository
+	HistoryRepository
+	ArchiveRepository
 }
 type Service interface {
 	AuditLogService
@@ -20,7 +33,21 @@ type Service interface {
 	BookService
 	BookCopyService
 	FineService
+	HealthService
 	BorrowService
 	ReportService
 	NotificationService
+	CampusService
+	AcademicService
+	RoutineService
+	TimetableService
+	TimetableDraftService
+	EmailService
+	SMSService
+	NotificationPreferenceService
+	AnnouncementService
+	WebhookService
+	HistoryService
+	ArchiveService
+	ExportService
 }
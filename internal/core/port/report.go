@@ -1,21 +1,54 @@
 package port
 
-import "github.com/sugaml/lms-api/internal/core/domain"
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
 
 type ReportRepository interface {
-	GetLibraryDashboardStats() (*domain.LibraryDashboardStats, error)
-	GetMonthlyChartData() ([]domain.ChartData, error)
-	GetDailyChartData(req *domain.ChartRequest) ([]domain.ChartData, error)
-	GetBorrowedBookStats() (*domain.BorrowedBookStats, error)
-	GetBookProgramstats() (*[]domain.BookProgramstats, error)
-	GetInventorystats() (*domain.InventoryStats, error)
+	GetLibraryDashboardStats(ctx context.Context) (*domain.LibraryDashboardStats, error)
+	GetMonthlyChartData(ctx context.Context) ([]domain.ChartData, error)
+	GetDailyChartData(ctx context.Context, req *domain.ChartRequest) ([]domain.ChartData, error)
+	GetBorrowedBookStats(ctx context.Context) (*domain.BorrowedBookStats, error)
+	GetBookProgramstats(ctx context.Context) (*[]domain.BookProgramstats, error)
+	GetInventorystats(ctx context.Context) (*domain.InventoryStats, error)
+	GetTeacherWorkload(ctx context.Context, req *domain.AcademicReportRequest) ([]domain.TeacherWorkload, error)
+	GetRoomUtilization(ctx context.Context, req *domain.AcademicReportRequest) ([]domain.RoomUtilization, error)
+	GetTopBooks(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopBook, error)
+	GetTopCategories(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopCategory, error)
+	ListInactiveStock(ctx context.Context, req *domain.InactiveStockRequest) ([]*domain.InactiveBook, int64, error)
+	EachInactiveStock(ctx context.Context, req *domain.InactiveStockRequest, fn func([]*domain.InactiveBook) error) error
+	GetLoanDuration(ctx context.Context, req *domain.CirculationRequest) (*domain.LoanDuration, error)
+	GetBorrowingRates(ctx context.Context, req *domain.CirculationRequest) (*domain.BorrowingRates, error)
+	GetTopReaders(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopReader, error)
+	GetFineCollection(ctx context.Context, req *domain.FineCollectionRequest) ([]domain.FineCollection, error)
+	GetFineAging(ctx context.Context) ([]domain.FineAgingBucket, error)
+	ListStudentFineAging(ctx context.Context, req *domain.ListRequest) ([]*domain.StudentFineAging, int64, error)
+	EachStudentFineAging(ctx context.Context, req *domain.ListRequest, fn func([]*domain.StudentFineAging) error) error
+	ListFineEvents(ctx context.Context, userID string, to time.Time) ([]domain.FineEvent, error)
 }
 
 type ReportService interface {
-	GetLibraryDashboardStats() (*domain.LibraryDashboardStats, error)
-	GetMonthlyChartData() ([]domain.ChartData, error)
-	GetDailyChartData(req *domain.ChartRequest) ([]domain.ChartData, error)
-	GetBorrowedBookStats() (*domain.BorrowedBookStats, error)
-	GetBookProgramstats() (*[]domain.BookProgramstats, error)
-	GetInventorystats() (*domain.InventoryStats, error)
+	GetLibraryDashboardStats(ctx context.Context) (*domain.LibraryDashboardStats, *domain.Freshness, error)
+	GetMonthlyChartData(ctx context.Context) ([]domain.ChartData, error)
+	GetDailyChartData(ctx context.Context, req *domain.ChartRequest) ([]domain.ChartData, *domain.Freshness, error)
+	GetBorrowedBookStats(ctx context.Context) (*domain.BorrowedBookStats, error)
+	GetBookProgramstats(ctx context.Context) (*[]domain.BookProgramstats, error)
+	GetInventorystats(ctx context.Context) (*domain.InventoryStats, *domain.Freshness, error)
+	GetTeacherWorkload(ctx context.Context, req *domain.AcademicReportRequest) ([]domain.TeacherWorkload, error)
+	GetRoomUtilization(ctx context.Context, req *domain.AcademicReportRequest) (*domain.RoomUtilizationReport, error)
+	GetTopBooks(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopBook, error)
+	GetTopCategories(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopCategory, error)
+	ListInactiveStock(ctx context.Context, req *domain.InactiveStockRequest) ([]*domain.InactiveBook, int64, error)
+	GetLoanDuration(ctx context.Context, req *domain.CirculationRequest) (*domain.LoanDuration, error)
+	GetBorrowingRates(ctx context.Context, req *domain.CirculationRequest) (*domain.BorrowingRates, error)
+	GetTopReaders(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopReader, error)
+	GetFineCollection(ctx context.Context, req *domain.FineCollectionRequest) (*domain.FineCollectionReport, error)
+	GetFineAging(ctx context.Context) (*domain.FineAging, error)
+	ListStudentFineAging(ctx context.Context, req *domain.ListRequest) ([]*domain.StudentFineAging, int64, error)
+	GetFineStatement(ctx context.Context, req *domain.FineStatementRequest) (*domain.FineStatement, error)
+	// RunStatsWarmer computes the dashboard statistics again after changes until ctx is done
+	RunStatsWarmer(ctx context.Context)
 }
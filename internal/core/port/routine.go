@@ -0,0 +1,37 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// RoutineRepository is an interface for interacting with class routine data
+type RoutineRepository interface {
+	CreateRoutine(ctx context.Context, data *domain.ClassRoutine) (*domain.ClassRoutine, error)
+	ListRoutine(ctx context.Context, req *domain.ListClassRoutineRequest) ([]*domain.ClassRoutine, int64, error)
+	GetRoutine(ctx context.Context, id string) (*domain.ClassRoutine, error)
+	UpdateRoutine(ctx context.Context, id string, req domain.Map) (*domain.ClassRoutine, error)
+	DeleteRoutine(ctx context.Context, id string) error
+	ListClashingRoutines(ctx context.Context, data *domain.ClassRoutine) ([]*domain.ClassRoutine, error)
+
+	CreateTeacherUnavailability(ctx context.Context, data *domain.TeacherUnavailability) (*domain.TeacherUnavailability, error)
+	ListTeacherUnavailability(ctx context.Context, req *domain.ListTeacherUnavailabilityRequest) ([]*domain.TeacherUnavailability, int64, error)
+	GetTeacherUnavailability(ctx context.Context, id string) (*domain.TeacherUnavailability, error)
+	DeleteTeacherUnavailability(ctx context.Context, id string) error
+	IsTeacherUnavailable(ctx context.Context, teacherID string, day domain.DayOfWeek, timeSlotID string) (bool, error)
+}
+
+// RoutineService is an interface for interacting with class routine business logic
+type RoutineService interface {
+	CreateRoutine(ctx context.Context, req *domain.ClassRoutineRequest) (*domain.ClassRoutineResponse, error)
+	CheckRoutine(ctx context.Context, req *domain.ClassRoutineRequest) ([]domain.RoutineConflict, error)
+	ListRoutine(ctx context.Context, req *domain.ListClassRoutineRequest) ([]*domain.ClassRoutineResponse, int64, error)
+	GetRoutine(ctx context.Context, id string) (*domain.ClassRoutineResponse, error)
+	UpdateRoutine(ctx context.Context, id string, req *domain.UpdateClassRoutineRequest) (*domain.ClassRoutineResponse, error)
+	DeleteRoutine(ctx context.Context, id string) (*domain.ClassRoutineResponse, error)
+
+	CreateTeacherUnavailability(ctx context.Context, req *domain.TeacherUnavailabilityRequest) (*domain.TeacherUnavailabilityResponse, error)
+	ListTeacherUnavailability(ctx context.Context, req *domain.ListTeacherUnavailabilityRequest) ([]*domain.TeacherUnavailabilityResponse, int64, error)
+	DeleteTeacherUnavailability(ctx context.Context, id string) (*domain.TeacherUnavailabilityResponse, error)
+}
@@ -0,0 +1,38 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// SMSSender sends a text message, a permanent refusal is reported as *domain.SMSRejectedError
+type SMSSender interface {
+	Send(msg *domain.SMSMessage) (*domain.SMSReceipt, error)
+}
+
+// SMSRepository is an interface for interacting with the SMS delivery queue
+type SMSRepository interface {
+	CreateSMSDelivery(ctx context.Context, data *domain.SMSDelivery) (*domain.SMSDelivery, error)
+	ListSMSDelivery(ctx context.Context, req *domain.ListSMSDeliveryRequest) ([]*domain.SMSDelivery, int64, error)
+	GetSMSDelivery(ctx context.Context, id string) (*domain.SMSDelivery, error)
+	UpdateSMSDelivery(ctx context.Context, id string, req domain.Map) (*domain.SMSDelivery, error)
+	// ClaimDueSMSDeliveries pushes the next attempt of due messages out by lease so that only one
+	// replica sends them
+	ClaimDueSMSDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.SMSDelivery, error)
+	// CreateSMSDeliveryWithinLimit counts and queues under a lock on the user, so concurrent
+	// sends cannot both pass the daily limit
+	CreateSMSDeliveryWithinLimit(ctx context.Context, data *domain.SMSDelivery, since time.Time, limit int) (*domain.SMSDelivery, error)
+	GetSMSUsage(ctx context.Context, req *domain.SMSUsageRequest) ([]*domain.SMSUsage, error)
+}
+
+// SMSService is an interface for SMS delivery and its background worker
+type SMSService interface {
+	ListSMSDelivery(ctx context.Context, req *domain.ListSMSDeliveryRequest) ([]*domain.SMSDeliveryResponse, int64, error)
+	GetSMSDelivery(ctx context.Context, id string) (*domain.SMSDeliveryResponse, error)
+	RetrySMSDelivery(ctx context.Context, id string) (*domain.SMSDeliveryResponse, error)
+	GetSMSUsage(ctx context.Context, req *domain.SMSUsageRequest) ([]*domain.SMSUsage, error)
+	// RunSMSDelivery sends queued text messages outside quiet hours until ctx is done
+	RunSMSDelivery(ctx context.Context)
+}
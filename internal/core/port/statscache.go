@@ -0,0 +1,17 @@
+package port
+
+import "context"
+
+// StatsCache keeps computed statistics between requests as JSON, values expire on their own
+type StatsCache interface {
+	// Get reports a miss as false, not as an error
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Generation counts the invalidations so far, across every instance of the API that shares the
+	// cache. It is read before statistics are computed and handed to Set.
+	Generation(ctx context.Context) (int64, error)
+	// Set keeps a value computed in the generation, it is never served once the generation has
+	// been invalidated, even when that happened while the value was being computed
+	Set(ctx context.Context, generation int64, key string, value []byte) error
+	// Invalidate drops every value, it is called when the data behind the statistics changes
+	Invalidate(ctx context.Context) error
+}
@@ -0,0 +1,46 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// TimetableRepository is an interface for reading weekly schedules and academic years
+type TimetableRepository interface {
+	ListTimetableRoutines(ctx context.Context, req *domain.TimetableRequest) ([]*domain.ClassRoutine, error)
+	ListAllTimeSlot(ctx context.Context) ([]*domain.TimeSlot, error)
+
+	CreateAcademicYear(ctx context.Context, data *domain.AcademicYear) (*domain.AcademicYear, error)
+	ListAcademicYear(ctx context.Context, req *domain.ListAcademicYearRequest) ([]*domain.AcademicYear, int64, error)
+	GetAcademicYear(ctx context.Context, id string) (*domain.AcademicYear, error)
+	GetAcademicYearByName(ctx context.Context, name string) (*domain.AcademicYear, error)
+	GetCurrentAcademicYear(ctx context.Context) (*domain.AcademicYear, error)
+	UpdateAcademicYear(ctx context.Context, id string, req domain.Map) (*domain.AcademicYear, error)
+	DeleteAcademicYear(ctx context.Context, id string) error
+	CountAcademicYearRoutines(ctx context.Context, name string) (int64, error)
+
+	// SaveCalendarToken replaces the user's calendar token
+	SaveCalendarToken(ctx context.Context, data *domain.CalendarToken) (*domain.CalendarToken, error)
+	GetCalendarToken(ctx context.Context, userID string) (*domain.CalendarToken, error)
+	GetCalendarTokenByHash(ctx context.Context, hash string) (*domain.CalendarToken, error)
+	DeleteCalendarToken(ctx context.Context, userID string) error
+}
+
+// TimetableService is an interface for weekly timetable views, calendar export and academic years
+type TimetableService interface {
+	GetTimetable(ctx context.Context, req *domain.TimetableRequest) (*domain.TimetableResponse, error)
+	GetTimetableCalendar(ctx context.Context, req *domain.TimetableRequest) ([]byte, error)
+
+	CreateAcademicYear(ctx context.Context, req *domain.AcademicYearRequest) (*domain.AcademicYearResponse, error)
+	ListAcademicYear(ctx context.Context, req *domain.ListAcademicYearRequest) ([]*domain.AcademicYearResponse, int64, error)
+	GetAcademicYear(ctx context.Context, id string) (*domain.AcademicYearResponse, error)
+	UpdateAcademicYear(ctx context.Context, id string, req *domain.UpdateAcademicYearRequest) (*domain.AcademicYearResponse, error)
+	DeleteAcademicYear(ctx context.Context, id string) (*domain.AcademicYearResponse, error)
+
+	IssueCalendarToken(ctx context.Context) (*domain.CalendarTokenResponse, error)
+	GetCalendarToken(ctx context.Context) (*domain.CalendarTokenResponse, error)
+	RevokeCalendarToken(ctx context.Context) (*domain.CalendarTokenResponse, error)
+	// VerifyCalendarToken returns the id of the user the calendar token was issued to
+	VerifyCalendarToken(ctx context.Context, token string) (string, error)
+}
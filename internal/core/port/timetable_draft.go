@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// TimetableDraftRepository is an interface for interacting with solver drafts and the schedule they are built against
+type TimetableDraftRepository interface {
+	CreateTimetableDraft(ctx context.Context, data *domain.TimetableDraft) (*domain.TimetableDraft, error)
+	ListTimetableDraft(ctx context.Context, req *domain.ListTimetableDraftRequest) ([]*domain.TimetableDraft, int64, error)
+	GetTimetableDraft(ctx context.Context, id string) (*domain.TimetableDraft, error)
+	DeleteTimetableDraft(ctx context.Context, id string) error
+	CommitTimetableDraft(ctx context.Context, draft *domain.TimetableDraft, routines []*domain.ClassRoutine) error
+
+	ListScheduledRoutines(ctx context.Context) ([]*domain.ClassRoutine, error)
+	ListSchedulableRooms(ctx context.Context, ids []string) ([]*domain.Room, error)
+	ListTeachersUnavailability(ctx context.Context, teacherIDs []string) ([]*domain.TeacherUnavailability, error)
+}
+
+// TimetableDraftService is an interface for generating, reviewing and committing solver drafts
+type TimetableDraftService interface {
+	GenerateTimetableDraft(ctx context.Context, req *domain.GenerateTimetableRequest) (*domain.TimetableDraftResponse, error)
+	ListTimetableDraft(ctx context.Context, req *domain.ListTimetableDraftRequest) ([]*domain.TimetableDraftResponse, int64, error)
+	GetTimetableDraft(ctx context.Context, id string) (*domain.TimetableDraftResponse, error)
+	DeleteTimetableDraft(ctx context.Context, id string) (*domain.TimetableDraftResponse, error)
+	CommitTimetableDraft(ctx context.Context, id string) ([]*domain.ClassRoutineResponse, error)
+}
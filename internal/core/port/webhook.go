@@ -0,0 +1,51 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// EventBus carries domain events from the services to their subscribers, Publish never blocks
+// on a subscriber
+type EventBus interface {
+	Publish(event *domain.DomainEvent)
+	Subscribe(handler func(event *domain.DomainEvent))
+}
+
+// WebhookRepository is an interface for interacting with webhook subscriptions and deliveries
+type WebhookRepository interface {
+	CreateWebhookSubscription(ctx context.Context, data *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	ListWebhookSubscription(ctx context.Context, req *domain.ListWebhookSubscriptionRequest) ([]*domain.WebhookSubscription, int64, error)
+	// ListEventWebhookSubscription lists the active subscriptions that take the event type
+	ListEventWebhookSubscription(ctx context.Context, eventType string) ([]*domain.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, id string, req domain.Map) (*domain.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	CreateWebhookDelivery(ctx context.Context, data *domain.WebhookDelivery) (*domain.WebhookDelivery, error)
+	ListWebhookDelivery(ctx context.Context, req *domain.ListWebhookDeliveryRequest) ([]*domain.WebhookDelivery, int64, error)
+	GetWebhookDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, id string, req domain.Map) (*domain.WebhookDelivery, error)
+	// ClaimDueWebhookDeliveries pushes the next attempt of due deliveries out by lease so that
+	// only one replica sends them
+	ClaimDueWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+}
+
+// WebhookService is an interface for managing webhook subscriptions and their delivery log
+type WebhookService interface {
+	CreateWebhookSubscription(ctx context.Context, req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscriptionResponse, error)
+	ListWebhookSubscription(ctx context.Context, req *domain.ListWebhookSubscriptionRequest) ([]*domain.WebhookSubscriptionResponse, int64, error)
+	GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscriptionResponse, error)
+	UpdateWebhookSubscription(ctx context.Context, id string, req *domain.UpdateWebhookSubscriptionRequest) (*domain.WebhookSubscriptionResponse, error)
+	DeleteWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscriptionResponse, error)
+	// PingWebhookSubscription sends a webhook.ping right away and returns its delivery
+	PingWebhookSubscription(ctx context.Context, id string) (*domain.WebhookDeliveryResponse, error)
+	ListWebhookEventType(ctx context.Context) []string
+	ListWebhookDelivery(ctx context.Context, req *domain.ListWebhookDeliveryRequest) ([]*domain.WebhookDeliveryResponse, int64, error)
+	GetWebhookDelivery(ctx context.Context, id string) (*domain.WebhookDeliveryResponse, error)
+	// ReplayWebhookDelivery queues a new delivery of the same payload
+	ReplayWebhookDelivery(ctx context.Context, id string) (*domain.WebhookDeliveryResponse, error)
+	// RunWebhookDelivery sends queued webhook deliveries until ctx is done
+	RunWebhookDelivery(ctx context.Context)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// defaultLabThreshold is the utilization percent below which a lab counts as under-used
+const defaultLabThreshold = 30
+
+func (s *Service) GetTeacherWorkload(ctx context.Context, req *domain.AcademicReportRequest) ([]domain.TeacherWorkload, error) {
+	ctx, span := startSpan(ctx, "GetTeacherWorkload")
+	defer span.End()
+	result, err := s.repo.GetTeacherWorkload(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []domain.TeacherWorkload{}
+	}
+	return result, nil
+}
+
+// GetRoomUtilization reports utilization per room, rolled up per building, and the labs below the threshold
+func (s *Service) GetRoomUtilization(ctx context.Context, req *domain.AcademicReportRequest) (*domain.RoomUtilizationReport, error) {
+	ctx, span := startSpan(ctx, "GetRoomUtilization")
+	defer span.End()
+	rooms, err := s.repo.GetRoomUtilization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	threshold := req.LabThreshold
+	if threshold <= 0 {
+		threshold = defaultLabThreshold
+	}
+	report := &domain.RoomUtilizationReport{
+		AcademicYear:  req.AcademicYear,
+		Rooms:         []domain.RoomUtilization{},
+		Buildings:     []domain.BuildingUtilization{},
+		UnderusedLabs: []domain.RoomUtilization{},
+	}
+	index := map[string]int{}
+	for _, room := range rooms {
+		report.Rooms = append(report.Rooms, room)
+		i, ok := index[room.BuildingID]
+		if !ok {
+			i = len(report.Buildings)
+			index[room.BuildingID] = i
+			report.Buildings = append(report.Buildings, domain.BuildingUtilization{
+				BuildingID:   room.BuildingID,
+				BuildingName: room.BuildingName,
+			})
+		}
+		report.Buildings[i].Rooms++
+		report.Buildings[i].OccupiedSlots += room.OccupiedSlots
+		report.Buildings[i].AvailableSlots += room.AvailableSlots
+		if room.RoomType == domain.LabRoom && room.Utilization < threshold {
+			report.UnderusedLabs = append(report.UnderusedLabs, room)
+		}
+	}
+	for i := range report.Buildings {
+		report.Buildings[i].Utilization = domain.Percent(report.Buildings[i].OccupiedSlots, report.Buildings[i].AvailableSlots)
+	}
+	return report, nil
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const (
+	announcementBatchSize    = 20
+	announcementPollInterval = time.Minute
+)
+
+// announcementPublishers are the roles that may post and manage announcements
+var announcementPublishers = []string{domain.RoleAdmin, domain.RoleDirector, domain.RoleLibrarian, domain.RoleStaff}
+
+func canPublishAnnouncement(recipient *domain.NotificationRecipient) bool {
+	for _, role := range recipient.Roles {
+		if slices.Contains(announcementPublishers, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// announcementPublisher loads the caller and makes sure they may manage announcements
+func (s *Service) announcementPublisher(ctx context.Context) (*domain.NotificationRecipient, error) {
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !canPublishAnnouncement(recipient) {
+		return nil, domain.NewForbiddenError("not_allowed", "only the library or academic office can manage announcements")
+	}
+	return recipient, nil
+}
+
+func announcementResponse(data *domain.Announcement) *domain.AnnouncementResponse {
+	result := domain.Convert[domain.Announcement, domain.AnnouncementResponse](data)
+	result.Status = data.State(time.Now())
+	if result.Attachments == nil {
+		result.Attachments = []domain.AnnouncementAttachment{}
+	}
+	return result
+}
+
+// CreateAnnouncement saves an announcement and publishes it straight away unless it is
+// scheduled for later
+func (s *Service) CreateAnnouncement(ctx context.Context, req *domain.AnnouncementRequest) (*domain.AnnouncementResponse, error) {
+	ctx, span := startSpan(ctx, "CreateAnnouncement")
+	defer span.End()
+	publisher, err := s.announcementPublisher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+	data := req.NewAnnouncement()
+	data.CreatedBy = publisher.UserID
+	result, err := s.repo.CreateAnnouncement(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "announcement",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new Announcement %s.", result.Title),
+		After:      result,
+	})
+	if !result.PublishAt.After(time.Now()) {
+		if published := s.publishAnnouncement(ctx, result.ID); published != nil {
+			result = published
+		}
+	}
+	return announcementResponse(result), nil
+}
+
+// ListAnnouncement lists every announcement for those who manage them
+func (s *Service) ListAnnouncement(ctx context.Context, req *domain.ListAnnouncementRequest) ([]*domain.AnnouncementResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListAnnouncement")
+	defer span.End()
+	var datas = []*domain.AnnouncementResponse{}
+	if _, err := s.announcementPublisher(ctx); err != nil {
+		return nil, 0, err
+	}
+	results, count, err := s.repo.ListAnnouncement(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, announcementResponse(result))
+	}
+	return datas, count, nil
+}
+
+// ListAnnouncementFeed lists the published announcements addressed to the caller
+func (s *Service) ListAnnouncementFeed(ctx context.Context, req *domain.ListAnnouncementRequest) ([]*domain.AnnouncementResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListAnnouncementFeed")
+	defer span.End()
+	var datas = []*domain.AnnouncementResponse{}
+	getUserID, err := getUserID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	results, count, err := s.repo.ListAnnouncementFeed(ctx, getUserID, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		data := announcementResponse(result)
+		data.UserIDs = nil
+		datas = append(datas, data)
+	}
+	return datas, count, nil
+}
+
+// GetAnnouncement shows any announcement with its receipt counts to those who manage them and
+// only a received one to everybody else
+func (s *Service) GetAnnouncement(ctx context.Context, id string) (*domain.AnnouncementResponse, error) {
+	ctx, span := startSpan(ctx, "GetAnnouncement")
+	defer span.End()
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if canPublishAnnouncement(recipient) {
+		data := announcementResponse(result)
+		data.Receipts, err = s.repo.SummarizeAnnouncementReceipt(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	return s.receivedAnnouncement(ctx, recipient, result)
+}
+
+// receivedAnnouncement returns the announcement as the recipient sees it, an expired or
+// unreceived announcement is not found
+func (s *Service) receivedAnnouncement(ctx context.Context, recipient *domain.NotificationRecipient, result *domain.Announcement) (*domain.AnnouncementResponse, error) {
+	receipt, err := s.repo.GetAnnouncementReceipt(ctx, result.ID, recipient.UserID)
+	if err != nil || result.State(time.Now()) == domain.AnnouncementExpired {
+		return nil, domain.NewNotFoundError("announcement_not_found", "announcement not found")
+	}
+	notification, err := s.repo.GetRecipientNotification(ctx, receipt.NotificationID, recipient)
+	if err != nil {
+		return nil, err
+	}
+	data := announcementResponse(result)
+	data.UserIDs = nil
+	data.IsRead = notification.IsRead
+	return data, nil
+}
+
+func (s *Service) UpdateAnnouncement(ctx context.Context, id string, req *domain.UpdateAnnouncementRequest) (*domain.AnnouncementResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateAnnouncement")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required Announcement id")
+	}
+	_, err := s.announcementPublisher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.PublishAt != nil && existing.Status != domain.AnnouncementScheduled {
+		return nil, domain.NewPolicyViolationError("announcement_published", "publish_at cannot be changed once the announcement is published")
+	}
+	publishAt := existing.PublishAt
+	if req.PublishAt != nil {
+		publishAt = *req.PublishAt
+	}
+	if req.ExpireAt != nil && !req.ExpireAt.After(publishAt) {
+		return nil, domain.NewValidationError("invalid_range", "expire_at must be after publish_at")
+	}
+
+	// update
+	mp := req.NewUpdate()
+	result, err := s.repo.UpdateAnnouncement(ctx, id, mp)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "announcement",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s Announcement details.", result.Title),
+		Before:     existing,
+		After:      result,
+	})
+	if result.Status == domain.AnnouncementScheduled && !result.PublishAt.After(time.Now()) {
+		if published := s.publishAnnouncement(ctx, result.ID); published != nil {
+			result = published
+		}
+	}
+	return announcementResponse(result), nil
+}
+
+func (s *Service) DeleteAnnouncement(ctx context.Context, id string) (*domain.AnnouncementResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteAnnouncement")
+	defer span.End()
+	_, err := s.announcementPublisher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.DeleteAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "announcement",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s announcement.", result.Title),
+		Before:     result,
+	})
+	return announcementResponse(result), nil
+}
+
+// ReadAnnouncement records the caller's read receipt by marking the notification the
+// announcement reached them as read
+func (s *Service) ReadAnnouncement(ctx context.Context, id string) (*domain.AnnouncementResponse, error) {
+	ctx, span := startSpan(ctx, "ReadAnnouncement")
+	defer span.End()
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.receivedAnnouncement(ctx, recipient, result)
+	if err != nil {
+		return nil, err
+	}
+	if !data.IsRead {
+		receipt, err := s.repo.GetAnnouncementReceipt(ctx, id, recipient.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.MarkNotificationRead(ctx, receipt.NotificationID, recipient.UserID); err != nil {
+			return nil, err
+		}
+		data.IsRead = true
+	}
+	return data, nil
+}
+
+func (s *Service) ListAnnouncementReceipt(ctx context.Context, id string, req *domain.ListAnnouncementReceiptRequest) ([]*domain.AnnouncementReceiptResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListAnnouncementReceipt")
+	defer span.End()
+	if _, err := s.announcementPublisher(ctx); err != nil {
+		return nil, 0, err
+	}
+	results, count, err := s.repo.ListAnnouncementReceipt(ctx, id, req)
+	if err != nil {
+		return nil, count, err
+	}
+	if results == nil {
+		results = []*domain.AnnouncementReceiptResponse{}
+	}
+	return results, count, nil
+}
+
+func (s *Service) AddAnnouncementAttachment(ctx context.Context, id string, req *domain.AnnouncementAttachmentRequest) (*domain.AnnouncementResponse, error) {
+	ctx, span := startSpan(ctx, "AddAnnouncementAttachment")
+	defer span.End()
+	_, err := s.announcementPublisher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetAnnouncement(ctx, id); err != nil {
+		return nil, err
+	}
+	data := domain.Convert[domain.AnnouncementAttachmentRequest, domain.AnnouncementAttachment](req)
+	data.AnnouncementID = id
+	attachment, err := s.repo.CreateAnnouncementAttachment(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "announcement_attachment",
+		EntityID:   attachment.ID,
+		Title:      fmt.Sprintf("Attached %s to announcement.", attachment.FileName),
+		After:      attachment,
+	})
+	result, err := s.repo.GetAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return announcementResponse(result), nil
+}
+
+func (s *Service) DeleteAnnouncementAttachment(ctx context.Context, id, attachmentID string) (*domain.AnnouncementResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteAnnouncementAttachment")
+	defer span.End()
+	_, err := s.announcementPublisher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attachment, err := s.repo.GetAnnouncementAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.AnnouncementID != id {
+		return nil, domain.NewNotFoundError("attachment_not_found", "attachment does not belong to the announcement")
+	}
+	if err := s.repo.DeleteAnnouncementAttachment(ctx, attachmentID); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "announcement_attachment",
+		EntityID:   attachment.ID,
+		Title:      fmt.Sprintf("Removed %s from announcement.", attachment.FileName),
+		Before:     attachment,
+	})
+	result, err := s.repo.GetAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return announcementResponse(result), nil
+}
+
+// RunAnnouncementPublisher publishes scheduled announcements once a minute
+func (s *Service) RunAnnouncementPublisher(ctx context.Context) {
+	poll := time.NewTicker(announcementPollInterval)
+	defer poll.Stop()
+
+	s.runJob(ctx, "announcement_publisher", s.publishDueAnnouncements)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			s.runJob(ctx, "announcement_publisher", s.publishDueAnnouncements)
+		}
+	}
+}
+
+func (s *Service) publishDueAnnouncements(ctx context.Context) {
+	for {
+		ids, err := s.repo.ListDueAnnouncementIDs(ctx, time.Now(), announcementBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("could not load scheduled announcements")
+			return
+		}
+		published := 0
+		for _, id := range ids {
+			if s.publishAnnouncement(ctx, id) != nil {
+				published++
+			}
+		}
+		// stop when the batch was the last or another replica holds the rest
+		if len(ids) < announcementBatchSize || published == 0 {
+			return
+		}
+	}
+}
+
+// publishAnnouncement fans the announcement out to its audience, it returns nil when it could
+// not be published or another replica already did
+func (s *Service) publishAnnouncement(ctx context.Context, id string) *domain.Announcement {
+	result, recipients, err := s.repo.PublishAnnouncement(ctx, id, time.Now())
+	if err != nil {
+		logrus.WithError(err).Errorf("could not publish announcement %s", id)
+		return nil
+	}
+	if result == nil {
+		return nil
+	}
+	s.audit(context.Background(), auditEntry{
+		Action:     "publish",
+		EntityType: "announcement",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Published announcement %s to %d recipients.", result.Title, recipients),
+		Data:       domain.Map{"recipients": recipients},
+		ActorID:    result.CreatedBy,
+	})
+	if refreshed, err := s.repo.GetAnnouncement(ctx, id); err == nil {
+		result = refreshed
+	}
+	return result
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const archiveBatchSize = 5000
+
+func (s *Service) RunArchival(ctx context.Context, every time.Duration) {
+	tick := time.NewTicker(every)
+	defer tick.Stop()
+
+	s.runJob(ctx, "archival", s.applyRetention)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			s.runJob(ctx, "archival", s.applyRetention)
+		}
+	}
+}
+
+// applyRetention purges read notifications and archives what the policy no longer keeps, a pass
+// is skipped while another replica is running one
+func (s *Service) applyRetention(ctx context.Context) {
+	locked, err := s.repo.WithArchivalLock(ctx, func() {
+		s.retain(ctx)
+	})
+	if err != nil {
+		logrus.WithError(err).Error("could not take the archival lock")
+	} else if !locked {
+		logrus.Debug("another replica is archiving, skipping this pass")
+	}
+}
+
+func (s *Service) retain(ctx context.Context) {
+	now := time.Now()
+	if s.retention.ReadNotification > 0 {
+		purged, err := s.repo.PurgeReadNotifications(ctx, now.Add(-s.retention.ReadNotification))
+		if err != nil {
+			logrus.WithError(err).Error("could not purge read notifications")
+		} else if purged > 0 {
+			logrus.Infof("purged %d read notifications", purged)
+		}
+	}
+	if s.archiveStore == nil {
+		logrus.Warn("file storage is not configured, audit logs and notifications are not archived")
+		return
+	}
+	for _, scope := range s.retention.AuditScopes(now) {
+		s.archiveAuditLogs(ctx, &scope, now)
+	}
+	if s.retention.Notification > 0 {
+		s.archiveNotifications(ctx, now.Add(-s.retention.Notification))
+	}
+}
+
+func (s *Service) archiveAuditLogs(ctx context.Context, scope *domain.AuditArchiveScope, now time.Time) {
+	for {
+		datas, err := s.repo.ListAuditLogForArchive(ctx, scope, now, archiveBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("could not load audit logs to archive")
+			return
+		}
+		if len(datas) == 0 {
+			return
+		}
+		ids := make([]string, len(datas))
+		sequences := make([]int64, len(datas))
+		for i, data := range datas {
+			ids[i], sequences[i] = data.ID, data.Sequence
+		}
+		file, err := encodeArchive(datas)
+		if err != nil {
+			logrus.WithError(err).Error("could not encode audit log archive")
+			return
+		}
+		archive, err := s.storeArchive(domain.ArchiveAuditLog, file, len(datas), datas[0].CreatedAt, datas[len(datas)-1].CreatedAt)
+		if err != nil {
+			logrus.WithError(err).Error("could not write audit log archive")
+			return
+		}
+		archive.Sequences = domain.SequenceRanges(sequences)
+		if err := s.repo.ArchiveAuditLogs(ctx, archive, ids); err != nil {
+			logrus.WithError(err).Errorf("could not archive audit logs to %s", archive.FileName)
+			return
+		}
+		s.audit(context.Background(), auditEntry{
+			Action:     "archive",
+			EntityType: "archive",
+			EntityID:   archive.ID,
+			Title:      fmt.Sprintf("Archived %d audit log entries to %s.", archive.Count, archive.FileName),
+		})
+		if len(datas) < archiveBatchSize {
+			return
+		}
+	}
+}
+
+func (s *Service) archiveNotifications(ctx context.Context, before time.Time) {
+	for {
+		datas, err := s.repo.ListNotificationForArchive(ctx, before, archiveBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("could not load notifications to archive")
+			return
+		}
+		if len(datas) == 0 {
+			return
+		}
+		ids := make([]string, len(datas))
+		for i, data := range datas {
+			ids[i] = data.ID
+		}
+		file, err := encodeArchive(datas)
+		if err != nil {
+			logrus.WithError(err).Error("could not encode notification archive")
+			return
+		}
+		archive, err := s.storeArchive(domain.ArchiveNotification, file, len(datas), datas[0].CreatedAt, datas[len(datas)-1].CreatedAt)
+		if err != nil {
+			logrus.WithError(err).Error("could not write notification archive")
+			return
+		}
+		if err := s.repo.ArchiveNotifications(ctx, archive, ids); err != nil {
+			logrus.WithError(err).Errorf("could not archive notifications to %s", archive.FileName)
+			return
+		}
+		s.audit(context.Background(), auditEntry{
+			Action:     "archive",
+			EntityType: "archive",
+			EntityID:   archive.ID,
+			Title:      fmt.Sprintf("Archived %d notifications to %s.", archive.Count, archive.FileName),
+		})
+		if len(datas) < archiveBatchSize {
+			return
+		}
+	}
+}
+
+// encodeArchive writes the rows as gzip compressed JSON lines
+func encodeArchive[T any](rows []T) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(zw)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// storeArchive puts an encoded archive in the archive store, the returned archive is not saved yet
+func (s *Service) storeArchive(kind string, file []byte, count int, oldest, newest time.Time) (*domain.Archive, error) {
+	sum := sha256.Sum256(file)
+	archive := &domain.Archive{
+		BaseModel: domain.BaseModel{ID: uuid.NewString()},
+		Kind:      kind,
+		Count:     count,
+		Size:      int64(len(file)),
+		Checksum:  hex.EncodeToString(sum[:]),
+		OldestAt:  oldest,
+		NewestAt:  newest,
+	}
+	archive.FileName = fmt.Sprintf("%s-%s-%s.jsonl.gz", kind, time.Now().UTC().Format("20060102T150405Z"), archive.ID[:8])
+	url, err := s.archiveStore.Put(kind, archive.FileName, file)
+	if err != nil {
+		return nil, err
+	}
+	archive.URL = url
+	return archive, nil
+}
+
+func (s *Service) ListArchive(ctx context.Context, req *domain.ListArchiveRequest) ([]*domain.ArchiveResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListArchive")
+	defer span.End()
+	if _, err := s.auditor(ctx); err != nil {
+		return nil, 0, err
+	}
+	var datas = []*domain.ArchiveResponse{}
+	results, count, err := s.repo.ListArchive(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.Archive, domain.ArchiveResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetArchive(ctx context.Context, id string) (*domain.ArchiveResponse, error) {
+	ctx, span := startSpan(ctx, "GetArchive")
+	defer span.End()
+	if _, err := s.auditor(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetArchive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.Archive, domain.ArchiveResponse](result), nil
+}
+
+// RestoreArchive checks the archive file against its checksum and every entry against its hash
+// before putting the entries back, they are then left out of archival for the restore period
+func (s *Service) RestoreArchive(ctx context.Context, id string) (*domain.RestoreArchiveResponse, error) {
+	ctx, span := startSpan(ctx, "RestoreArchive")
+	defer span.End()
+	auditor, err := s.auditor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.archiveStore == nil {
+		return nil, errors.New("file storage is not configured")
+	}
+	archive, err := s.repo.GetArchive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if archive.Kind != domain.ArchiveAuditLog {
+		return nil, domain.NewValidationError("archive_not_restorable", "only audit log archives can be restored")
+	}
+	file, err := s.archiveStore.Get(archive.Kind, archive.FileName)
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(file); hex.EncodeToString(sum[:]) != archive.Checksum {
+		return nil, domain.NewConflictError("archive_corrupted", fmt.Sprintf("archive %s does not match its checksum", archive.FileName))
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(file))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	retainUntil := time.Now().Add(s.retention.Restore)
+	var datas []*domain.AuditLog
+	lines := bufio.NewScanner(zr)
+	lines.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lines.Scan() {
+		var data domain.AuditLog
+		if err := json.Unmarshal(lines.Bytes(), &data); err != nil {
+			return nil, err
+		}
+		if data.ComputeHash() != data.Hash {
+			return nil, domain.NewConflictError("archive_corrupted", fmt.Sprintf("entry %d in archive %s does not match its hash", data.Sequence, archive.FileName))
+		}
+		data.RetainUntil = &retainUntil
+		datas = append(datas, &data)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+	restored, err := s.repo.RestoreAuditLogs(ctx, archive, datas, auditor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     "restore",
+		EntityType: "archive",
+		EntityID:   archive.ID,
+		Title:      fmt.Sprintf("Restored %d audit log entries from %s.", restored, archive.FileName),
+	})
+	return &domain.RestoreArchiveResponse{
+		Archive:     *domain.Convert[domain.Archive, domain.ArchiveResponse](archive),
+		Restored:    restored,
+		RetainUntil: retainUntil,
+	}, nil
+}
@@ -1,19 +1,88 @@
 package service
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"slices"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/auth"
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
-// CreateAuditLog creates a new AuditLog
-func (s *Service) CreateAuditLog(req *domain.AuditLogRequest) (*domain.AuditLogResponse, error) {
-	data := domain.Convert[domain.AuditLogRequest, domain.AuditLog](req)
+// auditEntry is one action to record. Before is nil on create and After is nil on delete, the
+// fields that differ between them are stored as the diff. Data is extra context that is not a
+// change to the entity. The actor is the caller in ctx, ActorID and ActorName stand in for it
+// outside a request, like on login or in background jobs.
+type auditEntry struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	Title      string
+	Before     interface{}
+	After      interface{}
+	Data       interface{}
+	ActorID    string
+	ActorName  string
+}
+
+// audit writes an audit log entry with the actor and request details taken from ctx, a failed
+// write is logged and never fails the action being audited
+func (s *Service) audit(ctx context.Context, entry auditEntry) {
+	if _, err := s.repo.CreateAuditLog(ctx, newAuditLog(ctx, entry)); err != nil {
+		logrus.WithError(err).Warnf("could not write audit log for %s %s %s", entry.Action, entry.EntityType, entry.EntityID)
+	}
+}
+
+func newAuditLog(ctx context.Context, entry auditEntry) *domain.AuditLog {
+	data := &domain.AuditLog{
+		Title:       entry.Title,
+		Action:      entry.Action,
+		PerformedBy: domain.AuditSystem,
+		EntityType:  entry.EntityType,
+		EntityID:    entry.EntityID,
+		IP:          contextString(ctx, "client_ip"),
+		UserAgent:   contextString(ctx, "user_agent"),
+		RequestID:   contextString(ctx, "request_id"),
+		Changes:     domain.AuditDiff(entry.Before, entry.After),
+		IsActive:    true,
+	}
+	if payload, ok := ctx.Value("authorization_payload").(*auth.Payload); ok {
+		entry.ActorID, entry.ActorName = payload.UserID, payload.Username
+	}
+	if entry.ActorID != "" {
+		data.UserID = &entry.ActorID
+	}
+	if entry.ActorName != "" {
+		data.PerformedBy = entry.ActorName
+	}
+	if entry.Data != nil {
+		data.Data = string(domain.ConvertToJson(entry.Data))
+	}
+	return data
+}
+
+func contextString(ctx context.Context, key string) string {
+	value, _ := ctx.Value(key).(string)
+	return value
+}
+
+// CreateAuditLog records a manual AuditLog entry
+func (s *Service) CreateAuditLog(ctx context.Context, req *domain.AuditLogRequest) (*domain.AuditLogResponse, error) {
+	ctx, span := startSpan(ctx, "CreateAuditLog")
+	defer span.End()
+	if _, err := s.auditor(ctx); err != nil {
+		return nil, err
+	}
+	data := newAuditLog(ctx, auditEntry{Action: req.Action, Title: req.Title})
+	data.Data, data.Details, data.Remarks = req.Data, req.Details, req.Remarks
 	err := data.Validate()
 	if err != nil {
 		return nil, err
 	}
-	result, err := s.repo.CreateAuditLog(data)
+	result, err := s.repo.CreateAuditLog(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -21,10 +90,15 @@ func (s *Service) CreateAuditLog(req *domain.AuditLogRequest) (*domain.AuditLogR
 }
 
 // ListAuditLogs retrieves a list of AuditLogs
-func (s *Service) ListAuditLog(req *domain.ListAuditLogRequest) ([]*domain.AuditLogResponse, int64, error) {
+func (s *Service) ListAuditLog(ctx context.Context, req *domain.ListAuditLogRequest) ([]*domain.AuditLogResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListAuditLog")
+	defer span.End()
+	if _, err := s.auditor(ctx); err != nil {
+		return nil, 0, err
+	}
 	var datas = []*domain.AuditLogResponse{}
 	req.Size = 5
-	results, count, err := s.repo.ListAuditLog(req)
+	results, count, err := s.repo.ListAuditLog(ctx, req)
 	if err != nil {
 		return nil, count, err
 	}
@@ -34,37 +108,274 @@ func (s *Service) ListAuditLog(req *domain.ListAuditLogRequest) ([]*domain.Audit
 	return datas, count, nil
 }
 
-func (s *Service) GetAuditLog(id string) (*domain.AuditLogResponse, error) {
-	result, err := s.repo.GetAuditLog(id)
+func (s *Service) GetAuditLog(ctx context.Context, id string) (*domain.AuditLogResponse, error) {
+	ctx, span := startSpan(ctx, "GetAuditLog")
+	defer span.End()
+	if _, err := s.auditor(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetAuditLog(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return domain.Convert[domain.AuditLog, domain.AuditLogResponse](result), nil
 }
 
-func (s *Service) UpdateAuditLog(id string, req *domain.AuditLogUpdateRequest) (*domain.AuditLogResponse, error) {
-	if id == "" {
-		return nil, errors.New("required AuditLog id")
+// auditor loads the caller and makes sure they may read, add to, verify and sign the audit trail
+func (s *Service) auditor(ctx context.Context) (*domain.NotificationRecipient, error) {
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(recipient.Roles, domain.RoleAdmin) && !slices.Contains(recipient.Roles, domain.RoleDirector) {
+		return nil, domain.NewForbiddenError("not_allowed", "only an admin or director can access the audit trail")
+	}
+	return recipient, nil
+}
+
+// VerifyAuditLog recomputes every hash in the range and checks each entry links to the one before
+// it, then checks the signed checkpoints in the range still match the chain
+func (s *Service) VerifyAuditLog(ctx context.Context, req *domain.VerifyAuditLogRequest) (*domain.VerifyAuditLogResponse, error) {
+	ctx, span := startSpan(ctx, "VerifyAuditLog")
+	defer span.End()
+	if _, err := s.auditor(ctx); err != nil {
+		return nil, err
+	}
+	if req.ToSequence > 0 && req.ToSequence < req.FromSequence {
+		return nil, domain.NewValidationError("invalid_range", "to_sequence must not be before from_sequence")
+	}
+	return s.verifyAuditChain(ctx, max(req.FromSequence, 1), req.ToSequence)
+}
+
+const (
+	auditVerifyBatch = 1000
+	maxAuditIssues   = 100
+)
+
+func (s *Service) verifyAuditChain(ctx context.Context, from, to int64) (*domain.VerifyAuditLogResponse, error) {
+	report := &domain.VerifyAuditLogResponse{Issues: []domain.AuditIssue{}}
+	issue := func(sequence int64, id, problem string, args ...interface{}) {
+		if len(report.Issues) >= maxAuditIssues {
+			report.Truncated = true
+			return
+		}
+		report.Issues = append(report.Issues, domain.AuditIssue{Sequence: sequence, ID: id, Problem: fmt.Sprintf(problem, args...)})
 	}
-	_, err := s.repo.GetAuditLog(id)
+
+	checkpoints, err := s.repo.ListAuditCheckpointBetween(ctx, from, to)
 	if err != nil {
 		return nil, err
 	}
-	mp := req.NewUpdate()
-	result, err := s.repo.UpdateAuditLog(id, mp)
+	archived, err := s.repo.ListArchivedAuditSequences(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return domain.Convert[domain.AuditLog, domain.AuditLogResponse](result), nil
+	pinned := map[int64][]*domain.AuditCheckpoint{}
+	for _, checkpoint := range checkpoints {
+		pinned[checkpoint.Sequence] = append(pinned[checkpoint.Sequence], checkpoint)
+	}
+
+	// the entry before the range is loaded so the first link is checked too
+	var prev *domain.AuditLog
+	if from > 1 {
+		prev, err = s.repo.GetAuditLogBySequence(ctx, from-1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	after := from - 1
+	for {
+		entries, err := s.repo.ListAuditLogChain(ctx, after, to, auditVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if report.Checked == 0 {
+				report.FirstSequence = entry.Sequence
+			}
+			report.Checked++
+			// entries moved to archive files leave gaps whose links cannot be checked here
+			switch {
+			case prev != nil && entry.Sequence != prev.Sequence+1 && archived.Covers(prev.Sequence+1, entry.Sequence-1):
+				report.Archived += entry.Sequence - prev.Sequence - 1
+			case prev != nil && entry.Sequence != prev.Sequence+1:
+				issue(entry.Sequence, entry.ID, "entries %d to %d are missing", prev.Sequence+1, entry.Sequence-1)
+			case prev != nil && entry.PrevHash != prev.Hash:
+				issue(entry.Sequence, entry.ID, "prev_hash does not match the hash of entry %d", prev.Sequence)
+			case prev == nil && entry.Sequence > from && archived.Covers(from, entry.Sequence-1):
+				report.Archived += entry.Sequence - from
+			case prev == nil && entry.Sequence > from:
+				issue(entry.Sequence, entry.ID, "entries %d to %d are missing", from, entry.Sequence-1)
+			case prev == nil && entry.Sequence == 1 && entry.PrevHash != "":
+				issue(entry.Sequence, entry.ID, "the first entry has a prev_hash")
+			}
+			if entry.ComputeHash() != entry.Hash {
+				issue(entry.Sequence, entry.ID, "hash does not match the content, the entry was edited")
+			}
+			for _, checkpoint := range pinned[entry.Sequence] {
+				report.Checkpoints++
+				if checkpoint.Hash != entry.Hash {
+					issue(entry.Sequence, entry.ID, "hash does not match checkpoint %s", checkpoint.ID)
+				}
+				if !checkpoint.VerifySignature() {
+					issue(entry.Sequence, entry.ID, "signature of checkpoint %s does not verify", checkpoint.ID)
+				}
+			}
+			prev = entry
+			after = entry.Sequence
+		}
+		if len(entries) < auditVerifyBatch {
+			break
+		}
+	}
+
+	if prev != nil && report.Checked > 0 {
+		report.LastSequence, report.HeadHash = prev.Sequence, prev.Hash
+	}
+	// a checkpoint past the last entry means entries were removed from the end of the chain
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Sequence > report.LastSequence && !archived.Covers(checkpoint.Sequence, checkpoint.Sequence) {
+			issue(checkpoint.Sequence, "", "checkpoint %s pins entry %d but the chain ends at %d", checkpoint.ID, checkpoint.Sequence, report.LastSequence)
+		}
+	}
+	report.Valid = len(report.Issues) == 0
+	return report, nil
+}
+
+// CreateAuditCheckpoint signs the current head of the chain
+func (s *Service) CreateAuditCheckpoint(ctx context.Context) (*domain.AuditCheckpointResponse, error) {
+	ctx, span := startSpan(ctx, "CreateAuditCheckpoint")
+	defer span.End()
+	if _, err := s.auditor(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.checkpointAuditLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "audit_checkpoint",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Signed audit checkpoint at entry %d.", result.Sequence),
+		After:      result,
+	})
+	return domain.Convert[domain.AuditCheckpoint, domain.AuditCheckpointResponse](result), nil
+}
+
+// checkpointAuditLog verifies the chain since the last checkpoint and signs its head, a broken
+// chain is never signed
+func (s *Service) checkpointAuditLog(ctx context.Context) (*domain.AuditCheckpoint, error) {
+	if s.auditSigner == nil {
+		return nil, domain.NewPolicyViolationError("audit_signing_disabled", "audit checkpoints are disabled until AUDIT_SIGNING_KEY is set")
+	}
+	head, err := s.repo.GetLastAuditLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, domain.NewNotFoundError("audit_trail_empty", "the audit trail is empty")
+	}
+	last, err := s.repo.GetLastAuditCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from := int64(1)
+	if last != nil {
+		from = last.Sequence
+	}
+	report, err := s.verifyAuditChain(ctx, from, head.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	if !report.Valid {
+		return nil, domain.NewConflictError("audit_trail_broken", fmt.Sprintf("the audit trail failed verification at entry %d: %s", report.Issues[0].Sequence, report.Issues[0].Problem))
+	}
+	data := &domain.AuditCheckpoint{
+		BaseModel: domain.BaseModel{ID: uuid.NewString(), CreatedAt: domain.AuditTime(time.Now())},
+		Sequence:  head.Sequence,
+		Hash:      head.Hash,
+		Algorithm: s.auditSigner.Algorithm(),
+		PublicKey: s.auditSigner.PublicKey(),
+	}
+	data.Signature, err = s.auditSigner.Sign(data.SignedPayload())
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CreateAuditCheckpoint(ctx, data)
+}
+
+func (s *Service) ListAuditCheckpoint(ctx context.Context, req *domain.ListAuditCheckpointRequest) ([]*domain.AuditCheckpointResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListAuditCheckpoint")
+	defer span.End()
+	if _, err := s.auditor(ctx); err != nil {
+		return nil, 0, err
+	}
+	var datas = []*domain.AuditCheckpointResponse{}
+	results, count, err := s.repo.ListAuditCheckpoint(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.AuditCheckpoint, domain.AuditCheckpointResponse](result))
+	}
+	return datas, count, nil
 }
 
-func (s *Service) DeleteAuditLog(id string) error {
-	if id == "" {
-		return errors.New("required AuditLog id")
+// ExportAuditCheckpoint returns the checkpoint with the exact payload that was signed
+func (s *Service) ExportAuditCheckpoint(ctx context.Context, id string) (*domain.AuditCheckpointExport, error) {
+	ctx, span := startSpan(ctx, "ExportAuditCheckpoint")
+	defer span.End()
+	if _, err := s.auditor(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetAuditCheckpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuditCheckpointExport{
+		Checkpoint: *domain.Convert[domain.AuditCheckpoint, domain.AuditCheckpointResponse](result),
+		Payload:    string(result.SignedPayload()),
+		ExportedAt: time.Now(),
+	}, nil
+}
+
+func (s *Service) RunAuditCheckpoint(ctx context.Context, every time.Duration) {
+	if s.auditSigner == nil {
+		return
+	}
+	tick := time.NewTicker(every)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			s.runJob(ctx, "audit_checkpoint", s.checkpointAuditHead)
+		}
+	}
+}
+
+// checkpointAuditHead signs a checkpoint when entries were added since the last one
+func (s *Service) checkpointAuditHead(ctx context.Context) {
+	head, err := s.repo.GetLastAuditLog(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("could not load the audit chain head")
+		return
+	}
+	last, err := s.repo.GetLastAuditCheckpoint(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("could not load the last audit checkpoint")
+		return
+	}
+	if head == nil || (last != nil && last.Sequence >= head.Sequence) {
+		return
 	}
-	err := s.repo.DeleteAuditLog(id)
+	result, err := s.checkpointAuditLog(ctx)
 	if err != nil {
-		return err
+		logrus.WithError(err).Error("could not sign an audit checkpoint")
+		return
 	}
-	return nil
+	logrus.Infof("signed audit checkpoint %s at entry %d", result.ID, result.Sequence)
 }
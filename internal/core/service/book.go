@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
@@ -11,6 +10,8 @@ import (
 
 // CreateBook creates a new Book
 func (s *Service) CreateBook(ctx context.Context, req *domain.BookRequest) (*domain.BookResponse, error) {
+	ctx, span := startSpan(ctx, "CreateBook")
+	defer span.End()
 	// Validate request
 	if err := req.Validate(); err != nil {
 		return nil, err
@@ -20,7 +21,7 @@ func (s *Service) CreateBook(ctx context.Context, req *domain.BookRequest) (*dom
 	book := domain.Convert[domain.BookRequest, domain.Book](req)
 	logrus.Info("Book to be created: ", book)
 	// Create Book record
-	result, err := s.repo.CreateBook(book)
+	result, err := s.repo.CreateBook(ctx, book)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +34,7 @@ func (s *Service) CreateBook(ctx context.Context, req *domain.BookRequest) (*dom
 				AccessionNumber: fmt.Sprintf("%03d", i),
 				Status:          "available",
 			}
-			if _, err := s.repo.CreateBookCopy(copy); err != nil {
+			if _, err := s.repo.CreateBookCopy(ctx, copy); err != nil {
 				// optionally log error but continue
 				logrus.Error("Failed to create book copy: ", err)
 			}
@@ -49,7 +50,7 @@ func (s *Service) CreateBook(ctx context.Context, req *domain.BookRequest) (*dom
 		}
 		// Bulk insert copies
 		for _, copy := range copies {
-			if _, err := s.repo.CreateBookCopy(copy); err != nil {
+			if _, err := s.repo.CreateBookCopy(ctx, copy); err != nil {
 				// optionally log error but continue
 				logrus.Error("Failed to create book copy: ", err)
 			}
@@ -58,43 +59,45 @@ func (s *Service) CreateBook(ctx context.Context, req *domain.BookRequest) (*dom
 
 	// Optional: Create notification and audit log
 	userID, _ := getUserID(ctx)
-	_, _ = s.repo.CreateNotification(&domain.Notification{
-		Title:       fmt.Sprintf("Created new Book %s with %d copies.", result.Title, result.TotalCopies),
-		Description: "create",
-		UserID:      userID,
-		Type:        "book",
-		Action:      "create",
-		Module:      "book",
-		IsActive:    true,
+	s.notify(ctx, notice{
+		UserID: userID,
+		Event:  domain.EventBookCreated,
+		Title:  fmt.Sprintf("Created new Book %s with %d copies.", result.Title, result.TotalCopies),
 	})
 
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Created new Book %s with copies.", result.Title),
-		Action:   "create",
-		Data:     string(domain.ConvertToJson(result)),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "book",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new Book %s with copies.", result.Title),
+		After:      result,
 	})
+	s.emit(domain.EventTypeBookCreated, userID, domain.Convert[domain.Book, domain.BookResponse](result))
 
 	return domain.Convert[domain.Book, domain.BookResponse](result), nil
 }
 
 // ListBooks retrieves a list of Books
 func (s *Service) ListBook(ctx context.Context, req *domain.BookListRequest) ([]*domain.BookResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListBook")
+	defer span.End()
 	var datas = []*domain.BookResponse{}
-	results, count, err := s.repo.ListBook(req)
+	results, count, err := s.repo.ListBook(ctx, req)
 	if err != nil {
 		return nil, count, err
 	}
 	for _, result := range results {
 		data := domain.Convert[domain.Book, domain.BookResponse](result)
-		data.AvailableCopies, _ = s.repo.GetAvailableCopies(data.ID)
+		data.AvailableCopies, _ = s.repo.GetAvailableCopies(ctx, data.ID)
 		datas = append(datas, data)
 	}
 	return datas, count, nil
 }
 
 func (s *Service) GetBook(ctx context.Context, id string) (*domain.BookResponse, error) {
-	result, err := s.repo.GetBook(id)
+	ctx, span := startSpan(ctx, "GetBook")
+	defer span.End()
+	result, err := s.repo.GetBook(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -103,10 +106,12 @@ func (s *Service) GetBook(ctx context.Context, id string) (*domain.BookResponse,
 }
 
 func (s *Service) UpdateBook(ctx context.Context, id string, req *domain.BookUpdateRequest) (*domain.BookResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateBook")
+	defer span.End()
 	if id == "" {
-		return nil, errors.New("required Book id")
+		return nil, domain.NewValidationError("id_required", "required Book id")
 	}
-	_, err := s.repo.GetBook(id)
+	before, err := s.repo.GetBook(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -116,32 +121,32 @@ func (s *Service) UpdateBook(ctx context.Context, id string, req *domain.BookUpd
 	}
 	// update
 	mp := req.NewUpdate()
-	result, err := s.repo.UpdateBook(id, mp)
+	result, err := s.repo.UpdateBook(ctx, id, mp)
 	if err != nil {
 		return nil, err
 	}
-	_, _ = s.repo.CreateNotification(&domain.Notification{
-		Title:       fmt.Sprintf("Updated %s Book details.", result.Title),
-		Description: "update",
-		Type:        "book",
-		Action:      "update",
-		Module:      "book",
-		UserID:      getUserID,
-		IsActive:    true,
+	s.notify(ctx, notice{
+		UserID: getUserID,
+		Event:  domain.EventBookUpdated,
+		Title:  fmt.Sprintf("Updated %s Book details.", result.Title),
 	})
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Updated %s Book details.", result.Title),
-		Action:   "update",
-		Data:     fmt.Sprint(req),
-		UserID:   &getUserID,
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "book",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s Book details.", result.Title),
+		Before:     before,
+		After:      result,
 	})
 	data := domain.Convert[domain.Book, domain.BookResponse](result)
+	s.emit(domain.EventTypeBookUpdated, getUserID, data)
 	return data, nil
 }
 
 func (s *Service) DeleteBook(ctx context.Context, id string) (*domain.BookResponse, error) {
-	result, err := s.repo.GetBook(id)
+	ctx, span := startSpan(ctx, "DeleteBook")
+	defer span.End()
+	result, err := s.repo.GetBook(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -149,31 +154,32 @@ func (s *Service) DeleteBook(ctx context.Context, id string) (*domain.BookRespon
 	if err != nil {
 		return nil, err
 	}
-	copyExists, err := s.repo.IsBookCopiesByBookId(id)
+	copyExists, err := s.repo.IsBookCopiesByBookId(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if copyExists {
-		return nil, fmt.Errorf("book has copies cannot delete it")
+		return nil, domain.NewConflictError("book_in_use", "book has copies cannot delete it")
 	}
-	CountBorrwedCopiesBookID, err := s.repo.CountBorrwedCopiesBookID(id)
+	CountBorrwedCopiesBookID, err := s.repo.CountBorrwedCopiesBookID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	logrus.Info("CountBorrwedCopiesBookID :: ", CountBorrwedCopiesBookID)
 	if CountBorrwedCopiesBookID > 0 {
-		return nil, fmt.Errorf("book has %d copies borrowed cannot delete it", CountBorrwedCopiesBookID)
+		return nil, domain.NewConflictError("book_in_use", fmt.Sprintf("book has %d copies borrowed cannot delete it", CountBorrwedCopiesBookID))
 	}
-	err = s.repo.DeleteBook(id)
+	err = s.repo.DeleteBook(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Deleted %s parking area.", result.Title),
-		Action:   "delete",
-		UserID:   &getUserID,
-		Data:     fmt.Sprint(result),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "book",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s Book.", result.Title),
+		Before:     result,
 	})
+	s.emit(domain.EventTypeBookDeleted, getUserID, domain.Convert[domain.Book, domain.BookResponse](result))
 	return domain.Convert[domain.Book, domain.BookResponse](result), nil
 }
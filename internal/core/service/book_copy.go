@@ -2,23 +2,24 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
 func (s *Service) CreateBookCopy(ctx context.Context, req *domain.AddBookCopiesRequest) (*domain.BookCopyResponse, error) {
+	ctx, span := startSpan(ctx, "CreateBookCopy")
+	defer span.End()
 	err := req.Validate()
 	if err != nil {
 		return nil, err
 	}
-	book, err := s.repo.GetBook(req.BookID)
+	book, err := s.repo.GetBook(ctx, req.BookID)
 	if err != nil {
-		return nil, errors.New("book not found")
+		return nil, domain.NewNotFoundError("book_not_found", "book not found")
 	}
 	total := book.TotalCopies + req.AddCopies
-	_, err = s.repo.UpdateBook(req.BookID, domain.Map{"total_copies": total})
+	_, err = s.repo.UpdateBook(ctx, req.BookID, domain.Map{"total_copies": total})
 	if err != nil {
 		return nil, err
 	}
@@ -29,33 +30,33 @@ func (s *Service) CreateBookCopy(ctx context.Context, req *domain.AddBookCopiesR
 			AccessionNumber: fmt.Sprintf("%03d", i),
 			Status:          "available",
 		}
-		result, err = s.repo.CreateBookCopy(data)
+		result, err = s.repo.CreateBookCopy(ctx, data)
 		if err != nil {
 			return nil, err
 		}
-		_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-			Title:    fmt.Sprintf("Created new copy %s of BookID %s", result.AccessionNumber, result.BookID),
-			Action:   "create",
-			Data:     fmt.Sprint(result),
-			IsActive: true,
+		s.audit(ctx, auditEntry{
+			Action:     domain.AuditCreate,
+			EntityType: "book_copy",
+			EntityID:   result.ID,
+			Title:      fmt.Sprintf("Created new copy %s of BookID %s", result.AccessionNumber, result.BookID),
+			After:      result,
 		})
 		userID, _ := getUserID(ctx)
-		_, _ = s.repo.CreateNotification(&domain.Notification{
-			Title:       fmt.Sprintf("Created new copy %s of BookID %s", result.AccessionNumber, result.BookID),
-			Description: "create",
-			UserID:      userID,
-			Type:        "book_copy",
-			Action:      "create",
-			Module:      "book_copy",
-			IsActive:    true,
+		s.notify(ctx, notice{
+			UserID: userID,
+			Event:  domain.EventBookCopyCreated,
+			Title:  fmt.Sprintf("Created new copy %s of BookID %s", result.AccessionNumber, result.BookID),
 		})
+		s.emit(domain.EventTypeBookCopyCreated, userID, domain.Convert[domain.BookCopy, domain.BookCopyResponse](result))
 	}
 	return domain.Convert[domain.BookCopy, domain.BookCopyResponse](result), nil
 }
 
 func (s *Service) ListBookCopies(ctx context.Context, req *domain.BookCopyListRequest) ([]*domain.BookCopyResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListBookCopies")
+	defer span.End()
 	var datas []*domain.BookCopyResponse
-	results, count, err := s.repo.ListBookCopies(req)
+	results, count, err := s.repo.ListBookCopies(ctx, req)
 	if err != nil {
 		return nil, count, err
 	}
@@ -69,8 +70,10 @@ func (s *Service) ListBookCopies(ctx context.Context, req *domain.BookCopyListRe
 }
 
 func (s *Service) ListBookCopiesByBookId(ctx context.Context, bookId string, req *domain.BookCopyListRequest) ([]*domain.BookCopyResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListBookCopiesByBookId")
+	defer span.End()
 	var datas []*domain.BookCopyResponse
-	results, count, err := s.repo.ListBookCopiesByBookId(bookId, req)
+	results, count, err := s.repo.ListBookCopiesByBookId(ctx, bookId, req)
 	if err != nil {
 		return nil, count, err
 	}
@@ -84,7 +87,9 @@ func (s *Service) ListBookCopiesByBookId(ctx context.Context, bookId string, req
 }
 
 func (s *Service) GetBookCopy(ctx context.Context, id string) (*domain.BookCopyResponse, error) {
-	result, err := s.repo.GetBookCopy(id)
+	ctx, span := startSpan(ctx, "GetBookCopy")
+	defer span.End()
+	result, err := s.repo.GetBookCopy(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -93,74 +98,77 @@ func (s *Service) GetBookCopy(ctx context.Context, id string) (*domain.BookCopyR
 }
 
 func (s *Service) UpdateBookCopy(ctx context.Context, id string, req *domain.BookCopyUpdateRequest) (*domain.BookCopyResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateBookCopy")
+	defer span.End()
 	if id == "" {
-		return nil, errors.New("required BookCopy id")
+		return nil, domain.NewValidationError("id_required", "required BookCopy id")
 	}
 	getUserID, err := getUserID(ctx)
 	if err != nil {
 		return nil, err
 	}
-	_, err = s.repo.GetBookCopy(id)
+	before, err := s.repo.GetBookCopy(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	mp := req.NewUpdate()
-	result, err := s.repo.UpdateBookCopy(id, mp)
+	result, err := s.repo.UpdateBookCopy(ctx, id, mp)
 	if err != nil {
 		return nil, err
 	}
 
-	_, _ = s.repo.CreateNotification(&domain.Notification{
-		Title:       fmt.Sprintf("Updated copy %s of BookID %s", result.AccessionNumber, result.BookID),
-		Description: "update",
-		UserID:      getUserID,
-		Type:        "book_copy",
-		Action:      "update",
-		Module:      "book_copy",
-		IsActive:    true,
+	s.notify(ctx, notice{
+		UserID: getUserID,
+		Event:  domain.EventBookCopyUpdated,
+		Title:  fmt.Sprintf("Updated copy %s of BookID %s", result.AccessionNumber, result.BookID),
 	})
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Updated copy %s of BookID %s", result.AccessionNumber, result.BookID),
-		Action:   "update",
-		UserID:   &getUserID,
-		Data:     fmt.Sprint(req),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "book_copy",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated copy %s of BookID %s", result.AccessionNumber, result.BookID),
+		Before:     before,
+		After:      result,
 	})
-
-	return domain.Convert[domain.BookCopy, domain.BookCopyResponse](result), nil
+	response := domain.Convert[domain.BookCopy, domain.BookCopyResponse](result)
+	s.emit(domain.EventTypeBookCopyUpdated, getUserID, response)
+	return response, nil
 }
 
 func (s *Service) DeleteBookCopy(ctx context.Context, id string) (*domain.BookCopyResponse, error) {
-	result, err := s.repo.GetBookCopy(id)
+	ctx, span := startSpan(ctx, "DeleteBookCopy")
+	defer span.End()
+	result, err := s.repo.GetBookCopy(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	getUserID, err := getUserID(ctx)
+	actorID, err := getUserID(ctx)
 	if err != nil {
 		return nil, err
 	}
 	// Optional: check if this copy is currently issued
-	borrowedCount, err := s.repo.CountBorrowedCopyID(id)
+	borrowedCount, err := s.repo.CountBorrowedCopyID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if borrowedCount > 0 {
-		return nil, fmt.Errorf("this copy is currently borrowed, cannot delete")
+		return nil, domain.NewConflictError("book_copy_in_use", "this copy is currently borrowed, cannot delete")
 	}
 
-	err = s.repo.DeleteBookCopy(id)
+	err = s.repo.DeleteBookCopy(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Deleted copy %s of BookID %s", result.AccessionNumber, result.BookID),
-		Action:   "delete",
-		UserID:   &getUserID,
-		Data:     fmt.Sprint(result),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "book_copy",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted copy %s of BookID %s", result.AccessionNumber, result.BookID),
+		Before:     result,
 	})
-
-	return domain.Convert[domain.BookCopy, domain.BookCopyResponse](result), nil
+	response := domain.Convert[domain.BookCopy, domain.BookCopyResponse](result)
+	s.emit(domain.EventTypeBookCopyDeleted, actorID, response)
+	return response, nil
 }
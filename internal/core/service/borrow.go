@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
@@ -11,6 +10,8 @@ import (
 
 // CreateBorrowBook creates a new BorrowedBook
 func (s *Service) CreateBorrow(ctx context.Context, req *domain.BorrowedBookRequest) (*domain.BorrowedBookResponse, error) {
+	ctx, span := startSpan(ctx, "CreateBorrow")
+	defer span.End()
 	err := req.Validate()
 	if err != nil {
 		return nil, err
@@ -19,15 +20,15 @@ func (s *Service) CreateBorrow(ctx context.Context, req *domain.BorrowedBookRequ
 	if err != nil {
 		return nil, err
 	}
-	isBookBorrowd := s.repo.IsBookBorrowByUserID(req.UserID, req.BookCopyID)
+	isBookBorrowd := s.repo.IsBookBorrowByUserID(ctx, req.UserID, req.BookCopyID)
 	if isBookBorrowd {
-		return nil, errors.New("book already borrowed")
+		return nil, domain.NewConflictError("book_already_borrowed", "book already borrowed")
 	}
-	bookCopy, err := s.repo.GetBookCopy(req.BookCopyID)
+	bookCopy, err := s.repo.GetBookCopy(ctx, req.BookCopyID)
 	if err != nil {
 		return nil, err
 	}
-	user, err := s.repo.GetUser(req.UserID)
+	user, err := s.repo.GetUser(ctx, req.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -37,55 +38,63 @@ func (s *Service) CreateBorrow(ctx context.Context, req *domain.BorrowedBookRequ
 	}
 	data.BookID = bookCopy.BookID
 	data.IsActive = true
-	result, err := s.repo.CreateBorrow(data)
+	result, err := s.repo.CreateBorrow(ctx, data)
 	if err != nil {
 		return nil, err
 	}
 	if data.Status == "borrowed" {
 		data.Status = "issued"
-		_, err = s.repo.UpdateBookCopy(bookCopy.ID, domain.Map{"status": "borrowed"})
+		_, err = s.repo.UpdateBookCopy(ctx, bookCopy.ID, domain.Map{"status": "borrowed"})
 		if err != nil {
 			return nil, err
 		}
-		_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-			Title:    fmt.Sprintf("Book %s Accession Number %s has been issued to %s", bookCopy.Book.Title, bookCopy.AccessionNumber, user.FullName),
-			UserID:   &getUserID,
-			Action:   "issue",
-			Data:     fmt.Sprint(req),
-			IsActive: true,
+		s.notify(ctx, notice{
+			UserID:      user.ID,
+			Event:       domain.EventHoldReady,
+			Title:       fmt.Sprintf("%s book has %s to %s", bookCopy.Book.Title, data.Status, user.FullName),
+			ReferenceID: result.ID,
+			Data: messageData{
+				BookTitle:       bookCopy.Book.Title,
+				AccessionNumber: bookCopy.AccessionNumber,
+				DueDate:         result.DueDate.Format(messageDateLayout),
+			},
 		})
-		_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-			Title:    fmt.Sprintf("%s book has %s to %s", bookCopy.Book.Title, result.Status, user.FullName),
-			UserID:   &getUserID,
-			Action:   "issue",
-			Data:     fmt.Sprint(req),
-			IsActive: true,
+		s.audit(ctx, auditEntry{
+			Action:     "issue",
+			EntityType: "borrowed_book",
+			EntityID:   result.ID,
+			Title:      fmt.Sprintf("Book %s Accession Number %s has been issued to %s", bookCopy.Book.Title, bookCopy.AccessionNumber, user.FullName),
+			After:      result,
 		})
+		s.emit(domain.EventTypeBookIssued, getUserID, domain.Convert[domain.BorrowedBook, domain.BorrowedBookResponse](result))
 	}
 	if data.Status == "pending" {
 		data.Status = "requested"
-		s.repo.CreateNotification(&domain.Notification{
-			Title:    fmt.Sprintf("%s book has %s by %s", bookCopy.Book.Title, data.Status, user.FullName),
-			UserID:   getUserID,
-			Module:   "borrow",
-			Action:   "borrow",
-			IsActive: true,
+		s.notify(ctx, notice{
+			UserID:      user.ID,
+			Event:       domain.EventBorrowRequested,
+			Title:       fmt.Sprintf("%s book has %s by %s", bookCopy.Book.Title, data.Status, user.FullName),
+			ReferenceID: result.ID,
 		})
-		_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-			Title:    fmt.Sprintf("%s book has %s by %s", bookCopy.Book.Title, result.Status, user.FullName),
-			UserID:   &getUserID,
-			Action:   "create",
-			Data:     fmt.Sprint(req),
-			IsActive: true,
+		s.notifyRole(ctx, domain.RoleLibrarian, domain.EventBorrowRequested, fmt.Sprintf("%s book has %s by %s", bookCopy.Book.Title, data.Status, user.FullName))
+		s.audit(ctx, auditEntry{
+			Action:     domain.AuditCreate,
+			EntityType: "borrowed_book",
+			EntityID:   result.ID,
+			Title:      fmt.Sprintf("%s book has %s by %s", bookCopy.Book.Title, data.Status, user.FullName),
+			After:      result,
 		})
+		s.emit(domain.EventTypeBorrowRequested, getUserID, domain.Convert[domain.BorrowedBook, domain.BorrowedBookResponse](result))
 	}
 	return domain.Convert[domain.BorrowedBook, domain.BorrowedBookResponse](result), nil
 }
 
 // ListBorrowedBooks retrieves a list of BorrowedBooks
 func (s *Service) ListBorrow(ctx context.Context, req *domain.ListBorrowedBookRequest) ([]*domain.BorrowedBookResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListBorrow")
+	defer span.End()
 	var datas = []*domain.BorrowedBookResponse{}
-	results, count, err := s.repo.ListBorrow(req)
+	results, count, err := s.repo.ListBorrow(ctx, req)
 	if err != nil {
 		return nil, count, err
 	}
@@ -97,7 +106,9 @@ func (s *Service) ListBorrow(ctx context.Context, req *domain.ListBorrowedBookRe
 }
 
 func (s *Service) GetBorrow(ctx context.Context, id string) (*domain.BorrowedBookResponse, error) {
-	result, err := s.repo.GetBorrow(id)
+	ctx, span := startSpan(ctx, "GetBorrow")
+	defer span.End()
+	result, err := s.repo.GetBorrow(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -106,8 +117,10 @@ func (s *Service) GetBorrow(ctx context.Context, id string) (*domain.BorrowedBoo
 }
 
 func (s *Service) GetStudentsBorrowBook(ctx context.Context, id string) ([]*domain.BorrowedBookResponse, error) {
+	ctx, span := startSpan(ctx, "GetStudentsBorrowBook")
+	defer span.End()
 	var datas = []*domain.BorrowedBookResponse{}
-	results, err := s.repo.GetBookBorrowByUserID(id)
+	results, err := s.repo.GetBookBorrowByUserID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -119,10 +132,12 @@ func (s *Service) GetStudentsBorrowBook(ctx context.Context, id string) ([]*doma
 }
 
 func (s *Service) UpdateBorrow(ctx context.Context, id string, req *domain.UpdateBorrowedBookRequest) (*domain.BorrowedBookResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateBorrow")
+	defer span.End()
 	if id == "" {
-		return nil, errors.New("required borrow id")
+		return nil, domain.NewValidationError("id_required", "required borrow id")
 	}
-	borrow, err := s.repo.GetBorrow(id)
+	borrow, err := s.repo.GetBorrow(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -130,83 +145,79 @@ func (s *Service) UpdateBorrow(ctx context.Context, id string, req *domain.Updat
 	if err != nil {
 		return nil, err
 	}
-	bookCopy, err := s.repo.GetBookCopy(borrow.BookCopyID)
+	bookCopy, err := s.repo.GetBookCopy(ctx, borrow.BookCopyID)
 	if err != nil {
 		return nil, err
 	}
-	user, err := s.repo.GetUser(borrow.UserID)
+	user, err := s.repo.GetUser(ctx, borrow.UserID)
 	if err != nil {
 		return nil, err
 	}
 	// update
 	mp := req.NewUpdate()
 	logrus.Info(mp)
-	result, err := s.repo.UpdateBorrow(id, mp)
+	result, err := s.repo.UpdateBorrow(ctx, id, mp)
 	if err != nil {
 		return nil, err
 	}
 	if result.Status == "borrowed" {
 		result.Status = "issued"
-		_, err = s.repo.UpdateBookCopy(bookCopy.ID, domain.Map{"status": "borrowed"})
+		_, err = s.repo.UpdateBookCopy(ctx, bookCopy.ID, domain.Map{"status": "borrowed"})
 		if err != nil {
 			return nil, err
 		}
-		_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-			Title:    fmt.Sprintf("Book %s Accession Number %s has been issued to %s", bookCopy.Book.Title, bookCopy.AccessionNumber, user.FullName),
-			UserID:   &getUserID,
-			Action:   "issue",
-			Data:     fmt.Sprint(req),
-			IsActive: true,
+		// sent once per borrow, later updates to an issued borrow stay quiet
+		s.notify(ctx, notice{
+			UserID:      user.ID,
+			Event:       domain.EventHoldReady,
+			Title:       fmt.Sprintf("%s book has %s to %s", bookCopy.Book.Title, result.Status, user.FullName),
+			ReferenceID: result.ID,
+			Data: messageData{
+				BookTitle:       bookCopy.Book.Title,
+				AccessionNumber: bookCopy.AccessionNumber,
+				DueDate:         result.DueDate.Format(messageDateLayout),
+			},
 		})
-		_, _ = s.repo.CreateNotification(&domain.Notification{
-			Title:    fmt.Sprintf("%s book has %s to %s", bookCopy.Book.Title, result.Status, user.FullName),
-			UserID:   user.ID,
-			Module:   "borrow",
-			Action:   "issue",
-			IsActive: true,
-		})
-		_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-			Title:    fmt.Sprintf("%s book has %s to %s", bookCopy.Book.Title, result.Status, user.FullName),
-			UserID:   &getUserID,
-			Action:   "issue",
-			Data:     fmt.Sprint(req),
-			IsActive: true,
+		s.audit(ctx, auditEntry{
+			Action:     "issue",
+			EntityType: "borrowed_book",
+			EntityID:   result.ID,
+			Title:      fmt.Sprintf("Book %s Accession Number %s has been issued to %s", bookCopy.Book.Title, bookCopy.AccessionNumber, user.FullName),
+			Before:     borrow,
+			After:      result,
 		})
+		s.emit(domain.EventTypeBookIssued, getUserID, domain.Convert[domain.BorrowedBook, domain.BorrowedBookResponse](result))
 	}
 	if result.Status == "returned" {
 		result.Status = "returned"
-		_, err = s.repo.UpdateBookCopy(bookCopy.ID, domain.Map{"status": "available"})
+		_, err = s.repo.UpdateBookCopy(ctx, bookCopy.ID, domain.Map{"status": "available"})
 		if err != nil {
 			return nil, err
 		}
-		_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-			Title:    fmt.Sprintf("Book %s Accession Number %s has been available", bookCopy.Book.Title, bookCopy.AccessionNumber),
-			UserID:   &getUserID,
-			Action:   "issue",
-			Data:     fmt.Sprint(req),
-			IsActive: true,
-		})
-		_, _ = s.repo.CreateNotification(&domain.Notification{
-			Title:    fmt.Sprintf("%s book has %s by %s", bookCopy.Book.Title, result.Status, user.FullName),
-			UserID:   user.ID,
-			Module:   "borrow",
-			Action:   "return",
-			IsActive: true,
+		s.notify(ctx, notice{
+			UserID:      user.ID,
+			Event:       domain.EventBorrowReturned,
+			Title:       fmt.Sprintf("%s book has %s by %s", bookCopy.Book.Title, result.Status, user.FullName),
+			ReferenceID: result.ID,
 		})
-		_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-			Title:    fmt.Sprintf("%s book has %s by %s", bookCopy.Book.Title, result.Status, user.FullName),
-			UserID:   &getUserID,
-			Action:   "update",
-			Data:     fmt.Sprint(req),
-			IsActive: true,
+		s.audit(ctx, auditEntry{
+			Action:     "return",
+			EntityType: "borrowed_book",
+			EntityID:   result.ID,
+			Title:      fmt.Sprintf("%s book has %s by %s", bookCopy.Book.Title, result.Status, user.FullName),
+			Before:     borrow,
+			After:      result,
 		})
+		s.emit(domain.EventTypeBookReturned, getUserID, domain.Convert[domain.BorrowedBook, domain.BorrowedBookResponse](result))
 	}
 	data := domain.Convert[domain.BorrowedBook, domain.BorrowedBookResponse](result)
 	return data, nil
 }
 
 func (s *Service) DeleteBorrow(ctx context.Context, id string) (*domain.BorrowedBookResponse, error) {
-	result, err := s.repo.GetBorrow(id)
+	ctx, span := startSpan(ctx, "DeleteBorrow")
+	defer span.End()
+	result, err := s.repo.GetBorrow(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -214,23 +225,23 @@ func (s *Service) DeleteBorrow(ctx context.Context, id string) (*domain.Borrowed
 	if err != nil {
 		return nil, err
 	}
-	err = s.repo.DeleteBorrow(id)
+	err = s.repo.DeleteBorrow(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	s.repo.CreateNotification(&domain.Notification{
-		Title:    fmt.Sprintf("%s book has been deleted by %s", result.BookCopy.Book.Title, result.Student.FullName),
-		UserID:   result.UserID,
-		Module:   "borrow",
-		Action:   "delete",
-		IsActive: true,
+	s.notify(ctx, notice{
+		UserID: result.UserID,
+		Event:  domain.EventBorrowDeleted,
+		Title:  fmt.Sprintf("%s book has been deleted by %s", result.BookCopy.Book.Title, result.Student.FullName),
 	})
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("%s book has been deleted by %s", result.BookCopy.Book.Title, result.Student.FullName),
-		UserID:   &getUserID,
-		Action:   "delete",
-		Data:     fmt.Sprint(result),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "borrowed_book",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("%s book has been deleted by %s", result.BookCopy.Book.Title, result.Student.FullName),
+		Before:     result,
 	})
-	return domain.Convert[domain.BorrowedBook, domain.BorrowedBookResponse](result), nil
+	response := domain.Convert[domain.BorrowedBook, domain.BorrowedBookResponse](result)
+	s.emit(domain.EventTypeBorrowDeleted, getUserID, response)
+	return response, nil
 }
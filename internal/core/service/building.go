@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateBuilding creates a new Building under an existing Campus
+func (s *Service) CreateBuilding(ctx context.Context, req *domain.BuildingRequest) (*domain.BuildingResponse, error) {
+	ctx, span := startSpan(ctx, "CreateBuilding")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetCampus(ctx, req.CampusID); err != nil {
+		return nil, domain.NewNotFoundError("campus_not_found", fmt.Sprintf("campus %s not found", req.CampusID))
+	}
+	data := domain.Convert[domain.BuildingRequest, domain.Building](req)
+	result, err := s.repo.CreateBuilding(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "building",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new building %s.", result.Name),
+		After:      result,
+	})
+	return domain.Convert[domain.Building, domain.BuildingResponse](result), nil
+}
+
+// ListBuilding retrieves a list of Buildings
+func (s *Service) ListBuilding(ctx context.Context, req *domain.ListBuildingRequest) ([]*domain.BuildingResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListBuilding")
+	defer span.End()
+	var datas = []*domain.BuildingResponse{}
+	results, count, err := s.repo.ListBuilding(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.Building, domain.BuildingResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetBuilding(ctx context.Context, id string) (*domain.BuildingResponse, error) {
+	ctx, span := startSpan(ctx, "GetBuilding")
+	defer span.End()
+	result, err := s.repo.GetBuilding(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.Building, domain.BuildingResponse](result), nil
+}
+
+func (s *Service) UpdateBuilding(ctx context.Context, id string, req *domain.UpdateBuildingRequest) (*domain.BuildingResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateBuilding")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required building id")
+	}
+	before, err := s.repo.GetBuilding(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.CampusID != "" {
+		if _, err := s.repo.GetCampus(ctx, req.CampusID); err != nil {
+			return nil, domain.NewNotFoundError("campus_not_found", fmt.Sprintf("campus %s not found", req.CampusID))
+		}
+	}
+	result, err := s.repo.UpdateBuilding(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "building",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s building details.", result.Name),
+		Before:     before,
+		After:      result,
+	})
+	return domain.Convert[domain.Building, domain.BuildingResponse](result), nil
+}
+
+// DeleteBuilding deletes a Building that has no floors left
+func (s *Service) DeleteBuilding(ctx context.Context, id string) (*domain.BuildingResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteBuilding")
+	defer span.End()
+	result, err := s.repo.GetBuilding(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	floors, err := s.repo.CountBuildingFloors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if floors > 0 {
+		return nil, domain.NewConflictError("building_in_use", fmt.Sprintf("building has %d floors cannot delete it", floors))
+	}
+	if err := s.repo.DeleteBuilding(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "building",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s building.", result.Name),
+		Before:     result,
+	})
+	return domain.Convert[domain.Building, domain.BuildingResponse](result), nil
+}
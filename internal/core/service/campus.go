@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateCampus creates a new Campus
+func (s *Service) CreateCampus(ctx context.Context, req *domain.CampusRequest) (*domain.CampusResponse, error) {
+	ctx, span := startSpan(ctx, "CreateCampus")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data := domain.Convert[domain.CampusRequest, domain.Campus](req)
+	result, err := s.repo.CreateCampus(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "campus",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new campus %s.", result.Name),
+		After:      result,
+	})
+	return domain.Convert[domain.Campus, domain.CampusResponse](result), nil
+}
+
+// ListCampus retrieves a list of Campuses
+func (s *Service) ListCampus(ctx context.Context, req *domain.ListCampusRequest) ([]*domain.CampusResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListCampus")
+	defer span.End()
+	var datas = []*domain.CampusResponse{}
+	results, count, err := s.repo.ListCampus(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.Campus, domain.CampusResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetCampus(ctx context.Context, id string) (*domain.CampusResponse, error) {
+	ctx, span := startSpan(ctx, "GetCampus")
+	defer span.End()
+	result, err := s.repo.GetCampus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.Campus, domain.CampusResponse](result), nil
+}
+
+func (s *Service) UpdateCampus(ctx context.Context, id string, req *domain.UpdateCampusRequest) (*domain.CampusResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateCampus")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required campus id")
+	}
+	before, err := s.repo.GetCampus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.UpdateCampus(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "campus",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s campus details.", result.Name),
+		Before:     before,
+		After:      result,
+	})
+	return domain.Convert[domain.Campus, domain.CampusResponse](result), nil
+}
+
+// DeleteCampus deletes a Campus that has no buildings left
+func (s *Service) DeleteCampus(ctx context.Context, id string) (*domain.CampusResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteCampus")
+	defer span.End()
+	result, err := s.repo.GetCampus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buildings, err := s.repo.CountCampusBuildings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if buildings > 0 {
+		return nil, domain.NewConflictError("campus_in_use", fmt.Sprintf("campus has %d buildings cannot delete it", buildings))
+	}
+	if err := s.repo.DeleteCampus(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "campus",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s campus.", result.Name),
+		Before:     result,
+	})
+	return domain.Convert[domain.Campus, domain.CampusResponse](result), nil
+}
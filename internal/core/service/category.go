@@ -8,11 +8,13 @@ import (
 )
 
 func (s *Service) Create(ctx context.Context, req *domain.CategoryRequest) (*domain.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "Create")
+	defer span.End()
 	category := &domain.Category{}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
-	getUserID, err := getUserID(ctx)
+	_, err := getUserID(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -22,17 +24,19 @@ func (s *Service) Create(ctx context.Context, req *domain.CategoryRequest) (*dom
 		return nil, err
 	}
 
-	s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Created %s Category", category.Name),
-		Action:   "create",
-		UserID:   &getUserID,
-		Data:     fmt.Sprint(category),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "category",
+		EntityID:   category.ID,
+		Title:      fmt.Sprintf("Created %s Category", category.Name),
+		After:      category,
 	})
 	return category.CategoryResponse(), err
 }
 
 func (s *Service) List(ctx context.Context, req *domain.ListCategoryRequest) ([]domain.CategoryResponse, int64, error) {
+	ctx, span := startSpan(ctx, "List")
+	defer span.End()
 	cr := []domain.CategoryResponse{}
 	categories, count, err := s.repo.List(ctx, req)
 	if err != nil {
@@ -45,6 +49,8 @@ func (s *Service) List(ctx context.Context, req *domain.ListCategoryRequest) ([]
 }
 
 func (s *Service) Get(ctx context.Context, id string) (*domain.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "Get")
+	defer span.End()
 	category, err := s.repo.Get(ctx, id)
 	if err != nil {
 		return nil, err
@@ -53,7 +59,13 @@ func (s *Service) Get(ctx context.Context, id string) (*domain.CategoryResponse,
 }
 
 func (s *Service) Update(ctx context.Context, id string, req *domain.CategoryUpdateRequest) (*domain.CategoryResponse, error) {
-	getUserID, err := getUserID(ctx)
+	ctx, span := startSpan(ctx, "Update")
+	defer span.End()
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.repo.Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -66,18 +78,21 @@ func (s *Service) Update(ctx context.Context, id string, req *domain.CategoryUpd
 	if err != nil {
 		return nil, err
 	}
-	s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Updated %s Category", category.Name),
-		Action:   "Update",
-		UserID:   &getUserID,
-		Data:     fmt.Sprint(category),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "category",
+		EntityID:   category.ID,
+		Title:      fmt.Sprintf("Updated %s Category", category.Name),
+		Before:     before,
+		After:      category,
 	})
 	return category.CategoryResponse(), err
 }
 
 func (s *Service) Delete(ctx context.Context, id string) error {
-	getUserID, err := getUserID(ctx)
+	ctx, span := startSpan(ctx, "Delete")
+	defer span.End()
+	_, err := getUserID(ctx)
 	if err != nil {
 		return err
 	}
@@ -89,12 +104,12 @@ func (s *Service) Delete(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
-	s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Created %s Category", category.Name),
-		UserID:   &getUserID,
-		Action:   "create",
-		Data:     fmt.Sprint(category),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "category",
+		EntityID:   category.ID,
+		Title:      fmt.Sprintf("Deleted %s Category", category.Name),
+		Before:     category,
 	})
 	return nil
 }
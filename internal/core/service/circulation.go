@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (s *Service) GetTopBooks(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopBook, error) {
+	ctx, span := startSpan(ctx, "GetTopBooks")
+	defer span.End()
+	result, err := s.repo.GetTopBooks(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []domain.TopBook{}
+	}
+	return result, nil
+}
+
+func (s *Service) GetTopCategories(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopCategory, error) {
+	ctx, span := startSpan(ctx, "GetTopCategories")
+	defer span.End()
+	result, err := s.repo.GetTopCategories(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []domain.TopCategory{}
+	}
+	return result, nil
+}
+
+// ListInactiveStock lists the titles nobody borrowed in the requested months, weeding candidates
+func (s *Service) ListInactiveStock(ctx context.Context, req *domain.InactiveStockRequest) ([]*domain.InactiveBook, int64, error) {
+	ctx, span := startSpan(ctx, "ListInactiveStock")
+	defer span.End()
+	results, count, err := s.repo.ListInactiveStock(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	if results == nil {
+		results = []*domain.InactiveBook{}
+	}
+	return results, count, nil
+}
+
+func (s *Service) GetLoanDuration(ctx context.Context, req *domain.CirculationRequest) (*domain.LoanDuration, error) {
+	ctx, span := startSpan(ctx, "GetLoanDuration")
+	defer span.End()
+	return s.repo.GetLoanDuration(ctx, req)
+}
+
+// GetBorrowingRates reports per program and per batch how many students borrowed and how much
+func (s *Service) GetBorrowingRates(ctx context.Context, req *domain.CirculationRequest) (*domain.BorrowingRates, error) {
+	ctx, span := startSpan(ctx, "GetBorrowingRates")
+	defer span.End()
+	result, err := s.repo.GetBorrowingRates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if result.Programs == nil {
+		result.Programs = []domain.BorrowingRate{}
+	}
+	if result.Batches == nil {
+		result.Batches = []domain.BorrowingRate{}
+	}
+	for _, rates := range [][]domain.BorrowingRate{result.Programs, result.Batches} {
+		for i := range rates {
+			rates[i].LoansPerStudent = domain.Ratio(rates[i].Loans, rates[i].Students)
+			rates[i].BorrowerRate = domain.Percent(rates[i].Borrowers, rates[i].Students)
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) GetTopReaders(ctx context.Context, req *domain.CirculationRequest) ([]domain.TopReader, error) {
+	ctx, span := startSpan(ctx, "GetTopReaders")
+	defer span.End()
+	result, err := s.repo.GetTopReaders(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []domain.TopReader{}
+	}
+	return result, nil
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+//go:embed templates/email/*.tmpl
+var emailTemplateFS embed.FS
+
+// emailTemplates holds one parsed file per template name, each file defines its own "subject"
+// and "body" so they are kept apart
+var emailTemplates = func() map[string]*template.Template {
+	tmpls := map[string]*template.Template{}
+	for _, name := range []string{
+		domain.EventDueReminder,
+		domain.EventOverdue,
+		domain.EventHoldReady,
+		domain.EventFineIssued,
+		domain.EventAccountCreated,
+	} {
+		tmpls[name] = template.Must(template.ParseFS(emailTemplateFS, "templates/email/"+name+".tmpl"))
+	}
+	return tmpls
+}()
+
+const (
+	emailBatchSize     = 50
+	emailPollInterval  = 30 * time.Second
+	emailScanInterval  = time.Hour
+	emailRetryBackoff  = time.Minute
+	emailLease         = 10 * time.Minute // longer than a batch takes to send
+	emailQueueCapacity = 1
+)
+
+func renderEmail(name string, data messageData) (string, string, error) {
+	tmpl, ok := emailTemplates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %s", name)
+	}
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return subject.String(), body.String(), nil
+}
+
+func (s *Service) enqueueEmail(ctx context.Context, user *domain.User, name, referenceID string, data messageData) error {
+	if user.Email == "" {
+		return nil
+	}
+	subject, body, err := renderEmail(name, data)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.CreateEmailDelivery(ctx, &domain.EmailDelivery{
+		UserID:        user.ID,
+		To:            user.Email,
+		Template:      name,
+		ReferenceID:   referenceID,
+		Subject:       subject,
+		Body:          body,
+		Status:        domain.EmailQueued,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	s.wakeEmailWorker()
+	return nil
+}
+
+func (s *Service) wakeEmailWorker() {
+	select {
+	case s.emailQueue <- struct{}{}:
+	default:
+	}
+}
+
+// RunEmailDelivery sends queued emails as they arrive and, once an hour, queues due and overdue
+// reminders for open borrows
+func (s *Service) RunEmailDelivery(ctx context.Context) {
+	poll := time.NewTicker(emailPollInterval)
+	defer poll.Stop()
+	scan := time.NewTicker(emailScanInterval)
+	defer scan.Stop()
+
+	s.runJob(ctx, "borrow_reminders", s.scheduleBorrowReminders)
+	s.runJob(ctx, "email_delivery", s.deliverEmails)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-scan.C:
+			s.runJob(ctx, "borrow_reminders", s.scheduleBorrowReminders)
+			s.runJob(ctx, "email_delivery", s.deliverEmails)
+		case <-poll.C:
+			s.runJob(ctx, "email_delivery", s.deliverEmails)
+		case <-s.emailQueue:
+			s.runJob(ctx, "email_delivery", s.deliverEmails)
+		}
+	}
+}
+
+func (s *Service) deliverEmails(ctx context.Context) {
+	for {
+		deliveries, err := s.repo.ClaimDueEmailDeliveries(ctx, time.Now(), emailLease, emailBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("could not claim queued emails")
+			return
+		}
+		for _, delivery := range deliveries {
+			s.deliverEmail(ctx, delivery)
+		}
+		if len(deliveries) < emailBatchSize {
+			return
+		}
+	}
+}
+
+func (s *Service) deliverEmail(ctx context.Context, delivery *domain.EmailDelivery) {
+	attempts := delivery.Attempts + 1
+	err := s.mailer.Send(&domain.EmailMessage{
+		To:      delivery.To,
+		Subject: delivery.Subject,
+		Body:    delivery.Body,
+	})
+	update := domain.Map{"attempts": attempts}
+	var bounce *domain.EmailBounceError
+	switch {
+	case err == nil:
+		now := time.Now()
+		update["status"] = domain.EmailSent
+		update["sent_at"] = &now
+		update["last_error"] = ""
+	case errors.As(err, &bounce):
+		update["status"] = domain.EmailBounced
+		update["last_error"] = err.Error()
+	case attempts >= domain.MaxEmailAttempts:
+		update["status"] = domain.EmailFailed
+		update["last_error"] = err.Error()
+	default:
+		// 1m, 2m, 4m, 8m ... between attempts
+		backoff := emailRetryBackoff * time.Duration(math.Pow(2, float64(attempts-1)))
+		update["next_attempt_at"] = time.Now().Add(backoff)
+		update["last_error"] = err.Error()
+	}
+	if err != nil {
+		logrus.WithError(err).Warnf("email %s to %s attempt %d", delivery.ID, delivery.To, attempts)
+	}
+	if _, err := s.repo.UpdateEmailDelivery(ctx, delivery.ID, update); err != nil {
+		logrus.WithError(err).Errorf("could not update email delivery %s", delivery.ID)
+	}
+}
+
+// emailOperator loads the caller and makes sure they are library staff, the log holds every
+// recipient's address and subject
+func (s *Service) emailOperator(ctx context.Context) (*domain.NotificationRecipient, error) {
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(recipient.Roles, domain.RoleAdmin) && !slices.Contains(recipient.Roles, domain.RoleLibrarian) {
+		return nil, domain.NewForbiddenError("not_allowed", "only an admin or librarian can manage email deliveries")
+	}
+	return recipient, nil
+}
+
+func (s *Service) ListEmailDelivery(ctx context.Context, req *domain.ListEmailDeliveryRequest) ([]*domain.EmailDeliveryResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListEmailDelivery")
+	defer span.End()
+	if _, err := s.emailOperator(ctx); err != nil {
+		return nil, 0, err
+	}
+	var datas = []*domain.EmailDeliveryResponse{}
+	results, count, err := s.repo.ListEmailDelivery(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		data := domain.Convert[domain.EmailDelivery, domain.EmailDeliveryResponse](result)
+		datas = append(datas, data)
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetEmailDelivery(ctx context.Context, id string) (*domain.EmailDeliveryResponse, error) {
+	ctx, span := startSpan(ctx, "GetEmailDelivery")
+	defer span.End()
+	if _, err := s.emailOperator(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetEmailDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.EmailDelivery, domain.EmailDeliveryResponse](result), nil
+}
+
+// RetryEmailDelivery puts a failed or bounced email back on the queue with a fresh set of attempts
+func (s *Service) RetryEmailDelivery(ctx context.Context, id string) (*domain.EmailDeliveryResponse, error) {
+	ctx, span := startSpan(ctx, "RetryEmailDelivery")
+	defer span.End()
+	if _, err := s.emailOperator(ctx); err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.GetEmailDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == domain.EmailSent {
+		return nil, domain.NewConflictError("email_already_sent", "email has already been sent")
+	}
+	result, err := s.repo.UpdateEmailDelivery(ctx, id, domain.Map{
+		"status":          domain.EmailQueued,
+		"attempts":        0,
+		"last_error":      "",
+		"next_attempt_at": time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.wakeEmailWorker()
+	s.audit(ctx, auditEntry{
+		Action:     "retry",
+		EntityType: "email_delivery",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Retried %s email to %s", result.Template, result.To),
+		Before:     delivery,
+		After:      result,
+	})
+	return domain.Convert[domain.EmailDelivery, domain.EmailDeliveryResponse](result), nil
+}
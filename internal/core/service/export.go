@@ -0,0 +1,546 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	"github.com/sugaml/lms-api/internal/core/port"
+)
+
+var borrowColumns = []domain.ExportColumn[*domain.BorrowedBook]{
+	{Key: "id", Value: func(b *domain.BorrowedBook) any { return b.ID }},
+	{Key: "student_name", Value: func(b *domain.BorrowedBook) any {
+		if b.Student == nil {
+			return ""
+		}
+		return b.Student.FullName
+	}},
+	{Key: "username", Value: func(b *domain.BorrowedBook) any {
+		if b.Student == nil {
+			return ""
+		}
+		return b.Student.Username
+	}},
+	{Key: "book_title", Value: func(b *domain.BorrowedBook) any {
+		if b.BookCopy == nil || b.BookCopy.Book == nil {
+			return ""
+		}
+		return b.BookCopy.Book.Title
+	}},
+	{Key: "accession_number", Value: func(b *domain.BorrowedBook) any {
+		if b.BookCopy == nil {
+			return ""
+		}
+		return b.BookCopy.AccessionNumber
+	}},
+	{Key: "borrowed_date", Value: func(b *domain.BorrowedBook) any { return b.BorrowedDate }},
+	{Key: "due_date", Value: func(b *domain.BorrowedBook) any { return b.DueDate }},
+	{Key: "returned_date", Value: func(b *domain.BorrowedBook) any { return b.ReturnedDate }},
+	{Key: "renewal_count", Value: func(b *domain.BorrowedBook) any { return b.RenewalCount }},
+	{Key: "status", Value: func(b *domain.BorrowedBook) any { return b.Status }},
+	{Key: "remarks", Value: func(b *domain.BorrowedBook) any { return b.Remarks }},
+}
+
+var fineColumns = []domain.ExportColumn[*domain.Fine]{
+	{Key: "id", Value: func(f *domain.Fine) any { return f.ID }},
+	{Key: "created_at", Value: func(f *domain.Fine) any { return f.CreatedAt }},
+	{Key: "user_id", Value: func(f *domain.Fine) any { return f.UserID }},
+	{Key: "borrowed_book_id", Value: func(f *domain.Fine) any { return f.BorrowedBookID }},
+	{Key: "amount", Value: func(f *domain.Fine) any { return rupees(int64(f.Amount)) }},
+	{Key: "receipt_number", Value: func(f *domain.Fine) any {
+		if f.ReceiptNumber == nil {
+			return ""
+		}
+		return *f.ReceiptNumber
+	}},
+	{Key: "reason", Value: func(f *domain.Fine) any { return f.Reason }},
+	{Key: "status", Value: func(f *domain.Fine) any { return f.Status }},
+	{Key: "paid_at", Value: func(f *domain.Fine) any { return f.PaidAt }},
+	{Key: "collected_by", Value: func(f *domain.Fine) any { return f.CollectedBy }},
+	{Key: "waived_at", Value: func(f *domain.Fine) any { return f.WaivedAt }},
+	{Key: "waiver_reason", Value: func(f *domain.Fine) any { return f.WaiverReason }},
+}
+
+var studentColumns = []domain.ExportColumn[*domain.User]{
+	{Key: "id", Value: func(u *domain.User) any { return u.ID }},
+	{Key: "username", Value: func(u *domain.User) any { return u.Username }},
+	{Key: "full_name", Value: func(u *domain.User) any { return u.FullName }},
+	{Key: "email", Value: func(u *domain.User) any { return u.Email }},
+	{Key: "mobile_number", Value: func(u *domain.User) any { return u.MobileNumber }},
+	{Key: "gender", Value: func(u *domain.User) any { return u.Gender }},
+	{Key: "dob", Value: func(u *domain.User) any { return u.Dob }},
+	{Key: "program_id", Value: func(u *domain.User) any { return u.ProgramID }},
+	{Key: "batch", Value: func(u *domain.User) any { return u.Batch }},
+	{Key: "section", Value: func(u *domain.User) any { return u.Section }},
+	{Key: "created_at", Value: func(u *domain.User) any { return u.CreatedAt }},
+}
+
+var chartColumns = []domain.ExportColumn[domain.ChartData]{
+	{Key: "date", Value: func(c domain.ChartData) any { return c.Date }},
+	{Key: "month", Value: func(c domain.ChartData) any { return c.Month }},
+	{Key: "borrowed", Value: func(c domain.ChartData) any { return c.Borrowed }},
+	{Key: "returned", Value: func(c domain.ChartData) any { return c.Returned }},
+	{Key: "due", Value: func(c domain.ChartData) any { return c.Due }},
+	{Key: "requests", Value: func(c domain.ChartData) any { return c.Requests }},
+	{Key: "totalStudents", Value: func(c domain.ChartData) any { return c.TotalStudents }},
+	{Key: "booksAdded", Value: func(c domain.ChartData) any { return c.BooksAdded }},
+}
+
+var programStatColumns = []domain.ExportColumn[domain.BookProgramstats]{
+	{Key: "program_id", Value: func(p domain.BookProgramstats) any { return p.ProgramID }},
+	{Key: "program_name", Value: func(p domain.BookProgramstats) any { return p.ProgramName }},
+	{Key: "count", Value: func(p domain.BookProgramstats) any { return p.Count }},
+}
+
+var teacherWorkloadColumns = []domain.ExportColumn[domain.TeacherWorkload]{
+	{Key: "teacher_id", Value: func(t domain.TeacherWorkload) any { return t.TeacherID }},
+	{Key: "teacher_name", Value: func(t domain.TeacherWorkload) any { return t.TeacherName }},
+	{Key: "classes", Value: func(t domain.TeacherWorkload) any { return t.Classes }},
+	{Key: "contact_hours", Value: func(t domain.TeacherWorkload) any { return t.ContactHours }},
+	{Key: "courses", Value: func(t domain.TeacherWorkload) any { return t.Courses }},
+	{Key: "credit_hours", Value: func(t domain.TeacherWorkload) any { return t.CreditHours }},
+}
+
+// writeExport writes the requested columns of every row that each hands over, then finishes
+// the file. Nothing is sent when the columns are wrong, so the caller can still answer with an
+// error.
+func writeExport[T any](w port.ExportWriter, meta *domain.ExportMeta, exp *domain.ExportRequest, columns []domain.ExportColumn[T], each func(func([]T) error) error) error {
+	selected, err := domain.SelectColumns(columns, exp.Columns)
+	if err != nil {
+		return err
+	}
+	meta.Lang, meta.GeneratedAt = exp.Lang, time.Now()
+	headers := make([]string, len(selected))
+	for i, column := range selected {
+		headers[i] = domain.ExportLabel(exp.Lang, column.Key)
+	}
+	if err := w.WriteHeader(meta, headers); err != nil {
+		return err
+	}
+	err = each(func(rows []T) error {
+		for _, row := range rows {
+			values := make([]any, len(selected))
+			for i, column := range selected {
+				values[i] = column.Value(row)
+			}
+			if err := w.WriteRow(values); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// exportRows hands rows that are already loaded to writeExport
+func exportRows[T any](rows []T) func(func([]T) error) error {
+	return func(fn func([]T) error) error {
+		return fn(rows)
+	}
+}
+
+// exportStats writes a summary report as one labelled row per figure
+func exportStats(w port.ExportWriter, meta *domain.ExportMeta, exp *domain.ExportRequest, stats []domain.ExportStat) error {
+	for i := range stats {
+		stats[i].Metric = domain.ExportLabel(exp.Lang, stats[i].Metric)
+	}
+	return writeExport(w, meta, exp, domain.StatColumns, exportRows(stats))
+}
+
+// periodOf is the date part of a prepared list request period
+func periodOf(req *domain.ListRequest) (string, string) {
+	return req.StartDate, req.EndDate[:min(len(req.EndDate), len("2006-01-02"))]
+}
+
+func (s *Service) ExportBorrow(ctx context.Context, req *domain.ListBorrowedBookRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportBorrow")
+	defer span.End()
+	meta := &domain.ExportMeta{Title: "borrows"}
+	meta.From, meta.To = periodOf(&req.ListRequest)
+	return writeExport(w, meta, exp, borrowColumns, func(fn func([]*domain.BorrowedBook) error) error {
+		return s.repo.EachBorrow(ctx, req, fn)
+	})
+}
+
+func (s *Service) ExportFine(ctx context.Context, req *domain.ListFineRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportFine")
+	defer span.End()
+	meta := &domain.ExportMeta{Title: "fines"}
+	meta.From, meta.To = periodOf(&req.ListRequest)
+	return writeExport(w, meta, exp, fineColumns, func(fn func([]*domain.Fine) error) error {
+		return s.repo.EachFine(ctx, req, fn)
+	})
+}
+
+func (s *Service) ExportStudent(ctx context.Context, req *domain.UserListRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportStudent")
+	defer span.End()
+	return writeExport(w, &domain.ExportMeta{Title: "students"}, exp, studentColumns, func(fn func([]*domain.User) error) error {
+		return s.repo.EachStudent(ctx, req, fn)
+	})
+}
+
+func (s *Service) ExportLibraryDashboardStats(ctx context.Context, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportLibraryDashboardStats")
+	defer span.End()
+	result, _, err := s.GetLibraryDashboardStats(ctx)
+	if err != nil {
+		return err
+	}
+	return exportStats(w, &domain.ExportMeta{Title: "dashboard-stats"}, exp, []domain.ExportStat{
+		{Metric: "totalBooks", Value: result.TotalBooks},
+		{Metric: "availableBooks", Value: result.AvailableBooks},
+		{Metric: "borrowedBooks", Value: result.BorrowedBooks},
+		{Metric: "overdueBooks", Value: result.OverdueBooks},
+		{Metric: "pendingRequests", Value: result.PendingRequests},
+		{Metric: "totalStudents", Value: result.TotalStudents},
+		{Metric: "activeStudents", Value: result.ActiveStudents},
+		{Metric: "totalFines", Value: rupees(result.TotalFines)},
+	})
+}
+
+func (s *Service) ExportChartData(ctx context.Context, req *domain.ChartRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportChartData")
+	defer span.End()
+	result, _, err := s.GetDailyChartData(ctx, req)
+	if err != nil {
+		return err
+	}
+	meta := &domain.ExportMeta{Title: "chart-stats", From: req.StartDate, To: req.EndDate}
+	return writeExport(w, meta, exp, chartColumns, exportRows(result))
+}
+
+func (s *Service) ExportBorrowedBookStats(ctx context.Context, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportBorrowedBookStats")
+	defer span.End()
+	result, err := s.GetBorrowedBookStats(ctx)
+	if err != nil {
+		return err
+	}
+	return exportStats(w, &domain.ExportMeta{Title: "borrowedbookstats"}, exp, []domain.ExportStat{
+		{Metric: "totalBorrowedBooks", Value: result.TotalBorrowedBooks},
+		{Metric: "totalOverdueBooks", Value: result.TotalOverdueBooks},
+		{Metric: "pendingRequests", Value: result.PendingRequests},
+		{Metric: "dueSoon", Value: result.DueSoon},
+	})
+}
+
+func (s *Service) ExportBookProgramstats(ctx context.Context, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportBookProgramstats")
+	defer span.End()
+	result, err := s.GetBookProgramstats(ctx)
+	if err != nil {
+		return err
+	}
+	var rows []domain.BookProgramstats
+	if result != nil {
+		rows = *result
+	}
+	return writeExport(w, &domain.ExportMeta{Title: "program-stats"}, exp, programStatColumns, exportRows(rows))
+}
+
+func (s *Service) ExportInventorystats(ctx context.Context, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportInventorystats")
+	defer span.End()
+	result, _, err := s.GetInventorystats(ctx)
+	if err != nil {
+		return err
+	}
+	return exportStats(w, &domain.ExportMeta{Title: "inventory-stats"}, exp, []domain.ExportStat{
+		{Metric: "totalBooks", Value: result.TotalBooks},
+		{Metric: "availableBooks", Value: result.AvailableBooks},
+		{Metric: "borrowedBooks", Value: result.BorrowedBooks},
+		{Metric: "overdueBooks", Value: result.OverdueBooks},
+		{Metric: "totalStudents", Value: result.TotalStudents},
+		{Metric: "activeStudents", Value: result.ActiveStudents},
+		{Metric: "pendingRequests", Value: result.PendingRequests},
+		{Metric: "totalFines", Value: rupees(result.TotalFines)},
+	})
+}
+
+func (s *Service) ExportTeacherWorkload(ctx context.Context, req *domain.AcademicReportRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportTeacherWorkload")
+	defer span.End()
+	result, err := s.GetTeacherWorkload(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeExport(w, &domain.ExportMeta{Title: "teacher-workload"}, exp, teacherWorkloadColumns, exportRows(result))
+}
+
+// ExportRoomUtilization writes per room utilization, flagging under-used labs
+func (s *Service) ExportRoomUtilization(ctx context.Context, req *domain.AcademicReportRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportRoomUtilization")
+	defer span.End()
+	report, err := s.GetRoomUtilization(ctx, req)
+	if err != nil {
+		return err
+	}
+	underused := map[string]bool{}
+	for _, lab := range report.UnderusedLabs {
+		underused[lab.RoomID] = true
+	}
+	columns := []domain.ExportColumn[domain.RoomUtilization]{
+		{Key: "building", Value: func(r domain.RoomUtilization) any { return r.BuildingName }},
+		{Key: "room_code", Value: func(r domain.RoomUtilization) any { return r.RoomCode }},
+		{Key: "room_type", Value: func(r domain.RoomUtilization) any { return string(r.RoomType) }},
+		{Key: "capacity", Value: func(r domain.RoomUtilization) any { return r.Capacity }},
+		{Key: "occupied_slots", Value: func(r domain.RoomUtilization) any { return r.OccupiedSlots }},
+		{Key: "available_slots", Value: func(r domain.RoomUtilization) any { return r.AvailableSlots }},
+		{Key: "utilization", Value: func(r domain.RoomUtilization) any { return r.Utilization }},
+		{Key: "underused_lab", Value: func(r domain.RoomUtilization) any { return underused[r.RoomID] }},
+	}
+	return writeExport(w, &domain.ExportMeta{Title: "room-utilization"}, exp, columns, exportRows(report.Rooms))
+}
+
+var topBookColumns = []domain.ExportColumn[domain.TopBook]{
+	{Key: "book_id", Value: func(b domain.TopBook) any { return b.BookID }},
+	{Key: "title", Value: func(b domain.TopBook) any { return b.Title }},
+	{Key: "author", Value: func(b domain.TopBook) any { return b.Author }},
+	{Key: "category_name", Value: func(b domain.TopBook) any { return b.CategoryName }},
+	{Key: "loans", Value: func(b domain.TopBook) any { return b.Loans }},
+	{Key: "borrowers", Value: func(b domain.TopBook) any { return b.Borrowers }},
+}
+
+var topCategoryColumns = []domain.ExportColumn[domain.TopCategory]{
+	{Key: "category_id", Value: func(c domain.TopCategory) any { return c.CategoryID }},
+	{Key: "category_name", Value: func(c domain.TopCategory) any { return c.CategoryName }},
+	{Key: "loans", Value: func(c domain.TopCategory) any { return c.Loans }},
+	{Key: "titles", Value: func(c domain.TopCategory) any { return c.Titles }},
+}
+
+var inactiveBookColumns = []domain.ExportColumn[*domain.InactiveBook]{
+	{Key: "book_id", Value: func(b *domain.InactiveBook) any { return b.BookID }},
+	{Key: "title", Value: func(b *domain.InactiveBook) any { return b.Title }},
+	{Key: "author", Value: func(b *domain.InactiveBook) any { return b.Author }},
+	{Key: "category_name", Value: func(b *domain.InactiveBook) any { return b.CategoryName }},
+	{Key: "copies", Value: func(b *domain.InactiveBook) any { return b.Copies }},
+	{Key: "last_loaned_at", Value: func(b *domain.InactiveBook) any { return b.LastLoanedAt }},
+	{Key: "added_at", Value: func(b *domain.InactiveBook) any { return b.AddedAt }},
+}
+
+var borrowingRateColumns = []domain.ExportColumn[domain.BorrowingRate]{
+	{Key: "program_name", Value: func(r domain.BorrowingRate) any { return r.ProgramName }},
+	{Key: "batch", Value: func(r domain.BorrowingRate) any { return r.Batch }},
+	{Key: "students", Value: func(r domain.BorrowingRate) any { return r.Students }},
+	{Key: "borrowers", Value: func(r domain.BorrowingRate) any { return r.Borrowers }},
+	{Key: "loans", Value: func(r domain.BorrowingRate) any { return r.Loans }},
+	{Key: "loans_per_student", Value: func(r domain.BorrowingRate) any { return r.LoansPerStudent }},
+	{Key: "borrower_rate", Value: func(r domain.BorrowingRate) any { return r.BorrowerRate }},
+}
+
+var topReaderColumns = []domain.ExportColumn[domain.TopReader]{
+	{Key: "user_id", Value: func(r domain.TopReader) any { return r.UserID }},
+	{Key: "full_name", Value: func(r domain.TopReader) any { return r.FullName }},
+	{Key: "username", Value: func(r domain.TopReader) any { return r.Username }},
+	{Key: "program_name", Value: func(r domain.TopReader) any { return r.ProgramName }},
+	{Key: "batch", Value: func(r domain.TopReader) any { return r.Batch }},
+	{Key: "loans", Value: func(r domain.TopReader) any { return r.Loans }},
+	{Key: "titles", Value: func(r domain.TopReader) any { return r.Titles }},
+}
+
+func circulationMeta(title string, req *domain.CirculationRequest) *domain.ExportMeta {
+	return &domain.ExportMeta{Title: title, From: req.StartDate, To: req.EndDate}
+}
+
+func (s *Service) ExportTopBooks(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportTopBooks")
+	defer span.End()
+	result, err := s.GetTopBooks(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeExport(w, circulationMeta("top-books", req), exp, topBookColumns, exportRows(result))
+}
+
+func (s *Service) ExportTopCategories(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportTopCategories")
+	defer span.End()
+	result, err := s.GetTopCategories(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeExport(w, circulationMeta("top-categories", req), exp, topCategoryColumns, exportRows(result))
+}
+
+func (s *Service) ExportInactiveStock(ctx context.Context, req *domain.InactiveStockRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportInactiveStock")
+	defer span.End()
+	return writeExport(w, &domain.ExportMeta{Title: "inactive-stock"}, exp, inactiveBookColumns, func(fn func([]*domain.InactiveBook) error) error {
+		return s.repo.EachInactiveStock(ctx, req, fn)
+	})
+}
+
+func (s *Service) ExportLoanDuration(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportLoanDuration")
+	defer span.End()
+	result, err := s.GetLoanDuration(ctx, req)
+	if err != nil {
+		return err
+	}
+	return exportStats(w, circulationMeta("loan-duration", req), exp, []domain.ExportStat{
+		{Metric: "loans", Value: result.Loans},
+		{Metric: "returned", Value: result.Returned},
+		{Metric: "late_returns", Value: result.LateReturns},
+		{Metric: "average_days", Value: result.AverageDays},
+		{Metric: "median_days", Value: result.MedianDays},
+		{Metric: "longest_days", Value: result.LongestDays},
+	})
+}
+
+// ExportBorrowingRates writes every program followed by its batches
+func (s *Service) ExportBorrowingRates(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportBorrowingRates")
+	defer span.End()
+	result, err := s.GetBorrowingRates(ctx, req)
+	if err != nil {
+		return err
+	}
+	rows := make([]domain.BorrowingRate, 0, len(result.Programs)+len(result.Batches))
+	for _, program := range result.Programs {
+		rows = append(rows, program)
+		for _, batch := range result.Batches {
+			if batch.ProgramID == program.ProgramID {
+				rows = append(rows, batch)
+			}
+		}
+	}
+	return writeExport(w, circulationMeta("borrowing-rates", req), exp, borrowingRateColumns, exportRows(rows))
+}
+
+func (s *Service) ExportTopReaders(ctx context.Context, req *domain.CirculationRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportTopReaders")
+	defer span.End()
+	result, err := s.GetTopReaders(ctx, req)
+	if err != nil {
+		return err
+	}
+	return writeExport(w, circulationMeta("top-readers", req), exp, topReaderColumns, exportRows(result))
+}
+
+// rupees writes an amount kept in paisa
+func rupees(paisa int64) float64 {
+	return float64(paisa) / 100
+}
+
+var fineCollectionColumns = []domain.ExportColumn[domain.FineCollection]{
+	{Key: "period", Value: func(c domain.FineCollection) any { return c.Period }},
+	{Key: "collector_id", Value: func(c domain.FineCollection) any { return c.CollectorID }},
+	{Key: "collector_name", Value: func(c domain.FineCollection) any { return c.CollectorName }},
+	{Key: "issued", Value: func(c domain.FineCollection) any { return c.Issued }},
+	{Key: "issued_amount", Value: func(c domain.FineCollection) any { return rupees(c.IssuedAmount) }},
+	{Key: "collected", Value: func(c domain.FineCollection) any { return c.Collected }},
+	{Key: "collected_amount", Value: func(c domain.FineCollection) any { return rupees(c.CollectedAmount) }},
+	{Key: "waived", Value: func(c domain.FineCollection) any { return c.Waived }},
+	{Key: "waived_amount", Value: func(c domain.FineCollection) any { return rupees(c.WaivedAmount) }},
+}
+
+var fineAgingColumns = []domain.ExportColumn[domain.FineAgingBucket]{
+	{Key: "bucket", Value: func(b domain.FineAgingBucket) any { return b.Bucket }},
+	{Key: "fines", Value: func(b domain.FineAgingBucket) any { return b.Fines }},
+	{Key: "students", Value: func(b domain.FineAgingBucket) any { return b.Students }},
+	{Key: "amount", Value: func(b domain.FineAgingBucket) any { return rupees(b.Amount) }},
+}
+
+var studentFineAgingColumns = []domain.ExportColumn[*domain.StudentFineAging]{
+	{Key: "user_id", Value: func(a *domain.StudentFineAging) any { return a.UserID }},
+	{Key: "full_name", Value: func(a *domain.StudentFineAging) any { return a.FullName }},
+	{Key: "username", Value: func(a *domain.StudentFineAging) any { return a.Username }},
+	{Key: "days_0_30", Value: func(a *domain.StudentFineAging) any { return rupees(a.Days0To30) }},
+	{Key: "days_31_60", Value: func(a *domain.StudentFineAging) any { return rupees(a.Days31To60) }},
+	{Key: "over_60_days", Value: func(a *domain.StudentFineAging) any { return rupees(a.Over60Days) }},
+	{Key: "total", Value: func(a *domain.StudentFineAging) any { return rupees(a.Total) }},
+}
+
+var fineStatementColumns = []domain.ExportColumn[domain.FineStatementLine]{
+	{Key: "date", Value: func(l domain.FineStatementLine) any {
+		if l.Date.IsZero() {
+			return nil
+		}
+		return l.Date
+	}},
+	{Key: "fine_id", Value: func(l domain.FineStatementLine) any { return l.FineID }},
+	{Key: "entry", Value: func(l domain.FineStatementLine) any { return l.Entry }},
+	{Key: "reason", Value: func(l domain.FineStatementLine) any { return l.Reason }},
+	{Key: "receipt_number", Value: func(l domain.FineStatementLine) any { return l.ReceiptNumber }},
+	{Key: "debit", Value: func(l domain.FineStatementLine) any { return rupees(l.Debit) }},
+	{Key: "credit", Value: func(l domain.FineStatementLine) any { return rupees(l.Credit) }},
+	{Key: "balance", Value: func(l domain.FineStatementLine) any { return rupees(l.Balance) }},
+}
+
+// ExportFineCollection writes the rows of the collection report then their total. The period or
+// collector columns the grouping leaves empty are not written unless asked for.
+func (s *Service) ExportFineCollection(ctx context.Context, req *domain.FineCollectionRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportFineCollection")
+	defer span.End()
+	result, err := s.GetFineCollection(ctx, req)
+	if err != nil {
+		return err
+	}
+	columns := fineCollectionColumns
+	if len(exp.Columns) == 0 {
+		if req.GroupBy == domain.FineGroupCollector {
+			columns = columns[1:]
+		} else {
+			columns = append(columns[:1:1], columns[3:]...)
+		}
+	}
+	total := result.Total
+	if req.GroupBy == domain.FineGroupCollector {
+		total.CollectorName = domain.ExportLabel(exp.Lang, "total")
+	} else {
+		total.Period = domain.ExportLabel(exp.Lang, "total")
+	}
+	meta := &domain.ExportMeta{Title: "fine-collection", From: req.StartDate, To: req.EndDate}
+	return writeExport(w, meta, exp, columns, exportRows(append(result.Rows, total)))
+}
+
+func (s *Service) ExportFineAging(ctx context.Context, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportFineAging")
+	defer span.End()
+	result, err := s.GetFineAging(ctx)
+	if err != nil {
+		return err
+	}
+	total := domain.FineAgingBucket{Bucket: domain.ExportLabel(exp.Lang, "total"), Fines: result.Fines, Amount: result.Outstanding}
+	return writeExport(w, &domain.ExportMeta{Title: "fine-aging"}, exp, fineAgingColumns, exportRows(append(result.Buckets, total)))
+}
+
+func (s *Service) ExportStudentFineAging(ctx context.Context, req *domain.ListRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportStudentFineAging")
+	defer span.End()
+	return writeExport(w, &domain.ExportMeta{Title: "student-fine-aging"}, exp, studentFineAgingColumns, func(fn func([]*domain.StudentFineAging) error) error {
+		return s.repo.EachStudentFineAging(ctx, req, fn)
+	})
+}
+
+// ExportFineStatement writes the statement lines between an opening and a closing balance row
+func (s *Service) ExportFineStatement(ctx context.Context, req *domain.FineStatementRequest, exp *domain.ExportRequest, w port.ExportWriter) error {
+	ctx, span := startSpan(ctx, "ExportFineStatement")
+	defer span.End()
+	result, err := s.GetFineStatement(ctx, req)
+	if err != nil {
+		return err
+	}
+	rows := make([]domain.FineStatementLine, 0, len(result.Lines)+2)
+	rows = append(rows, domain.FineStatementLine{Entry: domain.ExportLabel(exp.Lang, "opening_balance"), Balance: result.OpeningBalance})
+	rows = append(rows, result.Lines...)
+	rows = append(rows, domain.FineStatementLine{
+		Entry:   domain.ExportLabel(exp.Lang, "closing_balance"),
+		Debit:   result.Issued,
+		Credit:  result.Paid + result.Waived,
+		Balance: result.ClosingBalance,
+	})
+	meta := &domain.ExportMeta{
+		Title:   "fine-statement",
+		Subject: fmt.Sprintf("%s (%s)", result.FullName, result.Username),
+		From:    req.StartDate,
+		To:      req.EndDate,
+	}
+	return writeExport(w, meta, exp, fineStatementColumns, exportRows(rows))
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateFaculty creates a new Faculty
+func (s *Service) CreateFaculty(ctx context.Context, req *domain.FacultyRequest) (*domain.FacultyResponse, error) {
+	ctx, span := startSpan(ctx, "CreateFaculty")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data := domain.Convert[domain.FacultyRequest, domain.Faculty](req)
+	result, err := s.repo.CreateFaculty(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "faculty",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new faculty %s.", result.Name),
+		After:      result,
+	})
+	return domain.Convert[domain.Faculty, domain.FacultyResponse](result), nil
+}
+
+// ListFaculty retrieves a list of Faculties
+func (s *Service) ListFaculty(ctx context.Context, req *domain.ListFacultyRequest) ([]*domain.FacultyResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListFaculty")
+	defer span.End()
+	var datas = []*domain.FacultyResponse{}
+	results, count, err := s.repo.ListFaculty(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.Faculty, domain.FacultyResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetFaculty(ctx context.Context, id string) (*domain.FacultyResponse, error) {
+	ctx, span := startSpan(ctx, "GetFaculty")
+	defer span.End()
+	result, err := s.repo.GetFaculty(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.Faculty, domain.FacultyResponse](result), nil
+}
+
+func (s *Service) UpdateFaculty(ctx context.Context, id string, req *domain.UpdateFacultyRequest) (*domain.FacultyResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateFaculty")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required faculty id")
+	}
+	before, err := s.repo.GetFaculty(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.UpdateFaculty(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "faculty",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s faculty details.", result.Name),
+		Before:     before,
+		After:      result,
+	})
+	return domain.Convert[domain.Faculty, domain.FacultyResponse](result), nil
+}
+
+// DeleteFaculty deletes a Faculty that has no programs left
+func (s *Service) DeleteFaculty(ctx context.Context, id string) (*domain.FacultyResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteFaculty")
+	defer span.End()
+	result, err := s.repo.GetFaculty(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	programs, err := s.repo.CountFacultyPrograms(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if programs > 0 {
+		return nil, domain.NewConflictError("faculty_in_use", fmt.Sprintf("faculty has %d programs cannot delete it", programs))
+	}
+	if err := s.repo.DeleteFaculty(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "faculty",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s faculty.", result.Name),
+		Before:     result,
+	})
+	return domain.Convert[domain.Faculty, domain.FacultyResponse](result), nil
+}
@@ -1,29 +1,55 @@
 package service
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
 // CreateFine creates a new Fine
-func (s *Service) CreateFine(req *domain.FineRequest) (*domain.FineResponse, error) {
+func (s *Service) CreateFine(ctx context.Context, req *domain.FineRequest) (*domain.FineResponse, error) {
+	ctx, span := startSpan(ctx, "CreateFine")
+	defer span.End()
 	err := req.Validate()
 	if err != nil {
 		return nil, err
 	}
 	data := domain.Convert[domain.FineRequest, domain.Fine](req)
-	result, err := s.repo.CreateFine(data)
+	data.IssuedBy, _ = getUserID(ctx)
+	result, err := s.repo.CreateFine(ctx, data)
 	if err != nil {
 		return nil, err
 	}
-	return domain.Convert[domain.Fine, domain.FineResponse](result), nil
+	amount := fmt.Sprintf("%.2f", float64(result.Amount)/100)
+	s.notify(ctx, notice{
+		UserID:      result.UserID,
+		Event:       domain.EventFineIssued,
+		Title:       fmt.Sprintf("A fine of Rs. %s has been issued: %s", amount, result.Reason),
+		ReferenceID: result.ID,
+		Data:        messageData{Amount: amount, Reason: result.Reason},
+	})
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "fine",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Issued a fine of Rs. %s: %s", amount, result.Reason),
+		After:      result,
+	})
+	actorID, _ := getUserID(ctx)
+	response := domain.Convert[domain.Fine, domain.FineResponse](result)
+	s.emit(domain.EventTypeFineIssued, actorID, response)
+	return response, nil
 }
 
 // ListFines retrieves a list of Fines
-func (s *Service) ListFine(req *domain.ListFineRequest) ([]*domain.FineResponse, int64, error) {
+func (s *Service) ListFine(ctx context.Context, req *domain.ListFineRequest) ([]*domain.FineResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListFine")
+	defer span.End()
 	var datas = []*domain.FineResponse{}
-	results, count, err := s.repo.ListFine(req)
+	results, count, err := s.repo.ListFine(ctx, req)
 	if err != nil {
 		return nil, count, err
 	}
@@ -34,8 +60,10 @@ func (s *Service) ListFine(req *domain.ListFineRequest) ([]*domain.FineResponse,
 	return datas, count, nil
 }
 
-func (s *Service) GetFine(id string) (*domain.FineResponse, error) {
-	result, err := s.repo.GetFine(id)
+func (s *Service) GetFine(ctx context.Context, id string) (*domain.FineResponse, error) {
+	ctx, span := startSpan(ctx, "GetFine")
+	defer span.End()
+	result, err := s.repo.GetFine(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -43,33 +71,139 @@ func (s *Service) GetFine(id string) (*domain.FineResponse, error) {
 	return data, nil
 }
 
-func (s *Service) UpdateFine(id string, req *domain.UpdateFineRequest) (*domain.FineResponse, error) {
+func (s *Service) UpdateFine(ctx context.Context, id string, req *domain.UpdateFineRequest) (*domain.FineResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateFine")
+	defer span.End()
 	if id == "" {
-		return nil, errors.New("required Fine id")
+		return nil, domain.NewValidationError("id_required", "required Fine id")
 	}
-	_, err := s.repo.GetFine(id)
+	before, err := s.repo.GetFine(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	// update
 	mp := req.NewUpdate()
-	result, err := s.repo.UpdateFine(id, mp)
+	result, err := s.repo.UpdateFine(ctx, id, mp)
 	if err != nil {
 		return nil, err
 	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "fine",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated fine %s details.", result.ID),
+		Before:     before,
+		After:      result,
+	})
+	actorID, _ := getUserID(ctx)
 	data := domain.Convert[domain.Fine, domain.FineResponse](result)
+	s.emit(domain.EventTypeFineUpdated, actorID, data)
 	return data, nil
 }
 
-func (s *Service) DeleteFine(id string) (*domain.FineResponse, error) {
-	result, err := s.repo.GetFine(id)
+func (s *Service) DeleteFine(ctx context.Context, id string) (*domain.FineResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteFine")
+	defer span.End()
+	result, err := s.repo.GetFine(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	err = s.repo.DeleteFine(id)
+	err = s.repo.DeleteFine(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return domain.Convert[domain.Fine, domain.FineResponse](result), nil
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "fine",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted fine %s.", result.ID),
+		Before:     result,
+	})
+	actorID, _ := getUserID(ctx)
+	response := domain.Convert[domain.Fine, domain.FineResponse](result)
+	s.emit(domain.EventTypeFineDeleted, actorID, response)
+	return response, nil
+}
+
+// fineCashier loads the caller and makes sure they are library staff who may take and waive fines
+func (s *Service) fineCashier(ctx context.Context) (*domain.NotificationRecipient, error) {
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(recipient.Roles, domain.RoleAdmin) && !slices.Contains(recipient.Roles, domain.RoleLibrarian) {
+		return nil, domain.NewForbiddenError("not_allowed", "only an admin or librarian can collect or waive fines")
+	}
+	return recipient, nil
+}
+
+// PayFine settles a pending fine in full, the signed in staff member is recorded as the collector
+// and the payment gets a receipt number
+func (s *Service) PayFine(ctx context.Context, id string) (*domain.FineResponse, error) {
+	ctx, span := startSpan(ctx, "PayFine")
+	defer span.End()
+	cashier, err := s.fineCashier(ctx)
+	if err != nil {
+		return nil, err
+	}
+	actorID := cashier.UserID
+	before, err := s.repo.GetFine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if before.Status != domain.FinePending {
+		return nil, domain.NewConflictError("fine_not_pending", fmt.Sprintf("fine is already %s", before.Status))
+	}
+	result, err := s.repo.PayFine(ctx, id, actorID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "fine",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Collected Rs. %.2f for fine %s, receipt %s", float64(result.Amount)/100, result.ID, *result.ReceiptNumber),
+		Before:     before,
+		After:      result,
+	})
+	response := domain.Convert[domain.Fine, domain.FineResponse](result)
+	s.emit(domain.EventTypeFinePaid, actorID, response)
+	return response, nil
+}
+
+// WaiveFine writes a pending fine off
+func (s *Service) WaiveFine(ctx context.Context, id string, req *domain.WaiveFineRequest) (*domain.FineResponse, error) {
+	ctx, span := startSpan(ctx, "WaiveFine")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	cashier, err := s.fineCashier(ctx)
+	if err != nil {
+		return nil, err
+	}
+	actorID := cashier.UserID
+	before, err := s.repo.GetFine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if before.Status != domain.FinePending {
+		return nil, domain.NewConflictError("fine_not_pending", fmt.Sprintf("fine is already %s", before.Status))
+	}
+	result, err := s.repo.WaiveFine(ctx, id, actorID, req.Reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "fine",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Waived Rs. %.2f of fine %s: %s", float64(result.Amount)/100, result.ID, req.Reason),
+		Before:     before,
+		After:      result,
+	})
+	response := domain.Convert[domain.Fine, domain.FineResponse](result)
+	s.emit(domain.EventTypeFineWaived, actorID, response)
+	return response, nil
 }
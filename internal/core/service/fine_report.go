@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// GetFineCollection reports the fines issued, collected and waived in the period with their total
+func (s *Service) GetFineCollection(ctx context.Context, req *domain.FineCollectionRequest) (*domain.FineCollectionReport, error) {
+	ctx, span := startSpan(ctx, "GetFineCollection")
+	defer span.End()
+	rows, err := s.repo.GetFineCollection(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.FineCollectionReport{GroupBy: req.GroupBy, Rows: rows}
+	if result.Rows == nil {
+		result.Rows = []domain.FineCollection{}
+	}
+	for _, row := range result.Rows {
+		result.Total.Issued += row.Issued
+		result.Total.IssuedAmount += row.IssuedAmount
+		result.Total.Collected += row.Collected
+		result.Total.CollectedAmount += row.CollectedAmount
+		result.Total.Waived += row.Waived
+		result.Total.WaivedAmount += row.WaivedAmount
+	}
+	return result, nil
+}
+
+// GetFineAging splits the outstanding fines into age buckets, every bucket is reported even when
+// it is empty
+func (s *Service) GetFineAging(ctx context.Context) (*domain.FineAging, error) {
+	ctx, span := startSpan(ctx, "GetFineAging")
+	defer span.End()
+	buckets, err := s.repo.GetFineAging(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.FineAging{}
+	for _, name := range []string{domain.FineAge0To30, domain.FineAge31To60, domain.FineAgeOver60} {
+		bucket := domain.FineAgingBucket{Bucket: name}
+		for _, b := range buckets {
+			if b.Bucket == name {
+				bucket = b
+			}
+		}
+		result.Buckets = append(result.Buckets, bucket)
+		result.Fines += bucket.Fines
+		result.Outstanding += bucket.Amount
+	}
+	return result, nil
+}
+
+func (s *Service) ListStudentFineAging(ctx context.Context, req *domain.ListRequest) ([]*domain.StudentFineAging, int64, error) {
+	ctx, span := startSpan(ctx, "ListStudentFineAging")
+	defer span.End()
+	results, count, err := s.repo.ListStudentFineAging(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	if results == nil {
+		results = []*domain.StudentFineAging{}
+	}
+	return results, count, nil
+}
+
+// GetFineStatement lists a student's fines, payments and waivers in the period with the running
+// balance, what happened before the period is carried in as the opening balance
+func (s *Service) GetFineStatement(ctx context.Context, req *domain.FineStatementRequest) (*domain.FineStatement, error) {
+	ctx, span := startSpan(ctx, "GetFineStatement")
+	defer span.End()
+	user, err := s.repo.GetUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repo.ListFineEvents(ctx, req.UserID, req.To)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.FineStatement{
+		UserID:    user.ID,
+		FullName:  user.FullName,
+		Username:  user.Username,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Lines:     []domain.FineStatementLine{},
+	}
+	balance := int64(0)
+	for _, event := range events {
+		line := domain.FineStatementLine{
+			Date:          event.At,
+			FineID:        event.FineID,
+			Entry:         event.Entry,
+			Reason:        event.Reason,
+			ReceiptNumber: event.ReceiptNumber,
+		}
+		if event.Entry == domain.FineEntryIssued {
+			line.Debit = event.Amount
+		} else {
+			line.Credit = event.Amount
+		}
+		balance += line.Debit - line.Credit
+		line.Balance = balance
+		if event.At.Before(req.From) {
+			result.OpeningBalance = balance
+			continue
+		}
+		switch event.Entry {
+		case domain.FineEntryIssued:
+			result.Issued += event.Amount
+		case domain.FineEntryPaid:
+			result.Paid += event.Amount
+		case domain.FineEntryWaived:
+			result.Waived += event.Amount
+		}
+		result.Lines = append(result.Lines, line)
+	}
+	result.ClosingBalance = balance
+	return result, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateFloor creates a new Floor under an existing Building
+func (s *Service) CreateFloor(ctx context.Context, req *domain.FloorRequest) (*domain.FloorResponse, error) {
+	ctx, span := startSpan(ctx, "CreateFloor")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	building, err := s.repo.GetBuilding(ctx, req.BuildingID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("building_not_found", fmt.Sprintf("building %s not found", req.BuildingID))
+	}
+	data := domain.Convert[domain.FloorRequest, domain.Floor](req)
+	result, err := s.repo.CreateFloor(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "floor",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created floor %d in building %s.", result.FloorNumber, building.Name),
+		After:      result,
+	})
+	return domain.Convert[domain.Floor, domain.FloorResponse](result), nil
+}
+
+// ListFloor retrieves a list of Floors
+func (s *Service) ListFloor(ctx context.Context, req *domain.ListFloorRequest) ([]*domain.FloorResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListFloor")
+	defer span.End()
+	var datas = []*domain.FloorResponse{}
+	results, count, err := s.repo.ListFloor(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.Floor, domain.FloorResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetFloor(ctx context.Context, id string) (*domain.FloorResponse, error) {
+	ctx, span := startSpan(ctx, "GetFloor")
+	defer span.End()
+	result, err := s.repo.GetFloor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.Floor, domain.FloorResponse](result), nil
+}
+
+func (s *Service) UpdateFloor(ctx context.Context, id string, req *domain.UpdateFloorRequest) (*domain.FloorResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateFloor")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required floor id")
+	}
+	before, err := s.repo.GetFloor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.BuildingID != "" {
+		if _, err := s.repo.GetBuilding(ctx, req.BuildingID); err != nil {
+			return nil, domain.NewNotFoundError("building_not_found", fmt.Sprintf("building %s not found", req.BuildingID))
+		}
+	}
+	result, err := s.repo.UpdateFloor(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "floor",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated floor %d details.", result.FloorNumber),
+		Before:     before,
+		After:      result,
+	})
+	return domain.Convert[domain.Floor, domain.FloorResponse](result), nil
+}
+
+// DeleteFloor deletes a Floor that has no rooms left
+func (s *Service) DeleteFloor(ctx context.Context, id string) (*domain.FloorResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteFloor")
+	defer span.End()
+	result, err := s.repo.GetFloor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := s.repo.CountFloorRooms(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rooms > 0 {
+		return nil, domain.NewConflictError("floor_in_use", fmt.Sprintf("floor has %d rooms cannot delete it", rooms))
+	}
+	if err := s.repo.DeleteFloor(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "floor",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted floor %d.", result.FloorNumber),
+		Before:     result,
+	})
+	return domain.Convert[domain.Floor, domain.FloorResponse](result), nil
+}
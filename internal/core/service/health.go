@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (s *Service) Ping(ctx context.Context) error {
+	return s.repo.Ping(ctx)
+}
+
+func (s *Service) GetLoanGauges(ctx context.Context) (*domain.LoanGauges, error) {
+	return s.repo.GetLoanGauges(ctx)
+}
+
+// runJob runs one pass of a background job in its own trace and records how long it took
+func (s *Service) runJob(ctx context.Context, name string, job func(context.Context)) {
+	ctx, span := tracer.Start(ctx, "job."+name)
+	defer span.End()
+	start := time.Now()
+	job(ctx)
+	s.jobs.ObserveJob(name, time.Since(start))
+}
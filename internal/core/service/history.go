@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// canViewHistory tells whether the recipient may read the timeline of other users' records
+func canViewHistory(recipient *domain.NotificationRecipient) bool {
+	return slices.Contains(recipient.Roles, domain.RoleAdmin) ||
+		slices.Contains(recipient.Roles, domain.RoleLibrarian) ||
+		slices.Contains(recipient.Roles, domain.RoleDirector)
+}
+
+// historyReader loads the caller and makes sure they may read the timeline, patrons may read
+// their own with ownerID set to themselves
+func (s *Service) historyReader(ctx context.Context, ownerID string) error {
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return err
+	}
+	if !canViewHistory(recipient) && (ownerID == "" || ownerID != recipient.UserID) {
+		return domain.NewForbiddenError("not_allowed", "you are not allowed to view this history")
+	}
+	return nil
+}
+
+func prepareHistory(req *domain.ListHistoryRequest) {
+	if req.SortDirection != "desc" {
+		req.SortDirection = "asc"
+	}
+	req.Prepare()
+}
+
+// ListEntityHistory lists every change made to the entity, oldest first unless asked otherwise
+func (s *Service) ListEntityHistory(ctx context.Context, entityType, entityID string, req *domain.ListHistoryRequest) ([]*domain.HistoryEntry, int64, error) {
+	ctx, span := startSpan(ctx, "ListEntityHistory")
+	defer span.End()
+	if entityID == "" {
+		return nil, 0, domain.NewValidationError("id_required", "required id")
+	}
+	ownerID := ""
+	if entityType == domain.HistoryUser {
+		ownerID = entityID
+	}
+	if err := s.historyReader(ctx, ownerID); err != nil {
+		return nil, 0, err
+	}
+	prepareHistory(req)
+	results, count, err := s.repo.ListEntityHistory(ctx, entityType, entityID, req)
+	if err != nil {
+		return nil, count, err
+	}
+	datas, err := s.historyEntries(ctx, results, false)
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+// ListPatronHistory lists the patron's loans, returns, fines and payments together
+func (s *Service) ListPatronHistory(ctx context.Context, userID string, req *domain.ListHistoryRequest) ([]*domain.HistoryEntry, int64, error) {
+	ctx, span := startSpan(ctx, "ListPatronHistory")
+	defer span.End()
+	if userID == "" {
+		return nil, 0, domain.NewValidationError("id_required", "required user id")
+	}
+	if err := s.historyReader(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+	prepareHistory(req)
+	results, count, err := s.repo.ListPatronHistory(ctx, userID, req)
+	if err != nil {
+		return nil, count, err
+	}
+	datas, err := s.historyEntries(ctx, results, true)
+	if err != nil {
+		return nil, count, err
+	}
+	return datas, count, nil
+}
+
+// historyEntries turns audit log entries into timeline entries, looking the actors up in one go
+func (s *Service) historyEntries(ctx context.Context, results []*domain.AuditLog, patron bool) ([]*domain.HistoryEntry, error) {
+	var ids []string
+	for _, result := range results {
+		if result.UserID != nil && !slices.Contains(ids, *result.UserID) {
+			ids = append(ids, *result.UserID)
+		}
+	}
+	users, err := s.repo.ListUserByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	actors := map[string]*domain.User{}
+	for _, user := range users {
+		actors[user.ID] = user
+	}
+
+	var datas = []*domain.HistoryEntry{}
+	for _, result := range results {
+		data := &domain.HistoryEntry{
+			ID:         result.ID,
+			Sequence:   result.Sequence,
+			At:         result.CreatedAt,
+			Action:     result.Action,
+			EntityType: result.EntityType,
+			EntityID:   result.EntityID,
+			Title:      result.Title,
+			Actor:      domain.HistoryActor{Username: result.PerformedBy},
+			Changes:    result.Changes,
+			RequestID:  result.RequestID,
+		}
+		if result.UserID != nil {
+			data.Actor.ID = *result.UserID
+			if user, ok := actors[*result.UserID]; ok {
+				data.Actor.Username, data.Actor.FullName = user.Username, user.FullName
+			}
+		}
+		if patron {
+			data.Event = domain.PatronEvent(result)
+		}
+		datas = append(datas, data)
+	}
+	return datas, nil
+}
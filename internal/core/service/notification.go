@@ -1,48 +1,114 @@
 package service
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"slices"
 
 	"github.com/sugaml/lms-api/internal/core/domain"
 )
 
+// recipient loads who the caller is as a notification recipient
+func (s *Service) recipient(ctx context.Context) (*domain.NotificationRecipient, error) {
+	getUserID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.GetUser(ctx, getUserID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.NotificationRecipient{
+		UserID: user.ID,
+		Roles:  user.RoleNames(),
+		Since:  user.CreatedAt,
+	}, nil
+}
+
+// canManageNotification tells whether the recipient may change a notification beyond their own
+func canManageNotification(recipient *domain.NotificationRecipient) bool {
+	return slices.Contains(recipient.Roles, domain.RoleAdmin) || slices.Contains(recipient.Roles, domain.RoleLibrarian)
+}
+
 // CreateNotification creates a new Notification
-func (s *Service) CreateNotification(req *domain.NotificationRequest) (*domain.NotificationResponse, error) {
-	err := req.Validate()
+func (s *Service) CreateNotification(ctx context.Context, req *domain.NotificationRequest) (*domain.NotificationResponse, error) {
+	ctx, span := startSpan(ctx, "CreateNotification")
+	defer span.End()
+	recipient, err := s.recipient(ctx)
 	if err != nil {
 		return nil, err
 	}
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+	// anyone else can only leave a note for themselves
+	if !canManageNotification(recipient) && (req.Audience != domain.AudienceUser || req.UserID != recipient.UserID) {
+		return nil, domain.NewForbiddenError("not_allowed", "only an admin or librarian can notify other users, a role or everyone")
+	}
 	data := domain.Convert[domain.NotificationRequest, domain.Notification](req)
-	result, err := s.repo.CreateNotification(data)
+	data.Action = "create"
+	data.IsActive = true
+	result, err := s.repo.CreateNotification(ctx, data)
 	if err != nil {
 		return nil, err
 	}
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Created new Notification %s.", result.Title),
-		Action:   "create",
-		Data:     string(domain.ConvertToJson(result)),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "notification",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new Notification %s.", result.Title),
+		After:      result,
 	})
 	return domain.Convert[domain.Notification, domain.NotificationResponse](result), nil
 }
 
-// ListNotifications retrieves a list of Notifications
-func (s *Service) ListNotification(req *domain.ListNotificationRequest) ([]*domain.NotificationResponse, int64, error) {
+// ListNotification retrieves the caller's Notifications and how many of them are unread
+func (s *Service) ListNotification(ctx context.Context, req *domain.ListNotificationRequest) ([]*domain.NotificationResponse, int64, int64, error) {
+	ctx, span := startSpan(ctx, "ListNotification")
+	defer span.End()
 	var datas = []*domain.NotificationResponse{}
-	results, count, err := s.repo.ListNotification(req)
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	results, count, err := s.repo.ListNotification(ctx, recipient, req)
+	if err != nil {
+		return nil, count, 0, err
+	}
+	unread, err := s.repo.CountUnreadNotification(ctx, recipient)
 	if err != nil {
-		return nil, count, err
+		return nil, count, 0, err
 	}
 	for _, result := range results {
 		data := domain.Convert[domain.Notification, domain.NotificationResponse](result)
 		datas = append(datas, data)
 	}
-	return datas, count, nil
+	return datas, count, unread, nil
 }
 
-func (s *Service) GetNotification(id string) (*domain.NotificationResponse, error) {
-	result, err := s.repo.GetNotification(id)
+func (s *Service) CountUnreadNotification(ctx context.Context) (*domain.UnreadNotificationCount, error) {
+	ctx, span := startSpan(ctx, "CountUnreadNotification")
+	defer span.End()
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnreadNotification(ctx, recipient)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UnreadNotificationCount{Unread: unread}, nil
+}
+
+func (s *Service) GetNotification(ctx context.Context, id string) (*domain.NotificationResponse, error) {
+	ctx, span := startSpan(ctx, "GetNotification")
+	defer span.End()
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetRecipientNotification(ctx, id, recipient)
 	if err != nil {
 		return nil, err
 	}
@@ -50,64 +116,135 @@ func (s *Service) GetNotification(id string) (*domain.NotificationResponse, erro
 	return data, nil
 }
 
-func (s *Service) UpdateNotification(id string, req *domain.UpdateNotificationRequest) (*domain.NotificationResponse, error) {
+func (s *Service) UpdateNotification(ctx context.Context, id string, req *domain.UpdateNotificationRequest) (*domain.NotificationResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateNotification")
+	defer span.End()
 	if id == "" {
-		return nil, errors.New("required Notification id")
+		return nil, domain.NewValidationError("id_required", "required Notification id")
+	}
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
 	}
-	_, err := s.repo.GetNotification(id)
+	if !canManageNotification(recipient) {
+		return nil, domain.NewForbiddenError("not_allowed", "only an admin or librarian can update notifications")
+	}
+	before, err := s.repo.GetNotification(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	// update
 	mp := req.NewUpdate()
-	result, err := s.repo.UpdateNotification(id, mp)
+	result, err := s.repo.UpdateNotification(ctx, id, mp)
 	if err != nil {
 		return nil, err
 	}
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Updated %s Notification details.", result.Title),
-		Action:   "update",
-		Data:     fmt.Sprint(req),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "notification",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s Notification details.", result.Title),
+		Before:     before,
+		After:      result,
 	})
 	data := domain.Convert[domain.Notification, domain.NotificationResponse](result)
 	return data, nil
 }
 
-func (s *Service) ReadAllNotification() (*domain.NotificationResponse, error) {
-	mp := map[string]interface{}{"is_read": true}
-	result, err := s.repo.ReadAllNotification(mp)
+// ReadNotification marks one of the caller's notifications as read by them
+func (s *Service) ReadNotification(ctx context.Context, id string) (*domain.ReadNotificationResponse, error) {
+	ctx, span := startSpan(ctx, "ReadNotification")
+	defer span.End()
+	return s.markNotification(ctx, id, true)
+}
+
+// UnreadNotification marks one of the caller's notifications as not read by them
+func (s *Service) UnreadNotification(ctx context.Context, id string) (*domain.ReadNotificationResponse, error) {
+	ctx, span := startSpan(ctx, "UnreadNotification")
+	defer span.End()
+	return s.markNotification(ctx, id, false)
+}
+
+func (s *Service) markNotification(ctx context.Context, id string, read bool) (*domain.ReadNotificationResponse, error) {
+	recipient, err := s.recipient(ctx)
 	if err != nil {
 		return nil, err
 	}
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    "Read all Notifications.",
-		Action:   "read_all",
-		Data:     fmt.Sprint(result),
-		IsActive: true,
-	})
-	if result == nil {
-		return nil, errors.New("no unread notifications found")
+	result, err := s.repo.GetRecipientNotification(ctx, id, recipient)
+	if err != nil {
+		return nil, err
 	}
-	data := domain.Convert[domain.Notification, domain.NotificationResponse](result)
-	return data, nil
+	res := &domain.ReadNotificationResponse{}
+	if result.IsRead != read {
+		if read {
+			err = s.repo.MarkNotificationRead(ctx, id, recipient.UserID)
+		} else {
+			err = s.repo.MarkNotificationUnread(ctx, id, recipient.UserID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		res.Marked = 1
+	}
+	res.Unread, err = s.repo.CountUnreadNotification(ctx, recipient)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-func (s *Service) DeleteNotification(id string) (*domain.NotificationResponse, error) {
-	result, err := s.repo.GetNotification(id)
+// ReadAllNotification marks everything addressed to the caller as read by them
+func (s *Service) ReadAllNotification(ctx context.Context) (*domain.ReadNotificationResponse, error) {
+	ctx, span := startSpan(ctx, "ReadAllNotification")
+	defer span.End()
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	marked, err := s.repo.ReadAllNotification(ctx, recipient)
 	if err != nil {
 		return nil, err
 	}
-	err = s.repo.DeleteNotification(id)
+	if marked == 0 {
+		return &domain.ReadNotificationResponse{}, nil
+	}
+	s.audit(ctx, auditEntry{
+		Action:     "read_all",
+		EntityType: "notification",
+		Title:      fmt.Sprintf("Read all %d Notifications.", marked),
+		Data:       domain.Map{"marked": marked},
+	})
+	return &domain.ReadNotificationResponse{Marked: marked}, nil
+}
+
+// DeleteNotification removes one of the caller's own notifications, an admin or librarian can
+// also remove role and broadcast notifications
+func (s *Service) DeleteNotification(ctx context.Context, id string) (*domain.NotificationResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteNotification")
+	defer span.End()
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetNotification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	own := result.Audience == domain.AudienceUser && result.UserID == recipient.UserID
+	if !own && !canManageNotification(recipient) {
+		return nil, domain.NewNotFoundError("notification_not_found", "notification not found")
+	}
+	err = s.repo.DeleteNotification(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Deleted %s parking area.", result.Title),
-		Action:   "delete",
-		Data:     fmt.Sprint(result),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "notification",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s notification.", result.Title),
+		Before:     result,
 	})
 	return domain.Convert[domain.Notification, domain.NotificationResponse](result), nil
 }
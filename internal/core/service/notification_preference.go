@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// ListNotificationPreference returns every event and channel for the caller with the saved
+// preference or, when there is none, the default of their role
+func (s *Service) ListNotificationPreference(ctx context.Context) ([]*domain.NotificationPreferenceResponse, error) {
+	ctx, span := startSpan(ctx, "ListNotificationPreference")
+	defer span.End()
+	getUserID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.GetUser(ctx, getUserID)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.repo.ListUserNotificationPreference(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	saved := map[string]*domain.NotificationPreference{}
+	for _, pref := range prefs {
+		saved[pref.EventType+"/"+pref.Channel] = pref
+	}
+	role := userRole(user)
+	var datas = []*domain.NotificationPreferenceResponse{}
+	for _, event := range domain.NotificationEvents {
+		defaults := event.DefaultChannels(role)
+		for _, channel := range domain.NotificationChannels {
+			data := &domain.NotificationPreferenceResponse{
+				EventType:   event.Type,
+				Description: event.Description,
+				Channel:     channel,
+				IsDefault:   true,
+			}
+			for _, c := range defaults {
+				data.Enabled = data.Enabled || c == channel
+			}
+			if pref, ok := saved[event.Type+"/"+channel]; ok {
+				data.Enabled = pref.Enabled
+				data.Target = pref.Target
+				data.IsDefault = false
+				data.UpdatedAt = &pref.UpdatedAt
+			}
+			datas = append(datas, data)
+		}
+	}
+	return datas, nil
+}
+
+// UpdateNotificationPreference saves the given event and channel settings for the caller, the
+// ones left out keep their current value
+func (s *Service) UpdateNotificationPreference(ctx context.Context, req *domain.UpdateNotificationPreferenceRequest) ([]*domain.NotificationPreferenceResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateNotificationPreference")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	getUserID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var datas []*domain.NotificationPreference
+	for _, p := range req.Preferences {
+		datas = append(datas, &domain.NotificationPreference{
+			UserID:    getUserID,
+			EventType: p.EventType,
+			Channel:   p.Channel,
+			Enabled:   p.Enabled,
+			Target:    p.Target,
+		})
+	}
+	if err := s.repo.SaveNotificationPreferences(ctx, datas); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "notification_preference",
+		EntityID:   getUserID,
+		Title:      fmt.Sprintf("Updated %d notification preferences", len(datas)),
+		Data:       req,
+	})
+	return s.ListNotificationPreference(ctx)
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const (
+	// notificationStreamBatch caps how many missed notifications are replayed per sync
+	notificationStreamBatch = 100
+	notificationStreamQueue = 16
+)
+
+// notificationCursor turns a creation time into the SSE event id, microseconds keep it ordered
+// and let a reconnecting client resume with Last-Event-ID
+func notificationCursor(t time.Time) string {
+	return strconv.FormatInt(t.UnixMicro(), 10)
+}
+
+func parseNotificationCursor(id string) (time.Time, bool) {
+	micro, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(micro), true
+}
+
+// StreamNotification subscribes before loading anything so no change is missed between the
+// replay and the live signals, every signal triggers a sync from the last event sent
+func (s *Service) StreamNotification(ctx context.Context, lastEventID string) (<-chan *domain.NotificationStreamEvent, error) {
+	ctx, span := startSpan(ctx, "StreamNotification")
+	defer span.End()
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	since, ok := parseNotificationCursor(lastEventID)
+	if !ok {
+		since = time.Now()
+	}
+	signals, unsubscribe := s.notificationHub.Subscribe(recipient.UserID)
+	events := make(chan *domain.NotificationStreamEvent, notificationStreamQueue)
+
+	go func() {
+		defer close(events)
+		defer unsubscribe()
+		unread := int64(-1)
+		catchUp := func() bool {
+			for {
+				results, err := s.repo.ListNotificationSince(ctx, recipient, since, notificationStreamBatch)
+				if err != nil {
+					logrus.WithError(err).Warnf("could not load notifications for stream of user %s", recipient.UserID)
+					break
+				}
+				for _, result := range results {
+					since = result.CreatedAt
+					event := &domain.NotificationStreamEvent{
+						ID:    notificationCursor(result.CreatedAt),
+						Event: "notification",
+						Data:  domain.Convert[domain.Notification, domain.NotificationResponse](result),
+					}
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return false
+					}
+				}
+				if len(results) < notificationStreamBatch {
+					break
+				}
+			}
+			count, err := s.repo.CountUnreadNotification(ctx, recipient)
+			if err != nil || count == unread {
+				return true
+			}
+			unread = count
+			select {
+			case events <- &domain.NotificationStreamEvent{Event: "unread", Data: &domain.UnreadNotificationCount{Unread: count}}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if !catchUp() {
+			return
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				if !catchUp() {
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
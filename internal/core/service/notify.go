@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const (
+	dueReminderWindow = 24 * time.Hour
+	messageDateLayout = "Mon, 02 Jan 2006"
+	// noticeQueueCapacity is how many notices wait for a dispatcher before notify blocks
+	noticeQueueCapacity = 1024
+	noticeDispatchers   = 4
+)
+
+// messageData is the data available to every email and SMS template
+type messageData struct {
+	Name            string
+	Username        string
+	BookTitle       string
+	AccessionNumber string
+	DueDate         string
+	Amount          string
+	Reason          string
+}
+
+func borrowMessageData(borrow *domain.BorrowedBook) messageData {
+	data := messageData{DueDate: borrow.DueDate.Format(messageDateLayout)}
+	if borrow.BookCopy != nil {
+		data.AccessionNumber = borrow.BookCopy.AccessionNumber
+		if borrow.BookCopy.Book != nil {
+			data.BookTitle = borrow.BookCopy.Book.Title
+		}
+	}
+	return data
+}
+
+// notice is one notification for one user, a ReferenceID names the record it is about and makes
+// sure the event is sent for that record only once
+type notice struct {
+	UserID      string
+	Event       string
+	Title       string
+	Description string
+	ReferenceID string
+	Data        messageData
+}
+
+// webhookNotice is the JSON posted to a user's webhook channel
+type webhookNotice struct {
+	Event       string    `json:"event"`
+	UserID      string    `json:"user_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ReferenceID string    `json:"reference_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// queuedNotice keeps the trace of the request that sent the notice
+type queuedNotice struct {
+	ctx context.Context
+	notice
+}
+
+// notify queues a notice for RunNotifier so callers never wait on preferences or delivery
+func (s *Service) notify(ctx context.Context, n notice) {
+	s.notices <- queuedNotice{ctx: detach(ctx), notice: n}
+}
+
+// RunNotifier dispatches the queued notices until ctx is done, then dispatches the ones still
+// queued so that a shutdown does not drop them
+func (s *Service) RunNotifier(ctx context.Context) {
+	var dispatchers sync.WaitGroup
+	for range noticeDispatchers {
+		dispatchers.Add(1)
+		go func() {
+			defer dispatchers.Done()
+			for {
+				select {
+				case q := <-s.notices:
+					s.dispatchQueued(q)
+				case <-ctx.Done():
+					for {
+						select {
+						case q := <-s.notices:
+							s.dispatchQueued(q)
+						default:
+							return
+						}
+					}
+				}
+			}
+		}()
+	}
+	dispatchers.Wait()
+}
+
+func (s *Service) dispatchQueued(q queuedNotice) {
+	if err := s.dispatch(q.ctx, q.notice); err != nil {
+		logrus.WithError(err).Warnf("could not dispatch %s to user %s", q.Event, q.UserID)
+	}
+}
+
+// notifyRole shows a notification in the app of everyone with the role, role notifications are
+// not subject to personal preferences
+func (s *Service) notifyRole(ctx context.Context, role, eventType, title string) {
+	event, err := domain.GetNotificationEvent(eventType)
+	if err != nil {
+		logrus.WithError(err).Warnf("could not notify role %s", role)
+		return
+	}
+	_, err = s.repo.CreateNotification(ctx, &domain.Notification{
+		Audience:    domain.AudienceRole,
+		Role:        role,
+		Title:       title,
+		Description: event.Description,
+		Module:      event.Module,
+		Action:      event.Action,
+		Type:        event.Type,
+		IsActive:    true,
+	})
+	if err != nil {
+		logrus.WithError(err).Warnf("could not notify role %s of %s", role, event.Type)
+	}
+}
+
+// dispatch delivers a notice on every channel the user's preferences, or their role defaults,
+// enable for the event
+func (s *Service) dispatch(ctx context.Context, n notice) error {
+	event, err := domain.GetNotificationEvent(n.Event)
+	if err != nil {
+		return err
+	}
+	user, err := s.repo.GetUser(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+	channels, err := s.userChannels(ctx, user, event)
+	if err != nil {
+		return err
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+	if n.ReferenceID != "" {
+		claimed, err := s.repo.ClaimNotificationDispatch(ctx, &domain.NotificationDispatch{
+			UserID:      user.ID,
+			EventType:   event.Type,
+			ReferenceID: n.ReferenceID,
+		})
+		if err != nil || !claimed {
+			return err
+		}
+	}
+	if n.Description == "" {
+		n.Description = event.Description
+	}
+	if n.Data.Name == "" {
+		n.Data.Name = user.FullName
+	}
+	if n.Data.Username == "" {
+		n.Data.Username = user.Username
+	}
+
+	for channel, target := range channels {
+		var err error
+		switch channel {
+		case domain.ChannelInApp:
+			_, err = s.repo.CreateNotification(ctx, &domain.Notification{
+				Audience:    domain.AudienceUser,
+				UserID:      user.ID,
+				Title:       n.Title,
+				Description: n.Description,
+				Module:      event.Module,
+				Action:      event.Action,
+				Type:        event.Type,
+				IsActive:    true,
+			})
+		case domain.ChannelEmail:
+			if _, ok := emailTemplates[event.Type]; ok {
+				err = s.enqueueEmail(ctx, user, event.Type, n.ReferenceID, n.Data)
+			}
+		case domain.ChannelSMS:
+			// SMS costs money, the organisation decides which events may use it at all
+			if _, ok := smsTemplates[event.Type]; ok && s.smsPolicy.Routes(event.Type) {
+				err = s.enqueueSMS(ctx, user, event.Type, n.ReferenceID, n.Data)
+			}
+		case domain.ChannelWebhook:
+			err = s.enqueueUserWebhook(ctx, target, user, n)
+		}
+		if err != nil {
+			logrus.WithError(err).Warnf("could not deliver %s to user %s on %s", event.Type, user.ID, channel)
+		}
+	}
+	return nil
+}
+
+// userChannels returns the enabled channels of an event for a user with the webhook target as
+// value, saved preferences win over the role defaults
+func (s *Service) userChannels(ctx context.Context, user *domain.User, event *domain.NotificationEvent) (map[string]string, error) {
+	channels := map[string]string{}
+	for _, channel := range event.DefaultChannels(userRole(user)) {
+		channels[channel] = ""
+	}
+	prefs, err := s.repo.ListUserNotificationPreference(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pref := range prefs {
+		if pref.EventType != event.Type {
+			continue
+		}
+		if pref.Enabled {
+			channels[pref.Channel] = pref.Target
+		} else {
+			delete(channels, pref.Channel)
+		}
+	}
+	// a webhook can only be delivered once the user has given a target
+	if channels[domain.ChannelWebhook] == "" {
+		delete(channels, domain.ChannelWebhook)
+	}
+	return channels, nil
+}
+
+func userRole(user *domain.User) string {
+	if len(user.Roles) == 0 {
+		return ""
+	}
+	return strings.ToLower(user.Roles[0].Name)
+}
+
+// enqueueUserWebhook queues the notice for the webhook worker, which retries it like the
+// deliveries of a subscription
+func (s *Service) enqueueUserWebhook(ctx context.Context, target string, user *domain.User, n notice) error {
+	payload, err := json.Marshal(webhookNotice{
+		Event:       n.Event,
+		UserID:      user.ID,
+		Title:       n.Title,
+		Description: n.Description,
+		ReferenceID: n.ReferenceID,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.CreateWebhookDelivery(ctx, &domain.WebhookDelivery{
+		UserID:        user.ID,
+		URL:           target,
+		EventID:       uuid.NewString(),
+		EventType:     n.Event,
+		Payload:       string(payload),
+		Status:        domain.WebhookPending,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	s.wakeWebhookWorker()
+	return nil
+}
+
+// emitOverdue publishes book.overdue the first time the scan finds the borrow late
+func (s *Service) emitOverdue(ctx context.Context, borrow *domain.BorrowedBook) {
+	claimed, err := s.repo.ClaimNotificationDispatch(ctx, &domain.NotificationDispatch{
+		UserID:      borrow.UserID,
+		EventType:   domain.EventTypeBookOverdue,
+		ReferenceID: borrow.ID,
+	})
+	if err != nil {
+		logrus.WithError(err).Warnf("could not claim overdue event for borrow %s", borrow.ID)
+		return
+	}
+	if claimed {
+		s.emit(domain.EventTypeBookOverdue, "", domain.Convert[domain.BorrowedBook, domain.BorrowedBookResponse](borrow))
+	}
+}
+
+// scheduleBorrowReminders sends a due reminder for each borrow in the last day before it is due
+// and an overdue notice once it is late, the borrow id keeps the scan from sending either twice
+func (s *Service) scheduleBorrowReminders(ctx context.Context) {
+	now := time.Now()
+	borrows, err := s.repo.ListBorrowsDueBefore(ctx, now.Add(dueReminderWindow))
+	if err != nil {
+		logrus.WithError(err).Error("could not load borrows for reminders")
+		return
+	}
+	for _, borrow := range borrows {
+		data := borrowMessageData(borrow)
+		n := notice{
+			UserID:      borrow.UserID,
+			Event:       domain.EventDueReminder,
+			Title:       fmt.Sprintf("%s is due on %s", data.BookTitle, data.DueDate),
+			ReferenceID: borrow.ID,
+			Data:        data,
+		}
+		if borrow.DueDate.Before(now) {
+			n.Event = domain.EventOverdue
+			n.Title = fmt.Sprintf("%s was due on %s and is overdue", data.BookTitle, data.DueDate)
+			s.emitOverdue(ctx, borrow)
+		}
+		if err := s.dispatch(ctx, n); err != nil {
+			logrus.WithError(err).Warnf("could not dispatch %s for borrow %s", n.Event, borrow.ID)
+		}
+	}
+}
@@ -8,10 +8,18 @@ import (
 )
 
 func (s *Service) CreateProgram(ctx context.Context, req *domain.ProgramRequest) (*domain.ProgramResponse, error) {
+	ctx, span := startSpan(ctx, "CreateProgram")
+	defer span.End()
 	data := &domain.Program{}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
+	req.FacultyID = domain.OptionalID(req.FacultyID)
+	if req.FacultyID != nil {
+		if _, err := s.repo.GetFaculty(ctx, *req.FacultyID); err != nil {
+			return nil, domain.NewNotFoundError("faculty_not_found", fmt.Sprintf("faculty %s not found", *req.FacultyID))
+		}
+	}
 	data.NewProgram(req)
 	result, err := s.repo.CreateProgram(ctx, data)
 	if err != nil {
@@ -21,18 +29,25 @@ func (s *Service) CreateProgram(ctx context.Context, req *domain.ProgramRequest)
 	if err != nil {
 		return nil, err
 	}
-	s.repo.CreateNotification(&domain.Notification{
-		Title:    "New program created: " + result.Name,
-		UserID:   userID,
-		Module:   "program",
-		Action:   "create",
-		IsActive: true,
+	s.notify(ctx, notice{
+		UserID: userID,
+		Event:  domain.EventProgramCreated,
+		Title:  "New program created: " + result.Name,
+	})
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "program",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new program %s.", result.Name),
+		After:      result,
 	})
 	response := domain.Convert[domain.Program, domain.ProgramResponse](result)
 	return response, err
 }
 
 func (s *Service) LisProgram(ctx context.Context, req *domain.ListProgramRequest) ([]domain.ProgramResponse, int64, error) {
+	ctx, span := startSpan(ctx, "LisProgram")
+	defer span.End()
 	cr := []domain.ProgramResponse{}
 	categories, count, err := s.repo.ListProgram(ctx, req)
 	if err != nil {
@@ -45,6 +60,8 @@ func (s *Service) LisProgram(ctx context.Context, req *domain.ListProgramRequest
 }
 
 func (s *Service) GetProgram(ctx context.Context, id string) (*domain.ProgramResponse, error) {
+	ctx, span := startSpan(ctx, "GetProgram")
+	defer span.End()
 	Program, err := s.repo.GetProgram(ctx, id)
 	if err != nil {
 		return nil, err
@@ -53,12 +70,23 @@ func (s *Service) GetProgram(ctx context.Context, id string) (*domain.ProgramRes
 }
 
 func (s *Service) UpdateProgram(ctx context.Context, id string, req *domain.ProgramUpdateRequest) (*domain.ProgramResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateProgram")
+	defer span.End()
+	if facultyID := domain.OptionalID(req.FacultyID); facultyID != nil {
+		if _, err := s.repo.GetFaculty(ctx, *facultyID); err != nil {
+			return nil, domain.NewNotFoundError("faculty_not_found", fmt.Sprintf("faculty %s not found", *facultyID))
+		}
+	}
+	before, err := s.repo.GetProgram(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 	mp := req.NewUpdateRequest()
-	err := s.repo.UpdateProgram(ctx, id, mp)
+	err = s.repo.UpdateProgram(ctx, id, mp)
 	if err != nil {
 		return nil, err
 	}
-	getUserID, err := getUserID(ctx)
+	_, err = getUserID(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -66,31 +94,52 @@ func (s *Service) UpdateProgram(ctx context.Context, id string, req *domain.Prog
 	if err != nil {
 		return nil, err
 	}
-	s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    "Program updated",
-		Action:   "update",
-		UserID:   &getUserID,
-		Data:     fmt.Sprint(id),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "program",
+		EntityID:   id,
+		Title:      fmt.Sprintf("Updated %s program details.", Program.Name),
+		Before:     before,
+		After:      Program,
 	})
 	return Program.ProgramResponse(), err
 }
 
 func (s *Service) DeleteProgram(ctx context.Context, id string) error {
-	getUserID, err := getUserID(ctx)
+	ctx, span := startSpan(ctx, "DeleteProgram")
+	defer span.End()
+	_, err := getUserID(ctx)
+	if err != nil {
+		return err
+	}
+	before, err := s.repo.GetProgram(ctx, id)
 	if err != nil {
 		return err
 	}
+	semesters, err := s.repo.CountProgramSemesters(ctx, id)
+	if err != nil {
+		return err
+	}
+	if semesters > 0 {
+		return domain.NewConflictError("program_in_use", fmt.Sprintf("program has %d semesters cannot delete it", semesters))
+	}
+	subjects, err := s.repo.CountProgramSubjects(ctx, id)
+	if err != nil {
+		return err
+	}
+	if subjects > 0 {
+		return domain.NewConflictError("program_in_use", fmt.Sprintf("program has %d subjects cannot delete it", subjects))
+	}
 	err = s.repo.DeleteProgram(ctx, id)
 	if err != nil {
 		return err
 	}
-	s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    "Program deleted",
-		Action:   "delete",
-		UserID:   &getUserID,
-		Data:     fmt.Sprint(id),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "program",
+		EntityID:   id,
+		Title:      fmt.Sprintf("Deleted %s program.", before.Name),
+		Before:     before,
 	})
 	return nil
 }
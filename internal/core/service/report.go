@@ -1,50 +1,56 @@
 package service
 
-import "github.com/sugaml/lms-api/internal/core/domain"
+import (
+	"context"
 
-func (s *Service) GetLibraryDashboardStats() (*domain.LibraryDashboardStats, error) {
-	result, err := s.repo.GetLibraryDashboardStats()
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+func (s *Service) GetLibraryDashboardStats(ctx context.Context) (*domain.LibraryDashboardStats, *domain.Freshness, error) {
+	ctx, span := startSpan(ctx, "GetLibraryDashboardStats")
+	defer span.End()
+	return withStatsCache(ctx, s, "dashboard", s.repo.GetLibraryDashboardStats)
 }
 
-func (s *Service) GetMonthlyChartData() ([]domain.ChartData, error) {
-	result, err := s.repo.GetMonthlyChartData()
+func (s *Service) GetMonthlyChartData(ctx context.Context) ([]domain.ChartData, error) {
+	ctx, span := startSpan(ctx, "GetMonthlyChartData")
+	defer span.End()
+	result, err := s.repo.GetMonthlyChartData(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
-func (s *Service) GetDailyChartData(req *domain.ChartRequest) ([]domain.ChartData, error) {
-	result, err := s.repo.GetDailyChartData(req)
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
+func (s *Service) GetDailyChartData(ctx context.Context, req *domain.ChartRequest) ([]domain.ChartData, *domain.Freshness, error) {
+	ctx, span := startSpan(ctx, "GetDailyChartData")
+	defer span.End()
+	return withStatsCache(ctx, s, chartStatsKey(req), func(ctx context.Context) ([]domain.ChartData, error) {
+		return s.repo.GetDailyChartData(ctx, req)
+	})
 }
 
-func (s *Service) GetBorrowedBookStats() (*domain.BorrowedBookStats, error) {
-	result, err := s.repo.GetBorrowedBookStats()
+func (s *Service) GetBorrowedBookStats(ctx context.Context) (*domain.BorrowedBookStats, error) {
+	ctx, span := startSpan(ctx, "GetBorrowedBookStats")
+	defer span.End()
+	result, err := s.repo.GetBorrowedBookStats(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (s *Service) GetBookProgramstats() (*[]domain.BookProgramstats, error) {
-	result, err := s.repo.GetBookProgramstats()
+func (s *Service) GetBookProgramstats(ctx context.Context) (*[]domain.BookProgramstats, error) {
+	ctx, span := startSpan(ctx, "GetBookProgramstats")
+	defer span.End()
+	result, err := s.repo.GetBookProgramstats(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (s *Service) GetInventorystats() (*domain.InventoryStats, error) {
-	result, err := s.repo.GetInventorystats()
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
+func (s *Service) GetInventorystats(ctx context.Context) (*domain.InventoryStats, *domain.Freshness, error) {
+	ctx, span := startSpan(ctx, "GetInventorystats")
+	defer span.End()
+	return withStatsCache(ctx, s, "inventory", s.repo.GetInventorystats)
 }
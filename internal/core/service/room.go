@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateRoom creates a new Room on an existing Floor
+func (s *Service) CreateRoom(ctx context.Context, req *domain.RoomRequest) (*domain.RoomResponse, error) {
+	ctx, span := startSpan(ctx, "CreateRoom")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetFloor(ctx, req.FloorID); err != nil {
+		return nil, domain.NewNotFoundError("floor_not_found", fmt.Sprintf("floor %s not found", req.FloorID))
+	}
+	data := domain.Convert[domain.RoomRequest, domain.Room](req)
+	if data.Status == "" {
+		data.Status = "ACTIVE"
+	}
+	result, err := s.repo.CreateRoom(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "room",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new room %s.", result.RoomCode),
+		After:      result,
+	})
+	return domain.Convert[domain.Room, domain.RoomResponse](result), nil
+}
+
+// ListRoom retrieves a list of Rooms
+func (s *Service) ListRoom(ctx context.Context, req *domain.ListRoomRequest) ([]*domain.RoomResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListRoom")
+	defer span.End()
+	var datas = []*domain.RoomResponse{}
+	results, count, err := s.repo.ListRoom(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.Room, domain.RoomResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetRoom(ctx context.Context, id string) (*domain.RoomResponse, error) {
+	ctx, span := startSpan(ctx, "GetRoom")
+	defer span.End()
+	result, err := s.repo.GetRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.Room, domain.RoomResponse](result), nil
+}
+
+func (s *Service) UpdateRoom(ctx context.Context, id string, req *domain.UpdateRoomRequest) (*domain.RoomResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateRoom")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required room id")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.FloorID != "" {
+		if _, err := s.repo.GetFloor(ctx, req.FloorID); err != nil {
+			return nil, domain.NewNotFoundError("floor_not_found", fmt.Sprintf("floor %s not found", req.FloorID))
+		}
+	}
+	result, err := s.repo.UpdateRoom(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "room",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s room details.", result.RoomCode),
+		Before:     before,
+		After:      result,
+	})
+	return domain.Convert[domain.Room, domain.RoomResponse](result), nil
+}
+
+// DeleteRoom deletes a Room that is not used by any class routine
+func (s *Service) DeleteRoom(ctx context.Context, id string) (*domain.RoomResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteRoom")
+	defer span.End()
+	result, err := s.repo.GetRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	routines, err := s.repo.CountRoomRoutines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if routines > 0 {
+		return nil, domain.NewConflictError("room_in_use", fmt.Sprintf("room is used by %d routines cannot delete it", routines))
+	}
+	if err := s.repo.DeleteRoom(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "room",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s room.", result.RoomCode),
+		Before:     result,
+	})
+	return domain.Convert[domain.Room, domain.RoomResponse](result), nil
+}
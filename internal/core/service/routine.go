@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateRoutine creates a new ClassRoutine after checking it against existing schedules
+func (s *Service) CreateRoutine(ctx context.Context, req *domain.ClassRoutineRequest) (*domain.ClassRoutineResponse, error) {
+	ctx, span := startSpan(ctx, "CreateRoutine")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.FacultyID = domain.OptionalID(req.FacultyID)
+	data := domain.Convert[domain.ClassRoutineRequest, domain.ClassRoutine](req)
+	conflicts, err := s.routineConflicts(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, &domain.RoutineConflictError{Conflicts: conflicts}
+	}
+	result, err := s.repo.CreateRoutine(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "class_routine",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Scheduled %s on %s.", result.RoutineResponse().SubjectName, result.DayOfWeek),
+		After:      result,
+	})
+	return result.RoutineResponse(), nil
+}
+
+// CheckRoutine reports the conflicts a routine would have without saving it
+func (s *Service) CheckRoutine(ctx context.Context, req *domain.ClassRoutineRequest) ([]domain.RoutineConflict, error) {
+	ctx, span := startSpan(ctx, "CheckRoutine")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	data := domain.Convert[domain.ClassRoutineRequest, domain.ClassRoutine](req)
+	return s.routineConflicts(ctx, data)
+}
+
+// ListRoutine retrieves a list of ClassRoutines
+func (s *Service) ListRoutine(ctx context.Context, req *domain.ListClassRoutineRequest) ([]*domain.ClassRoutineResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListRoutine")
+	defer span.End()
+	var datas = []*domain.ClassRoutineResponse{}
+	results, count, err := s.repo.ListRoutine(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, result.RoutineResponse())
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetRoutine(ctx context.Context, id string) (*domain.ClassRoutineResponse, error) {
+	ctx, span := startSpan(ctx, "GetRoutine")
+	defer span.End()
+	result, err := s.repo.GetRoutine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return result.RoutineResponse(), nil
+}
+
+func (s *Service) UpdateRoutine(ctx context.Context, id string, req *domain.UpdateClassRoutineRequest) (*domain.ClassRoutineResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateRoutine")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required routine id")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	routine, err := s.repo.GetRoutine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Apply(routine)
+	conflicts, err := s.routineConflicts(ctx, routine)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, &domain.RoutineConflictError{Conflicts: conflicts}
+	}
+	result, err := s.repo.UpdateRoutine(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "class_routine",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated routine of %s on %s.", result.RoutineResponse().SubjectName, result.DayOfWeek),
+		Before:     routine,
+		After:      result,
+	})
+	return result.RoutineResponse(), nil
+}
+
+func (s *Service) DeleteRoutine(ctx context.Context, id string) (*domain.ClassRoutineResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteRoutine")
+	defer span.End()
+	result, err := s.repo.GetRoutine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteRoutine(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "class_routine",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted routine of %s on %s.", result.RoutineResponse().SubjectName, result.DayOfWeek),
+		Before:     result,
+	})
+	return result.RoutineResponse(), nil
+}
+
+// routineConflicts checks a routine against the room, teacher and semester schedules,
+// the room capacity and type, and the teacher's declared unavailability.
+func (s *Service) routineConflicts(ctx context.Context, data *domain.ClassRoutine) ([]domain.RoutineConflict, error) {
+	conflicts := []domain.RoutineConflict{}
+	room, err := s.repo.GetRoom(ctx, data.RoomID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("room_not_found", fmt.Sprintf("room %s not found", data.RoomID))
+	}
+	subject, err := s.repo.GetSubject(ctx, data.SubjectID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("subject_not_found", fmt.Sprintf("subject %s not found", data.SubjectID))
+	}
+	if _, err := s.repo.GetSemester(ctx, data.SemesterID); err != nil {
+		return nil, domain.NewNotFoundError("semester_not_found", fmt.Sprintf("semester %s not found", data.SemesterID))
+	}
+	if _, err := s.repo.GetTimeSlot(ctx, data.TimeSlotID); err != nil {
+		return nil, domain.NewNotFoundError("time_slot_not_found", fmt.Sprintf("time slot %s not found", data.TimeSlotID))
+	}
+	teacher, err := s.repo.GetUser(ctx, data.TeacherID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("teacher_not_found", fmt.Sprintf("teacher %s not found", data.TeacherID))
+	}
+
+	clashes, err := s.repo.ListClashingRoutines(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	for _, clash := range clashes {
+		name := clash.RoutineResponse().SubjectName
+		if clash.RoomID == data.RoomID {
+			conflicts = append(conflicts, domain.RoutineConflict{
+				Type:      domain.ConflictRoom,
+				Message:   fmt.Sprintf("room %s is already booked for %s on %s in this time slot", room.RoomCode, name, data.DayOfWeek),
+				RoutineID: clash.ID,
+			})
+		}
+		if clash.TeacherID == data.TeacherID {
+			conflicts = append(conflicts, domain.RoutineConflict{
+				Type:      domain.ConflictTeacher,
+				Message:   fmt.Sprintf("%s is already teaching %s on %s in this time slot", teacher.FullName, name, data.DayOfWeek),
+				RoutineID: clash.ID,
+			})
+		}
+		if clash.SemesterID == data.SemesterID {
+			conflicts = append(conflicts, domain.RoutineConflict{
+				Type:      domain.ConflictSemester,
+				Message:   fmt.Sprintf("semester already has %s on %s in this time slot", name, data.DayOfWeek),
+				RoutineID: clash.ID,
+			})
+		}
+	}
+	if data.StudentCount > 0 && room.Capacity < data.StudentCount {
+		conflicts = append(conflicts, domain.RoutineConflict{
+			Type:    domain.ConflictCapacity,
+			Message: fmt.Sprintf("room %s seats %d but the section has %d students", room.RoomCode, room.Capacity, data.StudentCount),
+		})
+	}
+	if subject.IsLab && room.RoomType != domain.LabRoom {
+		conflicts = append(conflicts, domain.RoutineConflict{
+			Type:    domain.ConflictLabRoom,
+			Message: fmt.Sprintf("%s is a lab subject but room %s is a %s", subject.Name, room.RoomCode, room.RoomType),
+		})
+	}
+	if !teacher.IsActive {
+		conflicts = append(conflicts, domain.RoutineConflict{
+			Type:    domain.ConflictTeacherUnavailable,
+			Message: fmt.Sprintf("%s is not an active user", teacher.FullName),
+		})
+	}
+	unavailable, err := s.repo.IsTeacherUnavailable(ctx, data.TeacherID, data.DayOfWeek, data.TimeSlotID)
+	if err != nil {
+		return nil, err
+	}
+	if unavailable {
+		conflicts = append(conflicts, domain.RoutineConflict{
+			Type:    domain.ConflictTeacherUnavailable,
+			Message: fmt.Sprintf("%s is unavailable on %s in this time slot", teacher.FullName, data.DayOfWeek),
+		})
+	}
+	return conflicts, nil
+}
+
+func (s *Service) CreateTeacherUnavailability(ctx context.Context, req *domain.TeacherUnavailabilityRequest) (*domain.TeacherUnavailabilityResponse, error) {
+	ctx, span := startSpan(ctx, "CreateTeacherUnavailability")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teacher, err := s.repo.GetUser(ctx, req.TeacherID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("teacher_not_found", fmt.Sprintf("teacher %s not found", req.TeacherID))
+	}
+	req.TimeSlotID = domain.OptionalID(req.TimeSlotID)
+	if req.TimeSlotID != nil {
+		if _, err := s.repo.GetTimeSlot(ctx, *req.TimeSlotID); err != nil {
+			return nil, domain.NewNotFoundError("time_slot_not_found", fmt.Sprintf("time slot %s not found", *req.TimeSlotID))
+		}
+	}
+	data := domain.Convert[domain.TeacherUnavailabilityRequest, domain.TeacherUnavailability](req)
+	result, err := s.repo.CreateTeacherUnavailability(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "teacher_unavailability",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Marked %s unavailable on %s.", teacher.FullName, result.DayOfWeek),
+		After:      result,
+	})
+	return domain.Convert[domain.TeacherUnavailability, domain.TeacherUnavailabilityResponse](result), nil
+}
+
+func (s *Service) ListTeacherUnavailability(ctx context.Context, req *domain.ListTeacherUnavailabilityRequest) ([]*domain.TeacherUnavailabilityResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListTeacherUnavailability")
+	defer span.End()
+	var datas = []*domain.TeacherUnavailabilityResponse{}
+	results, count, err := s.repo.ListTeacherUnavailability(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.TeacherUnavailability, domain.TeacherUnavailabilityResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) DeleteTeacherUnavailability(ctx context.Context, id string) (*domain.TeacherUnavailabilityResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteTeacherUnavailability")
+	defer span.End()
+	result, err := s.repo.GetTeacherUnavailability(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteTeacherUnavailability(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "teacher_unavailability",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Removed teacher unavailability on %s.", result.DayOfWeek),
+		Before:     result,
+	})
+	return domain.Convert[domain.TeacherUnavailability, domain.TeacherUnavailabilityResponse](result), nil
+}
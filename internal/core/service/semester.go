@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateSemester creates a new Semester under an existing Program
+func (s *Service) CreateSemester(ctx context.Context, req *domain.SemesterRequest) (*domain.SemesterResponse, error) {
+	ctx, span := startSpan(ctx, "CreateSemester")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetProgram(ctx, req.ProgramID); err != nil {
+		return nil, domain.NewNotFoundError("program_not_found", fmt.Sprintf("program %s not found", req.ProgramID))
+	}
+	data := domain.Convert[domain.SemesterRequest, domain.Semester](req)
+	result, err := s.repo.CreateSemester(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "semester",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new semester %s.", result.Name),
+		After:      result,
+	})
+	return domain.Convert[domain.Semester, domain.SemesterResponse](result), nil
+}
+
+// ListSemester retrieves a list of Semesters
+func (s *Service) ListSemester(ctx context.Context, req *domain.ListSemesterRequest) ([]*domain.SemesterResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListSemester")
+	defer span.End()
+	var datas = []*domain.SemesterResponse{}
+	results, count, err := s.repo.ListSemester(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.Semester, domain.SemesterResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetSemester(ctx context.Context, id string) (*domain.SemesterResponse, error) {
+	ctx, span := startSpan(ctx, "GetSemester")
+	defer span.End()
+	result, err := s.repo.GetSemester(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.Semester, domain.SemesterResponse](result), nil
+}
+
+func (s *Service) UpdateSemester(ctx context.Context, id string, req *domain.UpdateSemesterRequest) (*domain.SemesterResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateSemester")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required semester id")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetSemester(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.UpdateSemester(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "semester",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s semester details.", result.Name),
+		Before:     before,
+		After:      result,
+	})
+	return domain.Convert[domain.Semester, domain.SemesterResponse](result), nil
+}
+
+// DeleteSemester deletes a Semester that has no subjects or routines left
+func (s *Service) DeleteSemester(ctx context.Context, id string) (*domain.SemesterResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteSemester")
+	defer span.End()
+	result, err := s.repo.GetSemester(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := s.repo.CountSemesterSubjects(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subjects > 0 {
+		return nil, domain.NewConflictError("semester_in_use", fmt.Sprintf("semester has %d subjects cannot delete it", subjects))
+	}
+	routines, err := s.repo.CountSemesterRoutines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if routines > 0 {
+		return nil, domain.NewConflictError("semester_in_use", fmt.Sprintf("semester has %d routines cannot delete it", routines))
+	}
+	if err := s.repo.DeleteSemester(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "semester",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s semester.", result.Name),
+		Before:     result,
+	})
+	return domain.Convert[domain.Semester, domain.SemesterResponse](result), nil
+}
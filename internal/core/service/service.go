@@ -2,26 +2,72 @@ package service
 
 import (
 	"context"
-	"errors"
 
 	"github.com/sugaml/lms-api/internal/core/auth"
+	"github.com/sugaml/lms-api/internal/core/domain"
 	"github.com/sugaml/lms-api/internal/core/port"
 )
 
 type Service struct {
-	repo       port.Repository
-	tokenMaker auth.Maker
+	repo            port.Repository
+	tokenMaker      auth.Maker
+	mailer          port.Mailer
+	emailQueue      chan struct{}
+	sms             port.SMSSender
+	smsPolicy       *domain.SMSPolicy
+	smsQueue        chan struct{}
+	webhook         port.WebhookPoster
+	webhookQueue    chan struct{}
+	notificationHub port.NotificationHub
+	events          port.EventBus
+	auditSigner     port.AuditSigner
+	archiveStore    port.ArchiveStore
+	retention       *domain.RetentionPolicy
+	statsCache      port.StatsCache
+	statsQueue      chan struct{}
+	notices         chan queuedNotice
+	jobs            port.JobObserver
 }
 
 // NewAnnocuncementService creates a new product service instance
 func NewService(
 	repo port.Repository,
 	tokenMaker auth.Maker,
+	mailer port.Mailer,
+	sms port.SMSSender,
+	smsPolicy *domain.SMSPolicy,
+	webhook port.WebhookPoster,
+	notificationHub port.NotificationHub,
+	events port.EventBus,
+	auditSigner port.AuditSigner,
+	archiveStore port.ArchiveStore,
+	retention *domain.RetentionPolicy,
+	statsCache port.StatsCache,
+	jobs port.JobObserver,
 ) port.Service {
-	return &Service{
-		repo,
-		tokenMaker,
+	s := &Service{
+		repo:            repo,
+		tokenMaker:      tokenMaker,
+		mailer:          mailer,
+		emailQueue:      make(chan struct{}, emailQueueCapacity),
+		sms:             sms,
+		smsPolicy:       smsPolicy,
+		smsQueue:        make(chan struct{}, smsQueueCapacity),
+		webhook:         webhook,
+		webhookQueue:    make(chan struct{}, webhookQueueCapacity),
+		notificationHub: notificationHub,
+		events:          events,
+		auditSigner:     auditSigner,
+		archiveStore:    archiveStore,
+		retention:       retention,
+		statsCache:      statsCache,
+		statsQueue:      make(chan struct{}, statsQueueCapacity),
+		notices:         make(chan queuedNotice, noticeQueueCapacity),
+		jobs:            jobs,
 	}
+	events.Subscribe(s.enqueueWebhooks)
+	events.Subscribe(s.invalidateStats)
+	return s
 }
 
 type mapString map[string]string
@@ -43,7 +89,7 @@ type mapString map[string]string
 func getUserID(ctx context.Context) (string, error) {
 	userID, exists := ctx.Value("authorization_user_id").(string)
 	if !exists {
-		return "", errors.New("user ID not found in context")
+		return "", domain.NewUnauthorizedError("unauthorized", "user ID not found in context")
 	}
 	return userID, nil
 }
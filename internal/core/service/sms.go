@@ -0,0 +1,280 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+//go:embed templates/sms/*.tmpl
+var smsTemplateFS embed.FS
+
+// smsTemplates holds one parsed file per notification that has an SMS version
+var smsTemplates = func() map[string]*template.Template {
+	tmpls := map[string]*template.Template{}
+	for _, name := range []string{
+		domain.EventDueReminder,
+		domain.EventOverdue,
+		domain.EventHoldReady,
+		domain.EventFineIssued,
+		domain.EventAccountCreated,
+	} {
+		tmpls[name] = template.Must(template.ParseFS(smsTemplateFS, "templates/sms/"+name+".tmpl"))
+	}
+	return tmpls
+}()
+
+const (
+	smsBatchSize     = 50
+	smsPollInterval  = 30 * time.Second
+	smsRetryBackoff  = 2 * time.Minute
+	smsLease         = 10 * time.Minute // longer than a batch takes to send
+	smsQueueCapacity = 1
+)
+
+func renderSMS(name string, data messageData) (string, error) {
+	tmpl, ok := smsTemplates[name]
+	if !ok {
+		return "", fmt.Errorf("unknown sms template %s", name)
+	}
+	var body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(body.String()), nil
+}
+
+// enqueueSMS queues a message for the end of quiet hours when needed, one over the user's daily
+// limit is recorded as limited and never sent
+func (s *Service) enqueueSMS(ctx context.Context, user *domain.User, name, referenceID string, data messageData) error {
+	to := strings.TrimSpace(user.MobileNumber)
+	if to == "" {
+		return nil
+	}
+	body, err := renderSMS(name, data)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	segments := domain.SMSSegments(body)
+	delivery := &domain.SMSDelivery{
+		UserID:        user.ID,
+		To:            to,
+		Template:      name,
+		ReferenceID:   referenceID,
+		Body:          body,
+		Segments:      segments,
+		Cost:          segments * s.smsPolicy.CostPerSegment,
+		Status:        domain.SMSQueued,
+		NextAttemptAt: s.smsPolicy.NextSendTime(now),
+	}
+	if s.smsPolicy.DailyLimit > 0 {
+		_, err = s.repo.CreateSMSDeliveryWithinLimit(ctx, delivery, now.Add(-24*time.Hour), s.smsPolicy.DailyLimit)
+	} else {
+		_, err = s.repo.CreateSMSDelivery(ctx, delivery)
+	}
+	if err != nil {
+		return err
+	}
+	if delivery.Status == domain.SMSQueued {
+		s.wakeSMSWorker()
+	}
+	return nil
+}
+
+func (s *Service) wakeSMSWorker() {
+	select {
+	case s.smsQueue <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) RunSMSDelivery(ctx context.Context) {
+	poll := time.NewTicker(smsPollInterval)
+	defer poll.Stop()
+
+	s.runJob(ctx, "sms_delivery", s.deliverSMSBatch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			s.runJob(ctx, "sms_delivery", s.deliverSMSBatch)
+		case <-s.smsQueue:
+			s.runJob(ctx, "sms_delivery", s.deliverSMSBatch)
+		}
+	}
+}
+
+func (s *Service) deliverSMSBatch(ctx context.Context) {
+	for {
+		deliveries, err := s.repo.ClaimDueSMSDeliveries(ctx, time.Now(), smsLease, smsBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("could not claim queued sms")
+			return
+		}
+		for _, delivery := range deliveries {
+			s.deliverSMS(ctx, delivery)
+		}
+		if len(deliveries) < smsBatchSize {
+			return
+		}
+	}
+}
+
+func (s *Service) deliverSMS(ctx context.Context, delivery *domain.SMSDelivery) {
+	// a retry can fall due inside quiet hours, hold it until they end without using an attempt
+	now := time.Now()
+	if next := s.smsPolicy.NextSendTime(now); next.After(now) {
+		if _, err := s.repo.UpdateSMSDelivery(ctx, delivery.ID, domain.Map{"next_attempt_at": next}); err != nil {
+			logrus.WithError(err).Errorf("could not update sms delivery %s", delivery.ID)
+		}
+		return
+	}
+	attempts := delivery.Attempts + 1
+	receipt, err := s.sms.Send(&domain.SMSMessage{To: delivery.To, Body: delivery.Body})
+	update := domain.Map{"attempts": attempts}
+	var rejected *domain.SMSRejectedError
+	switch {
+	case err == nil:
+		update["status"] = domain.SMSSent
+		update["sent_at"] = &now
+		update["last_error"] = ""
+		if receipt != nil {
+			update["provider_id"] = receipt.ProviderID
+		}
+	case errors.As(err, &rejected):
+		update["status"] = domain.SMSRejected
+		update["last_error"] = err.Error()
+	case attempts >= domain.MaxSMSAttempts:
+		update["status"] = domain.SMSFailed
+		update["last_error"] = err.Error()
+	default:
+		backoff := smsRetryBackoff * time.Duration(math.Pow(2, float64(attempts-1)))
+		update["next_attempt_at"] = s.smsPolicy.NextSendTime(now.Add(backoff))
+		update["last_error"] = err.Error()
+	}
+	if err != nil {
+		logrus.WithError(err).Warnf("sms %s to %s attempt %d", delivery.ID, delivery.To, attempts)
+	}
+	if _, err := s.repo.UpdateSMSDelivery(ctx, delivery.ID, update); err != nil {
+		logrus.WithError(err).Errorf("could not update sms delivery %s", delivery.ID)
+	}
+}
+
+// smsOperator loads the caller and makes sure they are library staff, the log holds every
+// recipient's number and message and a retry spends SMS credit
+func (s *Service) smsOperator(ctx context.Context) (*domain.NotificationRecipient, error) {
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(recipient.Roles, domain.RoleAdmin) && !slices.Contains(recipient.Roles, domain.RoleLibrarian) {
+		return nil, domain.NewForbiddenError("not_allowed", "only an admin or librarian can manage sms deliveries")
+	}
+	return recipient, nil
+}
+
+func (s *Service) ListSMSDelivery(ctx context.Context, req *domain.ListSMSDeliveryRequest) ([]*domain.SMSDeliveryResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListSMSDelivery")
+	defer span.End()
+	if _, err := s.smsOperator(ctx); err != nil {
+		return nil, 0, err
+	}
+	var datas = []*domain.SMSDeliveryResponse{}
+	results, count, err := s.repo.ListSMSDelivery(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		data := domain.Convert[domain.SMSDelivery, domain.SMSDeliveryResponse](result)
+		datas = append(datas, data)
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetSMSDelivery(ctx context.Context, id string) (*domain.SMSDeliveryResponse, error) {
+	ctx, span := startSpan(ctx, "GetSMSDelivery")
+	defer span.End()
+	if _, err := s.smsOperator(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetSMSDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.SMSDelivery, domain.SMSDeliveryResponse](result), nil
+}
+
+// RetrySMSDelivery puts a failed message back on the queue, it still waits for quiet hours to
+// end. Messages dropped by the daily limit or refused by the gateway stay as they are.
+func (s *Service) RetrySMSDelivery(ctx context.Context, id string) (*domain.SMSDeliveryResponse, error) {
+	ctx, span := startSpan(ctx, "RetrySMSDelivery")
+	defer span.End()
+	if _, err := s.smsOperator(ctx); err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.GetSMSDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch delivery.Status {
+	case domain.SMSSent:
+		return nil, domain.NewConflictError("sms_already_sent", "sms has already been sent")
+	case domain.SMSLimited:
+		return nil, domain.NewConflictError("sms_limited", "sms was over the daily limit of its recipient and is not retried")
+	case domain.SMSRejected:
+		return nil, domain.NewConflictError("sms_rejected", "sms was refused by the gateway and is not retried")
+	}
+	result, err := s.repo.UpdateSMSDelivery(ctx, id, domain.Map{
+		"status":          domain.SMSQueued,
+		"attempts":        0,
+		"last_error":      "",
+		"cost":            delivery.Segments * s.smsPolicy.CostPerSegment,
+		"next_attempt_at": s.smsPolicy.NextSendTime(time.Now()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.wakeSMSWorker()
+	s.audit(ctx, auditEntry{
+		Action:     "retry",
+		EntityType: "sms_delivery",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Retried %s sms to %s", result.Template, result.To),
+		Before:     delivery,
+		After:      result,
+	})
+	return domain.Convert[domain.SMSDelivery, domain.SMSDeliveryResponse](result), nil
+}
+
+// GetSMSUsage sums sent messages, segments and cost per template over a date range, the last
+// month by default
+func (s *Service) GetSMSUsage(ctx context.Context, req *domain.SMSUsageRequest) ([]*domain.SMSUsage, error) {
+	ctx, span := startSpan(ctx, "GetSMSUsage")
+	defer span.End()
+	if _, err := s.smsOperator(ctx); err != nil {
+		return nil, err
+	}
+	list := domain.ListRequest{StartDate: req.StartDate, EndDate: req.EndDate}
+	list.Prepare()
+	req.StartDate, req.EndDate = list.StartDate, list.EndDate
+	datas, err := s.repo.GetSMSUsage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if datas == nil {
+		datas = []*domain.SMSUsage{}
+	}
+	return datas, nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// Soft constraint penalties used to rank otherwise valid placements
+const (
+	penaltySameDay     = 10 // another session of the subject already on that day
+	penaltyTeacherLoad = 20 // each class over the teacher's daily load
+	penaltyLabMisuse   = 5  // a theory class taking up a lab
+)
+
+type solverLesson struct {
+	subject   *domain.Subject
+	teacherID string
+	session   int
+	options   int
+}
+
+// timetableSolver greedily places lessons, most constrained first, into the
+// cheapest (day, time slot, room) that satisfies every hard constraint
+type timetableSolver struct {
+	days        []domain.DayOfWeek
+	slots       []*domain.TimeSlot
+	rooms       []*domain.Room
+	semesterID  string
+	students    int
+	maxLoad     int
+	busy        map[string]bool
+	unavailable map[string]bool
+	load        map[string]int
+	spread      map[string]int
+}
+
+func newTimetableSolver(semesterID string, students, maxLoad int, slots []*domain.TimeSlot, rooms []*domain.Room) *timetableSolver {
+	return &timetableSolver{
+		days:        domain.Days,
+		slots:       slots,
+		rooms:       rooms,
+		semesterID:  semesterID,
+		students:    students,
+		maxLoad:     maxLoad,
+		busy:        map[string]bool{},
+		unavailable: map[string]bool{},
+		load:        map[string]int{},
+		spread:      map[string]int{},
+	}
+}
+
+func solverKey(parts ...string) string {
+	return strings.Join(parts, "|")
+}
+
+// occupy marks an existing routine so the draft never clashes with it, mirroring the class_routines unique indexes
+func (t *timetableSolver) occupy(r *domain.ClassRoutine) {
+	day := string(r.DayOfWeek)
+	t.busy[solverKey("room", r.RoomID, day, r.TimeSlotID)] = true
+	t.busy[solverKey("teacher", r.TeacherID, day, r.TimeSlotID)] = true
+	t.busy[solverKey("semester", r.SemesterID, day, r.TimeSlotID)] = true
+	t.load[solverKey(r.TeacherID, day)]++
+}
+
+func (t *timetableSolver) block(u *domain.TeacherUnavailability) {
+	slot := ""
+	if u.TimeSlotID != nil {
+		slot = *u.TimeSlotID
+	}
+	t.unavailable[solverKey(u.TeacherID, string(u.DayOfWeek), slot)] = true
+}
+
+func (t *timetableSolver) teacherUnavailable(teacherID string, day domain.DayOfWeek, slotID string) bool {
+	return t.unavailable[solverKey(teacherID, string(day), "")] || t.unavailable[solverKey(teacherID, string(day), slotID)]
+}
+
+func (t *timetableSolver) suitableRooms(subject *domain.Subject) []*domain.Room {
+	rooms := []*domain.Room{}
+	for _, room := range t.rooms {
+		if room.Capacity < t.students {
+			continue
+		}
+		if subject.IsLab && room.RoomType != domain.LabRoom {
+			continue
+		}
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// slotOpen reports whether the teacher and semester are both free in a (day, slot)
+func (t *timetableSolver) slotOpen(l *solverLesson, day domain.DayOfWeek, slotID string) bool {
+	return !t.teacherUnavailable(l.teacherID, day, slotID) &&
+		!t.busy[solverKey("teacher", l.teacherID, string(day), slotID)] &&
+		!t.busy[solverKey("semester", t.semesterID, string(day), slotID)]
+}
+
+// openSlots counts the (day, slot) pairs where the teacher and semester are both free
+func (t *timetableSolver) openSlots(l *solverLesson) int {
+	n := 0
+	for _, day := range t.days {
+		for _, slot := range t.slots {
+			if !t.slotOpen(l, day, slot.ID) {
+				continue
+			}
+			n++
+		}
+	}
+	return n
+}
+
+func (t *timetableSolver) cost(l *solverLesson, day domain.DayOfWeek, room *domain.Room) int {
+	cost := t.spread[solverKey(l.subject.ID, string(day))] * penaltySameDay
+	if over := t.load[solverKey(l.teacherID, string(day))] + 1 - t.maxLoad; over > 0 {
+		cost += over * penaltyTeacherLoad
+	}
+	if !l.subject.IsLab && room.RoomType == domain.LabRoom {
+		cost += penaltyLabMisuse
+	}
+	if t.students > 0 {
+		cost += (room.Capacity - t.students) / 10
+	}
+	return cost
+}
+
+// place books the cheapest valid spot for a lesson, returning false when none exists
+func (t *timetableSolver) place(l *solverLesson) (*domain.TimetableDraftEntry, int, bool) {
+	var best *domain.TimetableDraftEntry
+	bestCost := -1
+	rooms := t.suitableRooms(l.subject)
+	for _, day := range t.days {
+		for _, slot := range t.slots {
+			if !t.slotOpen(l, day, slot.ID) {
+				continue
+			}
+			for _, room := range rooms {
+				if t.busy[solverKey("room", room.ID, string(day), slot.ID)] {
+					continue
+				}
+				if cost := t.cost(l, day, room); bestCost < 0 || cost < bestCost {
+					bestCost = cost
+					best = &domain.TimetableDraftEntry{
+						SubjectID:  l.subject.ID,
+						TeacherID:  l.teacherID,
+						RoomID:     room.ID,
+						TimeSlotID: slot.ID,
+						DayOfWeek:  day,
+					}
+				}
+			}
+		}
+	}
+	if best == nil {
+		return nil, 0, false
+	}
+	day := string(best.DayOfWeek)
+	t.busy[solverKey("room", best.RoomID, day, best.TimeSlotID)] = true
+	t.busy[solverKey("teacher", best.TeacherID, day, best.TimeSlotID)] = true
+	t.busy[solverKey("semester", t.semesterID, day, best.TimeSlotID)] = true
+	t.load[solverKey(best.TeacherID, day)]++
+	t.spread[solverKey(best.SubjectID, day)]++
+	return best, bestCost, true
+}
+
+// explain tallies which hard constraint blocked each weekly slot for a lesson that could not be placed
+func (t *timetableSolver) explain(l *solverLesson) string {
+	rooms := t.suitableRooms(l.subject)
+	if len(rooms) == 0 {
+		kind := "room"
+		if l.subject.IsLab {
+			kind = "lab room"
+		}
+		return fmt.Sprintf("no active %s seats %d students", kind, t.students)
+	}
+	var unavailable, teacherBusy, semesterBusy, roomBusy int
+	for _, day := range t.days {
+		for _, slot := range t.slots {
+			switch {
+			case t.teacherUnavailable(l.teacherID, day, slot.ID):
+				unavailable++
+			case t.busy[solverKey("teacher", l.teacherID, string(day), slot.ID)]:
+				teacherBusy++
+			case t.busy[solverKey("semester", t.semesterID, string(day), slot.ID)]:
+				semesterBusy++
+			default:
+				roomBusy++
+			}
+		}
+	}
+	return fmt.Sprintf("no free slot out of %d weekly slots: teacher unavailable in %d, teacher busy in %d, semester busy in %d, every suitable room booked in %d",
+		len(t.days)*len(t.slots), unavailable, teacherBusy, semesterBusy, roomBusy)
+}
+
+// solve places every lesson it can and explains the rest, returning the total soft constraint penalty
+func (t *timetableSolver) solve(lessons []*solverLesson) ([]domain.TimetableDraftEntry, []domain.UnplacedLesson, int) {
+	for _, l := range lessons {
+		l.options = t.openSlots(l) * len(t.suitableRooms(l.subject))
+	}
+	sort.SliceStable(lessons, func(i, j int) bool {
+		a, b := lessons[i], lessons[j]
+		if a.options != b.options {
+			return a.options < b.options
+		}
+		if a.subject.Code != b.subject.Code {
+			return a.subject.Code < b.subject.Code
+		}
+		return a.session < b.session
+	})
+	entries := []domain.TimetableDraftEntry{}
+	unplaced := []domain.UnplacedLesson{}
+	score := 0
+	for _, l := range lessons {
+		entry, cost, ok := t.place(l)
+		if !ok {
+			unplaced = append(unplaced, domain.UnplacedLesson{
+				SubjectID:   l.subject.ID,
+				SubjectCode: l.subject.Code,
+				TeacherID:   l.teacherID,
+				Session:     l.session,
+				Reason:      t.explain(l),
+			})
+			continue
+		}
+		score += cost
+		entries = append(entries, *entry)
+	}
+	return entries, unplaced, score
+}
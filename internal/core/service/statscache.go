@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const (
+	statsQueueCapacity = 1
+	// statsWarmDelay lets a burst of changes settle before the dashboard is computed again
+	statsWarmDelay = 5 * time.Second
+)
+
+// cachedStats is a value in the statistics cache with when it was computed
+type cachedStats[T any] struct {
+	ComputedAt time.Time `json:"computed_at"`
+	Data       T         `json:"data"`
+}
+
+// withStatsCache serves key from the statistics cache or computes and caches it. The cache only
+// speeds things up, when it fails the statistics are computed as if it was empty.
+func withStatsCache[T any](ctx context.Context, s *Service, key string, compute func(context.Context) (T, error)) (T, *domain.Freshness, error) {
+	if raw, ok, err := s.statsCache.Get(ctx, key); err != nil {
+		logrus.WithError(err).Warnf("could not read %s statistics from the cache", key)
+	} else if ok {
+		var cached cachedStats[T]
+		if err := json.Unmarshal(raw, &cached); err == nil {
+			return cached.Data, freshness(cached.ComputedAt, true), nil
+		}
+	}
+	// statistics computed while the data changed, on this or another instance, are served once but
+	// not kept
+	generation, err := s.statsCache.Generation(ctx)
+	if err != nil {
+		logrus.WithError(err).Warnf("could not read the statistics cache generation for %s", key)
+	}
+	cacheable := err == nil
+	cached := cachedStats[T]{ComputedAt: time.Now()}
+	data, err := compute(ctx)
+	if err != nil {
+		return data, nil, err
+	}
+	cached.Data = data
+	if cacheable {
+		raw, err := json.Marshal(cached)
+		if err == nil {
+			err = s.statsCache.Set(ctx, generation, key, raw)
+		}
+		if err != nil {
+			logrus.WithError(err).Warnf("could not cache %s statistics", key)
+		}
+	}
+	return data, freshness(cached.ComputedAt, false), nil
+}
+
+func freshness(computedAt time.Time, cached bool) *domain.Freshness {
+	return &domain.Freshness{
+		ComputedAt: computedAt,
+		AgeSeconds: int64(time.Since(computedAt).Seconds()),
+		Cached:     cached,
+	}
+}
+
+func chartStatsKey(req *domain.ChartRequest) string {
+	return fmt.Sprintf("chart:%s:%s:%s", req.Range, req.StartDate, req.EndDate)
+}
+
+// invalidateStats drops the cached statistics when a book, copy, borrow, fine or user changes and
+// has the dashboard computed again
+func (s *Service) invalidateStats(event *domain.DomainEvent) {
+	if event.Type == domain.EventTypeWebhookPing {
+		return
+	}
+	if err := s.statsCache.Invalidate(context.Background()); err != nil {
+		logrus.WithError(err).Errorf("could not invalidate cached statistics on %s", event.Type)
+	}
+	select {
+	case s.statsQueue <- struct{}{}:
+	default:
+	}
+}
+
+// RunStatsWarmer computes the dashboard statistics at start and again after changes drop them,
+// so that librarians opening the dashboard find them cached
+func (s *Service) RunStatsWarmer(ctx context.Context) {
+	s.runJob(ctx, "stats_warmer", s.warmStats)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.statsQueue:
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(statsWarmDelay):
+			}
+			s.runJob(ctx, "stats_warmer", s.warmStats)
+		}
+	}
+}
+
+func (s *Service) warmStats(ctx context.Context) {
+	if _, _, err := s.GetLibraryDashboardStats(ctx); err != nil {
+		logrus.WithError(err).Error("could not compute the library dashboard statistics")
+	}
+	if _, _, err := s.GetInventorystats(ctx); err != nil {
+		logrus.WithError(err).Error("could not compute the inventory statistics")
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateSubject creates a new Subject under an existing Program, optionally pinned to one of its semesters
+func (s *Service) CreateSubject(ctx context.Context, req *domain.SubjectRequest) (*domain.SubjectResponse, error) {
+	ctx, span := startSpan(ctx, "CreateSubject")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetProgram(ctx, req.ProgramID); err != nil {
+		return nil, domain.NewNotFoundError("program_not_found", fmt.Sprintf("program %s not found", req.ProgramID))
+	}
+	req.SemesterID = domain.OptionalID(req.SemesterID)
+	if req.SemesterID != nil {
+		if err := s.checkSubjectSemester(ctx, req.ProgramID, *req.SemesterID); err != nil {
+			return nil, err
+		}
+	}
+	data := domain.Convert[domain.SubjectRequest, domain.Subject](req)
+	result, err := s.repo.CreateSubject(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "subject",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new subject %s %s.", result.Code, result.Name),
+		After:      result,
+	})
+	return domain.Convert[domain.Subject, domain.SubjectResponse](result), nil
+}
+
+// ListSubject retrieves a list of Subjects
+func (s *Service) ListSubject(ctx context.Context, req *domain.ListSubjectRequest) ([]*domain.SubjectResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListSubject")
+	defer span.End()
+	var datas = []*domain.SubjectResponse{}
+	results, count, err := s.repo.ListSubject(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.Subject, domain.SubjectResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetSubject(ctx context.Context, id string) (*domain.SubjectResponse, error) {
+	ctx, span := startSpan(ctx, "GetSubject")
+	defer span.End()
+	result, err := s.repo.GetSubject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.Subject, domain.SubjectResponse](result), nil
+}
+
+func (s *Service) UpdateSubject(ctx context.Context, id string, req *domain.UpdateSubjectRequest) (*domain.SubjectResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateSubject")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required subject id")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	subject, err := s.repo.GetSubject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if semesterID := domain.OptionalID(req.SemesterID); semesterID != nil {
+		if err := s.checkSubjectSemester(ctx, subject.ProgramID, *semesterID); err != nil {
+			return nil, err
+		}
+	}
+	result, err := s.repo.UpdateSubject(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "subject",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s subject details.", result.Code),
+		Before:     subject,
+		After:      result,
+	})
+	return domain.Convert[domain.Subject, domain.SubjectResponse](result), nil
+}
+
+// DeleteSubject deletes a Subject that is not scheduled in any routine
+func (s *Service) DeleteSubject(ctx context.Context, id string) (*domain.SubjectResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteSubject")
+	defer span.End()
+	result, err := s.repo.GetSubject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	routines, err := s.repo.CountSubjectRoutines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if routines > 0 {
+		return nil, domain.NewConflictError("subject_in_use", fmt.Sprintf("subject has %d routines cannot delete it", routines))
+	}
+	if err := s.repo.DeleteSubject(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "subject",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s subject.", result.Code),
+		Before:     result,
+	})
+	return domain.Convert[domain.Subject, domain.SubjectResponse](result), nil
+}
+
+func (s *Service) checkSubjectSemester(ctx context.Context, programID, semesterID string) error {
+	semester, err := s.repo.GetSemester(ctx, semesterID)
+	if err != nil {
+		return domain.NewNotFoundError("semester_not_found", fmt.Sprintf("semester %s not found", semesterID))
+	}
+	if semester.ProgramID != programID {
+		return domain.NewValidationError("program_mismatch", fmt.Sprintf("semester %s does not belong to program %s", semesterID, programID))
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// CreateTimeSlot creates a new TimeSlot that does not overlap any existing slot
+func (s *Service) CreateTimeSlot(ctx context.Context, req *domain.TimeSlotRequest) (*domain.TimeSlotResponse, error) {
+	ctx, span := startSpan(ctx, "CreateTimeSlot")
+	defer span.End()
+	start, end, err := req.Clock()
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTimeSlotOverlap(ctx, start, end, ""); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.CreateTimeSlot(ctx, &domain.TimeSlot{
+		Name:      req.Name,
+		StartTime: start,
+		EndTime:   end,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "time_slot",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new time slot %s-%s.", req.StartTime, req.EndTime),
+		After:      result,
+	})
+	return result.TimeSlotResponse(), nil
+}
+
+// ListTimeSlot retrieves a list of TimeSlots
+func (s *Service) ListTimeSlot(ctx context.Context, req *domain.ListTimeSlotRequest) ([]*domain.TimeSlotResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListTimeSlot")
+	defer span.End()
+	var datas = []*domain.TimeSlotResponse{}
+	results, count, err := s.repo.ListTimeSlot(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, result.TimeSlotResponse())
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetTimeSlot(ctx context.Context, id string) (*domain.TimeSlotResponse, error) {
+	ctx, span := startSpan(ctx, "GetTimeSlot")
+	defer span.End()
+	result, err := s.repo.GetTimeSlot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return result.TimeSlotResponse(), nil
+}
+
+func (s *Service) UpdateTimeSlot(ctx context.Context, id string, req *domain.UpdateTimeSlotRequest) (*domain.TimeSlotResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateTimeSlot")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required time slot id")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	slot, err := s.repo.GetTimeSlot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start, end := slot.StartTime, slot.EndTime
+	if req.StartTime != "" {
+		start, _ = domain.ParseClock(req.StartTime)
+	}
+	if req.EndTime != "" {
+		end, _ = domain.ParseClock(req.EndTime)
+	}
+	if !end.After(start) {
+		return nil, domain.NewValidationError("invalid_range", "end time must be after start time")
+	}
+	if err := s.checkTimeSlotOverlap(ctx, start, end, id); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.UpdateTimeSlot(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "time_slot",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated time slot %s.", result.TimeSlotResponse().StartTime),
+		Before:     slot,
+		After:      result,
+	})
+	return result.TimeSlotResponse(), nil
+}
+
+// DeleteTimeSlot deletes a TimeSlot that is not scheduled in any routine
+func (s *Service) DeleteTimeSlot(ctx context.Context, id string) (*domain.TimeSlotResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteTimeSlot")
+	defer span.End()
+	result, err := s.repo.GetTimeSlot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	routines, err := s.repo.CountTimeSlotRoutines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if routines > 0 {
+		return nil, domain.NewConflictError("time_slot_in_use", fmt.Sprintf("time slot has %d routines cannot delete it", routines))
+	}
+	if err := s.repo.DeleteTimeSlot(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "time_slot",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted time slot %s.", result.TimeSlotResponse().StartTime),
+		Before:     result,
+	})
+	return result.TimeSlotResponse(), nil
+}
+
+func (s *Service) checkTimeSlotOverlap(ctx context.Context, start, end time.Time, excludeID string) error {
+	slots, err := s.repo.ListOverlappingTimeSlots(ctx, start, end, excludeID)
+	if err != nil {
+		return err
+	}
+	if len(slots) > 0 {
+		data := slots[0].TimeSlotResponse()
+		return domain.NewConflictError("time_slot_overlap", fmt.Sprintf("time slot overlaps %s-%s", data.StartTime, data.EndTime))
+	}
+	return nil
+}
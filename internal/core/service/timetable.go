@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+	util "github.com/sugaml/lms-api/internal/core/utils"
+)
+
+var weekdays = map[domain.DayOfWeek]time.Weekday{
+	domain.Monday:    time.Monday,
+	domain.Tuesday:   time.Tuesday,
+	domain.Wednesday: time.Wednesday,
+	domain.Thursday:  time.Thursday,
+	domain.Friday:    time.Friday,
+	domain.Saturday:  time.Saturday,
+}
+
+// GetTimetable renders the routines of a semester, teacher or room as a DayOfWeek × TimeSlot grid
+func (s *Service) GetTimetable(ctx context.Context, req *domain.TimetableRequest) (*domain.TimetableResponse, error) {
+	ctx, span := startSpan(ctx, "GetTimetable")
+	defer span.End()
+	title, err := s.timetableTitle(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	routines, err := s.repo.ListTimetableRoutines(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	timeSlots, err := s.repo.ListAllTimeSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]domain.TimetableSlot, 0, len(timeSlots))
+	index := map[string]int{}
+	for i, slot := range timeSlots {
+		index[slot.ID] = i
+		slots = append(slots, domain.TimetableSlot{
+			TimeSlotID: slot.ID,
+			StartTime:  slot.StartTime,
+			EndTime:    slot.EndTime,
+			Cells:      map[domain.DayOfWeek][]*domain.ClassRoutineResponse{},
+		})
+	}
+	for _, routine := range routines {
+		i, ok := index[routine.TimeSlotID]
+		if !ok {
+			continue
+		}
+		slots[i].Cells[routine.DayOfWeek] = append(slots[i].Cells[routine.DayOfWeek], routine.RoutineResponse())
+	}
+	return &domain.TimetableResponse{
+		View:         req.View,
+		ID:           req.ID,
+		Title:        title,
+		AcademicYear: req.AcademicYear,
+		Days:         domain.Days,
+		Slots:        slots,
+	}, nil
+}
+
+// GetTimetableCalendar exports a timetable as weekly recurring iCalendar events bounded by the academic year
+func (s *Service) GetTimetableCalendar(ctx context.Context, req *domain.TimetableRequest) ([]byte, error) {
+	ctx, span := startSpan(ctx, "GetTimetableCalendar")
+	defer span.End()
+	title, err := s.timetableTitle(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var year *domain.AcademicYear
+	if req.AcademicYear != "" {
+		year, err = s.repo.GetAcademicYearByName(ctx, req.AcademicYear)
+		if err != nil {
+			return nil, domain.NewNotFoundError("academic_year_not_found", fmt.Sprintf("academic year %s not found", req.AcademicYear))
+		}
+	} else {
+		year, err = s.repo.GetCurrentAcademicYear(ctx)
+		if err != nil {
+			return nil, domain.NewValidationError("academic_year_required", "no academic year is running, academic_year is required")
+		}
+		req.AcademicYear = year.Name
+	}
+	routines, err := s.repo.ListTimetableRoutines(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	until := time.Date(year.EndDate.Year(), year.EndDate.Month(), year.EndDate.Day(), 23, 59, 59, 0, time.Local)
+	events := make([]util.CalendarEvent, 0, len(routines))
+	for _, routine := range routines {
+		weekday, ok := weekdays[routine.DayOfWeek]
+		if !ok || routine.TimeSlot == nil {
+			continue
+		}
+		first := util.NextWeekday(year.StartDate, weekday)
+		if first.After(until) {
+			continue
+		}
+		data := routine.RoutineResponse()
+		events = append(events, util.CalendarEvent{
+			UID:         routine.ID + "@lms",
+			Summary:     fmt.Sprintf("%s %s", data.SubjectCode, data.SubjectName),
+			Description: fmt.Sprintf("%s %s, %s", data.SemesterName, data.Section, data.TeacherName),
+			Location:    data.Location,
+			Start:       util.AtClock(first, routine.TimeSlot.StartTime),
+			End:         util.AtClock(first, routine.TimeSlot.EndTime),
+			Until:       until,
+		})
+	}
+	return util.BuildCalendar(fmt.Sprintf("%s %s", title, year.Name), events), nil
+}
+
+func (s *Service) timetableTitle(ctx context.Context, req *domain.TimetableRequest) (string, error) {
+	switch req.View {
+	case domain.TimetableSemester:
+		semester, err := s.repo.GetSemester(ctx, req.ID)
+		if err != nil {
+			return "", domain.NewNotFoundError("semester_not_found", fmt.Sprintf("semester %s not found", req.ID))
+		}
+		if req.Section != "" {
+			return fmt.Sprintf("%s (%s)", semester.Name, req.Section), nil
+		}
+		return semester.Name, nil
+	case domain.TimetableTeacher:
+		teacher, err := s.repo.GetUser(ctx, req.ID)
+		if err != nil {
+			return "", domain.NewNotFoundError("teacher_not_found", fmt.Sprintf("teacher %s not found", req.ID))
+		}
+		return teacher.FullName, nil
+	case domain.TimetableRoom:
+		room, err := s.repo.GetRoom(ctx, req.ID)
+		if err != nil {
+			return "", domain.NewNotFoundError("room_not_found", fmt.Sprintf("room %s not found", req.ID))
+		}
+		return room.RoomCode, nil
+	}
+	return "", domain.NewValidationError("invalid_view", fmt.Sprintf("unsupported timetable view %s", req.View))
+}
+
+// CreateAcademicYear creates a new AcademicYear
+func (s *Service) CreateAcademicYear(ctx context.Context, req *domain.AcademicYearRequest) (*domain.AcademicYearResponse, error) {
+	ctx, span := startSpan(ctx, "CreateAcademicYear")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data := domain.Convert[domain.AcademicYearRequest, domain.AcademicYear](req)
+	result, err := s.repo.CreateAcademicYear(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "academic_year",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created academic year %s.", result.Name),
+		After:      result,
+	})
+	return domain.Convert[domain.AcademicYear, domain.AcademicYearResponse](result), nil
+}
+
+func (s *Service) ListAcademicYear(ctx context.Context, req *domain.ListAcademicYearRequest) ([]*domain.AcademicYearResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListAcademicYear")
+	defer span.End()
+	var datas = []*domain.AcademicYearResponse{}
+	results, count, err := s.repo.ListAcademicYear(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, domain.Convert[domain.AcademicYear, domain.AcademicYearResponse](result))
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetAcademicYear(ctx context.Context, id string) (*domain.AcademicYearResponse, error) {
+	ctx, span := startSpan(ctx, "GetAcademicYear")
+	defer span.End()
+	result, err := s.repo.GetAcademicYear(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.AcademicYear, domain.AcademicYearResponse](result), nil
+}
+
+func (s *Service) UpdateAcademicYear(ctx context.Context, id string, req *domain.UpdateAcademicYearRequest) (*domain.AcademicYearResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateAcademicYear")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required academic year id")
+	}
+	year, err := s.repo.GetAcademicYear(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start, end := year.StartDate, year.EndDate
+	if !req.StartDate.IsZero() {
+		start = req.StartDate
+	}
+	if !req.EndDate.IsZero() {
+		end = req.EndDate
+	}
+	if !end.After(start) {
+		return nil, domain.NewValidationError("invalid_range", "end date must be after start date")
+	}
+	if req.Name != "" && req.Name != year.Name {
+		count, err := s.repo.CountAcademicYearRoutines(ctx, year.Name)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, domain.NewPolicyViolationError("academic_year_in_use", fmt.Sprintf("academic year has %d routines cannot rename it", count))
+		}
+	}
+	result, err := s.repo.UpdateAcademicYear(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "academic_year",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated academic year %s.", result.Name),
+		Before:     year,
+		After:      result,
+	})
+	return domain.Convert[domain.AcademicYear, domain.AcademicYearResponse](result), nil
+}
+
+func (s *Service) DeleteAcademicYear(ctx context.Context, id string) (*domain.AcademicYearResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteAcademicYear")
+	defer span.End()
+	result, err := s.repo.GetAcademicYear(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.repo.CountAcademicYearRoutines(ctx, result.Name)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, domain.NewConflictError("academic_year_in_use", fmt.Sprintf("academic year has %d routines cannot delete it", count))
+	}
+	if err := s.repo.DeleteAcademicYear(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "academic_year",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted academic year %s.", result.Name),
+		Before:     result,
+	})
+	return domain.Convert[domain.AcademicYear, domain.AcademicYearResponse](result), nil
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IssueCalendarToken gives the caller a new token for the timetable feeds and revokes the one
+// they had, the token is shown this once
+func (s *Service) IssueCalendarToken(ctx context.Context) (*domain.CalendarTokenResponse, error) {
+	ctx, span := startSpan(ctx, "IssueCalendarToken")
+	defer span.End()
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	token := "cal_" + hex.EncodeToString(secret)
+	result, err := s.repo.SaveCalendarToken(ctx, &domain.CalendarToken{
+		UserID:    userID,
+		TokenHash: hashCalendarToken(token),
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "calendar_token",
+		EntityID:   result.ID,
+		Title:      "Issued a new calendar token.",
+		After:      result,
+	})
+	return &domain.CalendarTokenResponse{CreatedAt: result.CreatedAt, Token: token}, nil
+}
+
+// GetCalendarToken tells the caller when their calendar token was issued
+func (s *Service) GetCalendarToken(ctx context.Context) (*domain.CalendarTokenResponse, error) {
+	ctx, span := startSpan(ctx, "GetCalendarToken")
+	defer span.End()
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetCalendarToken(ctx, userID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("calendar_token_not_found", "no calendar token has been issued")
+	}
+	return &domain.CalendarTokenResponse{CreatedAt: result.CreatedAt}, nil
+}
+
+// RevokeCalendarToken stops the caller's calendar token from reading the feeds
+func (s *Service) RevokeCalendarToken(ctx context.Context) (*domain.CalendarTokenResponse, error) {
+	ctx, span := startSpan(ctx, "RevokeCalendarToken")
+	defer span.End()
+	userID, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetCalendarToken(ctx, userID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("calendar_token_not_found", "no calendar token has been issued")
+	}
+	if err := s.repo.DeleteCalendarToken(ctx, userID); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "calendar_token",
+		EntityID:   result.ID,
+		Title:      "Revoked the calendar token.",
+		Before:     result,
+	})
+	return &domain.CalendarTokenResponse{CreatedAt: result.CreatedAt}, nil
+}
+
+func (s *Service) VerifyCalendarToken(ctx context.Context, token string) (string, error) {
+	ctx, span := startSpan(ctx, "VerifyCalendarToken")
+	defer span.End()
+	result, err := s.repo.GetCalendarTokenByHash(ctx, hashCalendarToken(token))
+	if err != nil {
+		return "", domain.NewUnauthorizedError("invalid_token", "the calendar token is not valid")
+	}
+	return result.UserID, nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+// defaultMaxDailyLoad is the classes per teacher per day the solver tries not to exceed
+const defaultMaxDailyLoad = 4
+
+// GenerateTimetableDraft proposes a week of routines for a semester section and saves it as a draft for review
+func (s *Service) GenerateTimetableDraft(ctx context.Context, req *domain.GenerateTimetableRequest) (*domain.TimetableDraftResponse, error) {
+	ctx, span := startSpan(ctx, "GenerateTimetableDraft")
+	defer span.End()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	_, err := getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	semester, err := s.repo.GetSemester(ctx, req.SemesterID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("semester_not_found", fmt.Sprintf("semester %s not found", req.SemesterID))
+	}
+	slots, err := s.repo.ListAllTimeSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(slots) == 0 {
+		return nil, domain.NewPolicyViolationError("no_time_slots", "no time slots are defined")
+	}
+	rooms, err := s.repo.ListSchedulableRooms(ctx, req.RoomIDs)
+	if err != nil {
+		return nil, err
+	}
+	lessons, unplaced, err := s.draftLessons(ctx, semester, req)
+	if err != nil {
+		return nil, err
+	}
+	teacherIDs := []string{}
+	for _, a := range req.Assignments {
+		teacherIDs = append(teacherIDs, a.TeacherID)
+	}
+	unavailability, err := s.repo.ListTeachersUnavailability(ctx, teacherIDs)
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := s.repo.ListScheduledRoutines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLoad := req.MaxDailyLoad
+	if maxLoad == 0 {
+		maxLoad = defaultMaxDailyLoad
+	}
+	solver := newTimetableSolver(semester.ID, req.StudentCount, maxLoad, slots, rooms)
+	for _, routine := range scheduled {
+		solver.occupy(routine)
+	}
+	for _, u := range unavailability {
+		solver.block(u)
+	}
+	entries, missed, score := solver.solve(lessons)
+	unplaced = append(unplaced, missed...)
+
+	result, err := s.repo.CreateTimetableDraft(ctx, &domain.TimetableDraft{
+		ProgramID:    semester.ProgramID,
+		SemesterID:   semester.ID,
+		Section:      req.Section,
+		StudentCount: req.StudentCount,
+		AcademicYear: req.AcademicYear,
+		Status:       domain.DraftPending,
+		Score:        score,
+		Unplaced:     string(domain.ConvertToJson(unplaced)),
+		Entries:      entries,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "timetable_draft",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Generated timetable draft for %s with %d classes and %d unplaced.", semester.Name, len(entries), len(unplaced)),
+		After:      result,
+		Data:       req,
+	})
+	return result.DraftResponse(), nil
+}
+
+// draftLessons expands the semester's subjects and teacher assignments into weekly sessions,
+// reporting subjects nobody can teach as unplaced
+func (s *Service) draftLessons(ctx context.Context, semester *domain.Semester, req *domain.GenerateTimetableRequest) ([]*solverLesson, []domain.UnplacedLesson, error) {
+	subjects, _, err := s.repo.ListSubject(ctx, &domain.ListSubjectRequest{
+		ListRequest: domain.ListRequest{Page: 1, Size: 1000, SortColumn: "code", SortDirection: "asc"},
+		SemesterID:  semester.ID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	assigned := map[string]bool{}
+	lessons := []*solverLesson{}
+	unplaced := []domain.UnplacedLesson{}
+	for _, a := range req.Assignments {
+		subject, err := s.repo.GetSubject(ctx, a.SubjectID)
+		if err != nil {
+			return nil, nil, domain.NewNotFoundError("subject_not_found", fmt.Sprintf("subject %s not found", a.SubjectID))
+		}
+		if subject.ProgramID != semester.ProgramID {
+			return nil, nil, domain.NewValidationError("program_mismatch", fmt.Sprintf("subject %s does not belong to the semester's program", subject.Code))
+		}
+		assigned[subject.ID] = true
+		teacher, err := s.repo.GetUser(ctx, a.TeacherID)
+		if err != nil {
+			return nil, nil, domain.NewNotFoundError("teacher_not_found", fmt.Sprintf("teacher %s not found", a.TeacherID))
+		}
+		if !teacher.IsActive {
+			unplaced = append(unplaced, domain.UnplacedLesson{
+				SubjectID:   subject.ID,
+				SubjectCode: subject.Code,
+				TeacherID:   teacher.ID,
+				Reason:      fmt.Sprintf("%s is not an active user", teacher.FullName),
+			})
+			continue
+		}
+		sessions := a.SessionsPerWeek
+		if sessions == 0 {
+			sessions = subject.CreditHours
+		}
+		if sessions == 0 {
+			sessions = 1
+		}
+		for i := 1; i <= sessions; i++ {
+			lessons = append(lessons, &solverLesson{subject: subject, teacherID: teacher.ID, session: i})
+		}
+	}
+	for _, subject := range subjects {
+		if !assigned[subject.ID] {
+			unplaced = append(unplaced, domain.UnplacedLesson{
+				SubjectID:   subject.ID,
+				SubjectCode: subject.Code,
+				Reason:      "no teacher assigned",
+			})
+		}
+	}
+	return lessons, unplaced, nil
+}
+
+func (s *Service) ListTimetableDraft(ctx context.Context, req *domain.ListTimetableDraftRequest) ([]*domain.TimetableDraftResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListTimetableDraft")
+	defer span.End()
+	var datas = []*domain.TimetableDraftResponse{}
+	results, count, err := s.repo.ListTimetableDraft(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		datas = append(datas, result.DraftResponse())
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetTimetableDraft(ctx context.Context, id string) (*domain.TimetableDraftResponse, error) {
+	ctx, span := startSpan(ctx, "GetTimetableDraft")
+	defer span.End()
+	result, err := s.repo.GetTimetableDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return result.DraftResponse(), nil
+}
+
+// DeleteTimetableDraft discards a draft, committed routines are left untouched
+func (s *Service) DeleteTimetableDraft(ctx context.Context, id string) (*domain.TimetableDraftResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteTimetableDraft")
+	defer span.End()
+	result, err := s.repo.GetTimetableDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteTimetableDraft(ctx, id); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "timetable_draft",
+		EntityID:   result.ID,
+		Title:      "Discarded timetable draft.",
+		Before:     result,
+	})
+	return result.DraftResponse(), nil
+}
+
+// CommitTimetableDraft turns a draft into ClassRoutines, re-checking every entry against
+// the schedule as it is now since it may have changed after the draft was generated
+func (s *Service) CommitTimetableDraft(ctx context.Context, id string) ([]*domain.ClassRoutineResponse, error) {
+	ctx, span := startSpan(ctx, "CommitTimetableDraft")
+	defer span.End()
+	draft, err := s.repo.GetTimetableDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status == domain.DraftCommitted {
+		return nil, domain.NewConflictError("draft_already_committed", "timetable draft is already committed")
+	}
+	_, err = getUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	routines := make([]*domain.ClassRoutine, 0, len(draft.Entries))
+	conflicts := []domain.RoutineConflict{}
+	for i := range draft.Entries {
+		routine := draft.Entries[i].Routine(draft)
+		found, err := s.routineConflicts(ctx, routine)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, found...)
+		routines = append(routines, routine)
+	}
+	if len(conflicts) > 0 {
+		return nil, &domain.RoutineConflictError{Conflicts: conflicts}
+	}
+	if err := s.repo.CommitTimetableDraft(ctx, draft, routines); err != nil {
+		return nil, err
+	}
+	datas := []*domain.ClassRoutineResponse{}
+	for _, routine := range routines {
+		datas = append(datas, routine.RoutineResponse())
+	}
+	s.audit(ctx, auditEntry{
+		Action:     "commit",
+		EntityType: "timetable_draft",
+		EntityID:   draft.ID,
+		Title:      fmt.Sprintf("Committed timetable draft with %d routines.", len(routines)),
+		Data:       domain.Map{"routines": len(routines)},
+	})
+	return datas, nil
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/sugaml/lms-api/internal/core/service")
+
+// startSpan opens the span of a service method under the request's span, the caller ends it
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "service."+method)
+}
+
+// detach keeps the trace of ctx for work that outlives the request, the request's context is
+// cancelled and reused once the handler returns
+func detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
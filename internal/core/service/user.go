@@ -1,7 +1,7 @@
 package service
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"strings"
 
@@ -10,7 +10,9 @@ import (
 	util "github.com/sugaml/lms-api/internal/core/utils"
 )
 
-func (s *Service) CreateBulkUser(data *[]domain.UserRequest) ([]*domain.UserResponse, error) {
+func (s *Service) CreateBulkUser(ctx context.Context, data *[]domain.UserRequest) ([]*domain.UserResponse, error) {
+	ctx, span := startSpan(ctx, "CreateBulkUser")
+	defer span.End()
 	var responses []*domain.UserResponse
 	for _, req := range *data {
 		err := req.Validate()
@@ -24,37 +26,41 @@ func (s *Service) CreateBulkUser(data *[]domain.UserRequest) ([]*domain.UserResp
 		}
 		// if data.Role == "student" {
 		// 	data.Username = strings.ToLower(data.Username) + "." + data.StudentID
-		// 	studentExist, err := s.repo.GetStudentbyID(data.StudentID)
+		// 	studentExist, err := s.repo.GetStudentbyID(ctx, data.StudentID)
 		// 	if studentExist != nil && err == nil {
 		// 		return nil, errors.New("student already exist")
 		// 	}
 		// }
-		result, err := s.repo.CreateUser(data)
+		result, err := s.repo.CreateUser(ctx, data)
 		if err != nil {
 			return nil, err
 		}
-		s.repo.CreateNotification(&domain.Notification{
-			Title:    fmt.Sprintf("New student %s created.", result.Username),
-			UserID:   result.ID,
-			Module:   "user",
-			Action:   "create",
-			IsActive: true,
+		s.notify(ctx, notice{
+			UserID:      result.ID,
+			Event:       domain.EventAccountCreated,
+			Title:       fmt.Sprintf("New student %s created.", result.Username),
+			ReferenceID: result.ID,
 		})
-		_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-			Title:    fmt.Sprintf("Created new student %s.", result.Username),
-			UserID:   &result.ID,
-			Action:   "create",
-			Data:     string(domain.ConvertToJson(result)),
-			IsActive: true,
+		s.audit(ctx, auditEntry{
+			Action:     domain.AuditCreate,
+			EntityType: "user",
+			EntityID:   result.ID,
+			Title:      fmt.Sprintf("Created new student %s.", result.Username),
+			After:      result,
 		})
 		logrus.Infof("Student %s created successfully", result.Username)
-		responses = append(responses, domain.Convert[domain.User, domain.UserResponse](result))
+		response := domain.Convert[domain.User, domain.UserResponse](result)
+		actorID, _ := getUserID(ctx)
+		s.emit(domain.EventTypeUserCreated, actorID, response)
+		responses = append(responses, response)
 	}
 	return responses, nil
 }
 
 // CreateUser creates a new User
-func (s *Service) CreateUser(req *domain.UserRequest) (*domain.UserResponse, error) {
+func (s *Service) CreateUser(ctx context.Context, req *domain.UserRequest) (*domain.UserResponse, error) {
+	ctx, span := startSpan(ctx, "CreateUser")
+	defer span.End()
 	err := req.Validate()
 	if err != nil {
 		return nil, err
@@ -66,34 +72,38 @@ func (s *Service) CreateUser(req *domain.UserRequest) (*domain.UserResponse, err
 	}
 	// if data.Role == "student" {
 	// 	data.Username = strings.ToLower(data.Username) + "." + data.StudentID
-	// 	studentExist, err := s.repo.GetStudentbyID(data.StudentID)
+	// 	studentExist, err := s.repo.GetStudentbyID(ctx, data.StudentID)
 	// 	if studentExist != nil && err == nil {
 	// 		return nil, errors.New("student already exist")
 	// 	}
 	// }
-	result, err := s.repo.CreateUser(data)
+	result, err := s.repo.CreateUser(ctx, data)
 	if err != nil {
 		return nil, err
 	}
-	s.repo.CreateNotification(&domain.Notification{
-		Title:    fmt.Sprintf("New User %s created.", result.Username),
-		UserID:   result.ID,
-		Module:   "user",
-		Action:   "create",
-		IsActive: true,
+	s.notify(ctx, notice{
+		UserID:      result.ID,
+		Event:       domain.EventAccountCreated,
+		Title:       fmt.Sprintf("New User %s created.", result.Username),
+		ReferenceID: result.ID,
 	})
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Created new User %s.", result.Username),
-		UserID:   &result.ID,
-		Action:   "create",
-		Data:     string(domain.ConvertToJson(result)),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "user",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new User %s.", result.Username),
+		After:      result,
 	})
-	return domain.Convert[domain.User, domain.UserResponse](result), nil
+	response := domain.Convert[domain.User, domain.UserResponse](result)
+	actorID, _ := getUserID(ctx)
+	s.emit(domain.EventTypeUserCreated, actorID, response)
+	return response, nil
 }
 
-func (s *Service) LoginUser(req *domain.LoginRequest) (*domain.LoginUserResponse, error) {
-	user, err := s.repo.GetUserbyUsername(req.Username)
+func (s *Service) LoginUser(ctx context.Context, req *domain.LoginRequest) (*domain.LoginUserResponse, error) {
+	ctx, span := startSpan(ctx, "LoginUser")
+	defer span.End()
+	user, err := s.repo.GetUserbyUsername(ctx, req.Username)
 	if err != nil {
 		return nil, err
 	}
@@ -109,12 +119,13 @@ func (s *Service) LoginUser(req *domain.LoginRequest) (*domain.LoginUserResponse
 		return nil, err
 	}
 	// logrus.Infof("User %s logged in successfully", user.Role)
-	s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("User %s logged in.", user.Username),
-		UserID:   &user.ID,
-		Action:   "login",
-		Data:     fmt.Sprintf("User %s logged in successfully", user.Username),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     "login",
+		EntityType: "user",
+		EntityID:   user.ID,
+		Title:      fmt.Sprintf("User %s logged in.", user.Username),
+		ActorID:    user.ID,
+		ActorName:  user.Username,
 	})
 	result := domain.Convert[domain.User, domain.UserResponse](user)
 	for _, role := range user.Roles {
@@ -127,9 +138,11 @@ func (s *Service) LoginUser(req *domain.LoginRequest) (*domain.LoginUserResponse
 }
 
 // ListUsers retrieves a list of Users
-func (s *Service) ListUser(req *domain.UserListRequest) ([]*domain.UserResponse, int64, error) {
+func (s *Service) ListUser(ctx context.Context, req *domain.UserListRequest) ([]*domain.UserResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListUser")
+	defer span.End()
 	var datas = []*domain.UserResponse{}
-	results, count, err := s.repo.ListUser(req)
+	results, count, err := s.repo.ListUser(ctx, req)
 	if err != nil {
 		return nil, count, err
 	}
@@ -141,9 +154,11 @@ func (s *Service) ListUser(req *domain.UserListRequest) ([]*domain.UserResponse,
 }
 
 // ListUsers retrieves a list of Users
-func (s *Service) ListStudent(req *domain.UserListRequest) ([]*domain.StudentResponse, int64, error) {
+func (s *Service) ListStudent(ctx context.Context, req *domain.UserListRequest) ([]*domain.StudentResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListStudent")
+	defer span.End()
 	var datas = []*domain.StudentResponse{}
-	results, count, err := s.repo.ListStudent(req)
+	results, count, err := s.repo.ListStudent(ctx, req)
 	if err != nil {
 		return nil, count, err
 	}
@@ -159,8 +174,10 @@ func (s *Service) ListStudent(req *domain.UserListRequest) ([]*domain.StudentRes
 	return datas, count, nil
 }
 
-func (s *Service) GetUser(id string) (*domain.UserResponse, error) {
-	result, err := s.repo.GetUser(id)
+func (s *Service) GetUser(ctx context.Context, id string) (*domain.UserResponse, error) {
+	ctx, span := startSpan(ctx, "GetUser")
+	defer span.End()
+	result, err := s.repo.GetUser(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -168,33 +185,40 @@ func (s *Service) GetUser(id string) (*domain.UserResponse, error) {
 	return data, nil
 }
 
-func (s *Service) UpdateUser(id string, req *domain.UserUpdateRequest) (*domain.UserResponse, error) {
+func (s *Service) UpdateUser(ctx context.Context, id string, req *domain.UserUpdateRequest) (*domain.UserResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateUser")
+	defer span.End()
 	if id == "" {
-		return nil, errors.New("required User id")
+		return nil, domain.NewValidationError("id_required", "required User id")
 	}
-	_, err := s.repo.GetUser(id)
+	before, err := s.repo.GetUser(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	// update
 	mp := req.NewUpdate()
-	result, err := s.repo.UpdateUser(id, mp)
+	result, err := s.repo.UpdateUser(ctx, id, mp)
 	if err != nil {
 		return nil, err
 	}
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Updated %s User details.", result.Username),
-		UserID:   &result.ID,
-		Action:   "update",
-		Data:     fmt.Sprint(req),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "user",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s User details.", result.Username),
+		Before:     before,
+		After:      result,
 	})
 	data := domain.Convert[domain.User, domain.UserResponse](result)
+	actorID, _ := getUserID(ctx)
+	s.emit(domain.EventTypeUserUpdated, actorID, data)
 	return data, nil
 }
 
-func (s *Service) DeleteUser(id string) (*domain.UserResponse, error) {
-	result, err := s.repo.GetUser(id)
+func (s *Service) DeleteUser(ctx context.Context, id string) (*domain.UserResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteUser")
+	defer span.End()
+	result, err := s.repo.GetUser(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -202,24 +226,27 @@ func (s *Service) DeleteUser(id string) (*domain.UserResponse, error) {
 	// 	return nil, errors.New("cannot delete admin user")
 	// }
 	// if result.Role == "student" {
-	// 	CountBorrwedCopiesUserID, err := s.repo.CountBorrwedCopiesUserID(id)
+	// 	CountBorrwedCopiesUserID, err := s.repo.CountBorrwedCopiesUserID(ctx, id)
 	// 	if err != nil {
 	// 		return nil, err
 	// 	}
 	// 	if CountBorrwedCopiesUserID > 0 {
-	// 		return nil, fmt.Errorf("user has %d copies borrowed cannot delete it", CountBorrwedCopiesUserID)
+	// 		return nil, domain.NewConflictError("user_in_use", fmt.Sprintf("user has %d copies borrowed cannot delete it", CountBorrwedCopiesUserID))
 	// 	}
 	// }
-	err = s.repo.DeleteUser(id)
+	err = s.repo.DeleteUser(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	_, _ = s.repo.CreateAuditLog(&domain.AuditLog{
-		Title:    fmt.Sprintf("Deleted %s parking area.", result.Username),
-		UserID:   &result.ID,
-		Action:   "delete",
-		Data:     fmt.Sprint(result),
-		IsActive: true,
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "user",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s user.", result.Username),
+		Before:     result,
 	})
-	return domain.Convert[domain.User, domain.UserResponse](result), nil
+	response := domain.Convert[domain.User, domain.UserResponse](result)
+	actorID, _ := getUserID(ctx)
+	s.emit(domain.EventTypeUserDeleted, actorID, response)
+	return response, nil
 }
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/lms-api/internal/core/domain"
+)
+
+const (
+	webhookBatchSize     = 50
+	webhookPollInterval  = 15 * time.Second
+	webhookLease         = 2 * time.Minute // longer than a post can take
+	webhookQueueCapacity = 1
+)
+
+// emit publishes a domain event, actorID is empty when the change was not made by a signed in user
+func (s *Service) emit(eventType, actorID string, data interface{}) {
+	s.events.Publish(&domain.DomainEvent{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		ActorID:    actorID,
+		Data:       data,
+	})
+}
+
+// webhookAdmin loads the caller and makes sure they are an admin
+func (s *Service) webhookAdmin(ctx context.Context) (*domain.NotificationRecipient, error) {
+	recipient, err := s.recipient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(recipient.Roles, domain.RoleAdmin) {
+		return nil, domain.NewForbiddenError("not_allowed", "only an admin can manage webhooks")
+	}
+	return recipient, nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+func (s *Service) CreateWebhookSubscription(ctx context.Context, req *domain.WebhookSubscriptionRequest) (*domain.WebhookSubscriptionResponse, error) {
+	ctx, span := startSpan(ctx, "CreateWebhookSubscription")
+	defer span.End()
+	admin, err := s.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+	data := &domain.WebhookSubscription{
+		Name:      req.Name,
+		URL:       req.URL,
+		Secret:    req.Secret,
+		Events:    req.Events,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+		CreatedBy: admin.UserID,
+	}
+	if data.Secret == "" {
+		data.Secret, err = newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+	result, err := s.repo.CreateWebhookSubscription(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditCreate,
+		EntityType: "webhook_subscription",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Created new Webhook %s.", result.Name),
+		After:      result,
+	})
+	// the secret is shown this once, it is needed to verify signatures
+	response := domain.Convert[domain.WebhookSubscription, domain.WebhookSubscriptionResponse](result)
+	response.Secret = result.Secret
+	return response, nil
+}
+
+func (s *Service) ListWebhookSubscription(ctx context.Context, req *domain.ListWebhookSubscriptionRequest) ([]*domain.WebhookSubscriptionResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListWebhookSubscription")
+	defer span.End()
+	var datas = []*domain.WebhookSubscriptionResponse{}
+	if _, err := s.webhookAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	results, count, err := s.repo.ListWebhookSubscription(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		data := domain.Convert[domain.WebhookSubscription, domain.WebhookSubscriptionResponse](result)
+		datas = append(datas, data)
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscriptionResponse, error) {
+	ctx, span := startSpan(ctx, "GetWebhookSubscription")
+	defer span.End()
+	if _, err := s.webhookAdmin(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.WebhookSubscription, domain.WebhookSubscriptionResponse](result), nil
+}
+
+func (s *Service) UpdateWebhookSubscription(ctx context.Context, id string, req *domain.UpdateWebhookSubscriptionRequest) (*domain.WebhookSubscriptionResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateWebhookSubscription")
+	defer span.End()
+	if id == "" {
+		return nil, domain.NewValidationError("id_required", "required Webhook id")
+	}
+	_, err := s.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// update
+	mp := req.NewUpdate()
+	result, err := s.repo.UpdateWebhookSubscription(ctx, id, mp)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditUpdate,
+		EntityType: "webhook_subscription",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Updated %s Webhook details.", result.Name),
+		Before:     before,
+		After:      result,
+	})
+	data := domain.Convert[domain.WebhookSubscription, domain.WebhookSubscriptionResponse](result)
+	if req.Secret != "" {
+		data.Secret = result.Secret
+	}
+	return data, nil
+}
+
+func (s *Service) DeleteWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscriptionResponse, error) {
+	ctx, span := startSpan(ctx, "DeleteWebhookSubscription")
+	defer span.End()
+	_, err := s.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.DeleteWebhookSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     domain.AuditDelete,
+		EntityType: "webhook_subscription",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Deleted %s webhook.", result.Name),
+		Before:     result,
+	})
+	return domain.Convert[domain.WebhookSubscription, domain.WebhookSubscriptionResponse](result), nil
+}
+
+// PingWebhookSubscription posts a webhook.ping straight away, even to an inactive subscription,
+// a failed ping is retried like any other delivery
+func (s *Service) PingWebhookSubscription(ctx context.Context, id string) (*domain.WebhookDeliveryResponse, error) {
+	ctx, span := startSpan(ctx, "PingWebhookSubscription")
+	defer span.End()
+	admin, err := s.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	event := &domain.DomainEvent{
+		ID:         uuid.NewString(),
+		Type:       domain.EventTypeWebhookPing,
+		OccurredAt: time.Now(),
+		ActorID:    admin.UserID,
+		Data:       map[string]string{"subscription_id": sub.ID, "name": sub.Name},
+	}
+	// leased like a claimed delivery so the worker leaves it to this request
+	delivery, err := s.repo.CreateWebhookDelivery(ctx, &domain.WebhookDelivery{
+		SubscriptionID: &sub.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        string(domain.ConvertToJson(event)),
+		Status:         domain.WebhookPending,
+		NextAttemptAt:  time.Now().Add(webhookLease),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := s.deliverWebhook(ctx, delivery, sub)
+	return domain.Convert[domain.WebhookDelivery, domain.WebhookDeliveryResponse](result), nil
+}
+
+func (s *Service) ListWebhookEventType(ctx context.Context) []string {
+	ctx, span := startSpan(ctx, "ListWebhookEventType")
+	defer span.End()
+	return domain.EventTypes
+}
+
+func (s *Service) ListWebhookDelivery(ctx context.Context, req *domain.ListWebhookDeliveryRequest) ([]*domain.WebhookDeliveryResponse, int64, error) {
+	ctx, span := startSpan(ctx, "ListWebhookDelivery")
+	defer span.End()
+	var datas = []*domain.WebhookDeliveryResponse{}
+	if _, err := s.webhookAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	results, count, err := s.repo.ListWebhookDelivery(ctx, req)
+	if err != nil {
+		return nil, count, err
+	}
+	for _, result := range results {
+		data := domain.Convert[domain.WebhookDelivery, domain.WebhookDeliveryResponse](result)
+		datas = append(datas, data)
+	}
+	return datas, count, nil
+}
+
+func (s *Service) GetWebhookDelivery(ctx context.Context, id string) (*domain.WebhookDeliveryResponse, error) {
+	ctx, span := startSpan(ctx, "GetWebhookDelivery")
+	defer span.End()
+	if _, err := s.webhookAdmin(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Convert[domain.WebhookDelivery, domain.WebhookDeliveryResponse](result), nil
+}
+
+// ReplayWebhookDelivery queues the payload again as a new delivery, the original keeps its log.
+// Receivers can tell a replay from the unchanged event id
+func (s *Service) ReplayWebhookDelivery(ctx context.Context, id string) (*domain.WebhookDeliveryResponse, error) {
+	ctx, span := startSpan(ctx, "ReplayWebhookDelivery")
+	defer span.End()
+	_, err := s.webhookAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	original, err := s.repo.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.CreateWebhookDelivery(ctx, &domain.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		UserID:         original.UserID,
+		URL:            original.URL,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         domain.WebhookPending,
+		NextAttemptAt:  time.Now(),
+		ReplayOf:       original.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, auditEntry{
+		Action:     "replay",
+		EntityType: "webhook_delivery",
+		EntityID:   result.ID,
+		Title:      fmt.Sprintf("Replayed webhook delivery %s of %s.", original.ID, original.EventType),
+		After:      result,
+	})
+	s.wakeWebhookWorker()
+	return domain.Convert[domain.WebhookDelivery, domain.WebhookDeliveryResponse](result), nil
+}
+
+// enqueueWebhooks queues a delivery of the event for every subscription that takes it, it is
+// subscribed to the event bus
+func (s *Service) enqueueWebhooks(event *domain.DomainEvent) {
+	ctx := context.Background()
+	subs, err := s.repo.ListEventWebhookSubscription(ctx, event.Type)
+	if err != nil {
+		logrus.WithError(err).Errorf("could not load webhooks for %s", event.Type)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+	payload := string(domain.ConvertToJson(event))
+	for _, sub := range subs {
+		_, err := s.repo.CreateWebhookDelivery(ctx, &domain.WebhookDelivery{
+			SubscriptionID: &sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         domain.WebhookPending,
+			NextAttemptAt:  time.Now(),
+		})
+		if err != nil {
+			logrus.WithError(err).Errorf("could not queue %s for webhook %s", event.Type, sub.ID)
+		}
+	}
+	s.wakeWebhookWorker()
+}
+
+func (s *Service) wakeWebhookWorker() {
+	select {
+	case s.webhookQueue <- struct{}{}:
+	default:
+	}
+}
+
+// RunWebhookDelivery sends queued webhook deliveries as they arrive and retries failed ones once
+// their backoff is over
+func (s *Service) RunWebhookDelivery(ctx context.Context) {
+	poll := time.NewTicker(webhookPollInterval)
+	defer poll.Stop()
+
+	s.runJob(ctx, "webhook_delivery", s.deliverWebhooks)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			s.runJob(ctx, "webhook_delivery", s.deliverWebhooks)
+		case <-s.webhookQueue:
+			s.runJob(ctx, "webhook_delivery", s.deliverWebhooks)
+		}
+	}
+}
+
+func (s *Service) deliverWebhooks(ctx context.Context) {
+	for {
+		deliveries, err := s.repo.ClaimDueWebhookDeliveries(ctx, time.Now(), webhookLease, webhookBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("could not claim webhook deliveries")
+			return
+		}
+		subs := map[string]*domain.WebhookSubscription{}
+		for _, delivery := range deliveries {
+			// a user's notification webhook has its url on the delivery and no subscription
+			if delivery.SubscriptionID == nil {
+				s.deliverWebhook(ctx, delivery, nil)
+				continue
+			}
+			sub, ok := subs[*delivery.SubscriptionID]
+			if !ok {
+				sub, err = s.repo.GetWebhookSubscription(ctx, *delivery.SubscriptionID)
+				if err != nil {
+					logrus.WithError(err).Warnf("could not load webhook %s", *delivery.SubscriptionID)
+					continue
+				}
+				subs[sub.ID] = sub
+			}
+			if !sub.IsActive {
+				_, _ = s.repo.UpdateWebhookDelivery(ctx, delivery.ID, domain.Map{
+					"status":     domain.WebhookFailed,
+					"last_error": "webhook is inactive",
+				})
+				continue
+			}
+			s.deliverWebhook(ctx, delivery, sub)
+		}
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// deliverWebhook posts one delivery, signed when it belongs to a subscription, and records the
+// outcome. A failure is retried with exponential backoff until MaxWebhookAttempts
+func (s *Service) deliverWebhook(ctx context.Context, delivery *domain.WebhookDelivery, sub *domain.WebhookSubscription) *domain.WebhookDelivery {
+	attempts := delivery.Attempts + 1
+	payload := []byte(delivery.Payload)
+	started := time.Now()
+	target := delivery.URL
+	headers := map[string]string{
+		domain.WebhookEventHeader:    delivery.EventType,
+		domain.WebhookDeliveryHeader: delivery.ID,
+	}
+	if sub != nil {
+		target = sub.URL
+		headers[domain.WebhookSignatureHeader] = domain.SignWebhook(sub.Secret, started, payload)
+	}
+	err := s.webhook.Post(target, payload, headers)
+	update := domain.Map{
+		"attempts":    attempts,
+		"duration_ms": time.Since(started).Milliseconds(),
+	}
+	var statusErr *domain.WebhookStatusError
+	switch {
+	case err == nil:
+		update["status"] = domain.WebhookDelivered
+		update["delivered_at"] = time.Now()
+		update["last_error"] = ""
+	case attempts >= domain.MaxWebhookAttempts:
+		update["status"] = domain.WebhookFailed
+		update["last_error"] = err.Error()
+	default:
+		update["next_attempt_at"] = time.Now().Add(domain.WebhookBackoff(attempts))
+		update["last_error"] = err.Error()
+	}
+	if errors.As(err, &statusErr) {
+		update["response_status"] = statusErr.StatusCode
+	}
+	if err != nil {
+		logrus.WithError(err).Warnf("webhook delivery %s of %s to %s failed on attempt %d", delivery.ID, delivery.EventType, target, attempts)
+	}
+	result, uerr := s.repo.UpdateWebhookDelivery(ctx, delivery.ID, update)
+	if uerr != nil {
+		logrus.WithError(uerr).Errorf("could not record webhook delivery %s", delivery.ID)
+		return delivery
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const icalDateTime = "20060102T150405"
+
+// CalendarEvent is a single VEVENT. When Until is set the event repeats weekly
+// on the weekday of Start up to and including Until.
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	Until       time.Time
+}
+
+// BuildCalendar renders events as an iCalendar (RFC 5545) document.
+// Times are written as floating local times so they follow the reader's calendar zone.
+func BuildCalendar(name string, events []CalendarEvent) []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:-//LMS//Class Routine//EN")
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	writeLine(&buf, "X-WR-CALNAME:"+escapeText(name))
+	stamp := time.Now().UTC().Format(icalDateTime) + "Z"
+	for _, e := range events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+e.UID)
+		writeLine(&buf, "DTSTAMP:"+stamp)
+		writeLine(&buf, "DTSTART:"+e.Start.Format(icalDateTime))
+		writeLine(&buf, "DTEND:"+e.End.Format(icalDateTime))
+		if !e.Until.IsZero() {
+			writeLine(&buf, "RRULE:FREQ=WEEKLY;UNTIL="+e.Until.Format(icalDateTime))
+		}
+		writeLine(&buf, "SUMMARY:"+escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		if e.Location != "" {
+			writeLine(&buf, "LOCATION:"+escapeText(e.Location))
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// NextWeekday returns the first date on or after from that falls on day
+func NextWeekday(from time.Time, day time.Weekday) time.Time {
+	offset := (int(day) - int(from.Weekday()) + 7) % 7
+	return from.AddDate(0, 0, offset)
+}
+
+// AtClock returns date with the hour, minute and second taken from clock
+func AtClock(date, clock time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, date.Location())
+}
+
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// writeLine folds content lines longer than 75 octets as required by RFC 5545
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// do not split a multi-byte UTF-8 character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		fmt.Fprintf(buf, "%s\r\n ", line[:cut])
+		line = line[cut:]
+		// continuation lines start with a space
+		limit = 74
+	}
+	buf.WriteString(line + "\r\n")
+}